@@ -0,0 +1,129 @@
+// cmd/jwtkeys — управление ключами подписи JWT (jwt.keys_dir)
+//
+//	go run ./cmd/jwtkeys generate -dir ./keys -alg EdDSA
+//	go run ./cmd/jwtkeys rotate -dir ./keys -activate-in 10m
+//	go run ./cmd/jwtkeys list -dir ./keys
+//	go run ./cmd/jwtkeys prune -dir ./keys -older-than 24h
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kostinp/edu-platform-backend/internal/shared/jwtkeys"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = generate(os.Args[2:], false)
+	case "rotate":
+		err = generate(os.Args[2:], true)
+	case "list":
+		err = list(os.Args[2:])
+	case "prune":
+		err = prune(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ошибка:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Использование: jwtkeys <команда> [флаги]
+
+  generate  создать ключ (по умолчанию активен сразу)
+  rotate    создать ключ на смену текущему; до активации он только публикуется в JWKS
+  list      показать ключи
+  prune     удалить давно заменённые ключи
+
+Флаги команды: jwtkeys <команда> -h`)
+	os.Exit(2)
+}
+
+func generate(args []string, rotate bool) error {
+	name := "generate"
+	defaultDelay := time.Duration(0)
+	if rotate {
+		name = "rotate"
+		// Экземпляры сервиса перечитывают ключи раз в минуту, внешние потребители JWKS кэшируют дольше
+		defaultDelay = 10 * time.Minute
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	dir := fs.String("dir", "./keys", "каталог ключей (jwt.keys_dir)")
+	alg := fs.String("alg", "", "RS256 или EdDSA (для rotate по умолчанию — как у текущего ключа)")
+	activateIn := fs.Duration("activate-in", defaultDelay, "через сколько ключ начнёт подписывать токены")
+	_ = fs.Parse(args)
+
+	if *alg == "" {
+		*alg = jwtkeys.AlgEdDSA
+		if rotate {
+			current, err := jwtkeys.Current(*dir)
+			if err != nil && !errors.Is(err, jwtkeys.ErrNoSigningKey) {
+				return err
+			}
+			if current != nil {
+				*alg = current.Algorithm
+			}
+		}
+	}
+	info, err := jwtkeys.Generate(*dir, *alg, *activateIn)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("создан ключ %s (%s), подписывает с %s\n", info.ID, info.Algorithm, info.NotBefore.Format(time.RFC3339))
+	return nil
+}
+
+func list(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	dir := fs.String("dir", "./keys", "каталог ключей (jwt.keys_dir)")
+	_ = fs.Parse(args)
+
+	keys, err := jwtkeys.List(*dir)
+	if err != nil {
+		return err
+	}
+	current, _ := jwtkeys.Current(*dir)
+	now := time.Now()
+	for _, k := range keys {
+		status := "проверка"
+		switch {
+		case current != nil && current.ID == k.ID:
+			status = "подписывает"
+		case k.NotBefore.After(now):
+			status = "ожидает активации"
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\n", k.ID, k.Algorithm, k.NotBefore.Format(time.RFC3339), status)
+	}
+	return nil
+}
+
+func prune(args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	dir := fs.String("dir", "./keys", "каталог ключей (jwt.keys_dir)")
+	olderThan := fs.Duration("older-than", 24*time.Hour, "удалить ключи, заменённые раньше этого срока (больше срока жизни access-токена)")
+	_ = fs.Parse(args)
+
+	removed, err := jwtkeys.Prune(*dir, *olderThan)
+	if err != nil {
+		return err
+	}
+	for _, kid := range removed {
+		fmt.Println("удалён ключ", kid)
+	}
+	if len(removed) == 0 {
+		fmt.Println("нечего удалять")
+	}
+	return nil
+}
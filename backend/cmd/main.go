@@ -9,6 +9,8 @@ import (
 
 	_ "github.com/kostinp/edu-platform-backend/docs"
 
+	botClient "github.com/kostinp/edu-platform-backend/internal/bot/client"
+	notificationUsecase "github.com/kostinp/edu-platform-backend/internal/notification/usecase"
 	"github.com/kostinp/edu-platform-backend/internal/shared/abac"
 	"github.com/kostinp/edu-platform-backend/internal/shared/config"
 	"github.com/kostinp/edu-platform-backend/internal/shared/db"
@@ -75,8 +77,30 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Запускаем задачу очистки
-	go usecase.StartSessionCleanupTask(context.Background(), sessionUsecase, time.Hour*24)
+	// Запускаем задачу очистки: истёкшие сессии и сессии, простаивающие дольше таймаута неактивности
+	go usecase.StartSessionCleanupTask(context.Background(), sessionUsecase, time.Hour)
+
+	// Планировщик напоминаний через бота
+	notifications, err := InitializeNotificationUsecase(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	schedulerInterval := time.Duration(cfg.Notifications.SchedulerIntervalMinutes) * time.Minute
+	if schedulerInterval <= 0 {
+		schedulerInterval = 15 * time.Minute
+	}
+	notificationUsecase.StartNotificationScheduler(context.Background(), notifications, schedulerInterval)
+
+	// Регистрируем webhook бота, если он настроен
+	if cfg.Telegram.WebhookDomain != "" && cfg.Telegram.WebhookSecret != "" {
+		go func() {
+			api := botClient.NewTelegramAPI(config.BotToken(cfg.Telegram.Token))
+			webhookURL := fmt.Sprintf("https://%s/api/telegram/webhook/%s", cfg.Telegram.WebhookDomain, cfg.Telegram.WebhookSecret)
+			if err := api.SetWebhook(webhookURL); err != nil {
+				logger.Error("Не удалось зарегистрировать webhook Telegram-бота", err)
+			}
+		}()
+	}
 
 	// Теперь инициализируем сервер с готовым engine
 	server, err := InitializeServer(cfg, abacEngine)
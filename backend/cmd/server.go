@@ -2,17 +2,38 @@
 package main
 
 import (
+	"net/http"
+
+	bookmark_http "github.com/kostinp/edu-platform-backend/internal/bookmark/transport/http"
+	bot_http "github.com/kostinp/edu-platform-backend/internal/bot/transport/http"
+	category_repository "github.com/kostinp/edu-platform-backend/internal/category/repository"
 	category_http "github.com/kostinp/edu-platform-backend/internal/category/transport/http"
 	category_navigation_http "github.com/kostinp/edu-platform-backend/internal/category/transport/http"
+	course_repository "github.com/kostinp/edu-platform-backend/internal/course/repository"
 	course_http "github.com/kostinp/edu-platform-backend/internal/course/transport/http"
+	discussion_repository "github.com/kostinp/edu-platform-backend/internal/discussion/repository"
+	discussion_http "github.com/kostinp/edu-platform-backend/internal/discussion/transport/http"
+	group_repository "github.com/kostinp/edu-platform-backend/internal/group/repository"
+	group_http "github.com/kostinp/edu-platform-backend/internal/group/transport/http"
+	lesson_repository "github.com/kostinp/edu-platform-backend/internal/lesson/repository"
 	lesson_http "github.com/kostinp/edu-platform-backend/internal/lesson/transport/http"
+	module_repository "github.com/kostinp/edu-platform-backend/internal/module/repository"
 	module_http "github.com/kostinp/edu-platform-backend/internal/module/transport/http"
+	note_repository "github.com/kostinp/edu-platform-backend/internal/note/repository"
+	note_http "github.com/kostinp/edu-platform-backend/internal/note/transport/http"
+	notification_http "github.com/kostinp/edu-platform-backend/internal/notification/transport/http"
+	organization_http "github.com/kostinp/edu-platform-backend/internal/organization/transport/http"
+	progress_http "github.com/kostinp/edu-platform-backend/internal/progress/transport/http"
+	review_repository "github.com/kostinp/edu-platform-backend/internal/review/repository"
+	review_http "github.com/kostinp/edu-platform-backend/internal/review/transport/http"
 	search_http "github.com/kostinp/edu-platform-backend/internal/search/transport/http"
 	"github.com/kostinp/edu-platform-backend/internal/shared/abac"
 	"github.com/kostinp/edu-platform-backend/internal/shared/config"
 	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	customMiddleware "github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/kostinp/edu-platform-backend/internal/shared/oidc/mock"
 	"github.com/kostinp/edu-platform-backend/internal/shared/validation"
+	tag_repository "github.com/kostinp/edu-platform-backend/internal/tag/repository"
 	tag_http "github.com/kostinp/edu-platform-backend/internal/tag/transport/http"
 	transport "github.com/kostinp/edu-platform-backend/internal/user/transport/http"
 	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
@@ -40,10 +61,50 @@ func newEchoServer(
 	tagHandler *tag_http.TagHandler,
 	categoryNavigationHandler *category_navigation_http.CategoryNavigationHandler,
 	searchHandler *search_http.SearchHandler,
+	enrollmentHandler *course_http.EnrollmentHandler,
+	reviewHandler *review_http.ReviewHandler,
+	reviewRepo *review_repository.PostgresReviewRepository,
+	commentHandler *discussion_http.CommentHandler,
+	commentRepo *discussion_repository.PostgresCommentRepository,
+	noteHandler *note_http.NoteHandler,
+	bookmarkHandler *bookmark_http.BookmarkHandler,
+	progressHandler *progress_http.ProgressHandler,
+	webhookHandler *bot_http.WebhookHandler,
+	notificationHandler *notification_http.NotificationHandler,
+	emailAuthHandler *transport.EmailAuthHandler,
+	oidcHandler *transport.OIDCHandler,
+	oidcMockProvider *mock.Provider,
+	mergeHandler *transport.MergeHandler,
+	tokenService *usecase.TokenService,
+	tokenHandler *transport.TokenHandler,
+	jwksHandler *transport.JWKSHandler,
+	mfaHandler *transport.MFAHandler,
+	apiTokenService *usecase.APITokenService,
+	apiTokenHandler *transport.APITokenHandler,
+	accountHandler *transport.AccountHandler,
+	adminUserService *usecase.AdminUserService,
+	adminUserHandler *transport.AdminUserHandler,
+	teacherApplicationHandler *transport.TeacherApplicationHandler,
+	organizationHandler *organization_http.OrganizationHandler,
+	courseRepo *course_repository.PostgresCourseRepository,
+	categoryRepo *category_repository.PostgresCategoryRepository,
+	tagRepo *tag_repository.PostgresTagRepository,
+	groupHandler *group_http.GroupHandler,
+	groupRepo *group_repository.PostgresGroupRepository,
+	moduleRepo *module_repository.PostgresModuleRepository,
+	lessonRepo *lesson_repository.PostgresLessonRepository,
+	noteRepo *note_repository.PostgresNoteRepository,
 ) (*echo.Echo, error) {
 	e := echo.New()
 	e.Validator = validation.NewCustomValidator()
 
+	// IP клиента (сессии, GeoIP, ABAC) — с учётом только доверенных прокси
+	ipExtractor, err := customMiddleware.IPExtractor(cfg.App.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	e.IPExtractor = ipExtractor
+
 	// CORS middleware
 	e.Use(echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
 		AllowOrigins: []string{
@@ -66,9 +127,6 @@ func newEchoServer(
 			"X-Requested-With",
 			"X-CSRF-Token",
 		},
-		ExposeHeaders: []string{
-			"X-Refresh-Token", // если используете обновление токенов
-		},
 		AllowCredentials: true,
 		MaxAge:           86400,
 	}))
@@ -85,7 +143,7 @@ func newEchoServer(
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	// Ваш JWT middleware
-	jwtMiddleware := customMiddleware.JWTMiddleware([]byte(cfg.JWT.Secret), sessionUsecase)
+	jwtMiddleware := customMiddleware.JWTMiddleware(tokenService, sessionUsecase)
 
 	// Другие middleware, которые нужны для всех запросов
 	e.Use(customMiddleware.VisitorMiddleware)
@@ -97,63 +155,222 @@ func newEchoServer(
 	e.GET("/api/visitor", transport.GetVisitorIDHandler)
 	e.POST("/api/visitor/events", visitorEventHandler.LogEvent)
 	e.POST("/api/telegram/auth", telegramAuthHandler.Auth)
+	e.POST("/api/telegram/webapp/auth", telegramAuthHandler.WebAppAuth)
+	e.POST("/api/telegram/webhook/:secret", webhookHandler.Handle)
+
+	// Обновление access-токена по refresh-токену
+	e.POST("/api/auth/refresh", tokenHandler.Refresh)
+	e.POST("/api/auth/step-up", tokenHandler.StepUp)
+	// Открытые ключи для проверки access-токенов другими сервисами
+	e.GET("/.well-known/jwks.json", jwksHandler.JWKS)
+
+	// Вход по email: пароль, подтверждение адреса, сброс пароля, magic links
+	e.POST("/api/auth/email/register", emailAuthHandler.Register)
+	e.POST("/api/auth/email/login", emailAuthHandler.Login)
+	e.POST("/api/auth/email/verify", emailAuthHandler.VerifyEmail)
+	e.POST("/api/auth/email/resend-verification", emailAuthHandler.ResendVerification)
+	e.POST("/api/auth/password/forgot", emailAuthHandler.ForgotPassword)
+	e.POST("/api/auth/password/reset", emailAuthHandler.ResetPassword)
+	e.POST("/api/auth/magic-link", emailAuthHandler.RequestMagicLink)
+	e.POST("/api/auth/magic-link/verify", emailAuthHandler.ConsumeMagicLink)
+
+	// Вход через внешних провайдеров (OpenID Connect)
+	e.GET("/api/auth/oidc/providers", oidcHandler.Providers)
+	e.GET("/api/auth/oidc/:provider/authorize", oidcHandler.Authorize)
+	e.POST("/api/auth/oidc/:provider/callback", oidcHandler.Callback)
+	if oidcMockProvider != nil {
+		e.Any("/api/dev/oidc/*", echo.WrapHandler(http.StripPrefix("/api/dev/oidc", oidcMockProvider)))
+	}
 
 	// Создаем группу для маршрутов, защищённых JWT
 	apiProtected := e.Group("/api")
+	// Персональные API-токены проверяются до JWT
+	apiProtected.Use(customMiddleware.APITokenMiddleware(apiTokenService))
 	apiProtected.Use(jwtMiddleware)
+	apiProtected.Use(customMiddleware.LoadCurrentUser(userService))
+	apiProtected.Use(customMiddleware.ImpersonationAudit(adminUserService))
+
+	// Публичный каталог открыт всем, но вошедшим участникам организации
+	// показывает и её закрытый контент
+	orgOptional := customMiddleware.OptionalAuth(
+		customMiddleware.APITokenMiddleware(apiTokenService),
+		jwtMiddleware,
+		customMiddleware.LoadCurrentUser(userService),
+	)
+	// Организация ресурса для ABAC: чужой закрытый контент недоступен и по прямой ссылке.
+	// Модули, уроки, отзывы, комментарии и заметки наследуют организацию своего курса
+	courseOrg := middleware.SetResourceOrgMiddleware(courseRepo)
+	categoryOrg := middleware.SetResourceOrgMiddleware(categoryRepo)
+	tagOrg := middleware.SetResourceOrgMiddleware(tagRepo)
+	moduleOrg := middleware.SetResourceOrgMiddleware(moduleRepo)
+	lessonOrg := middleware.SetResourceOrgMiddleware(lessonRepo)
+	reviewOrg := middleware.SetResourceOrgMiddleware(reviewRepo)
+	commentOrg := middleware.SetResourceOrgMiddleware(commentRepo)
+	noteOrg := middleware.SetResourceOrgMiddleware(noteRepo)
 
 	// ========== КАТЕГОРИИ (навигация) ==========
 	// Публичные роуты - доступны всем
-	e.GET("/api/categories/tree", categoryNavigationHandler.GetCategoryTree)
-	e.GET("/api/categories/:id/breadcrumbs", categoryNavigationHandler.GetBreadcrumbs)
-	e.GET("/api/categories/:id/content", categoryNavigationHandler.GetCategoryContent)
+	e.GET("/api/categories/tree", categoryNavigationHandler.GetCategoryTree, orgOptional)
+	e.GET("/api/categories/by-slug/:slug", categoryHandler.GetBySlug, orgOptional)
+	e.GET("/api/courses/by-slug/:slug", courseHandler.GetBySlug, orgOptional)
+	e.GET("/api/categories/:id/breadcrumbs", categoryNavigationHandler.GetBreadcrumbs, orgOptional)
+	e.GET("/api/categories/:id/content", categoryNavigationHandler.GetCategoryContent, orgOptional)
 
 	// Поиск - доступен всем
-	e.GET("/api/search", searchHandler.Search)
+	e.GET("/api/search", searchHandler.Search, orgOptional)
 	e.GET("/api/search/autocomplete", searchHandler.Autocomplete)
 
 	// Защищенные роуты категорий
 	apiProtected.POST("/categories", middleware.ABACMiddleware(abacEngine, "category", "create")(categoryHandler.Create))
 	apiProtected.GET("/categories", middleware.ABACMiddleware(abacEngine, "category", "read")(categoryHandler.List))
-	apiProtected.GET("/categories/:id", middleware.ABACMiddleware(abacEngine, "category", "read")(categoryHandler.Get))
-	apiProtected.PUT("/categories/:id", middleware.ABACMiddleware(abacEngine, "category", "update")(categoryHandler.Update))
-	apiProtected.DELETE("/categories/:id", middleware.ABACMiddleware(abacEngine, "category", "delete")(categoryHandler.Delete))
+	apiProtected.GET("/categories/:id", categoryOrg(middleware.ABACMiddleware(abacEngine, "category", "read")(categoryHandler.Get)))
+	apiProtected.PUT("/categories/:id", categoryOrg(middleware.ABACMiddleware(abacEngine, "category", "update")(categoryHandler.Update)))
+	apiProtected.DELETE("/categories/:id", categoryOrg(middleware.ABACMiddleware(abacEngine, "category", "delete")(categoryHandler.Delete)))
 
 	// Убираем RequireRole — заменяем на ABAC
 	apiProtected.GET("/me/sessions",
 		middleware.ABACMiddleware(abacEngine, "user_sessions", "read")(sessionHandler.ListSessions))
 	apiProtected.DELETE("/me/sessions/:id",
 		middleware.ABACMiddleware(abacEngine, "user_sessions", "delete")(sessionHandler.DeleteSession))
-	apiProtected.GET("/analytics/page-views",
-		middleware.ABACMiddleware(abacEngine, "analytics", "read")(analyticsHandler.GetPageViews))
+	apiProtected.POST("/me/sessions/logout-others",
+		middleware.ABACMiddleware(abacEngine, "user_sessions", "delete")(sessionHandler.LogoutOthers))
+	apiProtected.POST("/me/sessions/logout-all",
+		middleware.ABACMiddleware(abacEngine, "user_sessions", "delete")(sessionHandler.LogoutAll))
 
 	// Для курсов
 	apiProtected.POST("/courses", middleware.ABACMiddleware(abacEngine, "course", "create")(courseHandler.Create))
 	apiProtected.GET("/courses", middleware.ABACMiddleware(abacEngine, "course", "read")(courseHandler.List))
-	apiProtected.GET("/courses/:id", middleware.ABACMiddleware(abacEngine, "course", "read")(courseHandler.Get))
-	apiProtected.PUT("/courses/:id", middleware.ABACMiddleware(abacEngine, "course", "update")(courseHandler.Update))
-	apiProtected.DELETE("/courses/:id", middleware.ABACMiddleware(abacEngine, "course", "delete")(courseHandler.Delete))
+	apiProtected.GET("/courses/:id", courseOrg(middleware.ABACMiddleware(abacEngine, "course", "read")(courseHandler.Get)))
+	apiProtected.PUT("/courses/:id", courseOrg(middleware.ABACMiddleware(abacEngine, "course", "update")(courseHandler.Update)))
+	apiProtected.DELETE("/courses/:id", courseOrg(middleware.ABACMiddleware(abacEngine, "course", "delete")(courseHandler.Delete)))
+	apiProtected.POST("/courses/:id/enroll", courseOrg(middleware.ABACMiddleware(abacEngine, "course", "enroll")(enrollmentHandler.Enroll)))
+	apiProtected.GET("/me/enrollments", middleware.ABACMiddleware(abacEngine, "enrollment", "read")(enrollmentHandler.ListMine))
+
+	// Отзывы о курсах
+	e.GET("/api/courses/:id/reviews", reviewHandler.ListByCourse, orgOptional)
+	reviewAuthor := middleware.SetResourceAuthorMiddleware(reviewRepo)
+	apiProtected.POST("/courses/:id/reviews", courseOrg(middleware.ABACMiddleware(abacEngine, "course_review", "create")(reviewHandler.Create)))
+	apiProtected.PUT("/reviews/:id", reviewAuthor(reviewOrg(middleware.ABACMiddleware(abacEngine, "course_review", "update")(reviewHandler.Update))))
+	apiProtected.DELETE("/reviews/:id", reviewAuthor(reviewOrg(middleware.ABACMiddleware(abacEngine, "course_review", "delete")(reviewHandler.Delete))))
+	apiProtected.GET("/reviews/moderation", middleware.ABACMiddleware(abacEngine, "course_review", "moderate")(reviewHandler.ListModerationQueue))
+	apiProtected.POST("/reviews/:id/approve", reviewAuthor(reviewOrg(middleware.ABACMiddleware(abacEngine, "course_review", "moderate")(reviewHandler.Approve))))
+	apiProtected.POST("/reviews/:id/reject", reviewAuthor(reviewOrg(middleware.ABACMiddleware(abacEngine, "course_review", "moderate")(reviewHandler.Reject))))
 
 	// Для модулей
 	apiProtected.POST("/modules", middleware.ABACMiddleware(abacEngine, "module", "create")(moduleHandler.Create))
 	apiProtected.GET("/modules", middleware.ABACMiddleware(abacEngine, "module", "read")(moduleHandler.List))
-	apiProtected.GET("/modules/:id", middleware.ABACMiddleware(abacEngine, "module", "read")(moduleHandler.Get))
-	apiProtected.PUT("/modules/:id", middleware.ABACMiddleware(abacEngine, "module", "update")(moduleHandler.Update))
-	apiProtected.DELETE("/modules/:id", middleware.ABACMiddleware(abacEngine, "module", "delete")(moduleHandler.Delete))
+	apiProtected.GET("/modules/:id", moduleOrg(middleware.ABACMiddleware(abacEngine, "module", "read")(moduleHandler.Get)))
+	apiProtected.PUT("/modules/:id", moduleOrg(middleware.ABACMiddleware(abacEngine, "module", "update")(moduleHandler.Update)))
+	apiProtected.DELETE("/modules/:id", moduleOrg(middleware.ABACMiddleware(abacEngine, "module", "delete")(moduleHandler.Delete)))
 
 	// Для уроков
 	apiProtected.POST("/lessons", middleware.ABACMiddleware(abacEngine, "lesson", "create")(lessonHandler.Create))
 	apiProtected.GET("/lessons", middleware.ABACMiddleware(abacEngine, "lesson", "read")(lessonHandler.List))
-	apiProtected.GET("/lessons/:id", middleware.ABACMiddleware(abacEngine, "lesson", "read")(lessonHandler.Get))
-	apiProtected.PUT("/lessons/:id", middleware.ABACMiddleware(abacEngine, "lesson", "update")(lessonHandler.Update))
-	apiProtected.DELETE("/lessons/:id", middleware.ABACMiddleware(abacEngine, "lesson", "delete")(lessonHandler.Delete))
+	apiProtected.GET("/lessons/:id", lessonOrg(middleware.ABACMiddleware(abacEngine, "lesson", "read")(lessonHandler.Get)))
+	apiProtected.PUT("/lessons/:id", lessonOrg(middleware.ABACMiddleware(abacEngine, "lesson", "update")(lessonHandler.Update)))
+	apiProtected.DELETE("/lessons/:id", lessonOrg(middleware.ABACMiddleware(abacEngine, "lesson", "delete")(lessonHandler.Delete)))
+
+	// Обсуждения уроков
+	commentAuthor := middleware.SetResourceAuthorMiddleware(commentRepo)
+	commentCourseAuthor := middleware.SetCourseAuthorMiddleware(commentRepo)
+	apiProtected.GET("/lessons/:id/comments", lessonOrg(middleware.ABACMiddleware(abacEngine, "lesson_comment", "read")(commentHandler.List)))
+	apiProtected.POST("/lessons/:id/comments", lessonOrg(middleware.ABACMiddleware(abacEngine, "lesson_comment", "create")(commentHandler.Create)))
+	apiProtected.PUT("/comments/:id", commentAuthor(commentOrg(middleware.ABACMiddleware(abacEngine, "lesson_comment", "update")(commentHandler.Edit))))
+	apiProtected.DELETE("/comments/:id", commentAuthor(commentCourseAuthor(commentOrg(middleware.ABACMiddleware(abacEngine, "lesson_comment", "delete")(commentHandler.Delete)))))
+	apiProtected.GET("/comments/:id/history", commentAuthor(commentCourseAuthor(commentOrg(middleware.ABACMiddleware(abacEngine, "lesson_comment", "history")(commentHandler.History)))))
+	apiProtected.POST("/comments/:id/pin", commentCourseAuthor(commentOrg(middleware.ABACMiddleware(abacEngine, "lesson_comment", "moderate")(commentHandler.Pin))))
+	apiProtected.POST("/comments/:id/answer", commentCourseAuthor(commentOrg(middleware.ABACMiddleware(abacEngine, "lesson_comment", "moderate")(commentHandler.MarkAnswer))))
+
+	// Личные заметки и выделения (доступ ограничен владельцем на уровне репозитория)
+	apiProtected.GET("/lessons/:id/notes", lessonOrg(middleware.ABACMiddleware(abacEngine, "note", "read")(noteHandler.ListByLesson)))
+	apiProtected.POST("/lessons/:id/notes", lessonOrg(middleware.ABACMiddleware(abacEngine, "note", "create")(noteHandler.Create)))
+	apiProtected.PUT("/notes/:id", noteOrg(middleware.ABACMiddleware(abacEngine, "note", "update")(noteHandler.Update)))
+	apiProtected.DELETE("/notes/:id", noteOrg(middleware.ABACMiddleware(abacEngine, "note", "delete")(noteHandler.Delete)))
+	apiProtected.GET("/me/notes/search", middleware.ABACMiddleware(abacEngine, "note", "read")(noteHandler.Search))
+	apiProtected.GET("/courses/:id/notes/export", courseOrg(middleware.ABACMiddleware(abacEngine, "note", "read")(noteHandler.ExportCourse)))
+
+	// Прогресс обучения
+	apiProtected.POST("/lessons/:id/view", lessonOrg(middleware.ABACMiddleware(abacEngine, "progress", "update")(progressHandler.MarkViewed)))
+	apiProtected.POST("/lessons/:id/complete", lessonOrg(middleware.ABACMiddleware(abacEngine, "progress", "update")(progressHandler.MarkCompleted)))
+	apiProtected.GET("/me/progress", middleware.ABACMiddleware(abacEngine, "progress", "read")(progressHandler.ListMine))
+	apiProtected.GET("/me/progress/continue", middleware.ABACMiddleware(abacEngine, "progress", "read")(progressHandler.Continue))
+	apiProtected.GET("/me/streak", middleware.ABACMiddleware(abacEngine, "progress", "read")(progressHandler.Streak))
+
+	// Уведомления
+	apiProtected.GET("/me/notification-preferences", middleware.ABACMiddleware(abacEngine, "notification_settings", "read")(notificationHandler.GetPreferences))
+	apiProtected.PUT("/me/notification-preferences", middleware.ABACMiddleware(abacEngine, "notification_settings", "update")(notificationHandler.UpdatePreferences))
+	apiProtected.GET("/me/notifications", middleware.ABACMiddleware(abacEngine, "notification_settings", "read")(notificationHandler.ListDeliveries))
+	apiProtected.POST("/notifications/send", middleware.ABACMiddleware(abacEngine, "notification", "send")(notificationHandler.Send))
+
+	// Слияние аккаунтов
+	apiProtected.POST("/me/merge", middleware.ABACMiddleware(abacEngine, "account_merge", "create")(mergeHandler.MergeOwn))
+	apiProtected.GET("/me/merges", middleware.ABACMiddleware(abacEngine, "account_merge", "read")(mergeHandler.ListMine))
+	apiProtected.POST("/admin/users/merge", middleware.ABACMiddleware(abacEngine, "user", "merge")(mergeHandler.AdminMerge))
+	apiProtected.GET("/admin/users/:id/merges", middleware.ABACMiddleware(abacEngine, "user", "read")(mergeHandler.AdminList))
+
+	// Управление пользователями (вход от имени пользователя — см. политики impersonate_*)
+	apiProtected.GET("/admin/users", middleware.ABACMiddleware(abacEngine, "user", "read")(adminUserHandler.List))
+	apiProtected.POST("/admin/users/bulk", middleware.ABACMiddleware(abacEngine, "user", "bulk")(adminUserHandler.Bulk))
+	apiProtected.PUT("/admin/users/:id/role", middleware.ABACMiddleware(abacEngine, "user", "update_role")(adminUserHandler.SetRole))
+	apiProtected.POST("/admin/users/:id/ban", middleware.ABACMiddleware(abacEngine, "user", "ban")(adminUserHandler.Ban))
+	apiProtected.DELETE("/admin/users/:id/ban", middleware.ABACMiddleware(abacEngine, "user", "ban")(adminUserHandler.Unban))
+	apiProtected.DELETE("/admin/users/:id/sessions", middleware.ABACMiddleware(abacEngine, "user", "revoke_sessions")(adminUserHandler.RevokeSessions))
+	apiProtected.POST("/admin/users/:id/impersonate", middleware.ABACMiddleware(abacEngine, "user", "impersonate")(adminUserHandler.Impersonate))
+	apiProtected.GET("/admin/audit", middleware.ABACMiddleware(abacEngine, "user", "audit")(adminUserHandler.Audit))
+
+	// Заявки на роль преподавателя
+	apiProtected.GET("/me/teacher-applications", middleware.ABACMiddleware(abacEngine, "teacher_application", "read")(teacherApplicationHandler.ListMine))
+	apiProtected.POST("/me/teacher-applications", middleware.ABACMiddleware(abacEngine, "teacher_application", "create")(teacherApplicationHandler.Apply))
+	apiProtected.DELETE("/me/teacher-applications/:id", middleware.ABACMiddleware(abacEngine, "teacher_application", "delete")(teacherApplicationHandler.Withdraw))
+	apiProtected.GET("/admin/teacher-applications", middleware.ABACMiddleware(abacEngine, "user", "review_application")(teacherApplicationHandler.List))
+	apiProtected.GET("/admin/teacher-applications/:id", middleware.ABACMiddleware(abacEngine, "user", "review_application")(teacherApplicationHandler.Get))
+	apiProtected.POST("/admin/teacher-applications/:id/approve", middleware.ABACMiddleware(abacEngine, "user", "review_application")(teacherApplicationHandler.Approve))
+	apiProtected.POST("/admin/teacher-applications/:id/reject", middleware.ABACMiddleware(abacEngine, "user", "review_application")(teacherApplicationHandler.Reject))
+
+	// Двухфакторная аутентификация
+	apiProtected.GET("/me/mfa", middleware.ABACMiddleware(abacEngine, "mfa", "read")(mfaHandler.Status))
+	apiProtected.DELETE("/me/mfa", middleware.ABACMiddleware(abacEngine, "mfa", "delete")(mfaHandler.Disable))
+	apiProtected.POST("/me/mfa/totp", middleware.ABACMiddleware(abacEngine, "mfa", "create")(mfaHandler.BeginTOTP))
+	apiProtected.POST("/me/mfa/totp/confirm", middleware.ABACMiddleware(abacEngine, "mfa", "create")(mfaHandler.ConfirmTOTP))
+	apiProtected.POST("/me/mfa/verify", middleware.ABACMiddleware(abacEngine, "mfa", "update")(mfaHandler.VerifySession))
+	apiProtected.POST("/me/mfa/recovery-codes", middleware.ABACMiddleware(abacEngine, "mfa", "update")(mfaHandler.RegenerateRecoveryCodes))
+
+	// Профиль, выгрузка данных и удаление аккаунта
+	apiProtected.GET("/me", middleware.ABACMiddleware(abacEngine, "profile", "read")(accountHandler.Get))
+	apiProtected.PATCH("/me", middleware.ABACMiddleware(abacEngine, "profile", "update")(accountHandler.Update))
+	apiProtected.GET("/me/export", middleware.ABACMiddleware(abacEngine, "account", "export")(accountHandler.Export))
+	apiProtected.DELETE("/me", middleware.ABACMiddleware(abacEngine, "account", "delete")(accountHandler.Delete))
+
+	// Персональные API-токены
+	apiProtected.GET("/me/tokens", middleware.ABACMiddleware(abacEngine, "api_token", "read")(apiTokenHandler.List))
+	apiProtected.POST("/me/tokens", middleware.ABACMiddleware(abacEngine, "api_token", "create")(apiTokenHandler.Create))
+	apiProtected.DELETE("/me/tokens/:id", middleware.ABACMiddleware(abacEngine, "api_token", "delete")(apiTokenHandler.Revoke))
+
+	// Привязанные внешние аккаунты
+	apiProtected.GET("/me/identities", middleware.ABACMiddleware(abacEngine, "identity", "read")(oidcHandler.ListIdentities))
+	apiProtected.POST("/me/identities/:provider", middleware.ABACMiddleware(abacEngine, "identity", "create")(oidcHandler.Link))
+	apiProtected.POST("/me/identities/:provider/callback", middleware.ABACMiddleware(abacEngine, "identity", "create")(oidcHandler.LinkCallback))
+	apiProtected.DELETE("/me/identities/:provider", middleware.ABACMiddleware(abacEngine, "identity", "delete")(oidcHandler.Unlink))
+
+	// Закладки и избранное (доступ ограничен владельцем на уровне репозитория)
+	apiProtected.GET("/me/bookmarks", middleware.ABACMiddleware(abacEngine, "bookmark", "read")(bookmarkHandler.List))
+	apiProtected.GET("/me/bookmarks/resolved", middleware.ABACMiddleware(abacEngine, "bookmark", "read")(bookmarkHandler.Resolve))
+	apiProtected.POST("/me/bookmarks", middleware.ABACMiddleware(abacEngine, "bookmark", "create")(bookmarkHandler.Add))
+	apiProtected.PUT("/me/bookmarks/order", middleware.ABACMiddleware(abacEngine, "bookmark", "update")(bookmarkHandler.Reorder))
+	apiProtected.PUT("/me/bookmarks/:id", middleware.ABACMiddleware(abacEngine, "bookmark", "update")(bookmarkHandler.Move))
+	apiProtected.DELETE("/me/bookmarks/:id", middleware.ABACMiddleware(abacEngine, "bookmark", "delete")(bookmarkHandler.Remove))
+	apiProtected.GET("/me/bookmark-folders", middleware.ABACMiddleware(abacEngine, "bookmark", "read")(bookmarkHandler.ListFolders))
+	apiProtected.POST("/me/bookmark-folders", middleware.ABACMiddleware(abacEngine, "bookmark", "create")(bookmarkHandler.CreateFolder))
+	apiProtected.PUT("/me/bookmark-folders/order", middleware.ABACMiddleware(abacEngine, "bookmark", "update")(bookmarkHandler.ReorderFolders))
+	apiProtected.PUT("/me/bookmark-folders/:id", middleware.ABACMiddleware(abacEngine, "bookmark", "update")(bookmarkHandler.RenameFolder))
+	apiProtected.DELETE("/me/bookmark-folders/:id", middleware.ABACMiddleware(abacEngine, "bookmark", "delete")(bookmarkHandler.DeleteFolder))
 
 	// Для категорий
 	apiProtected.POST("/categories", middleware.ABACMiddleware(abacEngine, "category", "create")(categoryHandler.Create))
 	apiProtected.GET("/categories", middleware.ABACMiddleware(abacEngine, "category", "read")(categoryHandler.List))
-	apiProtected.GET("/categories/:id", middleware.ABACMiddleware(abacEngine, "category", "read")(categoryHandler.Get))
-	apiProtected.PUT("/categories/:id", middleware.ABACMiddleware(abacEngine, "category", "update")(categoryHandler.Update))
-	apiProtected.DELETE("/categories/:id", middleware.ABACMiddleware(abacEngine, "category", "delete")(categoryHandler.Delete))
+	apiProtected.GET("/categories/:id", categoryOrg(middleware.ABACMiddleware(abacEngine, "category", "read")(categoryHandler.Get)))
+	apiProtected.PUT("/categories/:id", categoryOrg(middleware.ABACMiddleware(abacEngine, "category", "update")(categoryHandler.Update)))
+	apiProtected.DELETE("/categories/:id", categoryOrg(middleware.ABACMiddleware(abacEngine, "category", "delete")(categoryHandler.Delete)))
 	apiProtected.POST("/category-assignments", middleware.ABACMiddleware(abacEngine, "category_assignment", "create")(categoryHandler.Assign))
 	apiProtected.DELETE("/category-assignments/:id", middleware.ABACMiddleware(abacEngine, "category_assignment", "delete")(categoryHandler.Remove))
 	apiProtected.GET("/category-assignments/by-entity", middleware.ABACMiddleware(abacEngine, "category_assignment", "read")(categoryHandler.ListByEntity))
@@ -162,24 +379,57 @@ func newEchoServer(
 	// Для тегов
 	apiProtected.POST("/tags", middleware.ABACMiddleware(abacEngine, "tag", "create")(tagHandler.CreateTag))
 	apiProtected.GET("/tags", middleware.ABACMiddleware(abacEngine, "tag", "read")(tagHandler.ListTags))
-	apiProtected.GET("/tags/:id", middleware.ABACMiddleware(abacEngine, "tag", "read")(tagHandler.GetTagByID))
+	apiProtected.GET("/tags/:id", tagOrg(middleware.ABACMiddleware(abacEngine, "tag", "read")(tagHandler.GetTagByID)))
 
 	// apiProtected.PUT("/tags/:id", middleware.ABACMiddleware(abacEngine, "tag", "update")(tagHandler.UpdateTag))
-	apiProtected.DELETE("/tags/:id", middleware.ABACMiddleware(abacEngine, "tag", "delete")(tagHandler.DeleteTag))
+	apiProtected.DELETE("/tags/:id", tagOrg(middleware.ABACMiddleware(abacEngine, "tag", "delete")(tagHandler.DeleteTag)))
 	apiProtected.POST("/tag-assignments", middleware.ABACMiddleware(abacEngine, "tag_assignment", "create")(tagHandler.AssignTag))
 	apiProtected.DELETE("/tag-assignments/:id", middleware.ABACMiddleware(abacEngine, "tag_assignment", "delete")(tagHandler.RemoveAssignment))
 	apiProtected.GET("/tag-assignments/by-entity", middleware.ABACMiddleware(abacEngine, "tag_assignment", "read")(tagHandler.ListAssignmentsByEntity))
 	apiProtected.GET("/tag-assignments/by-tag", middleware.ABACMiddleware(abacEngine, "tag_assignment", "read")(tagHandler.ListAssignmentsByTag))
 
-	// Добавьте сюда все защищённые роуты, например:
-	apiProtected.GET("/me/sessions", sessionHandler.ListSessions)
-	apiProtected.DELETE("/me/sessions/:id", sessionHandler.DeleteSession)
-	apiProtected.POST("/me/inactivity-timeout", sessionHandler.SetInactivityTimeout)
-	apiProtected.GET("/me/inactivity-timeout", sessionHandler.GetInactivityTimeout)
+	// Добавьте сюда все защищённые роуты (через ABAC — иначе их не ограничат scopes API-токенов)
+	apiProtected.POST("/me/inactivity-timeout", middleware.ABACMiddleware(abacEngine, "user_sessions", "update")(sessionHandler.SetInactivityTimeout))
+	apiProtected.GET("/me/inactivity-timeout", middleware.ABACMiddleware(abacEngine, "user_sessions", "read")(sessionHandler.GetInactivityTimeout))
+
+	// ========== ОРГАНИЗАЦИИ ==========
+	// Создаёт и видит весь список только администратор платформы (admin_full_access)
+	apiProtected.POST("/organizations", middleware.ABACMiddleware(abacEngine, "organization", "create")(organizationHandler.Create))
+	apiProtected.GET("/admin/organizations", middleware.ABACMiddleware(abacEngine, "organization", "list")(organizationHandler.List))
+	apiProtected.GET("/me/organization", middleware.ABACMiddleware(abacEngine, "organization_membership", "read")(organizationHandler.GetMine))
+	apiProtected.DELETE("/me/organization", middleware.ABACMiddleware(abacEngine, "organization_membership", "delete")(organizationHandler.Leave))
+	apiProtected.GET("/organizations/:id", middleware.ABACMiddleware(abacEngine, "organization", "read")(organizationHandler.Get))
+	apiProtected.PUT("/organizations/:id", middleware.ABACMiddleware(abacEngine, "organization", "update")(organizationHandler.Update))
+	apiProtected.DELETE("/organizations/:id", middleware.ABACMiddleware(abacEngine, "organization", "delete")(organizationHandler.Delete))
+	apiProtected.GET("/organizations/:id/members", middleware.ABACMiddleware(abacEngine, "organization", "read")(organizationHandler.ListMembers))
+	apiProtected.POST("/organizations/:id/members", middleware.ABACMiddleware(abacEngine, "organization", "manage_members")(organizationHandler.AddMember))
+	apiProtected.PUT("/organizations/:id/members/:user_id", middleware.ABACMiddleware(abacEngine, "organization", "manage_members")(organizationHandler.SetMemberRole))
+	apiProtected.DELETE("/organizations/:id/members/:user_id", middleware.ABACMiddleware(abacEngine, "organization", "manage_members")(organizationHandler.RemoveMember))
+
+	// ========== УЧЕБНЫЕ ГРУППЫ ==========
+	// Группой управляет создавший её преподаватель; карточку и курсы видят и участники (проверка в usecase)
+	groupAuthor := middleware.SetResourceAuthorMiddleware(groupRepo)
+	groupOrg := middleware.SetResourceOrgMiddleware(groupRepo)
+	apiProtected.POST("/groups", middleware.ABACMiddleware(abacEngine, "study_group", "create")(groupHandler.Create))
+	apiProtected.GET("/groups", middleware.ABACMiddleware(abacEngine, "study_group", "list")(groupHandler.ListOwned))
+	apiProtected.POST("/groups/join", middleware.ABACMiddleware(abacEngine, "study_group_membership", "create")(groupHandler.Join))
+	apiProtected.GET("/me/groups", middleware.ABACMiddleware(abacEngine, "study_group_membership", "read")(groupHandler.ListJoined))
+	apiProtected.DELETE("/me/groups/:id", middleware.ABACMiddleware(abacEngine, "study_group_membership", "delete")(groupHandler.Leave))
+	apiProtected.GET("/groups/:id", middleware.ABACMiddleware(abacEngine, "study_group_membership", "read")(groupHandler.Get))
+	apiProtected.GET("/groups/:id/courses", middleware.ABACMiddleware(abacEngine, "study_group_membership", "read")(groupHandler.ListCourses))
+	apiProtected.PUT("/groups/:id", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "update")(groupHandler.Update))))
+	apiProtected.DELETE("/groups/:id", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "delete")(groupHandler.Delete))))
+	apiProtected.POST("/groups/:id/join-code", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "update")(groupHandler.RegenerateJoinCode))))
+	apiProtected.GET("/groups/:id/members", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "manage_members")(groupHandler.ListMembers))))
+	apiProtected.POST("/groups/:id/members", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "manage_members")(groupHandler.AddMember))))
+	apiProtected.DELETE("/groups/:id/members/:user_id", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "manage_members")(groupHandler.RemoveMember))))
+	apiProtected.POST("/groups/:id/courses", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "assign_courses")(groupHandler.AssignCourse))))
+	apiProtected.DELETE("/groups/:id/courses/:course_id", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "assign_courses")(groupHandler.UnassignCourse))))
+	apiProtected.GET("/groups/:id/progress", groupAuthor(groupOrg(middleware.ABACMiddleware(abacEngine, "study_group", "read_progress")(groupHandler.Progress))))
 
-	// Аналитика
-	apiProtected.GET("/analytics/page-views", analyticsHandler.GetPageViews)
-	apiProtected.GET("/analytics/utm-stats", analyticsHandler.GetUTMStats)
-	apiProtected.GET("/analytics/conversion-rate", analyticsHandler.GetConversionRate)
+	// Аналитика — только админы, прошедшие 2FA (см. политику analytics_require_mfa)
+	apiProtected.GET("/analytics/page-views", middleware.ABACMiddleware(abacEngine, "analytics", "read")(analyticsHandler.GetPageViews))
+	apiProtected.GET("/analytics/utm-stats", middleware.ABACMiddleware(abacEngine, "analytics", "read")(analyticsHandler.GetUTMStats))
+	apiProtected.GET("/analytics/conversion-rate", middleware.ABACMiddleware(abacEngine, "analytics", "read")(analyticsHandler.GetConversionRate))
 	return e, nil
 }
@@ -5,10 +5,22 @@ package main
 
 import (
 	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark"
+	"github.com/kostinp/edu-platform-backend/internal/bot"
+	botClient "github.com/kostinp/edu-platform-backend/internal/bot/client"
+	botUsecase "github.com/kostinp/edu-platform-backend/internal/bot/usecase"
 	"github.com/kostinp/edu-platform-backend/internal/category"
 	"github.com/kostinp/edu-platform-backend/internal/course"
+	"github.com/kostinp/edu-platform-backend/internal/discussion"
+	"github.com/kostinp/edu-platform-backend/internal/group"
 	"github.com/kostinp/edu-platform-backend/internal/lesson"
 	"github.com/kostinp/edu-platform-backend/internal/module"
+	"github.com/kostinp/edu-platform-backend/internal/note"
+	"github.com/kostinp/edu-platform-backend/internal/notification"
+	notificationUsecase "github.com/kostinp/edu-platform-backend/internal/notification/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/organization"
+	"github.com/kostinp/edu-platform-backend/internal/progress"
+	"github.com/kostinp/edu-platform-backend/internal/review"
 	"github.com/kostinp/edu-platform-backend/internal/search"
 	"github.com/kostinp/edu-platform-backend/internal/shared/abac"
 	"github.com/kostinp/edu-platform-backend/internal/shared/config"
@@ -26,6 +38,15 @@ func InitializeServer(cfg *config.Config, abacEngine *abac.Engine) (*echo.Echo,
 		lesson.LessonSet,
 		category.CategorySet,
 		search.SearchSet,
+		review.ReviewSet,
+		discussion.DiscussionSet,
+		note.NoteSet,
+		bookmark.BookmarkSet,
+		progress.ProgressSet,
+		bot.BotSet,
+		notification.NotificationSet,
+		organization.OrganizationSet,
+		group.GroupSet,
 		newEchoServer,
 	)
 	return nil, nil
@@ -36,3 +57,15 @@ func InitializeSessionUsecase(cfg *config.Config) (usecase.SessionUsecase, error
 	wire.Build(user.SessionUsecaseSet)
 	return nil, nil
 }
+
+// Для планировщика уведомлений
+func InitializeNotificationUsecase(cfg *config.Config) (notificationUsecase.NotificationUsecase, error) {
+	wire.Build(
+		notification.NotificationSet,
+		user.ProvideBotToken,
+		bot.ProvideSiteURL,
+		botClient.NewTelegramAPI,
+		wire.Bind(new(botUsecase.BotAPI), new(*botClient.TelegramAPI)),
+	)
+	return nil, nil
+}
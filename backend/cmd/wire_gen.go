@@ -14,24 +14,54 @@ import (
 	"github.com/kostinp/edu-platform-backend/internal/user/repository"
 	"github.com/kostinp/edu-platform-backend/internal/user/transport/http"
 	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	bookmark_repository "github.com/kostinp/edu-platform-backend/internal/bookmark/repository"
+	bookmark_usecase "github.com/kostinp/edu-platform-backend/internal/bookmark/usecase"
+	bookmark_http "github.com/kostinp/edu-platform-backend/internal/bookmark/transport/http"
+	bot_client "github.com/kostinp/edu-platform-backend/internal/bot/client"
+	bot_usecase "github.com/kostinp/edu-platform-backend/internal/bot/usecase"
+	bot_http "github.com/kostinp/edu-platform-backend/internal/bot/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/bot"
 	category_repository "github.com/kostinp/edu-platform-backend/internal/category/repository"
 	category_usecase "github.com/kostinp/edu-platform-backend/internal/category/usecase"
 	category_http "github.com/kostinp/edu-platform-backend/internal/category/transport/http"
 	course_repository "github.com/kostinp/edu-platform-backend/internal/course/repository"
 	course_usecase "github.com/kostinp/edu-platform-backend/internal/course/usecase"
 	course_http "github.com/kostinp/edu-platform-backend/internal/course/transport/http"
+	discussion_repository "github.com/kostinp/edu-platform-backend/internal/discussion/repository"
+	discussion_usecase "github.com/kostinp/edu-platform-backend/internal/discussion/usecase"
+	discussion_http "github.com/kostinp/edu-platform-backend/internal/discussion/transport/http"
 	lesson_repository "github.com/kostinp/edu-platform-backend/internal/lesson/repository"
 	lesson_usecase "github.com/kostinp/edu-platform-backend/internal/lesson/usecase"
 	lesson_http "github.com/kostinp/edu-platform-backend/internal/lesson/transport/http"
 	module_repository "github.com/kostinp/edu-platform-backend/internal/module/repository"
 	module_usecase "github.com/kostinp/edu-platform-backend/internal/module/usecase"
 	module_http "github.com/kostinp/edu-platform-backend/internal/module/transport/http"
+	progress_repository "github.com/kostinp/edu-platform-backend/internal/progress/repository"
+	progress_usecase "github.com/kostinp/edu-platform-backend/internal/progress/usecase"
+	progress_http "github.com/kostinp/edu-platform-backend/internal/progress/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/notification"
+	notification_repository "github.com/kostinp/edu-platform-backend/internal/notification/repository"
+	notification_usecase "github.com/kostinp/edu-platform-backend/internal/notification/usecase"
+	notification_http "github.com/kostinp/edu-platform-backend/internal/notification/transport/http"
+	note_repository "github.com/kostinp/edu-platform-backend/internal/note/repository"
+	note_usecase "github.com/kostinp/edu-platform-backend/internal/note/usecase"
+	note_http "github.com/kostinp/edu-platform-backend/internal/note/transport/http"
+	review_repository "github.com/kostinp/edu-platform-backend/internal/review/repository"
+	review_usecase "github.com/kostinp/edu-platform-backend/internal/review/usecase"
+	review_http "github.com/kostinp/edu-platform-backend/internal/review/transport/http"
 	tag_repository "github.com/kostinp/edu-platform-backend/internal/tag/repository"
 	tag_usecase "github.com/kostinp/edu-platform-backend/internal/tag/usecase"
 	tag_http "github.com/kostinp/edu-platform-backend/internal/tag/transport/http"
 	search_repository "github.com/kostinp/edu-platform-backend/internal/search/repository"
 	search_usecase "github.com/kostinp/edu-platform-backend/internal/search/usecase"
 	search_http "github.com/kostinp/edu-platform-backend/internal/search/transport/http"
+	organization_repository "github.com/kostinp/edu-platform-backend/internal/organization/repository"
+	organization_usecase "github.com/kostinp/edu-platform-backend/internal/organization/usecase"
+	organization_http "github.com/kostinp/edu-platform-backend/internal/organization/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/group"
+	group_repository "github.com/kostinp/edu-platform-backend/internal/group/repository"
+	group_usecase "github.com/kostinp/edu-platform-backend/internal/group/usecase"
+	group_http "github.com/kostinp/edu-platform-backend/internal/group/transport/http"
 	"github.com/labstack/echo/v4"
 )
 
@@ -45,7 +75,9 @@ func InitializeServer(cfg *config.Config, abacEngine *abac.Engine) (*echo.Echo,
 	pool := db.ConnectPostgres(cfg)
 	postgresUserRepository := repository.NewPostgresUserRepository(pool)
 	postgresSessionRepository := repository.NewPostgresSessionRepository(pool)
-	sessionUsecaseImpl := usecase.NewSessionUsecase(postgresSessionRepository)
+	revocationCache := user.ProvideSessionRevocationCache(cfg)
+	cachedSessionRepository := repository.NewCachedSessionRepository(postgresSessionRepository, revocationCache)
+	sessionUsecaseImpl := usecase.NewSessionUsecase(cachedSessionRepository)
 	userService := usecase.NewUserService(postgresUserRepository, sessionUsecaseImpl)
 	userHandler := transport.NewUserHandler(userService)
 	conn := user.ProvideClickHouseConn(cfg)
@@ -54,9 +86,58 @@ func InitializeServer(cfg *config.Config, abacEngine *abac.Engine) (*echo.Echo,
 	visitorEventUsecase := usecase.NewVisitorEventUsecase(visitorEventRepoWithFallback)
 	visitorEventHandler := transport.NewVisitorEventHandler(visitorEventUsecase)
 	botToken := user.ProvideBotToken(cfg)
-	jwtSecret := user.ProvideJwtSecret(cfg)
-	telegramAuthHandler := transport.NewTelegramAuthHandler(userService, botToken, jwtSecret)
+	keySet, err := user.ProvideJWTKeySet(cfg)
+	if err != nil {
+		return nil, err
+	}
+	replayCache := user.ProvideTelegramReplayCache(cfg)
+	loginVerifier := user.ProvideTelegramLoginVerifier(cfg, botToken, replayCache)
+	tokenTTL := user.ProvideTokenTTL(cfg)
+	locator, err := user.ProvideGeoLocator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	postgresLoginRiskRepository := repository.NewPostgresLoginRiskRepository(pool)
+	mailerMailer := user.ProvideMailer(cfg)
+	authLinkBaseURL := user.ProvideAuthLinkBaseURL(cfg)
+	loginNotifier := user.ProvideLoginNotifier(mailerMailer, botToken, authLinkBaseURL)
+	loginRiskPolicy := user.ProvideLoginRiskPolicy(cfg)
+	postgresMFARepository := repository.NewPostgresMFARepository(pool)
+	mfaIssuer := user.ProvideMFAIssuer(cfg)
+	mfaService := usecase.NewMFAService(postgresMFARepository, cachedSessionRepository, postgresUserRepository, mfaIssuer)
+	loginRiskService := usecase.NewLoginRiskService(postgresLoginRiskRepository, postgresUserRepository, loginNotifier, loginRiskPolicy, mfaService)
+	tokenService := usecase.NewTokenService(cachedSessionRepository, sessionUsecaseImpl, keySet, tokenTTL, locator, loginRiskService, postgresUserRepository)
+	tokenHandler := transport.NewTokenHandler(tokenService, userService)
+	jwksHandler := transport.NewJWKSHandler(keySet)
+	mfaHandler := transport.NewMFAHandler(mfaService)
+	postgresAPITokenRepository := repository.NewPostgresAPITokenRepository(pool)
+	apiTokenService := usecase.NewAPITokenService(postgresAPITokenRepository)
+	apiTokenHandler := transport.NewAPITokenHandler(apiTokenService)
+	postgresMergeRepository := repository.NewPostgresMergeRepository(pool)
+	mergeService := usecase.NewMergeService(postgresMergeRepository, sessionUsecaseImpl)
+	telegramAuthHandler := transport.NewTelegramAuthHandler(userService, loginVerifier, mergeService, tokenService, loginRiskService)
+	mergeHandler := transport.NewMergeHandler(mergeService, tokenService)
 	sessionHandler := transport.NewSessionHandler(sessionUsecaseImpl)
+	postgresAuthTokenRepository := repository.NewPostgresAuthTokenRepository(pool)
+	emailAuthLimits := user.ProvideEmailAuthLimits(cfg)
+	emailAuthService := usecase.NewEmailAuthService(postgresUserRepository, postgresAuthTokenRepository, sessionUsecaseImpl, mailerMailer, authLinkBaseURL, emailAuthLimits)
+	emailAuthHandler := transport.NewEmailAuthHandler(emailAuthService, tokenService, loginRiskService)
+	postgresIdentityRepository := repository.NewPostgresIdentityRepository(pool)
+	mockProvider := user.ProvideOIDCMockProvider(cfg)
+	oidcClients := user.ProvideOIDCClients(cfg, mockProvider)
+	stateStore := user.ProvideOIDCStateStore(cfg)
+	oidcService := usecase.NewOIDCService(oidcClients, stateStore, postgresIdentityRepository, postgresUserRepository)
+	oidcHandler := transport.NewOIDCHandler(oidcService, tokenService)
+	postgresAccountRepository := repository.NewPostgresAccountRepository(pool)
+	accountService := usecase.NewAccountService(postgresAccountRepository, postgresUserRepository, visitorEventRepoWithFallback, sessionUsecaseImpl, mfaService, emailAuthService)
+	accountHandler := transport.NewAccountHandler(accountService)
+	postgresAdminUserRepository := repository.NewPostgresAdminUserRepository(pool, revocationCache)
+	adminUserService := usecase.NewAdminUserService(postgresAdminUserRepository, postgresUserRepository, tokenService)
+	adminUserHandler := transport.NewAdminUserHandler(adminUserService)
+	postgresTeacherApplicationRepository := repository.NewPostgresTeacherApplicationRepository(pool)
+	teacherApplicationNotifier := user.ProvideTeacherApplicationNotifier(mailerMailer, botToken, authLinkBaseURL)
+	teacherApplicationService := usecase.NewTeacherApplicationService(postgresTeacherApplicationRepository, postgresUserRepository, teacherApplicationNotifier)
+	teacherApplicationHandler := transport.NewTeacherApplicationHandler(teacherApplicationService)
 	analyticsRepo := clickHouseVisitorEventRepo
 	if !cfg.Analytics.Enabled {
 		analyticsRepo = nil
@@ -66,6 +147,17 @@ func InitializeServer(cfg *config.Config, abacEngine *abac.Engine) (*echo.Echo,
 	postgresCourseRepository := course_repository.NewPostgresCourseRepository(pool)
 	courseUsecase := course_usecase.NewCourseUsecase(postgresCourseRepository)
 	courseHandler := course_http.NewCourseHandler(courseUsecase)
+	postgresEnrollmentRepository := course_repository.NewPostgresEnrollmentRepository(pool)
+	enrollmentUsecase := course_usecase.NewEnrollmentUsecase(postgresEnrollmentRepository, postgresCourseRepository)
+	enrollmentHandler := course_http.NewEnrollmentHandler(enrollmentUsecase)
+	// Notification
+	telegramAPI := bot_client.NewTelegramAPI(botToken)
+	siteURL := bot.ProvideSiteURL(cfg)
+	postgresNotificationRepository := notification_repository.NewPostgresNotificationRepository(pool)
+	rateLimiter := notification.ProvideRateLimiter(cfg)
+	templates := notification_usecase.NewTemplates()
+	notificationUsecase := notification_usecase.NewNotificationUsecase(postgresNotificationRepository, telegramAPI, rateLimiter, templates, siteURL)
+	notificationHandler := notification_http.NewNotificationHandler(notificationUsecase)
 	// Module
 	postgresModuleRepository := module_repository.NewPostgresModuleRepository(pool)
 	moduleUsecase := module_usecase.NewModuleUsecase(postgresModuleRepository)
@@ -93,7 +185,41 @@ func InitializeServer(cfg *config.Config, abacEngine *abac.Engine) (*echo.Echo,
 	postgresSearchRepository := search_repository.NewPostgresSearchRepository(pool)
 	searchUsecase := search_usecase.NewSearchUsecase(postgresSearchRepository)
 	searchHandler := search_http.NewSearchHandler(searchUsecase)
-	echoEcho, err := newEchoServer(cfg, userHandler, visitorEventHandler, telegramAuthHandler, sessionHandler, analyticsHandler, sessionUsecaseImpl, userService, abacEngine, courseHandler, moduleHandler, lessonHandler, categoryHandler, tagHandler, categoryNavigationHandler, searchHandler)
+	// Review
+	postgresReviewRepository := review_repository.NewPostgresReviewRepository(pool)
+	reviewUsecase := review_usecase.NewReviewUsecase(postgresReviewRepository, enrollmentUsecase)
+	reviewHandler := review_http.NewReviewHandler(reviewUsecase)
+	// Discussion
+	postgresCommentRepository := discussion_repository.NewPostgresCommentRepository(pool)
+	commentUsecase := discussion_usecase.NewCommentUsecase(postgresCommentRepository, lessonUsecase)
+	commentHandler := discussion_http.NewCommentHandler(commentUsecase)
+	// Note
+	postgresNoteRepository := note_repository.NewPostgresNoteRepository(pool)
+	noteUsecase := note_usecase.NewNoteUsecase(postgresNoteRepository, lessonUsecase, courseUsecase)
+	noteHandler := note_http.NewNoteHandler(noteUsecase)
+	// Bookmark
+	postgresBookmarkRepository := bookmark_repository.NewPostgresBookmarkRepository(pool)
+	bookmarkUsecase := bookmark_usecase.NewBookmarkUsecase(postgresBookmarkRepository, courseUsecase, moduleUsecase, lessonUsecase)
+	bookmarkHandler := bookmark_http.NewBookmarkHandler(bookmarkUsecase)
+	// Progress
+	postgresProgressRepository := progress_repository.NewPostgresProgressRepository(pool)
+	progressUsecase := progress_usecase.NewProgressUsecase(postgresProgressRepository)
+	progressHandler := progress_http.NewProgressHandler(progressUsecase)
+	// Organization
+	postgresOrganizationRepository := organization_repository.NewPostgresOrganizationRepository(pool)
+	organizationUsecase := organization_usecase.NewOrganizationUsecase(postgresOrganizationRepository)
+	organizationHandler := organization_http.NewOrganizationHandler(organizationUsecase)
+	// Group
+	postgresGroupRepository := group_repository.NewPostgresGroupRepository(pool)
+	joinLinkBaseURL := group.ProvideJoinLinkBaseURL(cfg)
+	groupUsecase := group_usecase.NewGroupUsecase(postgresGroupRepository, joinLinkBaseURL)
+	groupHandler := group_http.NewGroupHandler(groupUsecase)
+	// Bot
+	commands := bot_usecase.NewCommands(enrollmentUsecase, courseUsecase, progressUsecase, siteURL)
+	dispatcher := bot_usecase.NewDispatcher(telegramAPI, userService, commands)
+	webhookSecret := bot.ProvideWebhookSecret(cfg)
+	webhookHandler := bot_http.NewWebhookHandler(dispatcher, webhookSecret)
+	echoEcho, err := newEchoServer(cfg, userHandler, visitorEventHandler, telegramAuthHandler, sessionHandler, analyticsHandler, sessionUsecaseImpl, userService, abacEngine, courseHandler, moduleHandler, lessonHandler, categoryHandler, tagHandler, categoryNavigationHandler, searchHandler, enrollmentHandler, reviewHandler, postgresReviewRepository, commentHandler, postgresCommentRepository, noteHandler, bookmarkHandler, progressHandler, webhookHandler, notificationHandler, emailAuthHandler, oidcHandler, mockProvider, mergeHandler, tokenService, tokenHandler, jwksHandler, mfaHandler, apiTokenService, apiTokenHandler, accountHandler, adminUserService, adminUserHandler, teacherApplicationHandler, organizationHandler, postgresCourseRepository, postgresCategoryRepository, postgresTagRepository, groupHandler, postgresGroupRepository, postgresModuleRepository, postgresLessonRepository, postgresNoteRepository)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +230,21 @@ func InitializeServer(cfg *config.Config, abacEngine *abac.Engine) (*echo.Echo,
 func InitializeSessionUsecase(cfg *config.Config) (usecase.SessionUsecase, error) {
 	pool := db.ConnectPostgres(cfg)
 	postgresSessionRepository := repository.NewPostgresSessionRepository(pool)
-	sessionUsecaseImpl := usecase.NewSessionUsecase(postgresSessionRepository)
+	revocationCache := user.ProvideSessionRevocationCache(cfg)
+	cachedSessionRepository := repository.NewCachedSessionRepository(postgresSessionRepository, revocationCache)
+	sessionUsecaseImpl := usecase.NewSessionUsecase(cachedSessionRepository)
 	return sessionUsecaseImpl, nil
+}
+
+// Для планировщика уведомлений
+func InitializeNotificationUsecase(cfg *config.Config) (notification_usecase.NotificationUsecase, error) {
+	pool := db.ConnectPostgres(cfg)
+	postgresNotificationRepository := notification_repository.NewPostgresNotificationRepository(pool)
+	botToken := user.ProvideBotToken(cfg)
+	telegramAPI := bot_client.NewTelegramAPI(botToken)
+	rateLimiter := notification.ProvideRateLimiter(cfg)
+	templates := notification_usecase.NewTemplates()
+	siteURL := bot.ProvideSiteURL(cfg)
+	notificationUsecase := notification_usecase.NewNotificationUsecase(postgresNotificationRepository, telegramAPI, rateLimiter, templates, siteURL)
+	return notificationUsecase, nil
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	courseEntity "github.com/kostinp/edu-platform-backend/internal/course/entity"
+	lessonEntity "github.com/kostinp/edu-platform-backend/internal/lesson/entity"
+	moduleEntity "github.com/kostinp/edu-platform-backend/internal/module/entity"
+)
+
+const (
+	TargetCourse = "course"
+	TargetModule = "module"
+	TargetLesson = "lesson"
+)
+
+// Folder — пользовательская папка закладок
+type Folder struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Ordinal   int       `json:"ordinal"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Bookmark — закладка на курс, модуль или урок
+type Bookmark struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	FolderID   *uuid.UUID `json:"folder_id,omitempty"`
+	TargetType string     `json:"target_type"`
+	TargetID   uuid.UUID  `json:"target_id"`
+	Ordinal    int        `json:"ordinal"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BookmarkFilter — выборка закладок: по папке, без папки или по типу ресурса
+type BookmarkFilter struct {
+	FolderID   *uuid.UUID
+	Unfiled    bool
+	TargetType string
+}
+
+// ResolvedBookmark — закладка вместе с сущностью, на которую она указывает
+type ResolvedBookmark struct {
+	Bookmark
+	Course *courseEntity.Course `json:"course,omitempty"`
+	Module *moduleEntity.Module `json:"module,omitempty"`
+	Lesson *lessonEntity.Lesson `json:"lesson,omitempty"`
+}
@@ -0,0 +1,233 @@
+// internal/bookmark/repository/bookmark_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark/entity"
+)
+
+var (
+	ErrBookmarkNotFound = errors.New("bookmark not found")
+	ErrBookmarkExists   = errors.New("bookmark already exists")
+	ErrFolderNotFound   = errors.New("folder not found")
+	ErrFolderExists     = errors.New("folder with this name already exists")
+)
+
+// BookmarkRepository — все методы ограничены данными указанного пользователя
+type BookmarkRepository interface {
+	CreateFolder(ctx context.Context, folder *entity.Folder) error
+	RenameFolder(ctx context.Context, userID, id uuid.UUID, name string) error
+	DeleteFolder(ctx context.Context, userID, id uuid.UUID) error
+	GetFolder(ctx context.Context, userID, id uuid.UUID) (*entity.Folder, error)
+	ListFolders(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error)
+	ReorderFolders(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+
+	Create(ctx context.Context, bookmark *entity.Bookmark) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	Move(ctx context.Context, userID, id uuid.UUID, folderID *uuid.UUID) error
+	Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	List(ctx context.Context, userID uuid.UUID, filter entity.BookmarkFilter) ([]*entity.Bookmark, error)
+}
+
+type PostgresBookmarkRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresBookmarkRepository(db *pgxpool.Pool) *PostgresBookmarkRepository {
+	return &PostgresBookmarkRepository{db: db}
+}
+
+func (r *PostgresBookmarkRepository) CreateFolder(ctx context.Context, f *entity.Folder) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO bookmark_folders (id, user_id, name, ordinal, created_at, updated_at)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(ordinal) + 1, 0) FROM bookmark_folders WHERE user_id = $2), $4, $5)
+		RETURNING ordinal
+	`, f.ID, f.UserID, f.Name, f.CreatedAt, f.UpdatedAt).Scan(&f.Ordinal)
+	if isUniqueViolation(err) {
+		return ErrFolderExists
+	}
+	return err
+}
+
+func (r *PostgresBookmarkRepository) RenameFolder(ctx context.Context, userID, id uuid.UUID, name string) error {
+	cmdTag, err := r.db.Exec(ctx, `
+		UPDATE bookmark_folders SET name = $1, updated_at = NOW() WHERE id = $2 AND user_id = $3
+	`, name, id, userID)
+	if isUniqueViolation(err) {
+		return ErrFolderExists
+	}
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrFolderNotFound
+	}
+	return nil
+}
+
+// DeleteFolder удаляет папку; её закладки остаются без папки (ON DELETE SET NULL)
+func (r *PostgresBookmarkRepository) DeleteFolder(ctx context.Context, userID, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM bookmark_folders WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrFolderNotFound
+	}
+	return nil
+}
+
+func (r *PostgresBookmarkRepository) GetFolder(ctx context.Context, userID, id uuid.UUID) (*entity.Folder, error) {
+	f := &entity.Folder{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, user_id, name, ordinal, created_at, updated_at
+		FROM bookmark_folders WHERE id = $1 AND user_id = $2
+	`, id, userID).Scan(&f.ID, &f.UserID, &f.Name, &f.Ordinal, &f.CreatedAt, &f.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrFolderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *PostgresBookmarkRepository) ListFolders(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, name, ordinal, created_at, updated_at
+		FROM bookmark_folders WHERE user_id = $1
+		ORDER BY ordinal, created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := []*entity.Folder{}
+	for rows.Next() {
+		f := &entity.Folder{}
+		if err := rows.Scan(&f.ID, &f.UserID, &f.Name, &f.Ordinal, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			return nil, err
+		}
+		folders = append(folders, f)
+	}
+	return folders, rows.Err()
+}
+
+// ReorderFolders выставляет порядок папок по позиции в списке ids
+func (r *PostgresBookmarkRepository) ReorderFolders(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE bookmark_folders f
+		SET ordinal = o.pos - 1, updated_at = NOW()
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, pos)
+		WHERE f.id = o.id AND f.user_id = $1
+	`, userID, ids)
+	return err
+}
+
+// Create добавляет закладку в конец папки
+func (r *PostgresBookmarkRepository) Create(ctx context.Context, b *entity.Bookmark) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO bookmarks (id, user_id, folder_id, target_type, target_id, ordinal, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5,
+			(SELECT COALESCE(MAX(ordinal) + 1, 0) FROM bookmarks WHERE user_id = $2 AND folder_id IS NOT DISTINCT FROM $3),
+			$6, $7)
+		RETURNING ordinal
+	`, b.ID, b.UserID, b.FolderID, b.TargetType, b.TargetID, b.CreatedAt, b.UpdatedAt).Scan(&b.Ordinal)
+	if isUniqueViolation(err) {
+		return ErrBookmarkExists
+	}
+	return err
+}
+
+func (r *PostgresBookmarkRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM bookmarks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// Move переносит закладку в другую папку (nil — без папки) и ставит её в конец
+func (r *PostgresBookmarkRepository) Move(ctx context.Context, userID, id uuid.UUID, folderID *uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `
+		UPDATE bookmarks
+		SET folder_id = $1,
+			ordinal = (SELECT COALESCE(MAX(ordinal) + 1, 0) FROM bookmarks WHERE user_id = $3 AND folder_id IS NOT DISTINCT FROM $1),
+			updated_at = NOW()
+		WHERE id = $2 AND user_id = $3
+	`, folderID, id, userID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// Reorder выставляет порядок закладок по позиции в списке ids
+func (r *PostgresBookmarkRepository) Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE bookmarks b
+		SET ordinal = o.pos - 1, updated_at = NOW()
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, pos)
+		WHERE b.id = o.id AND b.user_id = $1
+	`, userID, ids)
+	return err
+}
+
+func (r *PostgresBookmarkRepository) List(ctx context.Context, userID uuid.UUID, filter entity.BookmarkFilter) ([]*entity.Bookmark, error) {
+	conditions := []string{"b.user_id = $1"}
+	args := []interface{}{userID}
+	switch {
+	case filter.FolderID != nil:
+		args = append(args, *filter.FolderID)
+		conditions = append(conditions, fmt.Sprintf("b.folder_id = $%d", len(args)))
+	case filter.Unfiled:
+		conditions = append(conditions, "b.folder_id IS NULL")
+	}
+	if filter.TargetType != "" {
+		args = append(args, filter.TargetType)
+		conditions = append(conditions, fmt.Sprintf("b.target_type = $%d", len(args)))
+	}
+
+	// Сначала закладки без папки, затем по порядку папок
+	rows, err := r.db.Query(ctx, `
+		SELECT b.id, b.user_id, b.folder_id, b.target_type, b.target_id, b.ordinal, b.created_at, b.updated_at
+		FROM bookmarks b
+		LEFT JOIN bookmark_folders f ON f.id = b.folder_id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY f.ordinal NULLS FIRST, b.ordinal, b.created_at
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookmarks := []*entity.Bookmark{}
+	for rows.Next() {
+		b := &entity.Bookmark{}
+		if err := rows.Scan(&b.ID, &b.UserID, &b.FolderID, &b.TargetType, &b.TargetID, &b.Ordinal, &b.CreatedAt, &b.UpdatedAt); err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	return bookmarks, rows.Err()
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
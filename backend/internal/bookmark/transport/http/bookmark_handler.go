@@ -0,0 +1,367 @@
+// internal/bookmark/transport/http/bookmark_handler.go
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark/entity"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+type BookmarkHandler struct {
+	usecase usecase.BookmarkUsecase
+}
+
+func NewBookmarkHandler(uc usecase.BookmarkUsecase) *BookmarkHandler {
+	return &BookmarkHandler{usecase: uc}
+}
+
+// CreateBookmarkRequest — добавление курса, модуля или урока в закладки
+type CreateBookmarkRequest struct {
+	TargetType string     `json:"target_type" example:"course"`
+	TargetID   uuid.UUID  `json:"target_id"`
+	FolderID   *uuid.UUID `json:"folder_id,omitempty"`
+}
+
+// MoveBookmarkRequest — перенос закладки в папку (null — без папки)
+type MoveBookmarkRequest struct {
+	FolderID *uuid.UUID `json:"folder_id"`
+}
+
+// FolderRequest — название папки
+type FolderRequest struct {
+	Name string `json:"name" example:"Посмотреть позже"`
+}
+
+// OrderRequest — новый порядок элементов
+type OrderRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// ListBookmarks godoc
+// @Summary Мои закладки
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Produce json
+// @Param folder_id query string false "ID папки или none для закладок без папки"
+// @Param target_type query string false "Тип ресурса (course, module, lesson)"
+// @Success 200 {array} entity.Bookmark
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /me/bookmarks [get]
+func (h *BookmarkHandler) List(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	bookmarks, err := h.usecase.List(c.Request().Context(), userID, filter)
+	if err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.JSON(http.StatusOK, bookmarks)
+}
+
+// ResolveBookmarks godoc
+// @Summary Мои закладки вместе с курсами, модулями и уроками
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Produce json
+// @Param folder_id query string false "ID папки или none для закладок без папки"
+// @Param target_type query string false "Тип ресурса (course, module, lesson)"
+// @Success 200 {array} entity.ResolvedBookmark
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /me/bookmarks/resolved [get]
+func (h *BookmarkHandler) Resolve(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	resolved, err := h.usecase.Resolve(c.Request().Context(), userID, middleware.CurrentOrgID(c), filter)
+	if err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.JSON(http.StatusOK, resolved)
+}
+
+// AddBookmark godoc
+// @Summary Добавить в закладки
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateBookmarkRequest true "Закладка"
+// @Success 201 {object} entity.Bookmark
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/bookmarks [post]
+func (h *BookmarkHandler) Add(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	req := new(CreateBookmarkRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	bookmark := &entity.Bookmark{TargetType: req.TargetType, TargetID: req.TargetID, FolderID: req.FolderID}
+	if err := h.usecase.Add(c.Request().Context(), bookmark, userID, middleware.CurrentOrgID(c)); err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.JSON(http.StatusCreated, bookmark)
+}
+
+// MoveBookmark godoc
+// @Summary Перенести закладку в папку
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "ID закладки"
+// @Param request body MoveBookmarkRequest true "Папка"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/bookmarks/{id} [put]
+func (h *BookmarkHandler) Move(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid bookmark id"})
+	}
+	req := new(MoveBookmarkRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := h.usecase.Move(c.Request().Context(), userID, id, req.FolderID); err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RemoveBookmark godoc
+// @Summary Удалить закладку
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Param id path string true "ID закладки"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/bookmarks/{id} [delete]
+func (h *BookmarkHandler) Remove(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid bookmark id"})
+	}
+	if err := h.usecase.Remove(c.Request().Context(), userID, id); err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ReorderBookmarks godoc
+// @Summary Изменить порядок закладок
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Accept json
+// @Param request body OrderRequest true "ID закладок в нужном порядке"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /me/bookmarks/order [put]
+func (h *BookmarkHandler) Reorder(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	req := new(OrderRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := h.usecase.Reorder(c.Request().Context(), userID, req.IDs); err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListFolders godoc
+// @Summary Мои папки закладок
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.Folder
+// @Failure 500 {object} map[string]string
+// @Router /me/bookmark-folders [get]
+func (h *BookmarkHandler) ListFolders(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	folders, err := h.usecase.ListFolders(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, folders)
+}
+
+// CreateFolder godoc
+// @Summary Создать папку закладок
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body FolderRequest true "Папка"
+// @Success 201 {object} entity.Folder
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/bookmark-folders [post]
+func (h *BookmarkHandler) CreateFolder(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	req := new(FolderRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	folder, err := h.usecase.CreateFolder(c.Request().Context(), userID, req.Name)
+	if err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.JSON(http.StatusCreated, folder)
+}
+
+// RenameFolder godoc
+// @Summary Переименовать папку закладок
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "ID папки"
+// @Param request body FolderRequest true "Новое название"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/bookmark-folders/{id} [put]
+func (h *BookmarkHandler) RenameFolder(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid folder id"})
+	}
+	req := new(FolderRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := h.usecase.RenameFolder(c.Request().Context(), userID, id, req.Name); err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteFolder godoc
+// @Summary Удалить папку (закладки остаются без папки)
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Param id path string true "ID папки"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/bookmark-folders/{id} [delete]
+func (h *BookmarkHandler) DeleteFolder(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid folder id"})
+	}
+	if err := h.usecase.DeleteFolder(c.Request().Context(), userID, id); err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ReorderFolders godoc
+// @Summary Изменить порядок папок
+// @Tags Bookmarks
+// @Security BearerAuth
+// @Accept json
+// @Param request body OrderRequest true "ID папок в нужном порядке"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /me/bookmark-folders/order [put]
+func (h *BookmarkHandler) ReorderFolders(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	req := new(OrderRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := h.usecase.ReorderFolders(c.Request().Context(), userID, req.IDs); err != nil {
+		return bookmarkError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func parseFilter(c echo.Context) (entity.BookmarkFilter, error) {
+	filter := entity.BookmarkFilter{TargetType: c.QueryParam("target_type")}
+	switch folder := c.QueryParam("folder_id"); folder {
+	case "":
+	case "none":
+		filter.Unfiled = true
+	default:
+		id, err := uuid.Parse(folder)
+		if err != nil {
+			return filter, errors.New("invalid folder id")
+		}
+		filter.FolderID = &id
+	}
+	return filter, nil
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func bookmarkError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrBookmarkNotFound), errors.Is(err, usecase.ErrFolderNotFound),
+		errors.Is(err, usecase.ErrTargetNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidTarget), errors.Is(err, usecase.ErrInvalidName),
+		errors.Is(err, usecase.ErrInvalidOrder):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrBookmarkExists), errors.Is(err, usecase.ErrFolderExists):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
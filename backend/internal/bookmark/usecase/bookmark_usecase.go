@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark/entity"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark/repository"
+	courseEntity "github.com/kostinp/edu-platform-backend/internal/course/entity"
+	lessonEntity "github.com/kostinp/edu-platform-backend/internal/lesson/entity"
+	moduleEntity "github.com/kostinp/edu-platform-backend/internal/module/entity"
+)
+
+const (
+	MaxFolderNameLength = 100
+	MaxReorderItems     = 500
+)
+
+var (
+	ErrBookmarkNotFound = repository.ErrBookmarkNotFound
+	ErrBookmarkExists   = repository.ErrBookmarkExists
+	ErrFolderNotFound   = repository.ErrFolderNotFound
+	ErrFolderExists     = repository.ErrFolderExists
+	ErrInvalidTarget    = errors.New("target_type must be course, module or lesson")
+	ErrTargetNotFound   = errors.New("bookmark target not found")
+	ErrInvalidName      = errors.New("folder name must be 1-100 characters")
+	ErrInvalidOrder     = errors.New("order must contain 1-500 unique ids")
+)
+
+// CourseResolver, ModuleResolver, LessonResolver — пакетная загрузка сущностей по ID;
+// контент чужих организаций не находится
+type CourseResolver interface {
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*courseEntity.Course, error)
+}
+
+type ModuleResolver interface {
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*moduleEntity.Module, error)
+}
+
+type LessonResolver interface {
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*lessonEntity.Lesson, error)
+}
+
+type BookmarkUsecase interface {
+	CreateFolder(ctx context.Context, userID uuid.UUID, name string) (*entity.Folder, error)
+	RenameFolder(ctx context.Context, userID, id uuid.UUID, name string) error
+	DeleteFolder(ctx context.Context, userID, id uuid.UUID) error
+	ListFolders(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error)
+	ReorderFolders(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+
+	// Add и Resolve видят общий каталог и контент организации viewerOrg
+	Add(ctx context.Context, bookmark *entity.Bookmark, userID uuid.UUID, viewerOrg *uuid.UUID) error
+	Remove(ctx context.Context, userID, id uuid.UUID) error
+	Move(ctx context.Context, userID, id uuid.UUID, folderID *uuid.UUID) error
+	Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+	List(ctx context.Context, userID uuid.UUID, filter entity.BookmarkFilter) ([]*entity.Bookmark, error)
+	Resolve(ctx context.Context, userID uuid.UUID, viewerOrg *uuid.UUID, filter entity.BookmarkFilter) ([]*entity.ResolvedBookmark, error)
+}
+
+type bookmarkUsecase struct {
+	repo    repository.BookmarkRepository
+	courses CourseResolver
+	modules ModuleResolver
+	lessons LessonResolver
+}
+
+func NewBookmarkUsecase(repo repository.BookmarkRepository, courses CourseResolver, modules ModuleResolver, lessons LessonResolver) BookmarkUsecase {
+	return &bookmarkUsecase{repo: repo, courses: courses, modules: modules, lessons: lessons}
+}
+
+func (u *bookmarkUsecase) CreateFolder(ctx context.Context, userID uuid.UUID, name string) (*entity.Folder, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	folder := &entity.Folder{ID: uuid.New(), UserID: userID, Name: name, CreatedAt: now, UpdatedAt: now}
+	if err := u.repo.CreateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (u *bookmarkUsecase) RenameFolder(ctx context.Context, userID, id uuid.UUID, name string) error {
+	name, err := normalizeName(name)
+	if err != nil {
+		return err
+	}
+	return u.repo.RenameFolder(ctx, userID, id, name)
+}
+
+func (u *bookmarkUsecase) DeleteFolder(ctx context.Context, userID, id uuid.UUID) error {
+	return u.repo.DeleteFolder(ctx, userID, id)
+}
+
+func (u *bookmarkUsecase) ListFolders(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error) {
+	return u.repo.ListFolders(ctx, userID)
+}
+
+func (u *bookmarkUsecase) ReorderFolders(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	if err := validateOrder(ids); err != nil {
+		return err
+	}
+	return u.repo.ReorderFolders(ctx, userID, ids)
+}
+
+func (u *bookmarkUsecase) Add(ctx context.Context, b *entity.Bookmark, userID uuid.UUID, viewerOrg *uuid.UUID) error {
+	exists, err := u.targetExists(ctx, b.TargetType, b.TargetID, viewerOrg)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTargetNotFound
+	}
+	if b.FolderID != nil {
+		if _, err := u.repo.GetFolder(ctx, userID, *b.FolderID); err != nil {
+			return err
+		}
+	}
+	now := time.Now().UTC()
+	b.ID = uuid.New()
+	b.UserID = userID
+	b.CreatedAt = now
+	b.UpdatedAt = now
+	return u.repo.Create(ctx, b)
+}
+
+func (u *bookmarkUsecase) Remove(ctx context.Context, userID, id uuid.UUID) error {
+	return u.repo.Delete(ctx, userID, id)
+}
+
+func (u *bookmarkUsecase) Move(ctx context.Context, userID, id uuid.UUID, folderID *uuid.UUID) error {
+	if folderID != nil {
+		if _, err := u.repo.GetFolder(ctx, userID, *folderID); err != nil {
+			return err
+		}
+	}
+	return u.repo.Move(ctx, userID, id, folderID)
+}
+
+func (u *bookmarkUsecase) Reorder(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	if err := validateOrder(ids); err != nil {
+		return err
+	}
+	return u.repo.Reorder(ctx, userID, ids)
+}
+
+func (u *bookmarkUsecase) List(ctx context.Context, userID uuid.UUID, filter entity.BookmarkFilter) ([]*entity.Bookmark, error) {
+	if filter.TargetType != "" && !validTarget(filter.TargetType) {
+		return nil, ErrInvalidTarget
+	}
+	return u.repo.List(ctx, userID, filter)
+}
+
+// Resolve подгружает сущности закладок — по одному запросу на каждый тип.
+// Закладки на удалённые ресурсы и контент чужих организаций в ответ не попадают
+func (u *bookmarkUsecase) Resolve(ctx context.Context, userID uuid.UUID, viewerOrg *uuid.UUID, filter entity.BookmarkFilter) ([]*entity.ResolvedBookmark, error) {
+	bookmarks, err := u.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string][]uuid.UUID{}
+	for _, b := range bookmarks {
+		ids[b.TargetType] = append(ids[b.TargetType], b.TargetID)
+	}
+
+	courses := map[uuid.UUID]*courseEntity.Course{}
+	if len(ids[entity.TargetCourse]) > 0 {
+		items, err := u.courses.GetByIDs(ctx, ids[entity.TargetCourse], viewerOrg)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range items {
+			courses[c.ID] = c
+		}
+	}
+	modules := map[uuid.UUID]*moduleEntity.Module{}
+	if len(ids[entity.TargetModule]) > 0 {
+		items, err := u.modules.GetByIDs(ctx, ids[entity.TargetModule], viewerOrg)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range items {
+			modules[m.ID] = m
+		}
+	}
+	lessons := map[uuid.UUID]*lessonEntity.Lesson{}
+	if len(ids[entity.TargetLesson]) > 0 {
+		items, err := u.lessons.GetByIDs(ctx, ids[entity.TargetLesson], viewerOrg)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range items {
+			lessons[l.ID] = l
+		}
+	}
+
+	resolved := make([]*entity.ResolvedBookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		rb := &entity.ResolvedBookmark{Bookmark: *b}
+		switch b.TargetType {
+		case entity.TargetCourse:
+			rb.Course = courses[b.TargetID]
+		case entity.TargetModule:
+			rb.Module = modules[b.TargetID]
+		case entity.TargetLesson:
+			rb.Lesson = lessons[b.TargetID]
+		}
+		if rb.Course == nil && rb.Module == nil && rb.Lesson == nil {
+			continue
+		}
+		resolved = append(resolved, rb)
+	}
+	return resolved, nil
+}
+
+func (u *bookmarkUsecase) targetExists(ctx context.Context, targetType string, id uuid.UUID, viewerOrg *uuid.UUID) (bool, error) {
+	ids := []uuid.UUID{id}
+	switch targetType {
+	case entity.TargetCourse:
+		items, err := u.courses.GetByIDs(ctx, ids, viewerOrg)
+		return len(items) > 0, err
+	case entity.TargetModule:
+		items, err := u.modules.GetByIDs(ctx, ids, viewerOrg)
+		return len(items) > 0, err
+	case entity.TargetLesson:
+		items, err := u.lessons.GetByIDs(ctx, ids, viewerOrg)
+		return len(items) > 0, err
+	}
+	return false, ErrInvalidTarget
+}
+
+func validTarget(targetType string) bool {
+	return targetType == entity.TargetCourse || targetType == entity.TargetModule || targetType == entity.TargetLesson
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxFolderNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+func validateOrder(ids []uuid.UUID) error {
+	if len(ids) == 0 || len(ids) > MaxReorderItems {
+		return ErrInvalidOrder
+	}
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return ErrInvalidOrder
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// internal/bookmark/wire.go
+package bookmark
+
+import (
+	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/bookmark/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/bookmark/usecase"
+	courseUsecase "github.com/kostinp/edu-platform-backend/internal/course/usecase"
+	lessonUsecase "github.com/kostinp/edu-platform-backend/internal/lesson/usecase"
+	moduleUsecase "github.com/kostinp/edu-platform-backend/internal/module/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+)
+
+var BookmarkSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresBookmarkRepository,
+	wire.Bind(new(repository.BookmarkRepository), new(*repository.PostgresBookmarkRepository)),
+	wire.Bind(new(usecase.CourseResolver), new(courseUsecase.CourseUsecase)),
+	wire.Bind(new(usecase.ModuleResolver), new(moduleUsecase.ModuleUsecase)),
+	wire.Bind(new(usecase.LessonResolver), new(lessonUsecase.LessonUsecase)),
+	usecase.NewBookmarkUsecase,
+	http.NewBookmarkHandler,
+)
@@ -0,0 +1,46 @@
+// internal/bot/client/telegram_api.go
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kostinp/edu-platform-backend/internal/shared/config"
+)
+
+// TelegramAPI — реализация usecase.BotAPI поверх telegram-bot-api.
+// В отличие от telegram.New не ходит в getMe при создании,
+// поэтому сервер стартует и без доступа к Telegram
+type TelegramAPI struct {
+	bot *tgbotapi.BotAPI
+}
+
+func NewTelegramAPI(token config.BotToken) *TelegramAPI {
+	bot := &tgbotapi.BotAPI{
+		Token:  string(token),
+		Client: &http.Client{Timeout: 10 * time.Second},
+		Buffer: 100,
+	}
+	bot.SetAPIEndpoint(tgbotapi.APIEndpoint)
+	return &TelegramAPI{bot: bot}
+}
+
+func (a *TelegramAPI) SendMessage(_ context.Context, chatID int64, text string) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.DisableWebPagePreview = true
+	_, err := a.bot.Send(msg)
+	return err
+}
+
+// SetWebhook регистрирует адрес webhook в Telegram
+func (a *TelegramAPI) SetWebhook(url string) error {
+	wh, err := tgbotapi.NewWebhook(url)
+	if err != nil {
+		return err
+	}
+	_, err = a.bot.Request(wh)
+	return err
+}
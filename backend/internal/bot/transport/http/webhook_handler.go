@@ -0,0 +1,48 @@
+// internal/bot/transport/http/webhook_handler.go
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/kostinp/edu-platform-backend/internal/bot/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookSecret — секретный сегмент пути webhook; пустой отключает бота
+type WebhookSecret string
+
+type WebhookHandler struct {
+	dispatcher usecase.Dispatcher
+	secret     string
+}
+
+func NewWebhookHandler(dispatcher usecase.Dispatcher, secret WebhookSecret) *WebhookHandler {
+	return &WebhookHandler{dispatcher: dispatcher, secret: string(secret)}
+}
+
+// Handle godoc
+// @Summary Webhook Telegram-бота
+// @Description Принимает обновления от Telegram. Всегда отвечает 200, чтобы Telegram не повторял доставку
+// @Tags Telegram
+// @Accept json
+// @Param secret path string true "Секрет webhook"
+// @Success 200
+// @Failure 404
+// @Router /telegram/webhook/{secret} [post]
+func (h *WebhookHandler) Handle(c echo.Context) error {
+	if h.secret == "" || subtle.ConstantTimeCompare([]byte(c.Param("secret")), []byte(h.secret)) != 1 {
+		return c.NoContent(http.StatusNotFound)
+	}
+	var update tgbotapi.Update
+	if err := c.Bind(&update); err != nil {
+		logger.Error("Некорректное обновление от Telegram", err)
+		return c.NoContent(http.StatusOK)
+	}
+	if err := h.dispatcher.Dispatch(c.Request().Context(), update); err != nil {
+		logger.Error("Ошибка обработки команды бота", err)
+	}
+	return c.NoContent(http.StatusOK)
+}
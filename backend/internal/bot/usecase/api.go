@@ -0,0 +1,9 @@
+package usecase
+
+import "context"
+
+// BotAPI — исходящие вызовы Telegram Bot API. Команды зависят только от этого
+// интерфейса, поэтому в тестах вместо Telegram подставляется фейк
+type BotAPI interface {
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
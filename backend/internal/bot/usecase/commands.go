@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/google/uuid"
+	courseEntity "github.com/kostinp/edu-platform-backend/internal/course/entity"
+	progressEntity "github.com/kostinp/edu-platform-backend/internal/progress/entity"
+	progressUsecase "github.com/kostinp/edu-platform-backend/internal/progress/usecase"
+)
+
+// maxListed — сколько курсов показывать в одном сообщении
+const maxListed = 10
+
+type EnrollmentLister interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*courseEntity.Enrollment, error)
+}
+
+type CourseResolver interface {
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*courseEntity.Course, error)
+}
+
+type ProgressReader interface {
+	ListCourseProgress(ctx context.Context, userID uuid.UUID) ([]*progressEntity.CourseProgress, error)
+	Continue(ctx context.Context, userID uuid.UUID) (*progressEntity.ContinuePoint, error)
+	Streak(ctx context.Context, userID uuid.UUID) (*progressEntity.Streak, error)
+}
+
+// SiteURL — адрес сайта для ссылок в ответах бота
+type SiteURL string
+
+// Commands — обработчики команд бота поверх существующих usecase'ов
+type Commands struct {
+	enrollments EnrollmentLister
+	courses     CourseResolver
+	progress    ProgressReader
+	siteURL     string
+}
+
+func NewCommands(enrollments EnrollmentLister, courses CourseResolver, progress ProgressReader, siteURL SiteURL) *Commands {
+	return &Commands{
+		enrollments: enrollments,
+		courses:     courses,
+		progress:    progress,
+		siteURL:     strings.TrimRight(string(siteURL), "/"),
+	}
+}
+
+func (c *Commands) Help(_ context.Context, _ *Command) (string, error) {
+	return "Я помогу не забрасывать учёбу.\n\n" +
+		"/courses — мои курсы\n" +
+		"/continue — продолжить с того места, где остановились\n" +
+		"/progress — прогресс по курсам\n" +
+		"/streak — серия дней с занятиями", nil
+}
+
+func (c *Commands) Courses(ctx context.Context, cmd *Command) (string, error) {
+	enrollments, err := c.enrollments.ListByUser(ctx, cmd.User.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(enrollments) == 0 {
+		return "Вы пока не записаны ни на один курс. Каталог: " + c.link("/courses"), nil
+	}
+	ids := make([]uuid.UUID, 0, len(enrollments))
+	for _, e := range enrollments {
+		ids = append(ids, e.CourseID)
+	}
+	courses, err := c.courses.GetByIDs(ctx, ids, cmd.User.OrgID)
+	if err != nil {
+		return "", err
+	}
+	byID := make(map[uuid.UUID]*courseEntity.Course, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+	}
+
+	var b strings.Builder
+	b.WriteString("<b>Мои курсы</b>\n")
+	listed := 0
+	// Порядок — как у записей: сначала новые
+	for _, e := range enrollments {
+		course, ok := byID[e.CourseID]
+		if !ok {
+			continue
+		}
+		if listed == maxListed {
+			fmt.Fprintf(&b, "\n…и ещё %d", len(courses)-listed)
+			break
+		}
+		fmt.Fprintf(&b, "\n• <a href=\"%s\">%s</a>", c.link("/courses/"+course.Slug), html.EscapeString(course.Title))
+		listed++
+	}
+	return b.String(), nil
+}
+
+func (c *Commands) Continue(ctx context.Context, cmd *Command) (string, error) {
+	point, err := c.progress.Continue(ctx, cmd.User.ID)
+	if errors.Is(err, progressUsecase.ErrNothingToResume) {
+		return "Пока нечего продолжать — откройте любой урок на сайте. Мои курсы — /courses", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Продолжим «%s»\n%s → <a href=\"%s\">%s</a>",
+		html.EscapeString(point.CourseTitle),
+		html.EscapeString(point.ModuleTitle),
+		c.link("/lessons/"+point.LessonID.String()),
+		html.EscapeString(point.LessonTitle),
+	), nil
+}
+
+func (c *Commands) Progress(ctx context.Context, cmd *Command) (string, error) {
+	items, err := c.progress.ListCourseProgress(ctx, cmd.User.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "Вы пока не записаны ни на один курс.", nil
+	}
+	var b strings.Builder
+	b.WriteString("<b>Прогресс</b>\n")
+	for i, item := range items {
+		if i == maxListed {
+			fmt.Fprintf(&b, "\n…и ещё %d", len(items)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n%s %d%% — %s (%d/%d)",
+			progressBar(item.Percent), item.Percent, html.EscapeString(item.CourseTitle),
+			item.CompletedLessons, item.TotalLessons)
+	}
+	return b.String(), nil
+}
+
+func (c *Commands) Streak(ctx context.Context, cmd *Command) (string, error) {
+	streak, err := c.progress.Streak(ctx, cmd.User.ID)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case streak.Current == 0:
+		return "Серии пока нет. Пройдите урок сегодня, чтобы начать! /continue", nil
+	case !streak.ActiveToday:
+		return fmt.Sprintf("🔥 Серия: %s. Позанимайтесь сегодня, чтобы её не потерять! /continue\nРекорд: %s",
+			pluralDays(streak.Current), pluralDays(streak.Longest)), nil
+	}
+	return fmt.Sprintf("🔥 Серия: %s\nРекорд: %s", pluralDays(streak.Current), pluralDays(streak.Longest)), nil
+}
+
+func (c *Commands) link(path string) string {
+	return c.siteURL + path
+}
+
+func progressBar(percent int) string {
+	filled := percent / 10
+	return strings.Repeat("▓", filled) + strings.Repeat("░", 10-filled)
+}
+
+// pluralDays — «1 день», «3 дня», «5 дней»
+func pluralDays(n int) string {
+	word := "дней"
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		word = "день"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		word = "дня"
+	}
+	return fmt.Sprintf("%d %s", n, word)
+}
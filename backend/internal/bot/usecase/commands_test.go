@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	progressEntity "github.com/kostinp/edu-platform-backend/internal/progress/entity"
+	progressUsecase "github.com/kostinp/edu-platform-backend/internal/progress/usecase"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+const testSiteURL = "https://edu.example.com"
+
+func TestCommands_Courses(t *testing.T) {
+	tests := []struct {
+		name        string
+		enrolled    int
+		wantCourses int
+		wantTail    string
+	}{
+		{"no enrollments", 0, 0, "Вы пока не записаны ни на один курс"},
+		{"fits in one message", maxListed, maxListed, ""},
+		{"truncated", maxListed + 3, maxListed, "…и ещё 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &userEntity.User{ID: uuid.New()}
+			catalog := newFakeCatalog(user.ID, tt.enrolled)
+			commands := NewCommands(catalog, catalog, &fakeProgress{}, testSiteURL)
+
+			text, err := commands.Courses(context.Background(), &Command{User: user})
+			if err != nil {
+				t.Fatalf("Courses() error = %v", err)
+			}
+			if got := strings.Count(text, "<a href="); got != tt.wantCourses {
+				t.Fatalf("listed %d courses, want %d:\n%s", got, tt.wantCourses, text)
+			}
+			if tt.wantTail != "" && !strings.Contains(text, tt.wantTail) {
+				t.Fatalf("text = %q, want %q", text, tt.wantTail)
+			}
+			if tt.wantTail == "" && strings.Contains(text, "…и ещё") {
+				t.Fatalf("unexpected truncation:\n%s", text)
+			}
+		})
+	}
+}
+
+func TestCommands_Continue(t *testing.T) {
+	lessonID := uuid.New()
+	tests := []struct {
+		name     string
+		progress *fakeProgress
+		want     string
+	}{
+		{"no progress", &fakeProgress{continueErr: progressUsecase.ErrNothingToResume}, "Пока нечего продолжать"},
+		{"resume lesson", &fakeProgress{point: &progressEntity.ContinuePoint{
+			CourseTitle: "Go", ModuleTitle: "Основы", LessonID: lessonID, LessonTitle: "Срезы & карты",
+		}}, testSiteURL + "/lessons/" + lessonID.String() + "\">Срезы &amp; карты</a>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := NewCommands(&fakeCatalog{}, &fakeCatalog{}, tt.progress, testSiteURL)
+			text, err := commands.Continue(context.Background(), &Command{User: &userEntity.User{ID: uuid.New()}})
+			if err != nil {
+				t.Fatalf("Continue() error = %v", err)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Fatalf("text = %q, want %q", text, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommands_Streak(t *testing.T) {
+	tests := []struct {
+		name   string
+		streak progressEntity.Streak
+		want   string
+	}{
+		{"no streak", progressEntity.Streak{}, "Серии пока нет"},
+		{"active today", progressEntity.Streak{Current: 1, Longest: 21, ActiveToday: true}, "Серия: 1 день\nРекорд: 21 день"},
+		{"not yet today", progressEntity.Streak{Current: 3, Longest: 5}, "Позанимайтесь сегодня"},
+		{"few days", progressEntity.Streak{Current: 22, Longest: 104, ActiveToday: true}, "Серия: 22 дня\nРекорд: 104 дня"},
+		{"many days", progressEntity.Streak{Current: 11, Longest: 112, ActiveToday: true}, "Серия: 11 дней\nРекорд: 112 дней"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := NewCommands(&fakeCatalog{}, &fakeCatalog{}, &fakeProgress{streak: tt.streak}, testSiteURL)
+			text, err := commands.Streak(context.Background(), &Command{User: &userEntity.User{ID: uuid.New()}})
+			if err != nil {
+				t.Fatalf("Streak() error = %v", err)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Fatalf("text = %q, want %q", text, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluralDays(t *testing.T) {
+	tests := map[int]string{
+		0: "0 дней", 1: "1 день", 2: "2 дня", 4: "4 дня", 5: "5 дней",
+		11: "11 дней", 12: "12 дней", 14: "14 дней", 21: "21 день", 22: "22 дня",
+		101: "101 день", 111: "111 дней", 112: "112 дней",
+	}
+	for n, want := range tests {
+		if got := pluralDays(n); got != want {
+			t.Errorf("pluralDays(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
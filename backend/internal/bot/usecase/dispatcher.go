@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+	userUsecase "github.com/kostinp/edu-platform-backend/internal/user/usecase"
+)
+
+// Command — входящая команда боту
+type Command struct {
+	ChatID int64
+	Name   string
+	Args   string
+	// User — пользователь платформы, привязанный к Telegram-аккаунту; nil, если не найден
+	User *userEntity.User
+}
+
+// CommandHandler возвращает текст ответа (HTML)
+type CommandHandler func(ctx context.Context, cmd *Command) (string, error)
+
+// UserFinder — поиск пользователя платформы по Telegram ID
+type UserFinder interface {
+	GetByTelegramID(ctx context.Context, telegramID int64) (*userEntity.User, error)
+}
+
+type Dispatcher interface {
+	Dispatch(ctx context.Context, update tgbotapi.Update) error
+}
+
+type dispatcher struct {
+	api      BotAPI
+	users    UserFinder
+	handlers map[string]CommandHandler
+	// requireUser — команды, доступные только после входа на платформу
+	requireUser map[string]bool
+}
+
+func NewDispatcher(api BotAPI, users UserFinder, commands *Commands) Dispatcher {
+	d := &dispatcher{
+		api:         api,
+		users:       users,
+		handlers:    map[string]CommandHandler{},
+		requireUser: map[string]bool{},
+	}
+	d.register("start", commands.Help, false)
+	d.register("help", commands.Help, false)
+	d.register("courses", commands.Courses, true)
+	d.register("continue", commands.Continue, true)
+	d.register("progress", commands.Progress, true)
+	d.register("streak", commands.Streak, true)
+	return d
+}
+
+func (d *dispatcher) register(name string, h CommandHandler, requireUser bool) {
+	d.handlers[name] = h
+	d.requireUser[name] = requireUser
+}
+
+// Dispatch обрабатывает одно обновление из webhook. Сообщения без команды игнорируются
+func (d *dispatcher) Dispatch(ctx context.Context, update tgbotapi.Update) error {
+	msg := update.Message
+	if msg == nil || !msg.IsCommand() || msg.From == nil {
+		return nil
+	}
+	cmd := &Command{
+		ChatID: msg.Chat.ID,
+		Name:   strings.ToLower(msg.Command()),
+		Args:   strings.TrimSpace(msg.CommandArguments()),
+	}
+
+	handler, ok := d.handlers[cmd.Name]
+	if !ok {
+		return d.api.SendMessage(ctx, cmd.ChatID, "Не знаю такой команды. Список команд — /help")
+	}
+	if d.requireUser[cmd.Name] {
+		user, err := d.users.GetByTelegramID(ctx, msg.From.ID)
+		if errors.Is(err, userUsecase.ErrUserNotFound) {
+			return d.api.SendMessage(ctx, cmd.ChatID, notLinkedText)
+		}
+		if err != nil {
+			_ = d.api.SendMessage(ctx, cmd.ChatID, "Что-то пошло не так, попробуйте позже.")
+			return fmt.Errorf("поиск пользователя по Telegram ID: %w", err)
+		}
+		cmd.User = user
+	}
+
+	text, err := handler(ctx, cmd)
+	if err != nil {
+		_ = d.api.SendMessage(ctx, cmd.ChatID, "Что-то пошло не так, попробуйте позже.")
+		return fmt.Errorf("команда /%s: %w", cmd.Name, err)
+	}
+	return d.api.SendMessage(ctx, cmd.ChatID, text)
+}
+
+const notLinkedText = "Этот Telegram-аккаунт ещё не связан с платформой. " +
+	"Войдите на сайте через Telegram, и команды станут доступны."
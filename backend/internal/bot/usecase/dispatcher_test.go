@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	const (
+		chatID     = int64(100)
+		linkedID   = int64(1)
+		unlinkedID = int64(2)
+	)
+	user := &userEntity.User{ID: uuid.New()}
+	users := fakeUserFinder{linkedID: user}
+
+	tests := []struct {
+		name     string
+		update   tgbotapi.Update
+		wantSent bool
+		wantText string
+	}{
+		{"unknown command", commandUpdate(chatID, linkedID, "/unknown"), true, "Не знаю такой команды"},
+		{"plain text", commandUpdate(chatID, linkedID, "привет"), false, ""},
+		{"no message", tgbotapi.Update{}, false, ""},
+		{"help without account", commandUpdate(chatID, unlinkedID, "/help"), true, "/courses — мои курсы"},
+		{"command with bot name", commandUpdate(chatID, linkedID, "/HELP@edu_bot"), true, "/courses — мои курсы"},
+		{"not linked", commandUpdate(chatID, unlinkedID, "/courses"), true, notLinkedText},
+		{"linked", commandUpdate(chatID, linkedID, "/courses"), true, "Курс 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeBotAPI{}
+			catalog := newFakeCatalog(user.ID, 1)
+			commands := NewCommands(catalog, catalog, &fakeProgress{}, "https://edu.example.com/")
+
+			if err := NewDispatcher(api, users, commands).Dispatch(context.Background(), tt.update); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+			if !tt.wantSent {
+				if len(api.sent) != 0 {
+					t.Fatalf("sent %d messages, want none", len(api.sent))
+				}
+				return
+			}
+			if len(api.sent) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(api.sent))
+			}
+			if got := api.sent[0]; got.chatID != chatID || !strings.Contains(got.text, tt.wantText) {
+				t.Fatalf("sent %+v, want chat %d with %q", got, chatID, tt.wantText)
+			}
+		})
+	}
+}
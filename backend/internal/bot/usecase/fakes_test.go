@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	courseEntity "github.com/kostinp/edu-platform-backend/internal/course/entity"
+	progressEntity "github.com/kostinp/edu-platform-backend/internal/progress/entity"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+	userUsecase "github.com/kostinp/edu-platform-backend/internal/user/usecase"
+)
+
+// sentMessage — сообщение, отправленное через fakeBotAPI
+type sentMessage struct {
+	chatID int64
+	text   string
+}
+
+// fakeBotAPI запоминает исходящие сообщения вместо вызова Telegram
+type fakeBotAPI struct {
+	mu   sync.Mutex
+	sent []sentMessage
+}
+
+func (a *fakeBotAPI) SendMessage(_ context.Context, chatID int64, text string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sent = append(a.sent, sentMessage{chatID: chatID, text: text})
+	return nil
+}
+
+// fakeUserFinder — привязки Telegram ID к пользователям платформы
+type fakeUserFinder map[int64]*userEntity.User
+
+func (f fakeUserFinder) GetByTelegramID(_ context.Context, telegramID int64) (*userEntity.User, error) {
+	user, ok := f[telegramID]
+	if !ok {
+		return nil, userUsecase.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// fakeCatalog отдаёт записи и курсы пользователя из памяти
+type fakeCatalog struct {
+	enrollments []*courseEntity.Enrollment
+	courses     map[uuid.UUID]*courseEntity.Course
+}
+
+// newFakeCatalog записывает пользователя на n курсов «Курс 1» … «Курс n»
+func newFakeCatalog(userID uuid.UUID, n int) *fakeCatalog {
+	c := &fakeCatalog{courses: map[uuid.UUID]*courseEntity.Course{}}
+	for i := 1; i <= n; i++ {
+		course := &courseEntity.Course{Slug: fmt.Sprintf("course-%d", i), Title: fmt.Sprintf("Курс %d", i)}
+		course.ID = uuid.New()
+		c.courses[course.ID] = course
+		c.enrollments = append(c.enrollments, &courseEntity.Enrollment{ID: uuid.New(), CourseID: course.ID, UserID: userID})
+	}
+	return c
+}
+
+func (c *fakeCatalog) ListByUser(_ context.Context, userID uuid.UUID) ([]*courseEntity.Enrollment, error) {
+	var items []*courseEntity.Enrollment
+	for _, e := range c.enrollments {
+		if e.UserID == userID {
+			items = append(items, e)
+		}
+	}
+	return items, nil
+}
+
+func (c *fakeCatalog) GetByIDs(_ context.Context, ids []uuid.UUID, _ *uuid.UUID) ([]*courseEntity.Course, error) {
+	var items []*courseEntity.Course
+	for _, id := range ids {
+		if course, ok := c.courses[id]; ok {
+			items = append(items, course)
+		}
+	}
+	return items, nil
+}
+
+// fakeProgress возвращает заранее заданные ответы
+type fakeProgress struct {
+	items       []*progressEntity.CourseProgress
+	point       *progressEntity.ContinuePoint
+	continueErr error
+	streak      progressEntity.Streak
+}
+
+func (p *fakeProgress) ListCourseProgress(context.Context, uuid.UUID) ([]*progressEntity.CourseProgress, error) {
+	return p.items, nil
+}
+
+func (p *fakeProgress) Continue(context.Context, uuid.UUID) (*progressEntity.ContinuePoint, error) {
+	return p.point, p.continueErr
+}
+
+func (p *fakeProgress) Streak(context.Context, uuid.UUID) (*progressEntity.Streak, error) {
+	streak := p.streak
+	return &streak, nil
+}
+
+// commandUpdate — обновление webhook с командой text от пользователя fromID
+func commandUpdate(chatID, fromID int64, text string) tgbotapi.Update {
+	msg := &tgbotapi.Message{
+		Text: text,
+		Chat: &tgbotapi.Chat{ID: chatID},
+		From: &tgbotapi.User{ID: fromID},
+	}
+	if len(text) > 0 && text[0] == '/' {
+		length := len(text)
+		for i, r := range text {
+			if r == ' ' {
+				length = i
+				break
+			}
+		}
+		msg.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: length}}
+	}
+	return tgbotapi.Update{Message: msg}
+}
@@ -0,0 +1,35 @@
+// internal/bot/wire.go
+package bot
+
+import (
+	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/bot/client"
+	http "github.com/kostinp/edu-platform-backend/internal/bot/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/bot/usecase"
+	courseUsecase "github.com/kostinp/edu-platform-backend/internal/course/usecase"
+	progressUsecase "github.com/kostinp/edu-platform-backend/internal/progress/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/config"
+	userUsecase "github.com/kostinp/edu-platform-backend/internal/user/usecase"
+)
+
+func ProvideSiteURL(cfg *config.Config) usecase.SiteURL {
+	return usecase.SiteURL("https://" + cfg.Telegram.WebhookDomain)
+}
+
+func ProvideWebhookSecret(cfg *config.Config) http.WebhookSecret {
+	return http.WebhookSecret(cfg.Telegram.WebhookSecret)
+}
+
+var BotSet = wire.NewSet(
+	client.NewTelegramAPI,
+	wire.Bind(new(usecase.BotAPI), new(*client.TelegramAPI)),
+	wire.Bind(new(usecase.UserFinder), new(*userUsecase.UserService)),
+	wire.Bind(new(usecase.EnrollmentLister), new(courseUsecase.EnrollmentUsecase)),
+	wire.Bind(new(usecase.CourseResolver), new(courseUsecase.CourseUsecase)),
+	wire.Bind(new(usecase.ProgressReader), new(progressUsecase.ProgressUsecase)),
+	ProvideSiteURL,
+	ProvideWebhookSecret,
+	usecase.NewCommands,
+	usecase.NewDispatcher,
+	http.NewWebhookHandler,
+)
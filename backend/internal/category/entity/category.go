@@ -17,8 +17,10 @@ type Category struct {
 	AuthorID    uuid.UUID  `json:"author_id"`
 	SortOrder   int        `json:"sort_order"`
 	IsVisible   bool       `json:"is_visible"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	// Организация, которой принадлежит категория; nil — общий каталог
+	OrgID     *uuid.UUID `json:"org_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 	// Навигационные поля
 	Parent   *Category   `json:"parent,omitempty"`
 	Children []*Category `json:"children,omitempty"`
@@ -10,21 +10,24 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/category/entity"
 	searchEntity "github.com/kostinp/edu-platform-backend/internal/search/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
 	tagEntity "github.com/kostinp/edu-platform-backend/internal/tag/entity"
 )
 
+// CategoryNavigationRepository — навигация по каталогу. Все выборки видят общий каталог
+// и контент организации viewerOrg (nil — только общий каталог)
 type CategoryNavigationRepository interface {
 	// Получение дерева категорий
-	GetTree(ctx context.Context) ([]*entity.TreeCategory, error)
-	GetSubtree(ctx context.Context, categoryID uuid.UUID) ([]*entity.TreeCategory, error)
-	GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error)
-	GetCategoryWithStats(ctx context.Context, categoryID uuid.UUID) (*entity.CategoryWithStats, error)
+	GetTree(ctx context.Context, viewerOrg *uuid.UUID) ([]*entity.TreeCategory, error)
+	GetSubtree(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.TreeCategory, error)
+	GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error)
+	GetCategoryWithStats(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) (*entity.CategoryWithStats, error)
 	// Поиск по категории
-	SearchInCategory(ctx context.Context, categoryID uuid.UUID, query string) ([]searchEntity.SearchEntity, error)
-	GetContentByCategory(ctx context.Context, categoryID uuid.UUID, contentType string) ([]searchEntity.SearchEntity, error)
+	SearchInCategory(ctx context.Context, categoryID uuid.UUID, query string, viewerOrg *uuid.UUID) ([]searchEntity.SearchEntity, error)
+	GetContentByCategory(ctx context.Context, categoryID uuid.UUID, contentType string, viewerOrg *uuid.UUID) ([]searchEntity.SearchEntity, error)
 	// Получение связанных категорий
-	GetSiblingCategories(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error)
-	GetPathCategories(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error)
+	GetSiblingCategories(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error)
+	GetPathCategories(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error)
 }
 
 type PostgresCategoryNavigationRepository struct {
@@ -36,7 +39,7 @@ func NewPostgresCategoryNavigationRepository(db *pgxpool.Pool) *PostgresCategory
 }
 
 // GetTree возвращает полное дерево категорий
-func (r *PostgresCategoryNavigationRepository) GetTree(ctx context.Context) ([]*entity.TreeCategory, error) {
+func (r *PostgresCategoryNavigationRepository) GetTree(ctx context.Context, viewerOrg *uuid.UUID) ([]*entity.TreeCategory, error) {
 	// Сначала получаем все категории
 	query := `
 		SELECT
@@ -48,12 +51,12 @@ func (r *PostgresCategoryNavigationRepository) GetTree(ctx context.Context) ([]*
 				'description', t.description
 			)) FILTER (WHERE t.id IS NOT NULL), '[]') as tags
 		FROM categories c
-		LEFT JOIN tags t ON t.category_id = c.id
-		WHERE c.is_visible = true
+		LEFT JOIN tags t ON t.category_id = c.id AND ` + tenant.Visible("t.org_id", 1) + `
+		WHERE c.is_visible = true AND ` + tenant.Visible("c.org_id", 1) + `
 		GROUP BY c.id
 		ORDER BY c.sort_order, c.name
 	`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, viewerOrg)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +97,7 @@ func (r *PostgresCategoryNavigationRepository) buildTree(
 }
 
 // GetSubtree возвращает поддерево категорий
-func (r *PostgresCategoryNavigationRepository) GetSubtree(ctx context.Context, categoryID uuid.UUID) ([]*entity.TreeCategory, error) {
+func (r *PostgresCategoryNavigationRepository) GetSubtree(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.TreeCategory, error) {
 	// Аналогично GetTree, но начиная с указанной категории
 	// Сначала получаем все подкатегории рекурсивно
 	query := `
@@ -113,12 +116,12 @@ func (r *PostgresCategoryNavigationRepository) GetSubtree(ctx context.Context, c
 				'description', t.description
 			)) FILTER (WHERE t.id IS NOT NULL), '[]') as tags
 		FROM sub_categories sc
-		LEFT JOIN tags t ON t.category_id = sc.id
-		WHERE sc.is_visible = true
+		LEFT JOIN tags t ON t.category_id = sc.id AND ` + tenant.Visible("t.org_id", 2) + `
+		WHERE sc.is_visible = true AND ` + tenant.Visible("sc.org_id", 2) + `
 		GROUP BY sc.id
 		ORDER BY sc.sort_order, sc.name
 	`
-	rows, err := r.db.Query(ctx, query, categoryID)
+	rows, err := r.db.Query(ctx, query, categoryID, viewerOrg)
 	if err != nil {
 		return nil, err
 	}
@@ -144,23 +147,24 @@ func (r *PostgresCategoryNavigationRepository) GetSubtree(ctx context.Context, c
 }
 
 // GetBreadcrumbs возвращает хлебные крошки для категории
-func (r *PostgresCategoryNavigationRepository) GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error) {
+func (r *PostgresCategoryNavigationRepository) GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error) {
 	// Используем рекурсивный CTE для получения пути
 	query := `
 		WITH RECURSIVE category_path AS (
 			SELECT id, name, slug, parent_id, 1 as depth
 			FROM categories
-			WHERE id = $1
+			WHERE id = $1 AND ` + tenant.Visible("org_id", 2) + `
 			UNION ALL
 			SELECT c.id, c.name, c.slug, c.parent_id, cp.depth + 1
 			FROM categories c
 			INNER JOIN category_path cp ON c.id = cp.parent_id
+			WHERE ` + tenant.Visible("c.org_id", 2) + `
 		)
 		SELECT id, name, slug, parent_id
 		FROM category_path
 		ORDER BY depth DESC
 	`
-	rows, err := r.db.Query(ctx, query, categoryID)
+	rows, err := r.db.Query(ctx, query, categoryID, viewerOrg)
 	if err != nil {
 		return nil, err
 	}
@@ -180,21 +184,21 @@ func (r *PostgresCategoryNavigationRepository) GetBreadcrumbs(ctx context.Contex
 }
 
 // GetCategoryWithStats возвращает категорию со статистикой
-func (r *PostgresCategoryNavigationRepository) GetCategoryWithStats(ctx context.Context, categoryID uuid.UUID) (*entity.CategoryWithStats, error) {
+func (r *PostgresCategoryNavigationRepository) GetCategoryWithStats(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) (*entity.CategoryWithStats, error) {
 	query := `
 		SELECT
-			c.*,
+			c.id, c.name, c.slug, c.description, c.parent_id,
+			c.author_id, c.sort_order, c.is_visible, c.created_at, c.updated_at, c.org_id,
 			(SELECT COUNT(*) FROM category_assignments ca WHERE ca.category_id = c.id AND ca.target_type = 'course') as courses_count,
 			(SELECT COUNT(*) FROM category_assignments ca WHERE ca.category_id = c.id AND ca.target_type = 'lesson') as lessons_count,
 			(SELECT COUNT(*) FROM category_assignments ca WHERE ca.category_id = c.id AND ca.target_type = 'module') as modules_count
 		FROM categories c
-		WHERE c.id = $1
-	`
-	row := r.db.QueryRow(ctx, query, categoryID)
+		WHERE c.id = $1 AND ` + tenant.Visible("c.org_id", 2)
+	row := r.db.QueryRow(ctx, query, categoryID, viewerOrg)
 	var stats entity.CategoryWithStats
 	err := row.Scan(
 		&stats.ID, &stats.Name, &stats.Slug, &stats.Description, &stats.ParentID,
-		&stats.AuthorID, &stats.SortOrder, &stats.IsVisible, &stats.CreatedAt, &stats.UpdatedAt,
+		&stats.AuthorID, &stats.SortOrder, &stats.IsVisible, &stats.CreatedAt, &stats.UpdatedAt, &stats.OrgID,
 		&stats.CoursesCount, &stats.LessonsCount, &stats.ModulesCount,
 	)
 	if err != nil {
@@ -204,7 +208,7 @@ func (r *PostgresCategoryNavigationRepository) GetCategoryWithStats(ctx context.
 }
 
 // SearchInCategory выполняет поиск внутри категории
-func (r *PostgresCategoryNavigationRepository) SearchInCategory(ctx context.Context, categoryID uuid.UUID, query string) ([]searchEntity.SearchEntity, error) {
+func (r *PostgresCategoryNavigationRepository) SearchInCategory(ctx context.Context, categoryID uuid.UUID, query string, viewerOrg *uuid.UUID) ([]searchEntity.SearchEntity, error) {
 	// Упрощённая реализация: поиск по курсам и урокам в категории
 	searchQuery := `
 		SELECT
@@ -212,15 +216,17 @@ func (r *PostgresCategoryNavigationRepository) SearchInCategory(ctx context.Cont
 		FROM courses c
 		INNER JOIN category_assignments ca ON ca.target_id = c.id AND ca.target_type = 'course'
 		WHERE ca.category_id = $1 AND (c.title ILIKE $2 OR c.description ILIKE $2) AND c.deleted_at IS NULL
+			AND ` + tenant.Visible("c.org_id", 3) + `
 		UNION ALL
 		SELECT
 			l.id, l.title, l.content as description, 'lesson' as type, l.created_at, l.updated_at, 1.0 as relevance
 		FROM lessons l
 		INNER JOIN category_assignments ca ON ca.target_id = l.id AND ca.target_type = 'lesson'
 		WHERE ca.category_id = $1 AND (l.title ILIKE $2 OR l.content ILIKE $2) AND l.deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM categories cat WHERE cat.id = $1 AND ` + tenant.Visible("cat.org_id", 3) + `)
 		ORDER BY relevance DESC, created_at DESC
 	`
-	rows, err := r.db.Query(ctx, searchQuery, categoryID, "%"+query+"%")
+	rows, err := r.db.Query(ctx, searchQuery, categoryID, "%"+query+"%", viewerOrg)
 	if err != nil {
 		return nil, err
 	}
@@ -242,7 +248,11 @@ func (r *PostgresCategoryNavigationRepository) GetContentByCategory(
 	ctx context.Context,
 	categoryID uuid.UUID,
 	contentType string,
+	viewerOrg *uuid.UUID,
 ) ([]searchEntity.SearchEntity, error) {
+	// Закрытая категория чужой организации пуста; теги и курсы других организаций не показываются
+	tagJoin := `LEFT JOIN tags t ON t.id = ta.tag_id AND ` + tenant.Visible("t.org_id", 2)
+	categoryVisible := tenant.Visible("cat.org_id", 2)
 	var query string
 	switch contentType {
 	case "course":
@@ -265,9 +275,10 @@ func (r *PostgresCategoryNavigationRepository) GetContentByCategory(
 			FROM courses c
 			INNER JOIN category_assignments ca ON ca.target_id = c.id AND ca.target_type = 'course'
 			LEFT JOIN tag_assignments ta ON ta.entity_id = c.id AND ta.entity_type = 'course'
-			LEFT JOIN tags t ON t.id = ta.tag_id
+			` + tagJoin + `
 			LEFT JOIN categories cat ON cat.id = ca.category_id
 			WHERE ca.category_id = $1 AND c.deleted_at IS NULL
+				AND ` + tenant.Visible("c.org_id", 2) + ` AND ` + categoryVisible + `
 			GROUP BY c.id, c.title, c.description, c.created_at
 			ORDER BY c.created_at DESC
 		`
@@ -291,16 +302,16 @@ func (r *PostgresCategoryNavigationRepository) GetContentByCategory(
 			FROM lessons l
 			INNER JOIN category_assignments ca ON ca.target_id = l.id AND ca.target_type = 'lesson'
 			LEFT JOIN tag_assignments ta ON ta.entity_id = l.id AND ta.entity_type = 'lesson'
-			LEFT JOIN tags t ON t.id = ta.tag_id
+			` + tagJoin + `
 			LEFT JOIN categories cat ON cat.id = ca.category_id
-			WHERE ca.category_id = $1 AND l.deleted_at IS NULL
+			WHERE ca.category_id = $1 AND l.deleted_at IS NULL AND ` + categoryVisible + `
 			GROUP BY l.id, l.title, l.content, l.created_at
 			ORDER BY l.created_at DESC
 		`
 	default:
 		return nil, fmt.Errorf("unsupported content type: %s", contentType)
 	}
-	rows, err := r.db.Query(ctx, query, categoryID)
+	rows, err := r.db.Query(ctx, query, categoryID, viewerOrg)
 	if err != nil {
 		return nil, err
 	}
@@ -324,16 +335,17 @@ func (r *PostgresCategoryNavigationRepository) GetContentByCategory(
 }
 
 // GetSiblingCategories возвращает соседние категории
-func (r *PostgresCategoryNavigationRepository) GetSiblingCategories(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error) {
+func (r *PostgresCategoryNavigationRepository) GetSiblingCategories(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error) {
 	query := `
-		SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at
+		SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, org_id
 		FROM categories
 		WHERE parent_id = (SELECT parent_id FROM categories WHERE id = $1)
 		AND id != $1
 		AND is_visible = true
+		AND ` + tenant.Visible("org_id", 2) + `
 		ORDER BY sort_order, name
 	`
-	rows, err := r.db.Query(ctx, query, categoryID)
+	rows, err := r.db.Query(ctx, query, categoryID, viewerOrg)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +353,7 @@ func (r *PostgresCategoryNavigationRepository) GetSiblingCategories(ctx context.
 	var siblings []*entity.Category
 	for rows.Next() {
 		var cat entity.Category
-		err := rows.Scan(&cat.ID, &cat.Name, &cat.Slug, &cat.Description, &cat.ParentID, &cat.AuthorID, &cat.SortOrder, &cat.IsVisible, &cat.CreatedAt, &cat.UpdatedAt)
+		err := rows.Scan(&cat.ID, &cat.Name, &cat.Slug, &cat.Description, &cat.ParentID, &cat.AuthorID, &cat.SortOrder, &cat.IsVisible, &cat.CreatedAt, &cat.UpdatedAt, &cat.OrgID)
 		if err != nil {
 			return nil, err
 		}
@@ -351,23 +363,24 @@ func (r *PostgresCategoryNavigationRepository) GetSiblingCategories(ctx context.
 }
 
 // GetPathCategories возвращает путь категорий от корня
-func (r *PostgresCategoryNavigationRepository) GetPathCategories(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error) {
+func (r *PostgresCategoryNavigationRepository) GetPathCategories(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error) {
 	// Аналогично breadcrumbs, но с полными данными
 	query := `
 		WITH RECURSIVE category_path AS (
-			SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, 1 as depth
+			SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, org_id, 1 as depth
 			FROM categories
-			WHERE id = $1
+			WHERE id = $1 AND ` + tenant.Visible("org_id", 2) + `
 			UNION ALL
-			SELECT c.id, c.name, c.slug, c.description, c.parent_id, c.author_id, c.sort_order, c.is_visible, c.created_at, c.updated_at, cp.depth + 1
+			SELECT c.id, c.name, c.slug, c.description, c.parent_id, c.author_id, c.sort_order, c.is_visible, c.created_at, c.updated_at, c.org_id, cp.depth + 1
 			FROM categories c
 			INNER JOIN category_path cp ON c.id = cp.parent_id
+			WHERE ` + tenant.Visible("c.org_id", 2) + `
 		)
-		SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at
+		SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, org_id
 		FROM category_path
 		ORDER BY depth DESC
 	`
-	rows, err := r.db.Query(ctx, query, categoryID)
+	rows, err := r.db.Query(ctx, query, categoryID, viewerOrg)
 	if err != nil {
 		return nil, err
 	}
@@ -375,7 +388,7 @@ func (r *PostgresCategoryNavigationRepository) GetPathCategories(ctx context.Con
 	var path []*entity.Category
 	for rows.Next() {
 		var cat entity.Category
-		err := rows.Scan(&cat.ID, &cat.Name, &cat.Slug, &cat.Description, &cat.ParentID, &cat.AuthorID, &cat.SortOrder, &cat.IsVisible, &cat.CreatedAt, &cat.UpdatedAt)
+		err := rows.Scan(&cat.ID, &cat.Name, &cat.Slug, &cat.Description, &cat.ParentID, &cat.AuthorID, &cat.SortOrder, &cat.IsVisible, &cat.CreatedAt, &cat.UpdatedAt, &cat.OrgID)
 		if err != nil {
 			return nil, err
 		}
@@ -2,69 +2,157 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/category/entity"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
 )
 
 type CategoryRepository interface {
+	// Create и Update возвращают slug.ErrSlugTaken, если slug успели занять параллельно
 	Create(ctx context.Context, category *entity.Category) error
 	Update(ctx context.Context, category *entity.Category) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Category, int, error)
+	// GetBySlug, GetSlugRedirect и List видят общий каталог и категории организации viewerOrg
+	GetBySlug(ctx context.Context, slug string, viewerOrg *uuid.UUID) (*entity.Category, error)
+	GetSlugRedirect(ctx context.Context, slug string, viewerOrg *uuid.UUID) (string, error)
+	SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error)
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Category, int, error)
+	// GetOrgID — организация категории (resource.org_id в ABAC); nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
 }
 
 type PostgresCategoryRepository struct {
 	db *pgxpool.Pool
 }
 
+// slugEntityType — тип сущности в slug_history
+const slugEntityType = "category"
+
 func NewPostgresCategoryRepository(db *pgxpool.Pool) *PostgresCategoryRepository {
 	return &PostgresCategoryRepository{db: db}
 }
 
 func (r *PostgresCategoryRepository) Create(ctx context.Context, category *entity.Category) error {
 	_, err := r.db.Exec(ctx, `
-			INSERT INTO categories (id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		`, category.ID, category.Name, category.Slug, category.Description, category.ParentID, category.AuthorID, category.SortOrder, category.IsVisible, category.CreatedAt, category.UpdatedAt)
+		INSERT INTO categories (id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, category.ID, category.Name, category.Slug, category.Description, category.ParentID, category.AuthorID, category.SortOrder, category.IsVisible, category.CreatedAt, category.UpdatedAt, category.OrgID)
+	if isUniqueViolation(err) {
+		return slug.ErrSlugTaken
+	}
 	return err
 }
 
+// Update сохраняет категорию; при смене slug прежний попадает в slug_history
 func (r *PostgresCategoryRepository) Update(ctx context.Context, category *entity.Category) error {
-	_, err := r.db.Exec(ctx, `
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var oldSlug string
+		err := tx.QueryRow(ctx, `SELECT slug FROM categories WHERE id = $1 FOR UPDATE`, category.ID).Scan(&oldSlug)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
 			UPDATE categories
 			SET name = $1, slug = $2, description = $3, parent_id = $4, sort_order = $5, is_visible = $6, updated_at = $7
 			WHERE id = $8
 		`, category.Name, category.Slug, category.Description, category.ParentID, category.SortOrder, category.IsVisible, category.UpdatedAt, category.ID)
-	return err
+		if isUniqueViolation(err) {
+			return slug.ErrSlugTaken
+		}
+		if err != nil || oldSlug == category.Slug {
+			return err
+		}
+		// Категория вернулась к своему прежнему slug — редирект с него больше не нужен
+		_, err = tx.Exec(ctx, `
+			DELETE FROM slug_history WHERE entity_type = $1 AND slug = $2 AND entity_id = $3
+		`, slugEntityType, category.Slug, category.ID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO slug_history (entity_type, entity_id, slug)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (entity_type, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = NOW()
+		`, slugEntityType, category.ID, oldSlug)
+		return err
+	})
 }
 
 func (r *PostgresCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
-	return err
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM slug_history WHERE entity_type = $1 AND entity_id = $2`, slugEntityType, id); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+		return err
+	})
 }
+
+func (r *PostgresCategoryRepository) GetBySlug(ctx context.Context, slug string, viewerOrg *uuid.UUID) (*entity.Category, error) {
+	row := r.db.QueryRow(ctx, `
+			SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, org_id
+			FROM categories WHERE slug = $1 AND `+tenant.Visible("org_id", 2),
+		slug, viewerOrg)
+	category := &entity.Category{}
+
+	err := row.Scan(&category.ID, &category.Name, &category.Slug, &category.Description, &category.ParentID, &category.AuthorID, &category.SortOrder, &category.IsVisible, &category.CreatedAt, &category.UpdatedAt, &category.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// GetSlugRedirect возвращает текущий slug категории по одному из её прежних slug
+func (r *PostgresCategoryRepository) GetSlugRedirect(ctx context.Context, slug string, viewerOrg *uuid.UUID) (string, error) {
+	var current string
+	err := r.db.QueryRow(ctx, `
+		SELECT c.slug
+		FROM slug_history h
+		JOIN categories c ON c.id = h.entity_id
+		WHERE h.entity_type = $1 AND h.slug = $2 AND `+tenant.Visible("c.org_id", 3),
+		slugEntityType, slug, viewerOrg).Scan(&current)
+	return current, err
+}
+
+// SlugExists считает занятыми и прежние slug других категорий — с них работают редиректы
+func (r *PostgresCategoryRepository) SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM categories WHERE slug = $1 AND id <> $2)
+			OR EXISTS(SELECT 1 FROM slug_history WHERE entity_type = $3 AND slug = $1 AND entity_id <> $2)
+	`, slug, excludeID, slugEntityType).Scan(&exists)
+	return exists, err
+}
+
 func (r *PostgresCategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error) {
 	row := r.db.QueryRow(ctx, `
-			SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at
+			SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, org_id
 			FROM categories WHERE id = $1
 		`, id)
 	category := &entity.Category{}
 
-	err := row.Scan(&category.ID, &category.Name, &category.Slug, &category.Description, &category.ParentID, &category.AuthorID, &category.SortOrder, &category.IsVisible, &category.CreatedAt, &category.UpdatedAt)
+	err := row.Scan(&category.ID, &category.Name, &category.Slug, &category.Description, &category.ParentID, &category.AuthorID, &category.SortOrder, &category.IsVisible, &category.CreatedAt, &category.UpdatedAt, &category.OrgID)
 	if err != nil {
 		return nil, err
 	}
 
 	return category, nil
 }
-func (r *PostgresCategoryRepository) List(ctx context.Context, pag pagination.Params) ([]*entity.Category, int, error) {
+func (r *PostgresCategoryRepository) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Category, int, error) {
 	baseQuery := `
-		SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at
-		FROM categories`
+		SELECT id, name, slug, description, parent_id, author_id, sort_order, is_visible, created_at, updated_at, org_id
+		FROM categories WHERE ` + tenant.Visible("org_id", 3)
 	query, args := pagination.SQLWithPagination(baseQuery, pag, map[string]string{"created_at": "created_at", "name": "name"})
+	args = append(args, viewerOrg)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -75,19 +163,30 @@ func (r *PostgresCategoryRepository) List(ctx context.Context, pag pagination.Pa
 	var categories []*entity.Category
 	for rows.Next() {
 		category := &entity.Category{}
-		err := rows.Scan(&category.ID, &category.Name, &category.Slug, &category.Description, &category.ParentID, &category.AuthorID, &category.SortOrder, &category.IsVisible, &category.CreatedAt, &category.UpdatedAt)
+		err := rows.Scan(&category.ID, &category.Name, &category.Slug, &category.Description, &category.ParentID, &category.AuthorID, &category.SortOrder, &category.IsVisible, &category.CreatedAt, &category.UpdatedAt, &category.OrgID)
 		if err != nil {
 			return nil, 0, err
 		}
 		categories = append(categories, category)
 	}
 
-	countQuery := `SELECT COUNT(*) FROM categories`
+	countQuery := `SELECT COUNT(*) FROM categories WHERE ` + tenant.Visible("org_id", 1)
 	var total int
-	err = r.db.QueryRow(ctx, countQuery).Scan(&total)
+	err = r.db.QueryRow(ctx, countQuery, viewerOrg).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	return categories, total, nil
 }
+
+func (r *PostgresCategoryRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT org_id FROM categories WHERE id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -2,13 +2,16 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/kostinp/edu-platform-backend/internal/category/entity"
 	"github.com/kostinp/edu-platform-backend/internal/category/usecase"
 	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
 	"github.com/labstack/echo/v4"
 )
 
@@ -23,12 +26,14 @@ func NewCategoryHandler(cuc usecase.CategoryUsecase, auc usecase.CategoryAssignm
 
 // CreateCategory godoc
 // @Summary Create a new category
+// @Description Категория участника организации попадает в её закрытый каталог
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Param category body entity.Category true "Category object"
 // @Success 201 {object} entity.Category
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /categories [post]
 func (h *CategoryHandler) Create(c echo.Context) error {
@@ -44,8 +49,8 @@ func (h *CategoryHandler) Create(c echo.Context) error {
 	if err := c.Bind(category); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	if err := h.categoryUC.Create(c.Request().Context(), category, authorID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if err := h.categoryUC.Create(c.Request().Context(), category, authorID, middleware.CurrentOrgID(c)); err != nil {
+		return categoryError(c, err)
 	}
 	return c.JSON(http.StatusCreated, category)
 }
@@ -72,6 +77,29 @@ func (h *CategoryHandler) Get(c echo.Context) error {
 	return c.JSON(http.StatusOK, category)
 }
 
+// GetCategoryBySlug godoc
+// @Summary Get category by slug
+// @Description Для устаревшего slug (после переименования) отвечает 301 на актуальный адрес.
+// @Description Категории организации видны только её участникам (с заголовком Authorization)
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} entity.Category
+// @Success 301
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories/by-slug/{slug} [get]
+func (h *CategoryHandler) GetBySlug(c echo.Context) error {
+	category, redirect, err := h.categoryUC.ResolveSlug(c.Request().Context(), c.Param("slug"), middleware.CurrentOrgID(c))
+	if err != nil {
+		return categoryError(c, err)
+	}
+	if redirect != "" {
+		return c.Redirect(http.StatusMovedPermanently, "/api/categories/by-slug/"+redirect)
+	}
+	return c.JSON(http.StatusOK, category)
+}
+
 // UpdateCategory godoc
 // @Summary Update category
 // @Tags categories
@@ -81,6 +109,7 @@ func (h *CategoryHandler) Get(c echo.Context) error {
 // @Param category body entity.Category true "Updated category object"
 // @Success 200 {object} entity.Category
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /categories/{id} [put]
 func (h *CategoryHandler) Update(c echo.Context) error {
@@ -95,7 +124,7 @@ func (h *CategoryHandler) Update(c echo.Context) error {
 	}
 	category.ID = id
 	if err := h.categoryUC.Update(c.Request().Context(), category); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return categoryError(c, err)
 	}
 	return c.JSON(http.StatusOK, category)
 }
@@ -122,6 +151,7 @@ func (h *CategoryHandler) Delete(c echo.Context) error {
 
 // ListCategories godoc
 // @Summary List categories
+// @Description Общий каталог и категории организации текущего пользователя
 // @Tags categories
 // @Produce json
 // @Param limit query int false "Limit"
@@ -134,7 +164,7 @@ func (h *CategoryHandler) Delete(c echo.Context) error {
 func (h *CategoryHandler) List(c echo.Context) error {
 	pagQuery := pagination.ParsePaginationParams(c)
 	pag := pagQuery.ToDomainParams()
-	categories, total, err := h.categoryUC.List(c.Request().Context(), pag)
+	categories, total, err := h.categoryUC.List(c.Request().Context(), middleware.CurrentOrgID(c), pag)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -240,3 +270,15 @@ func (h *CategoryHandler) ListByCategory(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, assignments)
 }
+
+func categoryError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCategoryNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, slug.ErrInvalidSlug):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, slug.ErrSlugTaken):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
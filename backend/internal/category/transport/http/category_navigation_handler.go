@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kostinp/edu-platform-backend/internal/category/entity"
 	"github.com/kostinp/edu-platform-backend/internal/category/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	"github.com/labstack/echo/v4"
 )
 
@@ -20,13 +21,14 @@ func NewCategoryNavigationHandler(uc usecase.CategoryNavigationUsecase) *Categor
 
 // GetCategoryTree godoc
 // @Summary Получить дерево категорий
+// @Description Общий каталог; участникам организации (с заголовком Authorization) — и её категории
 // @Tags Categories
 // @Produce json
 // @Success 200 {object} CategoryTreeResponse
 // @Failure 500 {object} map[string]string
 // @Router /categories/tree [get]
 func (h *CategoryNavigationHandler) GetCategoryTree(c echo.Context) error {
-	tree, err := h.navigationUC.GetTree(c.Request().Context())
+	tree, err := h.navigationUC.GetTree(c.Request().Context(), middleware.CurrentOrgID(c))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Не удалось получить дерево категорий",
@@ -54,7 +56,7 @@ func (h *CategoryNavigationHandler) GetBreadcrumbs(c echo.Context) error {
 			"error": "Некорректный ID категории",
 		})
 	}
-	breadcrumbs, err := h.navigationUC.GetBreadcrumbs(c.Request().Context(), categoryID)
+	breadcrumbs, err := h.navigationUC.GetBreadcrumbs(c.Request().Context(), categoryID, middleware.CurrentOrgID(c))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Не удалось получить хлебные крошки",
@@ -85,7 +87,7 @@ func (h *CategoryNavigationHandler) GetCategoryContent(c echo.Context) error {
 	if contentType == "" {
 		contentType = "course"
 	}
-	content, err := h.navigationUC.GetContentByCategory(c.Request().Context(), categoryID, contentType)
+	content, err := h.navigationUC.GetContentByCategory(c.Request().Context(), categoryID, contentType, middleware.CurrentOrgID(c))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Не удалось получить контент категории",
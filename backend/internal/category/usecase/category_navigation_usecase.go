@@ -10,10 +10,12 @@ import (
 	searchEntity "github.com/kostinp/edu-platform-backend/internal/search/entity"
 )
 
+// CategoryNavigationUsecase — публичная навигация; viewerOrg — организация зрителя
+// (nil — только общий каталог)
 type CategoryNavigationUsecase interface {
-	GetTree(ctx context.Context) ([]*entity.TreeCategory, error)
-	GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error)
-	GetContentByCategory(ctx context.Context, categoryID uuid.UUID, contentType string) ([]searchEntity.SearchEntity, error)
+	GetTree(ctx context.Context, viewerOrg *uuid.UUID) ([]*entity.TreeCategory, error)
+	GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error)
+	GetContentByCategory(ctx context.Context, categoryID uuid.UUID, contentType string, viewerOrg *uuid.UUID) ([]searchEntity.SearchEntity, error)
 }
 
 type categoryNavigationUsecase struct {
@@ -24,14 +26,14 @@ func NewCategoryNavigationUsecase(repo repository.CategoryNavigationRepository)
 	return &categoryNavigationUsecase{repo: repo}
 }
 
-func (u *categoryNavigationUsecase) GetTree(ctx context.Context) ([]*entity.TreeCategory, error) {
-	return u.repo.GetTree(ctx)
+func (u *categoryNavigationUsecase) GetTree(ctx context.Context, viewerOrg *uuid.UUID) ([]*entity.TreeCategory, error) {
+	return u.repo.GetTree(ctx, viewerOrg)
 }
 
-func (u *categoryNavigationUsecase) GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]*entity.Category, error) {
-	return u.repo.GetBreadcrumbs(ctx, categoryID)
+func (u *categoryNavigationUsecase) GetBreadcrumbs(ctx context.Context, categoryID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Category, error) {
+	return u.repo.GetBreadcrumbs(ctx, categoryID, viewerOrg)
 }
 
-func (u *categoryNavigationUsecase) GetContentByCategory(ctx context.Context, categoryID uuid.UUID, contentType string) ([]searchEntity.SearchEntity, error) {
-	return u.repo.GetContentByCategory(ctx, categoryID, contentType)
+func (u *categoryNavigationUsecase) GetContentByCategory(ctx context.Context, categoryID uuid.UUID, contentType string, viewerOrg *uuid.UUID) ([]searchEntity.SearchEntity, error) {
+	return u.repo.GetContentByCategory(ctx, categoryID, contentType, viewerOrg)
 }
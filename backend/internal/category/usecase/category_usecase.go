@@ -2,20 +2,35 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/kostinp/edu-platform-backend/internal/category/entity"
 	"github.com/kostinp/edu-platform-backend/internal/category/repository"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
 )
 
+var ErrCategoryNotFound = errors.New("category not found")
+
+// slugSaveAttempts — сколько раз пересобирать сгенерированный slug, если его
+// между проверкой и сохранением занял параллельный запрос
+const slugSaveAttempts = 3
+
 type CategoryUsecase interface {
-	Create(ctx context.Context, category *entity.Category, authorID uuid.UUID) error
+	// Create сохраняет категорию в организации автора (nil — в общем каталоге)
+	Create(ctx context.Context, category *entity.Category, authorID uuid.UUID, orgID *uuid.UUID) error
 	Update(ctx context.Context, category *entity.Category) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Category, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Category, int, error)
+	// ResolveSlug ищет категорию по slug; для устаревшего slug возвращает текущий в redirect.
+	// Категории других организаций не находятся
+	ResolveSlug(ctx context.Context, s string, viewerOrg *uuid.UUID) (category *entity.Category, redirect string, err error)
+	// List — общий каталог и категории организации viewerOrg
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Category, int, error)
 }
 
 type categoryUsecase struct {
@@ -26,17 +41,27 @@ func NewCategoryUsecase(repo repository.CategoryRepository) CategoryUsecase {
 	return &categoryUsecase{repo: repo}
 }
 
-func (u *categoryUsecase) Create(ctx context.Context, category *entity.Category, authorID uuid.UUID) error {
+func (u *categoryUsecase) Create(ctx context.Context, category *entity.Category, authorID uuid.UUID, orgID *uuid.UUID) error {
 	category.ID = uuid.New()
 	category.AuthorID = authorID
+	category.OrgID = orgID
 	category.CreatedAt = time.Now()
 	category.UpdatedAt = time.Now()
-	return u.repo.Create(ctx, category)
+	return u.saveWithSlug(ctx, category, nil, u.repo.Create)
 }
 
 func (u *categoryUsecase) Update(ctx context.Context, category *entity.Category) error {
+	current, err := u.repo.GetByID(ctx, category.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// Категория не переносится между организациями при редактировании
+	category.OrgID = current.OrgID
 	category.UpdatedAt = time.Now()
-	return u.repo.Update(ctx, category)
+	return u.saveWithSlug(ctx, category, current, u.repo.Update)
 }
 
 func (u *categoryUsecase) Delete(ctx context.Context, id uuid.UUID) error {
@@ -47,6 +72,72 @@ func (u *categoryUsecase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Ca
 	return u.repo.GetByID(ctx, id)
 }
 
-func (u *categoryUsecase) List(ctx context.Context, pag pagination.Params) ([]*entity.Category, int, error) {
-	return u.repo.List(ctx, pag)
+func (u *categoryUsecase) ResolveSlug(ctx context.Context, s string, viewerOrg *uuid.UUID) (*entity.Category, string, error) {
+	category, err := u.repo.GetBySlug(ctx, s, viewerOrg)
+	if err == nil {
+		return category, "", nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", err
+	}
+	redirect, err := u.repo.GetSlugRedirect(ctx, s, viewerOrg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, redirect, nil
+}
+
+// saveWithSlug назначает slug и сохраняет категорию. Явно заданный занятый slug — ErrSlugTaken (409),
+// а сгенерированный из названия при гонке подбирается заново со следующим суффиксом
+func (u *categoryUsecase) saveWithSlug(ctx context.Context, category, current *entity.Category, save func(context.Context, *entity.Category) error) error {
+	requested := strings.TrimSpace(category.Slug) != ""
+	for attempt := 1; ; attempt++ {
+		if err := u.assignSlug(ctx, category, current); err != nil {
+			return err
+		}
+		err := save(ctx, category)
+		if !errors.Is(err, slug.ErrSlugTaken) || requested || attempt == slugSaveAttempts {
+			return err
+		}
+		category.Slug = ""
+	}
+}
+
+// assignSlug проверяет явно переданный slug или генерирует его из названия.
+// Без явного slug он меняется только при переименовании категории
+func (u *categoryUsecase) assignSlug(ctx context.Context, category, current *entity.Category) error {
+	exists := func(ctx context.Context, s string) (bool, error) {
+		return u.repo.SlugExists(ctx, s, category.ID)
+	}
+	if requested := strings.TrimSpace(category.Slug); requested != "" {
+		if !slug.Valid(requested) {
+			return slug.ErrInvalidSlug
+		}
+		taken, err := exists(ctx, requested)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return slug.ErrSlugTaken
+		}
+		category.Slug = requested
+		return nil
+	}
+	if current != nil && current.Name == category.Name {
+		category.Slug = current.Slug
+		return nil
+	}
+	generated, err := slug.Unique(ctx, slug.Make(category.Name), "category", exists)
+	if err != nil {
+		return err
+	}
+	category.Slug = generated
+	return nil
+}
+
+func (u *categoryUsecase) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Category, int, error) {
+	return u.repo.List(ctx, viewerOrg, pag)
 }
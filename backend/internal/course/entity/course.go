@@ -3,6 +3,7 @@ package entity
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kostinp/edu-platform-backend/internal/shared/entity"
 )
 
@@ -15,4 +16,10 @@ type Course struct {
 	Price       int        `json:"price"`
 	ImageURL    string     `json:"image_url"`
 	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	// Организация, которой принадлежит курс; nil — общий каталог
+	OrgID *uuid.UUID `json:"org_id,omitempty"`
+
+	// Агрегаты по одобренным отзывам, пересчитываются при модерации
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
 }
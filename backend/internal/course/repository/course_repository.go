@@ -3,44 +3,91 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/course/entity"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
 )
 
 type CourseRepository interface {
+	// Create и Update возвращают slug.ErrSlugTaken, если slug успели занять параллельно
 	Create(ctx context.Context, course *entity.Course) error
 	Update(ctx context.Context, course *entity.Course) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Course, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Course, int, error)
+	// GetByIDs видит общий каталог и курсы организации viewerOrg
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Course, error)
+	// GetBySlug и GetSlugRedirect видят общий каталог и курсы организации viewerOrg
+	GetBySlug(ctx context.Context, slug string, viewerOrg *uuid.UUID) (*entity.Course, error)
+	GetSlugRedirect(ctx context.Context, slug string, viewerOrg *uuid.UUID) (string, error)
+	SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error)
+	// List — общий каталог и курсы организации viewerOrg (nil — только общий каталог)
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Course, int, error)
+	// GetOrgID — организация курса (resource.org_id в ABAC); nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
 }
 
 type PostgresCourseRepository struct {
 	db *pgxpool.Pool
 }
 
+// slugEntityType — тип сущности в slug_history
+const slugEntityType = "course"
+
 func NewPostgresCourseRepository(db *pgxpool.Pool) *PostgresCourseRepository {
 	return &PostgresCourseRepository{db: db}
 }
 
 func (r *PostgresCourseRepository) Create(ctx context.Context, course *entity.Course) error {
 	_, err := r.db.Exec(ctx, `
-		INSERT INTO courses (id, slug, title, description, price, image_url, author_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-	`, course.ID, course.Slug, course.Title, course.Description, course.Price, course.ImageURL, course.AuthorID, course.CreatedAt, course.UpdatedAt)
+		INSERT INTO courses (id, slug, title, description, price, image_url, author_id, created_at, updated_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, course.ID, course.Slug, course.Title, course.Description, course.Price, course.ImageURL, course.AuthorID, course.CreatedAt, course.UpdatedAt, course.OrgID)
+	if isUniqueViolation(err) {
+		return slug.ErrSlugTaken
+	}
 	return err
 }
 
+// Update сохраняет курс; при смене slug прежний попадает в slug_history
 func (r *PostgresCourseRepository) Update(ctx context.Context, course *entity.Course) error {
-	_, err := r.db.Exec(ctx, `
-		UPDATE courses
-		SET slug = $1, title = $2, description = $3, price = $4, image_url = $5, updated_at = $6, deleted_at = $7
-		WHERE id = $8
-	`, course.Slug, course.Title, course.Description, course.Price, course.ImageURL, course.UpdatedAt, course.DeletedAt, course.ID)
-	return err
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var oldSlug string
+		err := tx.QueryRow(ctx, `SELECT slug FROM courses WHERE id = $1 FOR UPDATE`, course.ID).Scan(&oldSlug)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE courses
+			SET slug = $1, title = $2, description = $3, price = $4, image_url = $5, updated_at = $6, deleted_at = $7
+			WHERE id = $8
+		`, course.Slug, course.Title, course.Description, course.Price, course.ImageURL, course.UpdatedAt, course.DeletedAt, course.ID)
+		if isUniqueViolation(err) {
+			return slug.ErrSlugTaken
+		}
+		if err != nil || oldSlug == course.Slug {
+			return err
+		}
+		// Курс вернулся к своему прежнему slug — редирект с него больше не нужен
+		_, err = tx.Exec(ctx, `
+			DELETE FROM slug_history WHERE entity_type = $1 AND slug = $2 AND entity_id = $3
+		`, slugEntityType, course.Slug, course.ID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO slug_history (entity_type, entity_id, slug)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (entity_type, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = NOW()
+		`, slugEntityType, course.ID, oldSlug)
+		return err
+	})
 }
 
 func (r *PostgresCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -52,23 +99,81 @@ func (r *PostgresCourseRepository) Delete(ctx context.Context, id uuid.UUID) err
 
 func (r *PostgresCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Course, error) {
 	row := r.db.QueryRow(ctx, `
-		SELECT id, slug, title, description, price, image_url, author_id, created_at, updated_at, deleted_at
+		SELECT id, slug, title, description, price, image_url, rating_avg, rating_count, author_id, created_at, updated_at, deleted_at, org_id
 		FROM courses WHERE id = $1 AND deleted_at IS NULL
 	`, id)
 	course := &entity.Course{}
-	err := row.Scan(&course.ID, &course.Slug, &course.Title, &course.Description, &course.Price, &course.ImageURL, &course.AuthorID, &course.CreatedAt, &course.UpdatedAt, &course.DeletedAt)
+	err := row.Scan(&course.ID, &course.Slug, &course.Title, &course.Description, &course.Price, &course.ImageURL, &course.RatingAvg, &course.RatingCount, &course.AuthorID, &course.CreatedAt, &course.UpdatedAt, &course.DeletedAt, &course.OrgID)
 	if err != nil {
 		return nil, err
 	}
 	return course, nil
 }
 
-func (r *PostgresCourseRepository) List(ctx context.Context, pag pagination.Params) ([]*entity.Course, int, error) {
+func (r *PostgresCourseRepository) GetBySlug(ctx context.Context, slug string, viewerOrg *uuid.UUID) (*entity.Course, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT id, slug, title, description, price, image_url, rating_avg, rating_count, author_id, created_at, updated_at, deleted_at, org_id
+		FROM courses WHERE slug = $1 AND deleted_at IS NULL AND `+tenant.Visible("org_id", 2),
+		slug, viewerOrg)
+	course := &entity.Course{}
+	err := row.Scan(&course.ID, &course.Slug, &course.Title, &course.Description, &course.Price, &course.ImageURL, &course.RatingAvg, &course.RatingCount, &course.AuthorID, &course.CreatedAt, &course.UpdatedAt, &course.DeletedAt, &course.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// GetSlugRedirect возвращает текущий slug курса по одному из его прежних slug
+func (r *PostgresCourseRepository) GetSlugRedirect(ctx context.Context, slug string, viewerOrg *uuid.UUID) (string, error) {
+	var current string
+	err := r.db.QueryRow(ctx, `
+		SELECT c.slug
+		FROM slug_history h
+		JOIN courses c ON c.id = h.entity_id
+		WHERE h.entity_type = $1 AND h.slug = $2 AND c.deleted_at IS NULL AND `+tenant.Visible("c.org_id", 3),
+		slugEntityType, slug, viewerOrg).Scan(&current)
+	return current, err
+}
+
+// SlugExists учитывает и удалённые курсы: ограничение UNIQUE действует на всю таблицу.
+// Прежние slug других курсов тоже заняты — с них работают редиректы
+func (r *PostgresCourseRepository) SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM courses WHERE slug = $1 AND id <> $2)
+			OR EXISTS(SELECT 1 FROM slug_history WHERE entity_type = $3 AND slug = $1 AND entity_id <> $2)
+	`, slug, excludeID, slugEntityType).Scan(&exists)
+	return exists, err
+}
+
+// GetByIDs загружает несколько курсов одним запросом; удалённые пропускаются
+func (r *PostgresCourseRepository) GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Course, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, slug, title, description, price, image_url, rating_avg, rating_count, author_id, created_at, updated_at, deleted_at, org_id
+		FROM courses WHERE id = ANY($1) AND deleted_at IS NULL AND `+tenant.Visible("org_id", 2),
+		ids, viewerOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var courses []*entity.Course
+	for rows.Next() {
+		course := &entity.Course{}
+		err := rows.Scan(&course.ID, &course.Slug, &course.Title, &course.Description, &course.Price, &course.ImageURL, &course.RatingAvg, &course.RatingCount, &course.AuthorID, &course.CreatedAt, &course.UpdatedAt, &course.DeletedAt, &course.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
+func (r *PostgresCourseRepository) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Course, int, error) {
 	baseQuery := `
-		SELECT id, slug, title, description, price, image_url, author_id, created_at, updated_at, deleted_at
-		FROM courses WHERE deleted_at IS NULL
-	`
-	query, args := pagination.SQLWithPagination(baseQuery, pag, map[string]string{"created_at": "created_at", "title": "title"})
+		SELECT id, slug, title, description, price, image_url, rating_avg, rating_count, author_id, created_at, updated_at, deleted_at, org_id
+		FROM courses WHERE deleted_at IS NULL AND ` + tenant.Visible("org_id", 3)
+	query, args := pagination.SQLWithPagination(baseQuery, pag, map[string]string{"created_at": "created_at", "title": "title", "rating": "rating_avg", "rating_count": "rating_count"})
+	args = append(args, viewerOrg)
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
@@ -77,17 +182,28 @@ func (r *PostgresCourseRepository) List(ctx context.Context, pag pagination.Para
 	var courses []*entity.Course
 	for rows.Next() {
 		course := &entity.Course{}
-		err := rows.Scan(&course.ID, &course.Slug, &course.Title, &course.Description, &course.Price, &course.ImageURL, &course.AuthorID, &course.CreatedAt, &course.UpdatedAt, &course.DeletedAt)
+		err := rows.Scan(&course.ID, &course.Slug, &course.Title, &course.Description, &course.Price, &course.ImageURL, &course.RatingAvg, &course.RatingCount, &course.AuthorID, &course.CreatedAt, &course.UpdatedAt, &course.DeletedAt, &course.OrgID)
 		if err != nil {
 			return nil, 0, err
 		}
 		courses = append(courses, course)
 	}
-	countQuery := `SELECT COUNT(*) FROM courses WHERE deleted_at IS NULL`
+	countQuery := `SELECT COUNT(*) FROM courses WHERE deleted_at IS NULL AND ` + tenant.Visible("org_id", 1)
 	var total int
-	err = r.db.QueryRow(ctx, countQuery).Scan(&total)
+	err = r.db.QueryRow(ctx, countQuery, viewerOrg).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 	return courses, total, nil
 }
+
+func (r *PostgresCourseRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT org_id FROM courses WHERE id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
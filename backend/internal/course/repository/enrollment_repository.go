@@ -0,0 +1,73 @@
+// internal/course/repository/enrollment_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/course/entity"
+)
+
+var ErrAlreadyEnrolled = errors.New("already enrolled")
+
+type EnrollmentRepository interface {
+	Enroll(ctx context.Context, enrollment *entity.Enrollment) error
+	IsEnrolled(ctx context.Context, courseID, userID uuid.UUID) (bool, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Enrollment, error)
+}
+
+type PostgresEnrollmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresEnrollmentRepository(db *pgxpool.Pool) *PostgresEnrollmentRepository {
+	return &PostgresEnrollmentRepository{db: db}
+}
+
+// Enroll записывает пользователя на курс; повторная запись — ErrAlreadyEnrolled
+func (r *PostgresEnrollmentRepository) Enroll(ctx context.Context, e *entity.Enrollment) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO course_enrollments (id, course_id, user_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (course_id, user_id) DO NOTHING
+		RETURNING id, created_at
+	`, e.ID, e.CourseID, e.UserID, e.CreatedAt).Scan(&e.ID, &e.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrAlreadyEnrolled
+	}
+	return err
+}
+
+func (r *PostgresEnrollmentRepository) IsEnrolled(ctx context.Context, courseID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM course_enrollments WHERE course_id = $1 AND user_id = $2)
+	`, courseID, userID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresEnrollmentRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Enrollment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT e.id, e.course_id, e.user_id, e.created_at
+		FROM course_enrollments e
+		JOIN courses c ON c.id = e.course_id AND c.deleted_at IS NULL
+		WHERE e.user_id = $1
+		ORDER BY e.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	enrollments := []*entity.Enrollment{}
+	for rows.Next() {
+		e := &entity.Enrollment{}
+		if err := rows.Scan(&e.ID, &e.CourseID, &e.UserID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, e)
+	}
+	return enrollments, rows.Err()
+}
@@ -2,13 +2,16 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/kostinp/edu-platform-backend/internal/course/entity"
 	"github.com/kostinp/edu-platform-backend/internal/course/usecase"
 	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
 	"github.com/labstack/echo/v4"
 )
 
@@ -22,12 +25,14 @@ func NewCourseHandler(uc usecase.CourseUsecase) *CourseHandler {
 
 // CreateCourse godoc
 // @Summary Create a new course
+// @Description Курс участника организации попадает в её закрытый каталог
 // @Tags courses
 // @Accept json
 // @Produce json
 // @Param course body entity.Course true "Course object"
 // @Success 201 {object} entity.Course
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /courses [post]
 func (h *CourseHandler) Create(c echo.Context) error {
@@ -43,8 +48,8 @@ func (h *CourseHandler) Create(c echo.Context) error {
 	if err := c.Bind(course); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	if err := h.usecase.Create(c.Request().Context(), course, authorID); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if err := h.usecase.Create(c.Request().Context(), course, authorID, middleware.CurrentOrgID(c)); err != nil {
+		return courseError(c, err)
 	}
 	return c.JSON(http.StatusCreated, course)
 }
@@ -71,6 +76,29 @@ func (h *CourseHandler) Get(c echo.Context) error {
 	return c.JSON(http.StatusOK, course)
 }
 
+// GetCourseBySlug godoc
+// @Summary Get course by slug
+// @Description Для устаревшего slug (после переименования) отвечает 301 на актуальный адрес.
+// @Description Курсы организации видны только её участникам (с заголовком Authorization)
+// @Tags courses
+// @Produce json
+// @Param slug path string true "Course slug"
+// @Success 200 {object} entity.Course
+// @Success 301
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /courses/by-slug/{slug} [get]
+func (h *CourseHandler) GetBySlug(c echo.Context) error {
+	course, redirect, err := h.usecase.ResolveSlug(c.Request().Context(), c.Param("slug"), middleware.CurrentOrgID(c))
+	if err != nil {
+		return courseError(c, err)
+	}
+	if redirect != "" {
+		return c.Redirect(http.StatusMovedPermanently, "/api/courses/by-slug/"+redirect)
+	}
+	return c.JSON(http.StatusOK, course)
+}
+
 // UpdateCourse godoc
 // @Summary Update course
 // @Tags courses
@@ -80,6 +108,7 @@ func (h *CourseHandler) Get(c echo.Context) error {
 // @Param course body entity.Course true "Updated course object"
 // @Success 200 {object} entity.Course
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /courses/{id} [put]
 func (h *CourseHandler) Update(c echo.Context) error {
@@ -94,7 +123,7 @@ func (h *CourseHandler) Update(c echo.Context) error {
 	}
 	course.ID = id
 	if err := h.usecase.Update(c.Request().Context(), course); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return courseError(c, err)
 	}
 	return c.JSON(http.StatusOK, course)
 }
@@ -121,6 +150,7 @@ func (h *CourseHandler) Delete(c echo.Context) error {
 
 // ListCourses godoc
 // @Summary List courses
+// @Description Общий каталог и курсы организации текущего пользователя
 // @Tags courses
 // @Produce json
 // @Param limit query int false "Limit"
@@ -133,7 +163,7 @@ func (h *CourseHandler) Delete(c echo.Context) error {
 func (h *CourseHandler) List(c echo.Context) error {
 	pagQuery := pagination.ParsePaginationParams(c)
 	pag := pagQuery.ToDomainParams()
-	courses, total, err := h.usecase.List(c.Request().Context(), pag)
+	courses, total, err := h.usecase.List(c.Request().Context(), middleware.CurrentOrgID(c), pag)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -144,3 +174,15 @@ func (h *CourseHandler) List(c echo.Context) error {
 		Offset: pag.Offset,
 	})
 }
+
+func courseError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCourseNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, slug.ErrInvalidSlug):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, slug.ErrSlugTaken):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,82 @@
+// internal/course/transport/http/enrollment_handler.go
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/course/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type EnrollmentHandler struct {
+	usecase usecase.EnrollmentUsecase
+}
+
+func NewEnrollmentHandler(uc usecase.EnrollmentUsecase) *EnrollmentHandler {
+	return &EnrollmentHandler{usecase: uc}
+}
+
+// Enroll godoc
+// @Summary Enroll current user into course
+// @Tags courses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 201 {object} entity.Enrollment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /courses/{id}/enroll [post]
+func (h *EnrollmentHandler) Enroll(c echo.Context) error {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user ID"})
+	}
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	}
+	enrollment, err := h.usecase.Enroll(c.Request().Context(), courseID, userID)
+	switch {
+	case errors.Is(err, usecase.ErrCourseNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAlreadyEnrolled):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to enroll"})
+	}
+	return c.JSON(http.StatusCreated, enrollment)
+}
+
+// ListMyEnrollments godoc
+// @Summary List courses the current user is enrolled in
+// @Tags courses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.Enrollment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /me/enrollments [get]
+func (h *EnrollmentHandler) ListMine(c echo.Context) error {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user ID"})
+	}
+	enrollments, err := h.usecase.ListByUser(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, enrollments)
+}
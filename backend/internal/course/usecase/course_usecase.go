@@ -2,19 +2,36 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/kostinp/edu-platform-backend/internal/course/entity"
 	"github.com/kostinp/edu-platform-backend/internal/course/repository"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
 )
 
+var ErrCourseNotFound = errors.New("course not found")
+
+// slugSaveAttempts — сколько раз пересобирать сгенерированный slug, если его
+// между проверкой и сохранением занял параллельный запрос
+const slugSaveAttempts = 3
+
 type CourseUsecase interface {
-	Create(ctx context.Context, course *entity.Course, authorID uuid.UUID) error
+	// Create сохраняет курс в организации автора (nil — в общем каталоге)
+	Create(ctx context.Context, course *entity.Course, authorID uuid.UUID, orgID *uuid.UUID) error
 	Update(ctx context.Context, course *entity.Course) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Course, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Course, int, error)
+	// GetByIDs — общий каталог и курсы организации viewerOrg
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Course, error)
+	// ResolveSlug ищет курс по slug; для устаревшего slug возвращает текущий в redirect.
+	// Курсы других организаций не находятся
+	ResolveSlug(ctx context.Context, s string, viewerOrg *uuid.UUID) (course *entity.Course, redirect string, err error)
+	// List — общий каталог и курсы организации viewerOrg
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Course, int, error)
 }
 
 type courseUsecase struct {
@@ -25,14 +42,24 @@ func NewCourseUsecase(repo repository.CourseRepository) CourseUsecase {
 	return &courseUsecase{repo: repo}
 }
 
-func (u *courseUsecase) Create(ctx context.Context, course *entity.Course, authorID uuid.UUID) error {
+func (u *courseUsecase) Create(ctx context.Context, course *entity.Course, authorID uuid.UUID, orgID *uuid.UUID) error {
 	course.Init(authorID)
-	return u.repo.Create(ctx, course)
+	course.OrgID = orgID
+	return u.saveWithSlug(ctx, course, nil, u.repo.Create)
 }
 
 func (u *courseUsecase) Update(ctx context.Context, course *entity.Course) error {
+	current, err := u.repo.GetByID(ctx, course.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCourseNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// Курс не переносится между организациями при редактировании
+	course.OrgID = current.OrgID
 	course.Touch()
-	return u.repo.Update(ctx, course)
+	return u.saveWithSlug(ctx, course, current, u.repo.Update)
 }
 
 func (u *courseUsecase) Delete(ctx context.Context, id uuid.UUID) error {
@@ -43,6 +70,76 @@ func (u *courseUsecase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Cour
 	return u.repo.GetByID(ctx, id)
 }
 
-func (u *courseUsecase) List(ctx context.Context, pag pagination.Params) ([]*entity.Course, int, error) {
-	return u.repo.List(ctx, pag)
+func (u *courseUsecase) GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Course, error) {
+	return u.repo.GetByIDs(ctx, ids, viewerOrg)
+}
+
+func (u *courseUsecase) ResolveSlug(ctx context.Context, s string, viewerOrg *uuid.UUID) (*entity.Course, string, error) {
+	course, err := u.repo.GetBySlug(ctx, s, viewerOrg)
+	if err == nil {
+		return course, "", nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", err
+	}
+	redirect, err := u.repo.GetSlugRedirect(ctx, s, viewerOrg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, redirect, nil
+}
+
+// saveWithSlug назначает slug и сохраняет курс. Явно заданный занятый slug — ErrSlugTaken (409),
+// а сгенерированный из названия при гонке подбирается заново со следующим суффиксом
+func (u *courseUsecase) saveWithSlug(ctx context.Context, course, current *entity.Course, save func(context.Context, *entity.Course) error) error {
+	requested := strings.TrimSpace(course.Slug) != ""
+	for attempt := 1; ; attempt++ {
+		if err := u.assignSlug(ctx, course, current); err != nil {
+			return err
+		}
+		err := save(ctx, course)
+		if !errors.Is(err, slug.ErrSlugTaken) || requested || attempt == slugSaveAttempts {
+			return err
+		}
+		course.Slug = ""
+	}
+}
+
+// assignSlug проверяет явно переданный slug или генерирует его из названия.
+// Без явного slug он меняется только при переименовании курса
+func (u *courseUsecase) assignSlug(ctx context.Context, course, current *entity.Course) error {
+	exists := func(ctx context.Context, s string) (bool, error) {
+		return u.repo.SlugExists(ctx, s, course.ID)
+	}
+	if requested := strings.TrimSpace(course.Slug); requested != "" {
+		if !slug.Valid(requested) {
+			return slug.ErrInvalidSlug
+		}
+		taken, err := exists(ctx, requested)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return slug.ErrSlugTaken
+		}
+		course.Slug = requested
+		return nil
+	}
+	if current != nil && current.Title == course.Title {
+		course.Slug = current.Slug
+		return nil
+	}
+	generated, err := slug.Unique(ctx, slug.Make(course.Title), "course", exists)
+	if err != nil {
+		return err
+	}
+	course.Slug = generated
+	return nil
+}
+
+func (u *courseUsecase) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Course, int, error) {
+	return u.repo.List(ctx, viewerOrg, pag)
 }
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kostinp/edu-platform-backend/internal/course/entity"
+	"github.com/kostinp/edu-platform-backend/internal/course/repository"
+)
+
+var ErrAlreadyEnrolled = repository.ErrAlreadyEnrolled
+
+type EnrollmentUsecase interface {
+	Enroll(ctx context.Context, courseID, userID uuid.UUID) (*entity.Enrollment, error)
+	IsEnrolled(ctx context.Context, courseID, userID uuid.UUID) (bool, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Enrollment, error)
+}
+
+type enrollmentUsecase struct {
+	repo       repository.EnrollmentRepository
+	courseRepo repository.CourseRepository
+}
+
+func NewEnrollmentUsecase(repo repository.EnrollmentRepository, courseRepo repository.CourseRepository) EnrollmentUsecase {
+	return &enrollmentUsecase{repo: repo, courseRepo: courseRepo}
+}
+
+func (u *enrollmentUsecase) Enroll(ctx context.Context, courseID, userID uuid.UUID) (*entity.Enrollment, error) {
+	// Проверяем, что курс существует и не удалён
+	_, err := u.courseRepo.GetByID(ctx, courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	enrollment := &entity.Enrollment{
+		ID:        uuid.New(),
+		CourseID:  courseID,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := u.repo.Enroll(ctx, enrollment); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+func (u *enrollmentUsecase) IsEnrolled(ctx context.Context, courseID, userID uuid.UUID) (bool, error) {
+	return u.repo.IsEnrolled(ctx, courseID, userID)
+}
+
+func (u *enrollmentUsecase) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Enrollment, error) {
+	return u.repo.ListByUser(ctx, userID)
+}
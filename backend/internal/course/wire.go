@@ -13,6 +13,10 @@ var CourseSet = wire.NewSet(
 	db.ConnectPostgres,
 	repository.NewPostgresCourseRepository,
 	wire.Bind(new(repository.CourseRepository), new(*repository.PostgresCourseRepository)),
+	repository.NewPostgresEnrollmentRepository,
+	wire.Bind(new(repository.EnrollmentRepository), new(*repository.PostgresEnrollmentRepository)),
 	usecase.NewCourseUsecase,
+	usecase.NewEnrollmentUsecase,
 	http.NewCourseHandler,
+	http.NewEnrollmentHandler,
 )
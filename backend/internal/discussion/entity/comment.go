@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/entity"
+)
+
+// Comment — комментарий в обсуждении урока. ParentID указывает на комментарий,
+// на который дан ответ, RootID — на корень ветки.
+type Comment struct {
+	entity.Base
+
+	LessonID  uuid.UUID  `json:"lesson_id"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	RootID    *uuid.UUID `json:"root_id,omitempty"`
+	Body      string     `json:"body"`
+	IsPinned  bool       `json:"is_pinned"`
+	IsAnswer  bool       `json:"is_answer"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *uuid.UUID `json:"-"`
+
+	Replies []*Comment `json:"replies,omitempty"`
+}
+
+// IsDeleted — комментарий удалён, но остаётся в дереве ради ответов
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// CommentRevision — предыдущая версия текста комментария
+type CommentRevision struct {
+	ID        uuid.UUID `json:"id"`
+	CommentID uuid.UUID `json:"comment_id"`
+	Body      string    `json:"body"`
+	EditedBy  uuid.UUID `json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,250 @@
+// internal/discussion/repository/comment_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/discussion/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
+)
+
+var ErrCommentNotFound = errors.New("comment not found")
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *entity.Comment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Comment, error)
+	UpdateBody(ctx context.Context, comment *entity.Comment, previousBody string, editorID uuid.UUID) error
+	SoftDelete(ctx context.Context, id, deletedBy uuid.UUID) error
+	SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error
+	SetAnswer(ctx context.Context, comment *entity.Comment, isAnswer bool) error
+	// ListThreads видит обсуждения уроков общего каталога и курсов организации viewerOrg
+	ListThreads(ctx context.Context, lessonID uuid.UUID, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Comment, int, error)
+	ListReplies(ctx context.Context, rootIDs []uuid.UUID) ([]*entity.Comment, error)
+	ListRevisions(ctx context.Context, commentID uuid.UUID) ([]*entity.CommentRevision, error)
+	GetAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	GetCourseAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	// GetOrgID — организация курса, к уроку которого относится комментарий; nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
+}
+
+type PostgresCommentRepository struct {
+	db *pgxpool.Pool
+}
+
+var allowedSortFields = map[string]string{
+	"created_at": "lc.created_at",
+	"updated_at": "lc.updated_at",
+}
+
+const commentColumns = `lc.id, lc.lesson_id, lc.parent_id, lc.root_id, lc.author_id, lc.body, lc.is_pinned, lc.is_answer,
+	lc.edited_at, lc.deleted_at, lc.deleted_by, lc.created_at, lc.updated_at`
+
+// commentCourseJoin связывает комментарий с курсом его урока
+const commentCourseJoin = `lesson_comments lc
+	JOIN lessons l ON l.id = lc.lesson_id
+	JOIN modules m ON m.id = l.module_id
+	JOIN courses c ON c.id = m.course_id`
+
+func NewPostgresCommentRepository(db *pgxpool.Pool) *PostgresCommentRepository {
+	return &PostgresCommentRepository{db: db}
+}
+
+func (r *PostgresCommentRepository) Create(ctx context.Context, c *entity.Comment) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO lesson_comments (id, lesson_id, parent_id, root_id, author_id, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, c.ID, c.LessonID, c.ParentID, c.RootID, c.AuthorID, c.Body, c.CreatedAt, c.UpdatedAt)
+	return err
+}
+
+func (r *PostgresCommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Comment, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+commentColumns+` FROM lesson_comments lc WHERE lc.id = $1`, id)
+	comment, err := scanComment(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	return comment, err
+}
+
+// UpdateBody сохраняет предыдущую версию в историю и обновляет текст
+func (r *PostgresCommentRepository) UpdateBody(ctx context.Context, c *entity.Comment, previousBody string, editorID uuid.UUID) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO lesson_comment_revisions (comment_id, body, edited_by, created_at)
+			VALUES ($1, $2, $3, $4)
+		`, c.ID, previousBody, editorID, c.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		cmdTag, err := tx.Exec(ctx, `
+			UPDATE lesson_comments SET body = $1, edited_at = $2, updated_at = $3
+			WHERE id = $4 AND deleted_at IS NULL
+		`, c.Body, c.EditedAt, c.UpdatedAt, c.ID)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return ErrCommentNotFound
+		}
+		return nil
+	})
+}
+
+func (r *PostgresCommentRepository) SoftDelete(ctx context.Context, id, deletedBy uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `
+		UPDATE lesson_comments
+		SET deleted_at = NOW(), deleted_by = $2, is_pinned = FALSE, is_answer = FALSE, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, deletedBy)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *PostgresCommentRepository) SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE lesson_comments SET is_pinned = $2, updated_at = NOW() WHERE id = $1
+	`, id, pinned)
+	return err
+}
+
+// SetAnswer помечает ответ решением; в ветке может быть только один ответ-решение
+func (r *PostgresCommentRepository) SetAnswer(ctx context.Context, c *entity.Comment, isAnswer bool) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if isAnswer && c.RootID != nil {
+			_, err := tx.Exec(ctx, `
+				UPDATE lesson_comments SET is_answer = FALSE, updated_at = NOW()
+				WHERE root_id = $1 AND is_answer AND id <> $2
+			`, *c.RootID, c.ID)
+			if err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE lesson_comments SET is_answer = $2, updated_at = NOW() WHERE id = $1
+		`, c.ID, isAnswer)
+		return err
+	})
+}
+
+// ListThreads возвращает корневые комментарии урока; закреплённые всегда первыми
+func (r *PostgresCommentRepository) ListThreads(ctx context.Context, lessonID uuid.UUID, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Comment, int, error) {
+	pag.Normalize()
+	sortField, ok := allowedSortFields[pag.SortBy]
+	if !ok {
+		sortField = "lc.created_at"
+	}
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE lc.lesson_id = $1 AND lc.parent_id IS NULL AND %s
+		ORDER BY lc.is_pinned DESC, %s %s
+		LIMIT $3 OFFSET $4
+	`, commentColumns, commentCourseJoin, tenant.Visible("c.org_id", 2), sortField, strings.ToUpper(pag.Order))
+	comments, err := r.queryComments(ctx, query, lessonID, viewerOrg, pag.Limit, pag.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM `+commentCourseJoin+`
+		WHERE lc.lesson_id = $1 AND lc.parent_id IS NULL AND `+tenant.Visible("c.org_id", 2),
+		lessonID, viewerOrg).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// ListReplies возвращает все ответы в указанных ветках в хронологическом порядке
+func (r *PostgresCommentRepository) ListReplies(ctx context.Context, rootIDs []uuid.UUID) ([]*entity.Comment, error) {
+	if len(rootIDs) == 0 {
+		return []*entity.Comment{}, nil
+	}
+	return r.queryComments(ctx, `
+		SELECT `+commentColumns+` FROM lesson_comments lc
+		WHERE lc.root_id = ANY($1)
+		ORDER BY lc.created_at ASC
+	`, rootIDs)
+}
+
+func (r *PostgresCommentRepository) ListRevisions(ctx context.Context, commentID uuid.UUID) ([]*entity.CommentRevision, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, comment_id, body, edited_by, created_at
+		FROM lesson_comment_revisions
+		WHERE comment_id = $1
+		ORDER BY created_at DESC
+	`, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	revisions := []*entity.CommentRevision{}
+	for rows.Next() {
+		rev := &entity.CommentRevision{}
+		if err := rows.Scan(&rev.ID, &rev.CommentID, &rev.Body, &rev.EditedBy, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// GetAuthorID используется SetResourceAuthorMiddleware для ABAC-проверок
+func (r *PostgresCommentRepository) GetAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var authorID uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT author_id FROM lesson_comments WHERE id = $1`, id).Scan(&authorID)
+	return authorID, err
+}
+
+// GetCourseAuthorID возвращает автора курса, к уроку которого относится комментарий
+func (r *PostgresCommentRepository) GetCourseAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var authorID uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT c.author_id FROM `+commentCourseJoin+` WHERE lc.id = $1`, id).Scan(&authorID)
+	return authorID, err
+}
+
+func (r *PostgresCommentRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT c.org_id FROM `+commentCourseJoin+` WHERE lc.id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
+
+func (r *PostgresCommentRepository) queryComments(ctx context.Context, query string, args ...interface{}) ([]*entity.Comment, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	comments := []*entity.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+func scanComment(row pgx.Row) (*entity.Comment, error) {
+	c := &entity.Comment{}
+	err := row.Scan(
+		&c.ID, &c.LessonID, &c.ParentID, &c.RootID, &c.AuthorID, &c.Body, &c.IsPinned, &c.IsAnswer,
+		&c.EditedAt, &c.DeletedAt, &c.DeletedBy, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,260 @@
+// internal/discussion/transport/http/comment_handler.go
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/discussion/entity"
+	"github.com/kostinp/edu-platform-backend/internal/discussion/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type CommentHandler struct {
+	usecase usecase.CommentUsecase
+}
+
+func NewCommentHandler(uc usecase.CommentUsecase) *CommentHandler {
+	return &CommentHandler{usecase: uc}
+}
+
+// CreateCommentRequest — новый комментарий или ответ (parent_id)
+type CreateCommentRequest struct {
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	Body     string     `json:"body" example:"Почему здесь используется указатель?"`
+}
+
+// EditCommentRequest — новый текст комментария
+type EditCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// FlagRequest — установка/снятие флага (закреп, ответ-решение)
+type FlagRequest struct {
+	Value bool `json:"value" example:"true"`
+}
+
+// ListComments godoc
+// @Summary Получить обсуждение урока
+// @Tags Discussions
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID урока"
+// @Param limit query int false "Лимит веток"
+// @Param offset query int false "Смещение"
+// @Param sort_by query string false "Сортировка (created_at, updated_at)"
+// @Param order query string false "Порядок"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Comment]
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /lessons/{id}/comments [get]
+func (h *CommentHandler) List(c echo.Context) error {
+	lessonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid lesson id"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	threads, total, err := h.usecase.ListThreads(c.Request().Context(), lessonID, middleware.CurrentOrgID(c), pag)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Comment]{
+		Items:  threads,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// CreateComment godoc
+// @Summary Написать комментарий к уроку
+// @Tags Discussions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID урока"
+// @Param request body CreateCommentRequest true "Комментарий"
+// @Success 201 {object} entity.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /lessons/{id}/comments [post]
+func (h *CommentHandler) Create(c echo.Context) error {
+	authorID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	lessonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid lesson id"})
+	}
+	req := new(CreateCommentRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	comment := &entity.Comment{LessonID: lessonID, ParentID: req.ParentID, Body: req.Body}
+	if err := h.usecase.Create(c.Request().Context(), comment, authorID, middleware.CurrentOrgID(c)); err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusCreated, comment)
+}
+
+// EditComment godoc
+// @Summary Редактировать свой комментарий
+// @Tags Discussions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID комментария"
+// @Param request body EditCommentRequest true "Новый текст"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id} [put]
+func (h *CommentHandler) Edit(c echo.Context) error {
+	editorID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid comment id"})
+	}
+	req := new(EditCommentRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	comment, err := h.usecase.Edit(c.Request().Context(), id, editorID, req.Body)
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment godoc
+// @Summary Удалить комментарий (мягкое удаление)
+// @Tags Discussions
+// @Security BearerAuth
+// @Param id path string true "ID комментария"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) Delete(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid comment id"})
+	}
+	if err := h.usecase.Delete(c.Request().Context(), id, userID); err != nil {
+		return commentError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetCommentHistory godoc
+// @Summary История правок комментария
+// @Tags Discussions
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID комментария"
+// @Success 200 {array} entity.CommentRevision
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id}/history [get]
+func (h *CommentHandler) History(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid comment id"})
+	}
+	revisions, err := h.usecase.History(c.Request().Context(), id)
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, revisions)
+}
+
+// PinComment godoc
+// @Summary Закрепить/открепить ветку (преподаватель курса)
+// @Tags Discussions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID комментария"
+// @Param request body FlagRequest true "Закрепить"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id}/pin [post]
+func (h *CommentHandler) Pin(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid comment id"})
+	}
+	req := new(FlagRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	comment, err := h.usecase.Pin(c.Request().Context(), id, req.Value)
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, comment)
+}
+
+// MarkAnswer godoc
+// @Summary Отметить ответ как решение (преподаватель курса)
+// @Tags Discussions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID комментария"
+// @Param request body FlagRequest true "Отметить как решение"
+// @Success 200 {object} entity.Comment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /comments/{id}/answer [post]
+func (h *CommentHandler) MarkAnswer(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid comment id"})
+	}
+	req := new(FlagRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	comment, err := h.usecase.MarkAnswer(c.Request().Context(), id, req.Value)
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, comment)
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func commentError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrCommentNotFound), errors.Is(err, usecase.ErrLessonNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrEmptyBody), errors.Is(err, usecase.ErrBodyTooLong),
+		errors.Is(err, usecase.ErrParentMismatch), errors.Is(err, usecase.ErrNotRootComment),
+		errors.Is(err, usecase.ErrNotReply):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCommentDeleted):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/discussion/entity"
+	"github.com/kostinp/edu-platform-backend/internal/discussion/repository"
+	lessonEntity "github.com/kostinp/edu-platform-backend/internal/lesson/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+)
+
+const MaxCommentLength = 10000
+
+var (
+	ErrCommentNotFound = repository.ErrCommentNotFound
+	ErrLessonNotFound  = errors.New("lesson not found")
+	ErrEmptyBody       = errors.New("comment body is empty")
+	ErrBodyTooLong     = errors.New("comment body is too long")
+	ErrParentMismatch  = errors.New("parent comment belongs to another lesson")
+	ErrCommentDeleted  = errors.New("comment is deleted")
+	ErrNotRootComment  = errors.New("only top-level comments can be pinned")
+	ErrNotReply        = errors.New("only replies can be marked as answer")
+)
+
+// LessonReader — проверка существования урока (реализуется lesson usecase);
+// уроки курсов чужих организаций не находятся
+type LessonReader interface {
+	GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*lessonEntity.Lesson, error)
+}
+
+type CommentUsecase interface {
+	// ListThreads и Create видят уроки общего каталога и курсов организации viewerOrg
+	ListThreads(ctx context.Context, lessonID uuid.UUID, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Comment, int, error)
+	Create(ctx context.Context, comment *entity.Comment, authorID uuid.UUID, viewerOrg *uuid.UUID) error
+	Edit(ctx context.Context, id, editorID uuid.UUID, body string) (*entity.Comment, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	Pin(ctx context.Context, id uuid.UUID, pinned bool) (*entity.Comment, error)
+	MarkAnswer(ctx context.Context, id uuid.UUID, isAnswer bool) (*entity.Comment, error)
+	History(ctx context.Context, id uuid.UUID) ([]*entity.CommentRevision, error)
+}
+
+type commentUsecase struct {
+	repo    repository.CommentRepository
+	lessons LessonReader
+}
+
+func NewCommentUsecase(repo repository.CommentRepository, lessons LessonReader) CommentUsecase {
+	return &commentUsecase{repo: repo, lessons: lessons}
+}
+
+// ListThreads возвращает страницу веток обсуждения с вложенными ответами
+func (u *commentUsecase) ListThreads(ctx context.Context, lessonID uuid.UUID, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Comment, int, error) {
+	roots, total, err := u.repo.ListThreads(ctx, lessonID, viewerOrg, pag)
+	if err != nil {
+		return nil, 0, err
+	}
+	rootIDs := make([]uuid.UUID, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+	replies, err := u.repo.ListReplies(ctx, rootIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buildThreads(roots, replies), total, nil
+}
+
+func (u *commentUsecase) Create(ctx context.Context, comment *entity.Comment, authorID uuid.UUID, viewerOrg *uuid.UUID) error {
+	body, err := normalizeBody(comment.Body)
+	if err != nil {
+		return err
+	}
+	if _, err := u.lessons.GetByID(ctx, comment.LessonID, viewerOrg); err != nil {
+		return ErrLessonNotFound
+	}
+	comment.RootID = nil
+	if comment.ParentID != nil {
+		parent, err := u.repo.GetByID(ctx, *comment.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.LessonID != comment.LessonID {
+			return ErrParentMismatch
+		}
+		if parent.IsDeleted() {
+			return ErrCommentDeleted
+		}
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.RootID = &rootID
+	}
+	comment.Init(authorID)
+	comment.Body = body
+	comment.IsPinned = false
+	comment.IsAnswer = false
+	comment.EditedAt = nil
+	comment.DeletedAt = nil
+	return u.repo.Create(ctx, comment)
+}
+
+func (u *commentUsecase) Edit(ctx context.Context, id, editorID uuid.UUID, body string) (*entity.Comment, error) {
+	body, err := normalizeBody(body)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, ErrCommentDeleted
+	}
+	if comment.Body == body {
+		return comment, nil
+	}
+	previous := comment.Body
+	now := time.Now().UTC()
+	comment.Body = body
+	comment.EditedAt = &now
+	comment.UpdatedAt = now
+	if err := u.repo.UpdateBody(ctx, comment, previous, editorID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (u *commentUsecase) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	return u.repo.SoftDelete(ctx, id, userID)
+}
+
+func (u *commentUsecase) Pin(ctx context.Context, id uuid.UUID, pinned bool) (*entity.Comment, error) {
+	comment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ParentID != nil {
+		return nil, ErrNotRootComment
+	}
+	if comment.IsDeleted() {
+		return nil, ErrCommentDeleted
+	}
+	if err := u.repo.SetPinned(ctx, id, pinned); err != nil {
+		return nil, err
+	}
+	comment.IsPinned = pinned
+	return comment, nil
+}
+
+func (u *commentUsecase) MarkAnswer(ctx context.Context, id uuid.UUID, isAnswer bool) (*entity.Comment, error) {
+	comment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ParentID == nil {
+		return nil, ErrNotReply
+	}
+	if comment.IsDeleted() {
+		return nil, ErrCommentDeleted
+	}
+	if err := u.repo.SetAnswer(ctx, comment, isAnswer); err != nil {
+		return nil, err
+	}
+	comment.IsAnswer = isAnswer
+	return comment, nil
+}
+
+func (u *commentUsecase) History(ctx context.Context, id uuid.UUID) ([]*entity.CommentRevision, error) {
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.repo.ListRevisions(ctx, id)
+}
+
+func normalizeBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", ErrEmptyBody
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", ErrBodyTooLong
+	}
+	return body, nil
+}
+
+// buildThreads раскладывает ответы по родителям; текст удалённых комментариев скрывается
+func buildThreads(roots, replies []*entity.Comment) []*entity.Comment {
+	byID := make(map[uuid.UUID]*entity.Comment, len(roots)+len(replies))
+	for _, c := range roots {
+		byID[c.ID] = c
+	}
+	for _, c := range replies {
+		byID[c.ID] = c
+	}
+	for _, c := range replies {
+		if parent, ok := byID[*c.ParentID]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+	for _, c := range byID {
+		if c.IsDeleted() {
+			c.Body = ""
+		}
+	}
+	return roots
+}
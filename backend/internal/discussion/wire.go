@@ -0,0 +1,20 @@
+// internal/discussion/wire.go
+package discussion
+
+import (
+	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/discussion/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/discussion/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/discussion/usecase"
+	lessonUsecase "github.com/kostinp/edu-platform-backend/internal/lesson/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+)
+
+var DiscussionSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresCommentRepository,
+	wire.Bind(new(repository.CommentRepository), new(*repository.PostgresCommentRepository)),
+	wire.Bind(new(usecase.LessonReader), new(lessonUsecase.LessonUsecase)),
+	usecase.NewCommentUsecase,
+	http.NewCommentHandler,
+)
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/entity"
+)
+
+// Group — учебная группа преподавателя (AuthorID). Студенты вступают по коду
+// или ссылке и записываются на все курсы группы
+type Group struct {
+	entity.Base
+
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	OrgID       *uuid.UUID `json:"org_id,omitempty"`
+	// Код и ссылка приглашения видны только преподавателю группы
+	JoinCode    string `json:"join_code,omitempty"`
+	JoinURL     string `json:"join_url,omitempty"`
+	JoinEnabled bool   `json:"join_enabled"`
+
+	MembersCount int `json:"members_count"`
+	CoursesCount int `json:"courses_count"`
+}
+
+// Member — студент в составе группы
+type Member struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username,omitempty"`
+	FullName string    `json:"full_name,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// JoinResult — итог вступления в группу: на сколько курсов группы участник записан впервые
+type JoinResult struct {
+	GroupID  uuid.UUID `json:"group_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Enrolled int       `json:"enrolled"`
+}
+
+// Course — курс, назначенный группе
+type Course struct {
+	CourseID   uuid.UUID  `json:"course_id"`
+	Title      string     `json:"title"`
+	Slug       string     `json:"slug"`
+	AssignedBy *uuid.UUID `json:"assigned_by,omitempty"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	// DueAt — срок прохождения; за сутки до него участникам приходит напоминание
+	DueAt *time.Time `json:"due_at,omitempty"`
+}
+
+// Assignment — итог назначения курса: сколько участников записано впервые
+type Assignment struct {
+	Course
+	Enrolled int `json:"enrolled"`
+}
+
+// CourseProgress — прогресс участника по одному курсу группы
+type CourseProgress struct {
+	CourseID         uuid.UUID  `json:"course_id"`
+	TotalLessons     int        `json:"total_lessons"`
+	CompletedLessons int        `json:"completed_lessons"`
+	Percent          int        `json:"percent"`
+	LastActivityAt   *time.Time `json:"last_activity_at,omitempty"`
+}
+
+// MemberProgress — строка дашборда: участник и его прогресс по курсам группы
+type MemberProgress struct {
+	Member
+	Courses []*CourseProgress `json:"courses"`
+	// Средний процент по курсам группы
+	AveragePercent int        `json:"average_percent"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+}
+
+// CourseSummary — сводка по курсу группы
+type CourseSummary struct {
+	Course
+	TotalLessons int `json:"total_lessons"`
+	// Участники, прошедшие все уроки
+	CompletedMembers int `json:"completed_members"`
+	// Участники, не открывавшие курс
+	NotStartedMembers int `json:"not_started_members"`
+	AveragePercent    int `json:"average_percent"`
+}
+
+// Progress — дашборд прогресса группы: сводка по всем курсам и страница участников
+type Progress struct {
+	GroupID uuid.UUID         `json:"group_id"`
+	Courses []*CourseSummary  `json:"courses"`
+	Members []*MemberProgress `json:"members"`
+	Total   int               `json:"total"`
+	Limit   int               `json:"limit"`
+	Offset  int               `json:"offset"`
+}
@@ -0,0 +1,575 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/group/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+)
+
+var (
+	ErrGroupNotFound         = errors.New("group not found")
+	ErrJoinCodeTaken         = errors.New("join code is already in use")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrAlreadyMember         = errors.New("user is already a member of this group")
+	ErrMemberNotFound        = errors.New("user is not a member of this group")
+	ErrOutsideOrganization   = errors.New("user does not belong to the group's organization")
+	ErrCourseNotFound        = errors.New("course not found")
+	ErrCourseNotAssigned     = errors.New("course is not assigned to this group")
+	ErrCourseOutsideGroupOrg = errors.New("course belongs to another organization")
+)
+
+type GroupRepository interface {
+	Create(ctx context.Context, g *entity.Group) error
+	// Update сохраняет название, описание и флаг приёма по коду
+	Update(ctx context.Context, g *entity.Group) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Group, error)
+	GetByJoinCode(ctx context.Context, code string) (*entity.Group, error)
+	SetJoinCode(ctx context.Context, id uuid.UUID, code string) error
+	// ListByAuthor — группы преподавателя, новые первыми
+	ListByAuthor(ctx context.Context, authorID uuid.UUID, pag pagination.Params) ([]*entity.Group, int, error)
+	// ListByMember — группы, в которых состоит пользователь
+	ListByMember(ctx context.Context, userID uuid.UUID) ([]*entity.Group, error)
+	GetAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
+
+	IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error)
+	ListMembers(ctx context.Context, groupID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error)
+	FindUserByEmail(ctx context.Context, email string) (uuid.UUID, error)
+	// AddMember добавляет пользователя и в той же транзакции записывает его на все курсы группы.
+	// Возвращает число новых записей на курсы
+	AddMember(ctx context.Context, groupID, userID uuid.UUID) (int, error)
+	// RemoveMember исключает участника; записи на курсы и прогресс сохраняются
+	RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error
+
+	ListCourses(ctx context.Context, groupID uuid.UUID) ([]*entity.Course, error)
+	// AssignCourse назначает курс группе и записывает на него всех участников.
+	// Повторное назначение дозаписывает тех, кого на курсе нет
+	AssignCourse(ctx context.Context, groupID, courseID, assignedBy uuid.UUID, dueAt *time.Time) (*entity.Assignment, error)
+	// UnassignCourse снимает курс с группы; уже записанные участники остаются на курсе
+	UnassignCourse(ctx context.Context, groupID, courseID uuid.UUID) error
+
+	// CourseSummaries — сводка по каждому курсу группы для дашборда
+	CourseSummaries(ctx context.Context, groupID uuid.UUID) ([]*entity.CourseSummary, error)
+	// MemberProgress — страница участников с прогрессом по каждому курсу группы
+	MemberProgress(ctx context.Context, groupID uuid.UUID, limit, offset int) ([]*entity.MemberProgress, int, error)
+}
+
+type PostgresGroupRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresGroupRepository(db *pgxpool.Pool) *PostgresGroupRepository {
+	return &PostgresGroupRepository{db: db}
+}
+
+const groupColumns = `g.id, g.name, g.description, g.author_id, g.org_id, g.join_code, g.join_enabled,
+	g.created_at, g.updated_at,
+	(SELECT COUNT(*) FROM study_group_members gm JOIN users u ON u.id = gm.user_id AND u.deleted_at IS NULL
+		WHERE gm.group_id = g.id),
+	(SELECT COUNT(*) FROM study_group_courses gc JOIN courses c ON c.id = gc.course_id AND c.deleted_at IS NULL
+		WHERE gc.group_id = g.id)`
+
+const memberColumns = `u.id, COALESCE(u.username, ''), COALESCE(u.first_name, ''), COALESCE(u.last_name, ''),
+	COALESCE(u.email, ''), gm.joined_at`
+
+// lessonsTotal — число уроков курса gc.course_id
+const lessonsTotal = `(SELECT COUNT(*) FROM lessons l JOIN modules m ON m.id = l.module_id
+	WHERE m.course_id = gc.course_id AND l.deleted_at IS NULL AND m.deleted_at IS NULL)`
+
+var groupSortFields = map[string]string{
+	"name":       "g.name",
+	"created_at": "g.created_at",
+}
+
+var memberSortFields = map[string]string{
+	"joined_at": "gm.joined_at",
+	"username":  "u.username",
+}
+
+func scanGroup(row pgx.Row) (*entity.Group, error) {
+	g := &entity.Group{}
+	err := row.Scan(&g.ID, &g.Name, &g.Description, &g.AuthorID, &g.OrgID, &g.JoinCode, &g.JoinEnabled,
+		&g.CreatedAt, &g.UpdatedAt, &g.MembersCount, &g.CoursesCount)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func collectGroups(rows pgx.Rows) ([]*entity.Group, error) {
+	defer rows.Close()
+	groups := []*entity.Group{}
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+func scanMember(row pgx.Row) (*entity.Member, error) {
+	m := &entity.Member{}
+	var firstName, lastName string
+	if err := row.Scan(&m.UserID, &m.Username, &firstName, &lastName, &m.Email, &m.JoinedAt); err != nil {
+		return nil, err
+	}
+	m.FullName = strings.TrimSpace(firstName + " " + lastName)
+	return m, nil
+}
+
+func (r *PostgresGroupRepository) Create(ctx context.Context, g *entity.Group) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO study_groups (id, name, description, author_id, org_id, join_code, join_enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, g.ID, g.Name, g.Description, g.AuthorID, g.OrgID, g.JoinCode, g.JoinEnabled, g.CreatedAt, g.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrJoinCodeTaken
+	}
+	return err
+}
+
+func (r *PostgresGroupRepository) Update(ctx context.Context, g *entity.Group) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE study_groups SET name = $2, description = $3, join_enabled = $4, updated_at = $5
+		WHERE id = $1
+	`, g.ID, g.Name, g.Description, g.JoinEnabled, g.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM study_groups WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Group, error) {
+	g, err := scanGroup(r.db.QueryRow(ctx, `SELECT `+groupColumns+` FROM study_groups g WHERE g.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	return g, err
+}
+
+func (r *PostgresGroupRepository) GetByJoinCode(ctx context.Context, code string) (*entity.Group, error) {
+	g, err := scanGroup(r.db.QueryRow(ctx, `SELECT `+groupColumns+` FROM study_groups g WHERE g.join_code = $1`, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	return g, err
+}
+
+func (r *PostgresGroupRepository) SetJoinCode(ctx context.Context, id uuid.UUID, code string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE study_groups SET join_code = $2, updated_at = NOW() WHERE id = $1`, id, code)
+	if isUniqueViolation(err) {
+		return ErrJoinCodeTaken
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) ListByAuthor(ctx context.Context, authorID uuid.UUID, pag pagination.Params) ([]*entity.Group, int, error) {
+	if pag.SortBy == "" {
+		pag.SortBy = "created_at"
+		pag.Order = "desc"
+	}
+	query, args := pagination.SQLWithPagination(
+		`SELECT `+groupColumns+` FROM study_groups g WHERE g.author_id = $3`, pag, groupSortFields)
+	args = append(args, authorID)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	groups, err := collectGroups(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM study_groups WHERE author_id = $1`, authorID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (r *PostgresGroupRepository) ListByMember(ctx context.Context, userID uuid.UUID) ([]*entity.Group, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+groupColumns+`
+		FROM study_groups g
+		JOIN study_group_members me ON me.group_id = g.id AND me.user_id = $1
+		ORDER BY me.joined_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return collectGroups(rows)
+}
+
+func (r *PostgresGroupRepository) GetAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var authorID uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT author_id FROM study_groups WHERE id = $1`, id).Scan(&authorID)
+	return authorID, err
+}
+
+func (r *PostgresGroupRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT org_id FROM study_groups WHERE id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
+
+func (r *PostgresGroupRepository) IsMember(ctx context.Context, groupID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM study_group_members WHERE group_id = $1 AND user_id = $2)
+	`, groupID, userID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresGroupRepository) ListMembers(ctx context.Context, groupID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error) {
+	if pag.SortBy == "" {
+		pag.SortBy = "joined_at"
+		pag.Order = "asc"
+	}
+	query, args := pagination.SQLWithPagination(`
+		SELECT `+memberColumns+`
+		FROM study_group_members gm
+		JOIN users u ON u.id = gm.user_id AND u.deleted_at IS NULL
+		WHERE gm.group_id = $3`, pag, memberSortFields)
+	args = append(args, groupID)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	members := []*entity.Member{}
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	total, err := r.countMembers(ctx, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return members, total, nil
+}
+
+func (r *PostgresGroupRepository) countMembers(ctx context.Context, groupID uuid.UUID) (int, error) {
+	var total int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM study_group_members gm
+		JOIN users u ON u.id = gm.user_id AND u.deleted_at IS NULL
+		WHERE gm.group_id = $1
+	`, groupID).Scan(&total)
+	return total, err
+}
+
+func (r *PostgresGroupRepository) FindUserByEmail(ctx context.Context, email string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, `
+		SELECT id FROM users WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL
+	`, email).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrUserNotFound
+	}
+	return id, err
+}
+
+func (r *PostgresGroupRepository) AddMember(ctx context.Context, groupID, userID uuid.UUID) (int, error) {
+	var enrolled int
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var sameOrg bool
+		err := tx.QueryRow(ctx, `
+			SELECT g.org_id IS NULL OR g.org_id = u.org_id
+			FROM study_groups g, users u
+			WHERE g.id = $1 AND u.id = $2 AND u.deleted_at IS NULL
+		`, groupID, userID).Scan(&sameOrg)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !sameOrg {
+			return ErrOutsideOrganization
+		}
+
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO study_group_members (group_id, user_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, groupID, userID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrAlreadyMember
+		}
+
+		tag, err = tx.Exec(ctx, `
+			INSERT INTO course_enrollments (course_id, user_id)
+			SELECT gc.course_id, $2
+			FROM study_group_courses gc
+			JOIN courses c ON c.id = gc.course_id AND c.deleted_at IS NULL
+			WHERE gc.group_id = $1
+			ON CONFLICT (course_id, user_id) DO NOTHING
+		`, groupID, userID)
+		if err != nil {
+			return err
+		}
+		enrolled = int(tag.RowsAffected())
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return enrolled, nil
+}
+
+func (r *PostgresGroupRepository) RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM study_group_members WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) ListCourses(ctx context.Context, groupID uuid.UUID) ([]*entity.Course, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT gc.course_id, c.title, c.slug, gc.assigned_by, gc.assigned_at, gc.due_at
+		FROM study_group_courses gc
+		JOIN courses c ON c.id = gc.course_id AND c.deleted_at IS NULL
+		WHERE gc.group_id = $1
+		ORDER BY gc.assigned_at
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	courses := []*entity.Course{}
+	for rows.Next() {
+		c := &entity.Course{}
+		if err := rows.Scan(&c.CourseID, &c.Title, &c.Slug, &c.AssignedBy, &c.AssignedAt, &c.DueAt); err != nil {
+			return nil, err
+		}
+		courses = append(courses, c)
+	}
+	return courses, rows.Err()
+}
+
+func (r *PostgresGroupRepository) AssignCourse(ctx context.Context, groupID, courseID, assignedBy uuid.UUID, dueAt *time.Time) (*entity.Assignment, error) {
+	a := &entity.Assignment{}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// Блокируем группу: вступающие в это время участники не пропустят новый курс
+		var visible bool
+		err := tx.QueryRow(ctx, `
+			SELECT c.org_id IS NULL OR c.org_id = g.org_id, c.title, c.slug
+			FROM study_groups g, courses c
+			WHERE g.id = $1 AND c.id = $2 AND c.deleted_at IS NULL
+			FOR UPDATE OF g
+		`, groupID, courseID).Scan(&visible, &a.Title, &a.Slug)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCourseNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !visible {
+			return ErrCourseOutsideGroupOrg
+		}
+
+		// Повторное назначение меняет срок (nil — срок снят)
+		err = tx.QueryRow(ctx, `
+			INSERT INTO study_group_courses (group_id, course_id, assigned_by, due_at) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (group_id, course_id) DO UPDATE SET due_at = EXCLUDED.due_at
+			RETURNING course_id, assigned_by, assigned_at, due_at
+		`, groupID, courseID, assignedBy, dueAt).Scan(&a.CourseID, &a.AssignedBy, &a.AssignedAt, &a.DueAt)
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO course_enrollments (course_id, user_id)
+			SELECT $2, gm.user_id
+			FROM study_group_members gm
+			JOIN users u ON u.id = gm.user_id AND u.deleted_at IS NULL
+			WHERE gm.group_id = $1
+			ON CONFLICT (course_id, user_id) DO NOTHING
+		`, groupID, courseID)
+		if err != nil {
+			return err
+		}
+		a.Enrolled = int(tag.RowsAffected())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *PostgresGroupRepository) UnassignCourse(ctx context.Context, groupID, courseID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM study_group_courses WHERE group_id = $1 AND course_id = $2`, groupID, courseID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCourseNotAssigned
+	}
+	return nil
+}
+
+func (r *PostgresGroupRepository) CourseSummaries(ctx context.Context, groupID uuid.UUID) ([]*entity.CourseSummary, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH group_courses AS (
+			SELECT gc.course_id, c.title, c.slug, gc.assigned_by, gc.assigned_at, gc.due_at, `+lessonsTotal+` AS total
+			FROM study_group_courses gc
+			JOIN courses c ON c.id = gc.course_id AND c.deleted_at IS NULL
+			WHERE gc.group_id = $1
+		), member_courses AS (
+			SELECT gc.course_id, gc.total,
+				(SELECT COUNT(*) FROM lesson_progress p JOIN lessons l ON l.id = p.lesson_id AND l.deleted_at IS NULL
+					WHERE p.user_id = gm.user_id AND p.course_id = gc.course_id AND p.completed_at IS NOT NULL) AS completed,
+				EXISTS (SELECT 1 FROM lesson_progress p WHERE p.user_id = gm.user_id AND p.course_id = gc.course_id) AS started
+			FROM group_courses gc
+			JOIN study_group_members gm ON gm.group_id = $1
+			JOIN users u ON u.id = gm.user_id AND u.deleted_at IS NULL
+		)
+		SELECT gc.course_id, gc.title, gc.slug, gc.assigned_by, gc.assigned_at, gc.due_at, gc.total,
+			COUNT(mc.course_id) FILTER (WHERE gc.total > 0 AND mc.completed >= gc.total),
+			COUNT(mc.course_id) FILTER (WHERE NOT mc.started),
+			COALESCE(ROUND(AVG(CASE WHEN gc.total > 0 THEN mc.completed * 100 / gc.total ELSE 0 END)), 0)::int
+		FROM group_courses gc
+		LEFT JOIN member_courses mc ON mc.course_id = gc.course_id
+		GROUP BY gc.course_id, gc.title, gc.slug, gc.assigned_by, gc.assigned_at, gc.due_at, gc.total
+		ORDER BY gc.assigned_at
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	summaries := []*entity.CourseSummary{}
+	for rows.Next() {
+		s := &entity.CourseSummary{}
+		err := rows.Scan(&s.CourseID, &s.Title, &s.Slug, &s.AssignedBy, &s.AssignedAt, &s.DueAt, &s.TotalLessons,
+			&s.CompletedMembers, &s.NotStartedMembers, &s.AveragePercent)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+func (r *PostgresGroupRepository) MemberProgress(ctx context.Context, groupID uuid.UUID, limit, offset int) ([]*entity.MemberProgress, int, error) {
+	// Одна строка на пару участник × курс; участники без курсов — с пустым курсом
+	rows, err := r.db.Query(ctx, `
+		WITH page AS (
+			SELECT u.id, COALESCE(u.username, '') AS username, COALESCE(u.first_name, '') AS first_name,
+				COALESCE(u.last_name, '') AS last_name, COALESCE(u.email, '') AS email, gm.joined_at
+			FROM study_group_members gm
+			JOIN users u ON u.id = gm.user_id AND u.deleted_at IS NULL
+			WHERE gm.group_id = $1
+			ORDER BY gm.joined_at, gm.user_id
+			LIMIT $2 OFFSET $3
+		), group_courses AS (
+			SELECT gc.course_id, gc.assigned_at, `+lessonsTotal+` AS total
+			FROM study_group_courses gc
+			JOIN courses c ON c.id = gc.course_id AND c.deleted_at IS NULL
+			WHERE gc.group_id = $1
+		)
+		SELECT page.id, page.username, page.first_name, page.last_name, page.email, page.joined_at, gc.course_id, COALESCE(gc.total, 0),
+			(SELECT COUNT(*) FROM lesson_progress p JOIN lessons l ON l.id = p.lesson_id AND l.deleted_at IS NULL
+				WHERE p.user_id = page.id AND p.course_id = gc.course_id AND p.completed_at IS NOT NULL),
+			(SELECT MAX(p.last_viewed_at) FROM lesson_progress p WHERE p.user_id = page.id AND p.course_id = gc.course_id)
+		FROM page
+		LEFT JOIN group_courses gc ON TRUE
+		ORDER BY page.joined_at, page.id, gc.assigned_at
+	`, groupID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []*entity.MemberProgress{}
+	var current *entity.MemberProgress
+	for rows.Next() {
+		var (
+			m                entity.Member
+			firstName        string
+			lastName         string
+			courseID         *uuid.UUID
+			total, completed int
+			lastActivityAt   *time.Time
+		)
+		err := rows.Scan(&m.UserID, &m.Username, &firstName, &lastName, &m.Email, &m.JoinedAt,
+			&courseID, &total, &completed, &lastActivityAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		if current == nil || current.UserID != m.UserID {
+			m.FullName = strings.TrimSpace(firstName + " " + lastName)
+			current = &entity.MemberProgress{Member: m, Courses: []*entity.CourseProgress{}}
+			items = append(items, current)
+		}
+		if courseID == nil {
+			continue
+		}
+		cp := &entity.CourseProgress{
+			CourseID:         *courseID,
+			TotalLessons:     total,
+			CompletedLessons: completed,
+			LastActivityAt:   lastActivityAt,
+		}
+		if total > 0 {
+			cp.Percent = completed * 100 / total
+		}
+		current.Courses = append(current.Courses, cp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	total, err := r.countMembers(ctx, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
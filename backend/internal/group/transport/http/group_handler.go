@@ -0,0 +1,498 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/group/entity"
+	"github.com/kostinp/edu-platform-backend/internal/group/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/labstack/echo/v4"
+)
+
+type GroupHandler struct {
+	usecase usecase.GroupUsecase
+}
+
+func NewGroupHandler(uc usecase.GroupUsecase) *GroupHandler {
+	return &GroupHandler{usecase: uc}
+}
+
+// CreateGroup godoc
+// @Summary Создать учебную группу
+// @Description Преподавателям. Группа участника организации принимает только её участников.
+// @Description В ответе — код и ссылка-приглашение для студентов
+// @Tags groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body usecase.GroupInput true "Группа"
+// @Success 201 {object} entity.Group
+// @Failure 400 {object} map[string]string
+// @Router /groups [post]
+func (h *GroupHandler) Create(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.GroupInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	g, err := h.usecase.Create(c.Request().Context(), userID, middleware.CurrentOrgID(c), req)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusCreated, g)
+}
+
+// ListOwnedGroups godoc
+// @Summary Мои группы (преподаватель)
+// @Tags groups
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param sort_by query string false "name, created_at"
+// @Param order query string false "asc / desc"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Group]
+// @Router /groups [get]
+func (h *GroupHandler) ListOwned(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	groups, total, err := h.usecase.ListOwned(c.Request().Context(), userID, pag)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Group]{
+		Items:  groups,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// ListJoinedGroups godoc
+// @Summary Группы, в которых я учусь
+// @Tags groups
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.Group
+// @Router /me/groups [get]
+func (h *GroupHandler) ListJoined(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	groups, err := h.usecase.ListJoined(c.Request().Context(), userID)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, groups)
+}
+
+// GetGroup godoc
+// @Summary Учебная группа
+// @Description Преподавателю группы и её участникам; код приглашения — только преподавателю
+// @Tags groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID группы"
+// @Success 200 {object} entity.Group
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id} [get]
+func (h *GroupHandler) Get(c echo.Context) error {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	g, err := h.usecase.Get(c.Request().Context(), viewer, id)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, g)
+}
+
+// UpdateGroup godoc
+// @Summary Изменить группу
+// @Description join_enabled=false закрывает вступление по коду и ссылке
+// @Tags groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID группы"
+// @Param request body usecase.GroupInput true "Группа"
+// @Success 200 {object} entity.Group
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id} [put]
+func (h *GroupHandler) Update(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	var req usecase.GroupInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	g, err := h.usecase.Update(c.Request().Context(), id, req)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, g)
+}
+
+// DeleteGroup godoc
+// @Summary Удалить группу
+// @Description Участники остаются записанными на курсы группы
+// @Tags groups
+// @Security BearerAuth
+// @Param id path string true "ID группы"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id} [delete]
+func (h *GroupHandler) Delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	if err := h.usecase.Delete(c.Request().Context(), id); err != nil {
+		return groupError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RegenerateJoinCode godoc
+// @Summary Новый код приглашения
+// @Description Старый код и ссылки с ним перестают работать
+// @Tags groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID группы"
+// @Success 200 {object} entity.Group
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id}/join-code [post]
+func (h *GroupHandler) RegenerateJoinCode(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	g, err := h.usecase.RegenerateJoinCode(c.Request().Context(), id)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, g)
+}
+
+// JoinGroup godoc
+// @Summary Вступить в группу по коду
+// @Description Код из ссылки-приглашения или от преподавателя (регистр, пробелы и дефисы не важны).
+// @Description Студент сразу записывается на все курсы группы
+// @Tags groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body usecase.JoinInput true "Код"
+// @Success 201 {object} entity.JoinResult
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /groups/join [post]
+func (h *GroupHandler) Join(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.JoinInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	result, err := h.usecase.Join(c.Request().Context(), userID, req)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// LeaveGroup godoc
+// @Summary Выйти из группы
+// @Description Записи на курсы и прогресс сохраняются
+// @Tags groups
+// @Security BearerAuth
+// @Param id path string true "ID группы"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /me/groups/{id} [delete]
+func (h *GroupHandler) Leave(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	if err := h.usecase.Leave(c.Request().Context(), id, userID); err != nil {
+		return groupError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListGroupMembers godoc
+// @Summary Состав группы
+// @Tags groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID группы"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param sort_by query string false "joined_at, username"
+// @Param order query string false "asc / desc"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Member]
+// @Router /groups/{id}/members [get]
+func (h *GroupHandler) ListMembers(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	members, total, err := h.usecase.ListMembers(c.Request().Context(), id, pag)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Member]{
+		Items:  members,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// AddGroupMember godoc
+// @Summary Добавить студента в группу
+// @Description По id или email; студент записывается на все курсы группы
+// @Tags groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID группы"
+// @Param request body usecase.AddMemberInput true "Студент"
+// @Success 201 {object} entity.JoinResult
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /groups/{id}/members [post]
+func (h *GroupHandler) AddMember(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	var req usecase.AddMemberInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	result, err := h.usecase.AddMember(c.Request().Context(), id, req)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// RemoveGroupMember godoc
+// @Summary Исключить студента из группы
+// @Description Записи на курсы и прогресс сохраняются
+// @Tags groups
+// @Security BearerAuth
+// @Param id path string true "ID группы"
+// @Param user_id path string true "ID пользователя"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id}/members/{user_id} [delete]
+func (h *GroupHandler) RemoveMember(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user id"})
+	}
+	if err := h.usecase.RemoveMember(c.Request().Context(), id, userID); err != nil {
+		return groupError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListGroupCourses godoc
+// @Summary Курсы группы
+// @Description Преподавателю и участникам группы
+// @Tags groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID группы"
+// @Success 200 {array} entity.Course
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id}/courses [get]
+func (h *GroupHandler) ListCourses(c echo.Context) error {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	// Та же проверка доступа, что и у карточки группы
+	if _, err := h.usecase.Get(c.Request().Context(), viewer, id); err != nil {
+		return groupError(c, err)
+	}
+	courses, err := h.usecase.ListCourses(c.Request().Context(), id)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, courses)
+}
+
+// AssignGroupCourse godoc
+// @Summary Назначить курс группе
+// @Description Все участники записываются на курс; новые участники — при вступлении.
+// @Description Со сроком due_at участники, не прошедшие курс, получают напоминание в Telegram за сутки.
+// @Description Повторное назначение меняет срок и дозаписывает тех, кого на курсе ещё нет
+// @Tags groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID группы"
+// @Param request body usecase.AssignCourseInput true "Курс"
+// @Success 200 {object} entity.Assignment
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id}/courses [post]
+func (h *GroupHandler) AssignCourse(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	var req usecase.AssignCourseInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	assignment, err := h.usecase.AssignCourse(c.Request().Context(), id, userID, req)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, assignment)
+}
+
+// UnassignGroupCourse godoc
+// @Summary Снять курс с группы
+// @Description Уже записанные участники остаются на курсе
+// @Tags groups
+// @Security BearerAuth
+// @Param id path string true "ID группы"
+// @Param course_id path string true "ID курса"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id}/courses/{course_id} [delete]
+func (h *GroupHandler) UnassignCourse(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	courseID, err := uuid.Parse(c.Param("course_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid course id"})
+	}
+	if err := h.usecase.UnassignCourse(c.Request().Context(), id, courseID); err != nil {
+		return groupError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GroupProgress godoc
+// @Summary Прогресс группы
+// @Description Сводка по каждому курсу группы и страница участников с прогрессом по курсам
+// @Tags groups
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID группы"
+// @Param limit query int false "Участников на странице (до 200)" default(50)
+// @Param offset query int false "Offset"
+// @Success 200 {object} entity.Progress
+// @Failure 404 {object} map[string]string
+// @Router /groups/{id}/progress [get]
+func (h *GroupHandler) Progress(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid group id"})
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	progress, err := h.usecase.Progress(c.Request().Context(), id, limit, offset)
+	if err != nil {
+		return groupError(c, err)
+	}
+	return c.JSON(http.StatusOK, progress)
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get(middleware.UserIDKey).(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func currentViewer(c echo.Context) (usecase.Viewer, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return usecase.Viewer{}, false
+	}
+	user, _ := c.Get(middleware.UserKey).(*userEntity.User)
+	return usecase.Viewer{
+		UserID:        userID,
+		PlatformAdmin: user != nil && user.Role == userEntity.RoleAdmin,
+	}, true
+}
+
+func groupError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrGroupName),
+		errors.Is(err, usecase.ErrGroupDescription),
+		errors.Is(err, usecase.ErrInvalidJoinCode),
+		errors.Is(err, usecase.ErrMemberRequired),
+		errors.Is(err, usecase.ErrDueInPast):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrOutsideOrganization),
+		errors.Is(err, usecase.ErrCourseOutsideGroupOrg),
+		errors.Is(err, usecase.ErrJoinOwnGroup):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrGroupNotFound),
+		errors.Is(err, usecase.ErrUserNotFound),
+		errors.Is(err, usecase.ErrMemberNotFound),
+		errors.Is(err, usecase.ErrCourseNotFound),
+		errors.Is(err, usecase.ErrCourseNotAssigned):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAlreadyMember):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
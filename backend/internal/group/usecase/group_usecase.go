@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/group/entity"
+	"github.com/kostinp/edu-platform-backend/internal/group/repository"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+)
+
+var (
+	ErrGroupNotFound         = repository.ErrGroupNotFound
+	ErrUserNotFound          = repository.ErrUserNotFound
+	ErrAlreadyMember         = repository.ErrAlreadyMember
+	ErrMemberNotFound        = repository.ErrMemberNotFound
+	ErrOutsideOrganization   = repository.ErrOutsideOrganization
+	ErrCourseNotFound        = repository.ErrCourseNotFound
+	ErrCourseNotAssigned     = repository.ErrCourseNotAssigned
+	ErrCourseOutsideGroupOrg = repository.ErrCourseOutsideGroupOrg
+	ErrGroupName             = errors.New("group name must be between 2 and 200 characters")
+	ErrGroupDescription      = errors.New("group description must be at most 2000 characters")
+	ErrInvalidJoinCode       = errors.New("join code is invalid or joining is disabled")
+	ErrJoinOwnGroup          = errors.New("teacher cannot join their own group")
+	ErrMemberRequired        = errors.New("user_id or email is required")
+	ErrDueInPast             = errors.New("due_at must be in the future")
+)
+
+const (
+	groupNameMinLen        = 2
+	groupNameMaxLen        = 200
+	groupDescriptionMaxLen = 2000
+	joinCodeLen            = 8
+	joinCodeAttempts       = 5
+	progressDefaultLimit   = 50
+	progressMaxLimit       = 200
+)
+
+// Без похожих символов (0/O, 1/I/L), чтобы код было легко продиктовать на уроке
+const joinCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// JoinLinkBaseURL — адрес фронтенда для ссылок-приглашений в группу
+type JoinLinkBaseURL string
+
+// GroupInput — название и описание группы; JoinEnabled — принимать ли по коду (по умолчанию да)
+type GroupInput struct {
+	Name        string `json:"name" example:"10 «Б» — информатика"`
+	Description string `json:"description,omitempty" example:"Дополнительные занятия по четвергам"`
+	JoinEnabled *bool  `json:"join_enabled,omitempty"`
+}
+
+// JoinInput — код приглашения из ссылки или от преподавателя
+type JoinInput struct {
+	Code string `json:"code" example:"K7QM3XPA"`
+}
+
+// AddMemberInput — студент по id или email
+type AddMemberInput struct {
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	Email  string     `json:"email,omitempty" example:"student@school57.ru"`
+}
+
+// AssignCourseInput — курс для всей группы; DueAt — необязательный срок прохождения
+type AssignCourseInput struct {
+	CourseID uuid.UUID  `json:"course_id"`
+	DueAt    *time.Time `json:"due_at,omitempty" example:"2026-11-01T18:00:00Z"`
+}
+
+// Viewer — кто смотрит группу: код приглашения видят только её преподаватель и администраторы
+type Viewer struct {
+	UserID        uuid.UUID
+	PlatformAdmin bool
+}
+
+type GroupUsecase interface {
+	Create(ctx context.Context, authorID uuid.UUID, orgID *uuid.UUID, in GroupInput) (*entity.Group, error)
+	Update(ctx context.Context, id uuid.UUID, in GroupInput) (*entity.Group, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Get — карточка группы для преподавателя, администратора или участника; остальным ErrGroupNotFound
+	Get(ctx context.Context, viewer Viewer, id uuid.UUID) (*entity.Group, error)
+	ListOwned(ctx context.Context, authorID uuid.UUID, pag pagination.Params) ([]*entity.Group, int, error)
+	ListJoined(ctx context.Context, userID uuid.UUID) ([]*entity.Group, error)
+	// RegenerateJoinCode выдаёт новый код; старые ссылки перестают работать
+	RegenerateJoinCode(ctx context.Context, id uuid.UUID) (*entity.Group, error)
+
+	Join(ctx context.Context, userID uuid.UUID, in JoinInput) (*entity.JoinResult, error)
+	Leave(ctx context.Context, groupID, userID uuid.UUID) error
+	ListMembers(ctx context.Context, groupID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error)
+	AddMember(ctx context.Context, groupID uuid.UUID, in AddMemberInput) (*entity.JoinResult, error)
+	RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error
+
+	ListCourses(ctx context.Context, groupID uuid.UUID) ([]*entity.Course, error)
+	AssignCourse(ctx context.Context, groupID, assignedBy uuid.UUID, in AssignCourseInput) (*entity.Assignment, error)
+	UnassignCourse(ctx context.Context, groupID, courseID uuid.UUID) error
+	Progress(ctx context.Context, groupID uuid.UUID, limit, offset int) (*entity.Progress, error)
+}
+
+type groupUsecase struct {
+	repo     repository.GroupRepository
+	joinBase string
+}
+
+func NewGroupUsecase(repo repository.GroupRepository, joinBase JoinLinkBaseURL) GroupUsecase {
+	return &groupUsecase{repo: repo, joinBase: strings.TrimRight(string(joinBase), "/")}
+}
+
+func (u *groupUsecase) Create(ctx context.Context, authorID uuid.UUID, orgID *uuid.UUID, in GroupInput) (*entity.Group, error) {
+	g := &entity.Group{OrgID: orgID, JoinEnabled: true}
+	if err := applyGroupInput(g, in); err != nil {
+		return nil, err
+	}
+	g.Init(authorID)
+
+	// Коды случайные, но уникальность всё равно проверяет база
+	for attempt := 0; ; attempt++ {
+		code, err := newJoinCode()
+		if err != nil {
+			return nil, err
+		}
+		g.JoinCode = code
+		err = u.repo.Create(ctx, g)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, repository.ErrJoinCodeTaken) || attempt == joinCodeAttempts-1 {
+			return nil, err
+		}
+	}
+	return u.withJoinURL(g), nil
+}
+
+func (u *groupUsecase) Update(ctx context.Context, id uuid.UUID, in GroupInput) (*entity.Group, error) {
+	g, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyGroupInput(g, in); err != nil {
+		return nil, err
+	}
+	g.Touch()
+	if err := u.repo.Update(ctx, g); err != nil {
+		return nil, err
+	}
+	return u.withJoinURL(g), nil
+}
+
+func (u *groupUsecase) Delete(ctx context.Context, id uuid.UUID) error {
+	return u.repo.Delete(ctx, id)
+}
+
+func (u *groupUsecase) Get(ctx context.Context, viewer Viewer, id uuid.UUID) (*entity.Group, error) {
+	g, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if g.AuthorID == viewer.UserID || viewer.PlatformAdmin {
+		return u.withJoinURL(g), nil
+	}
+	member, err := u.repo.IsMember(ctx, id, viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrGroupNotFound
+	}
+	return hideJoinCode(g), nil
+}
+
+func (u *groupUsecase) ListOwned(ctx context.Context, authorID uuid.UUID, pag pagination.Params) ([]*entity.Group, int, error) {
+	groups, total, err := u.repo.ListByAuthor(ctx, authorID, pag)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, g := range groups {
+		u.withJoinURL(g)
+	}
+	return groups, total, nil
+}
+
+func (u *groupUsecase) ListJoined(ctx context.Context, userID uuid.UUID) ([]*entity.Group, error) {
+	groups, err := u.repo.ListByMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		hideJoinCode(g)
+	}
+	return groups, nil
+}
+
+func (u *groupUsecase) RegenerateJoinCode(ctx context.Context, id uuid.UUID) (*entity.Group, error) {
+	for attempt := 0; ; attempt++ {
+		code, err := newJoinCode()
+		if err != nil {
+			return nil, err
+		}
+		err = u.repo.SetJoinCode(ctx, id, code)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, repository.ErrJoinCodeTaken) || attempt == joinCodeAttempts-1 {
+			return nil, err
+		}
+	}
+	g, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.withJoinURL(g), nil
+}
+
+func (u *groupUsecase) Join(ctx context.Context, userID uuid.UUID, in JoinInput) (*entity.JoinResult, error) {
+	code := normalizeJoinCode(in.Code)
+	if len(code) != joinCodeLen {
+		return nil, ErrInvalidJoinCode
+	}
+	g, err := u.repo.GetByJoinCode(ctx, code)
+	if errors.Is(err, repository.ErrGroupNotFound) {
+		return nil, ErrInvalidJoinCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !g.JoinEnabled {
+		return nil, ErrInvalidJoinCode
+	}
+	if g.AuthorID == userID {
+		return nil, ErrJoinOwnGroup
+	}
+	enrolled, err := u.repo.AddMember(ctx, g.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.JoinResult{GroupID: g.ID, UserID: userID, Enrolled: enrolled}, nil
+}
+
+func (u *groupUsecase) Leave(ctx context.Context, groupID, userID uuid.UUID) error {
+	return u.repo.RemoveMember(ctx, groupID, userID)
+}
+
+func (u *groupUsecase) ListMembers(ctx context.Context, groupID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error) {
+	return u.repo.ListMembers(ctx, groupID, pag)
+}
+
+func (u *groupUsecase) AddMember(ctx context.Context, groupID uuid.UUID, in AddMemberInput) (*entity.JoinResult, error) {
+	var userID uuid.UUID
+	switch {
+	case in.UserID != nil:
+		userID = *in.UserID
+	case strings.TrimSpace(in.Email) != "":
+		id, err := u.repo.FindUserByEmail(ctx, strings.TrimSpace(in.Email))
+		if err != nil {
+			return nil, err
+		}
+		userID = id
+	default:
+		return nil, ErrMemberRequired
+	}
+
+	g, err := u.repo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if g.AuthorID == userID {
+		return nil, ErrJoinOwnGroup
+	}
+	enrolled, err := u.repo.AddMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.JoinResult{GroupID: groupID, UserID: userID, Enrolled: enrolled}, nil
+}
+
+func (u *groupUsecase) RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	return u.repo.RemoveMember(ctx, groupID, userID)
+}
+
+func (u *groupUsecase) ListCourses(ctx context.Context, groupID uuid.UUID) ([]*entity.Course, error) {
+	return u.repo.ListCourses(ctx, groupID)
+}
+
+func (u *groupUsecase) AssignCourse(ctx context.Context, groupID, assignedBy uuid.UUID, in AssignCourseInput) (*entity.Assignment, error) {
+	if in.CourseID == uuid.Nil {
+		return nil, ErrCourseNotFound
+	}
+	if in.DueAt != nil {
+		if !in.DueAt.After(time.Now()) {
+			return nil, ErrDueInPast
+		}
+		dueAt := in.DueAt.UTC()
+		in.DueAt = &dueAt
+	}
+	return u.repo.AssignCourse(ctx, groupID, in.CourseID, assignedBy, in.DueAt)
+}
+
+func (u *groupUsecase) UnassignCourse(ctx context.Context, groupID, courseID uuid.UUID) error {
+	return u.repo.UnassignCourse(ctx, groupID, courseID)
+}
+
+func (u *groupUsecase) Progress(ctx context.Context, groupID uuid.UUID, limit, offset int) (*entity.Progress, error) {
+	if limit <= 0 {
+		limit = progressDefaultLimit
+	}
+	if limit > progressMaxLimit {
+		limit = progressMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	courses, err := u.repo.CourseSummaries(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	members, total, err := u.repo.MemberProgress(ctx, groupID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if len(m.Courses) == 0 {
+			continue
+		}
+		sum := 0
+		for _, c := range m.Courses {
+			sum += c.Percent
+			if c.LastActivityAt != nil && (m.LastActivityAt == nil || c.LastActivityAt.After(*m.LastActivityAt)) {
+				m.LastActivityAt = c.LastActivityAt
+			}
+		}
+		m.AveragePercent = sum / len(m.Courses)
+	}
+	return &entity.Progress{
+		GroupID: groupID,
+		Courses: courses,
+		Members: members,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}, nil
+}
+
+func (u *groupUsecase) withJoinURL(g *entity.Group) *entity.Group {
+	if u.joinBase != "" {
+		g.JoinURL = u.joinBase + "/groups/join/" + g.JoinCode
+	}
+	return g
+}
+
+func hideJoinCode(g *entity.Group) *entity.Group {
+	g.JoinCode = ""
+	g.JoinURL = ""
+	return g
+}
+
+func applyGroupInput(g *entity.Group, in GroupInput) error {
+	name := strings.TrimSpace(in.Name)
+	if n := utf8.RuneCountInString(name); n < groupNameMinLen || n > groupNameMaxLen {
+		return ErrGroupName
+	}
+	description := strings.TrimSpace(in.Description)
+	if utf8.RuneCountInString(description) > groupDescriptionMaxLen {
+		return ErrGroupDescription
+	}
+	g.Name = name
+	g.Description = description
+	if in.JoinEnabled != nil {
+		g.JoinEnabled = *in.JoinEnabled
+	}
+	return nil
+}
+
+func newJoinCode() (string, error) {
+	raw := make([]byte, joinCodeLen)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := make([]byte, joinCodeLen)
+	for i, b := range raw {
+		code[i] = joinCodeAlphabet[int(b)%len(joinCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// normalizeJoinCode принимает код в любом регистре, с пробелами и дефисами
+func normalizeJoinCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, code)
+}
@@ -0,0 +1,25 @@
+// internal/group/wire.go
+package group
+
+import (
+	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/group/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/group/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/group/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/config"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+)
+
+// ProvideJoinLinkBaseURL — ссылки-приглашения ведут на тот же фронтенд, что и ссылки из писем
+func ProvideJoinLinkBaseURL(cfg *config.Config) usecase.JoinLinkBaseURL {
+	return usecase.JoinLinkBaseURL(cfg.Mail.LinkBaseURL)
+}
+
+var GroupSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresGroupRepository,
+	wire.Bind(new(repository.GroupRepository), new(*repository.PostgresGroupRepository)),
+	ProvideJoinLinkBaseURL,
+	usecase.NewGroupUsecase,
+	http.NewGroupHandler,
+)
@@ -7,20 +7,31 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/lesson/entity"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
 )
 
 type LessonRepository interface {
 	Create(ctx context.Context, lesson *entity.Lesson) error
 	Update(ctx context.Context, lesson *entity.Lesson) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	GetByID(ctx context.Context, id uuid.UUID) (*entity.Lesson, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Lesson, int, error)
+	// GetByID, GetByIDs и List видят уроки курсов общего каталога и организации viewerOrg
+	GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Lesson, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Lesson, error)
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Lesson, int, error)
+	// GetOrgID — организация курса урока (resource.org_id в ABAC); nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
 }
 
 type PostgresLessonRepository struct {
 	db *pgxpool.Pool
 }
 
+// lessonColumns и lessonCourseJoin — урок вместе с курсом, по которому проверяется организация
+const (
+	lessonColumns    = `l.id, l.module_id, l.title, l.content, l.duration, l.ordinal, l.author_id, l.created_at, l.updated_at, l.deleted_at`
+	lessonCourseJoin = `lessons l JOIN modules m ON m.id = l.module_id JOIN courses c ON c.id = m.course_id`
+)
+
 func NewPostgresLessonRepository(db *pgxpool.Pool) *PostgresLessonRepository {
 	return &PostgresLessonRepository{db: db}
 }
@@ -47,11 +58,11 @@ func (r *PostgresLessonRepository) Delete(ctx context.Context, id uuid.UUID) err
 	return err
 }
 
-func (r *PostgresLessonRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Lesson, error) {
+func (r *PostgresLessonRepository) GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Lesson, error) {
 	row := r.db.QueryRow(ctx, `
-		SELECT id, module_id, title, content, duration, ordinal, author_id, created_at, updated_at, deleted_at 		
-		FROM lessons WHERE id = $1 AND deleted_at IS NULL
-		`, id)
+		SELECT `+lessonColumns+` FROM `+lessonCourseJoin+`
+		WHERE l.id = $1 AND l.deleted_at IS NULL AND `+tenant.Visible("c.org_id", 2),
+		id, viewerOrg)
 	lesson := &entity.Lesson{}
 
 	err := row.Scan(&lesson.ID, &lesson.ModuleID, &lesson.Title, &lesson.Content, &lesson.Duration, &lesson.Ordinal, &lesson.AuthorID, &lesson.CreatedAt, &lesson.UpdatedAt, &lesson.DeletedAt)
@@ -61,11 +72,35 @@ func (r *PostgresLessonRepository) GetByID(ctx context.Context, id uuid.UUID) (*
 
 	return lesson, nil
 }
-func (r *PostgresLessonRepository) List(ctx context.Context, pag pagination.Params) ([]*entity.Lesson, int, error) {
+
+// GetByIDs загружает несколько уроков одним запросом; удалённые пропускаются
+func (r *PostgresLessonRepository) GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Lesson, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+lessonColumns+` FROM `+lessonCourseJoin+`
+		WHERE l.id = ANY($1) AND l.deleted_at IS NULL AND `+tenant.Visible("c.org_id", 2),
+		ids, viewerOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lessons []*entity.Lesson
+	for rows.Next() {
+		lesson := &entity.Lesson{}
+		err := rows.Scan(&lesson.ID, &lesson.ModuleID, &lesson.Title, &lesson.Content, &lesson.Duration, &lesson.Ordinal, &lesson.AuthorID, &lesson.CreatedAt, &lesson.UpdatedAt, &lesson.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, lesson)
+	}
+	return lessons, rows.Err()
+}
+
+func (r *PostgresLessonRepository) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Lesson, int, error) {
 	baseQuery := `
-		SELECT id, module_id, title, content, duration, ordinal, author_id, created_at, updated_at, deleted_at 		
-		FROM lessons WHERE deleted_at IS NULL`
-	query, args := pagination.SQLWithPagination(baseQuery, pag, map[string]string{"created_at": "created_at", "title": "title"})
+		SELECT ` + lessonColumns + ` FROM ` + lessonCourseJoin + `
+		WHERE l.deleted_at IS NULL AND ` + tenant.Visible("c.org_id", 3)
+	query, args := pagination.SQLWithPagination(baseQuery, pag, map[string]string{"created_at": "l.created_at", "title": "l.title"})
+	args = append(args, viewerOrg)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -83,12 +118,18 @@ func (r *PostgresLessonRepository) List(ctx context.Context, pag pagination.Para
 		lessons = append(lessons, lesson)
 	}
 
-	countQuery := `SELECT COUNT(*) FROM lessons WHERE deleted_at IS NULL`
+	countQuery := `SELECT COUNT(*) FROM ` + lessonCourseJoin + ` WHERE l.deleted_at IS NULL AND ` + tenant.Visible("c.org_id", 1)
 	var total int
-	err = r.db.QueryRow(ctx, countQuery).Scan(&total)
+	err = r.db.QueryRow(ctx, countQuery, viewerOrg).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	return lessons, total, nil
 }
+
+func (r *PostgresLessonRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT c.org_id FROM `+lessonCourseJoin+` WHERE l.id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
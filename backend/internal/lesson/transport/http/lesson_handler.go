@@ -8,6 +8,7 @@ import (
 	"github.com/kostinp/edu-platform-backend/internal/lesson/entity"
 	"github.com/kostinp/edu-platform-backend/internal/lesson/usecase"
 	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
 	"github.com/labstack/echo/v4"
 )
@@ -64,7 +65,7 @@ func (h *LessonHandler) Get(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
 	}
-	lesson, err := h.usecase.GetByID(c.Request().Context(), id)
+	lesson, err := h.usecase.GetByID(c.Request().Context(), id, middleware.CurrentOrgID(c))
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "lesson not found"})
 	}
@@ -133,7 +134,7 @@ func (h *LessonHandler) Delete(c echo.Context) error {
 func (h *LessonHandler) List(c echo.Context) error {
 	pagQuery := pagination.ParsePaginationParams(c)
 	pag := pagQuery.ToDomainParams()
-	lessons, total, err := h.usecase.List(c.Request().Context(), pag)
+	lessons, total, err := h.usecase.List(c.Request().Context(), middleware.CurrentOrgID(c), pag)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
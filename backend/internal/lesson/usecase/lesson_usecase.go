@@ -13,8 +13,10 @@ type LessonUsecase interface {
 	Create(ctx context.Context, lesson *entity.Lesson, authorID uuid.UUID) error
 	Update(ctx context.Context, lesson *entity.Lesson) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	GetByID(ctx context.Context, id uuid.UUID) (*entity.Lesson, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Lesson, int, error)
+	// GetByID, GetByIDs и List — общий каталог и курсы организации viewerOrg
+	GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Lesson, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Lesson, error)
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Lesson, int, error)
 }
 
 type lessonUsecase struct {
@@ -39,10 +41,14 @@ func (u *lessonUsecase) Delete(ctx context.Context, id uuid.UUID) error {
 	return u.repo.Delete(ctx, id)
 }
 
-func (u *lessonUsecase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Lesson, error) {
-	return u.repo.GetByID(ctx, id)
+func (u *lessonUsecase) GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Lesson, error) {
+	return u.repo.GetByID(ctx, id, viewerOrg)
 }
 
-func (u *lessonUsecase) List(ctx context.Context, pag pagination.Params) ([]*entity.Lesson, int, error) {
-	return u.repo.List(ctx, pag)
+func (u *lessonUsecase) GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Lesson, error) {
+	return u.repo.GetByIDs(ctx, ids, viewerOrg)
+}
+
+func (u *lessonUsecase) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Lesson, int, error) {
+	return u.repo.List(ctx, viewerOrg, pag)
 }
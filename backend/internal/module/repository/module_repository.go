@@ -7,20 +7,31 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/module/entity"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
 )
 
 type ModuleRepository interface {
 	Create(ctx context.Context, module *entity.Module) error
 	Update(ctx context.Context, module *entity.Module) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	GetByID(ctx context.Context, id uuid.UUID) (*entity.Module, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Module, int, error)
+	// GetByID, GetByIDs и List видят модули курсов общего каталога и организации viewerOrg
+	GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Module, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Module, error)
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Module, int, error)
+	// GetOrgID — организация курса модуля (resource.org_id в ABAC); nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
 }
 
 type PostgresModuleRepository struct {
 	db *pgxpool.Pool
 }
 
+// moduleColumns и moduleCourseJoin — модуль вместе с курсом, по которому проверяется организация
+const (
+	moduleColumns    = `m.id, m.course_id, m.title, m.description, m.ordinal, m.author_id, m.created_at, m.updated_at, m.deleted_at`
+	moduleCourseJoin = `modules m JOIN courses c ON c.id = m.course_id`
+)
+
 func NewPostgresModuleRepository(db *pgxpool.Pool) *PostgresModuleRepository {
 	return &PostgresModuleRepository{db: db}
 }
@@ -47,11 +58,12 @@ func (r *PostgresModuleRepository) Delete(ctx context.Context, id uuid.UUID) err
 	return err
 }
 
-func (r *PostgresModuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Module, error) {
+func (r *PostgresModuleRepository) GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Module, error) {
 	row := r.db.QueryRow(ctx, `
-			SELECT id, course_id, title, description, ordinal, author_id, created_at, updated_at, deleted_at
-			FROM modules WHERE id = $1 AND deleted_at IS NULL
-		`, id)
+			SELECT `+moduleColumns+`
+			FROM `+moduleCourseJoin+`
+			WHERE m.id = $1 AND m.deleted_at IS NULL AND `+tenant.Visible("c.org_id", 2),
+		id, viewerOrg)
 	module := &entity.Module{}
 	err := row.Scan(&module.ID, &module.CourseID, &module.Title, &module.Description, &module.Ordinal, &module.AuthorID, &module.CreatedAt, &module.UpdatedAt, &module.DeletedAt)
 	if err != nil {
@@ -60,11 +72,36 @@ func (r *PostgresModuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*
 	return module, nil
 }
 
-func (r *PostgresModuleRepository) List(ctx context.Context, pag pagination.Params) ([]*entity.Module, int, error) {
+// GetByIDs загружает несколько модулей одним запросом; удалённые пропускаются
+func (r *PostgresModuleRepository) GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Module, error) {
+	rows, err := r.db.Query(ctx, `
+			SELECT `+moduleColumns+`
+			FROM `+moduleCourseJoin+`
+			WHERE m.id = ANY($1) AND m.deleted_at IS NULL AND `+tenant.Visible("c.org_id", 2),
+		ids, viewerOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var modules []*entity.Module
+	for rows.Next() {
+		module := &entity.Module{}
+		err := rows.Scan(&module.ID, &module.CourseID, &module.Title, &module.Description, &module.Ordinal, &module.AuthorID, &module.CreatedAt, &module.UpdatedAt, &module.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, module)
+	}
+	return modules, rows.Err()
+}
+
+func (r *PostgresModuleRepository) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Module, int, error) {
 	baseQuery := `
-		SELECT id, course_id, title, description, ordinal, author_id, created_at, updated_at, deleted_at
-		FROM modules WHERE deleted_at IS NULL`
-	query, args := pagination.SQLWithPagination(baseQuery, pag, map[string]string{"created_at": "created_at", "title": "title"})
+		SELECT ` + moduleColumns + `
+		FROM ` + moduleCourseJoin + `
+		WHERE m.deleted_at IS NULL AND ` + tenant.Visible("c.org_id", 3)
+	query, args := pagination.SQLWithPagination(baseQuery, pag, map[string]string{"created_at": "m.created_at", "title": "m.title"})
+	args = append(args, viewerOrg)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -83,12 +120,20 @@ func (r *PostgresModuleRepository) List(ctx context.Context, pag pagination.Para
 		modules = append(modules, module)
 	}
 
-	countQuery := `SELECT COUNT(*) FROM modules WHERE deleted_at IS NULL`
+	countQuery := `
+		SELECT COUNT(*) FROM ` + moduleCourseJoin + `
+		WHERE m.deleted_at IS NULL AND ` + tenant.Visible("c.org_id", 1)
 	var total int
-	err = r.db.QueryRow(ctx, countQuery).Scan(&total)
+	err = r.db.QueryRow(ctx, countQuery, viewerOrg).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	return modules, total, nil
 }
+
+func (r *PostgresModuleRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT c.org_id FROM `+moduleCourseJoin+` WHERE m.id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
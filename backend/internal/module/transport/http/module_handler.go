@@ -8,6 +8,7 @@ import (
 	"github.com/kostinp/edu-platform-backend/internal/module/entity"
 	"github.com/kostinp/edu-platform-backend/internal/module/usecase"
 	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
 	"github.com/labstack/echo/v4"
 )
@@ -64,7 +65,7 @@ func (h *ModuleHandler) Get(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
 	}
-	module, err := h.usecase.GetByID(c.Request().Context(), id)
+	module, err := h.usecase.GetByID(c.Request().Context(), id, middleware.CurrentOrgID(c))
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "module not found"})
 	}
@@ -133,7 +134,7 @@ func (h *ModuleHandler) Delete(c echo.Context) error {
 func (h *ModuleHandler) List(c echo.Context) error {
 	pagQuery := pagination.ParsePaginationParams(c)
 	pag := pagQuery.ToDomainParams()
-	modules, total, err := h.usecase.List(c.Request().Context(), pag)
+	modules, total, err := h.usecase.List(c.Request().Context(), middleware.CurrentOrgID(c), pag)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -13,14 +13,18 @@ type ModuleUsecase interface {
 	Create(ctx context.Context, module *entity.Module, authorID uuid.UUID) error
 	Update(ctx context.Context, module *entity.Module) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	GetByID(ctx context.Context, id uuid.UUID) (*entity.Module, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Module, int, error)
+	// GetByID, GetByIDs и List — общий каталог и курсы организации viewerOrg
+	GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Module, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Module, error)
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Module, int, error)
 }
 
 type moduleUsecase struct {
 	repo repository.ModuleRepository
 }
 
+// Записанных на курс о новом модуле оповещает планировщик уведомлений
+// (notification.SendModuleReleases), а не обработчик запроса
 func NewModuleUsecase(repo repository.ModuleRepository) ModuleUsecase {
 	return &moduleUsecase{repo: repo}
 }
@@ -39,10 +43,14 @@ func (u *moduleUsecase) Delete(ctx context.Context, id uuid.UUID) error {
 	return u.repo.Delete(ctx, id)
 }
 
-func (u *moduleUsecase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Module, error) {
-	return u.repo.GetByID(ctx, id)
+func (u *moduleUsecase) GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*entity.Module, error) {
+	return u.repo.GetByID(ctx, id, viewerOrg)
 }
 
-func (u *moduleUsecase) List(ctx context.Context, pag pagination.Params) ([]*entity.Module, int, error) {
-	return u.repo.List(ctx, pag)
+func (u *moduleUsecase) GetByIDs(ctx context.Context, ids []uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Module, error) {
+	return u.repo.GetByIDs(ctx, ids, viewerOrg)
+}
+
+func (u *moduleUsecase) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Module, int, error) {
+	return u.repo.List(ctx, viewerOrg, pag)
 }
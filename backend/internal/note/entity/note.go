@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteKind string
+
+const (
+	NoteKindNote      NoteKind = "note"
+	NoteKindHighlight NoteKind = "highlight"
+)
+
+// Anchor — диапазон символов [Start, End) в тексте урока
+type Anchor struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Note — личная заметка или выделение пользователя в уроке
+type Note struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	LessonID  uuid.UUID `json:"lesson_id"`
+	Kind      NoteKind  `json:"kind"`
+	Body      string    `json:"body"`
+	Quote     string    `json:"quote,omitempty"`
+	Anchor    *Anchor   `json:"anchor,omitempty"`
+	Color     string    `json:"color,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NoteSearchResult — найденная заметка с релевантностью
+type NoteSearchResult struct {
+	Note
+	LessonTitle string  `json:"lesson_title"`
+	Rank        float64 `json:"rank"`
+}
+
+// CourseNote — заметка с контекстом курса для экспорта
+type CourseNote struct {
+	Note
+	ModuleTitle string
+	LessonTitle string
+}
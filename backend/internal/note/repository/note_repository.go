@@ -0,0 +1,219 @@
+// internal/note/repository/note_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/note/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
+)
+
+var ErrNoteNotFound = errors.New("note not found")
+
+// NoteRepository — все методы ограничены заметками указанного пользователя.
+// Списки и поиск к тому же видят только уроки курсов общего каталога и организации viewerOrg
+type NoteRepository interface {
+	Create(ctx context.Context, note *entity.Note) error
+	Update(ctx context.Context, note *entity.Note) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*entity.Note, error)
+	ListByLesson(ctx context.Context, userID, lessonID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Note, error)
+	Search(ctx context.Context, userID uuid.UUID, query string, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.NoteSearchResult, int, error)
+	ListByCourse(ctx context.Context, userID, courseID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.CourseNote, error)
+	// GetOrgID — организация курса, к уроку которого относится заметка; nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
+}
+
+type PostgresNoteRepository struct {
+	db *pgxpool.Pool
+}
+
+const noteColumns = `n.id, n.user_id, n.lesson_id, n.kind, n.body, n.quote, n.anchor_start, n.anchor_end,
+	COALESCE(n.color, ''), n.created_at, n.updated_at`
+
+// noteCourseJoin связывает заметку с курсом её урока
+const noteCourseJoin = `lesson_notes n
+	JOIN lessons l ON l.id = n.lesson_id
+	JOIN modules m ON m.id = l.module_id
+	JOIN courses c ON c.id = m.course_id`
+
+func NewPostgresNoteRepository(db *pgxpool.Pool) *PostgresNoteRepository {
+	return &PostgresNoteRepository{db: db}
+}
+
+func (r *PostgresNoteRepository) Create(ctx context.Context, n *entity.Note) error {
+	start, end := anchorValues(n.Anchor)
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO lesson_notes (id, user_id, lesson_id, kind, body, quote, anchor_start, anchor_end, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11)
+	`, n.ID, n.UserID, n.LessonID, n.Kind, n.Body, n.Quote, start, end, n.Color, n.CreatedAt, n.UpdatedAt)
+	return err
+}
+
+func (r *PostgresNoteRepository) Update(ctx context.Context, n *entity.Note) error {
+	start, end := anchorValues(n.Anchor)
+	cmdTag, err := r.db.Exec(ctx, `
+		UPDATE lesson_notes
+		SET body = $1, quote = $2, anchor_start = $3, anchor_end = $4, color = NULLIF($5, ''), updated_at = $6
+		WHERE id = $7 AND user_id = $8
+	`, n.Body, n.Quote, start, end, n.Color, n.UpdatedAt, n.ID, n.UserID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNoteRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM lesson_notes WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNoteRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*entity.Note, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+noteColumns+` FROM lesson_notes n WHERE n.id = $1 AND n.user_id = $2
+	`, id, userID)
+	note, err := scanNote(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoteNotFound
+	}
+	return note, err
+}
+
+func (r *PostgresNoteRepository) ListByLesson(ctx context.Context, userID, lessonID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Note, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+noteColumns+` FROM `+noteCourseJoin+`
+		WHERE n.user_id = $1 AND n.lesson_id = $2 AND `+tenant.Visible("c.org_id", 3)+`
+		ORDER BY n.anchor_start NULLS LAST, n.created_at
+	`, userID, lessonID, viewerOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	notes := []*entity.Note{}
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// Search — полнотекстовый поиск по своим заметкам (русская морфология)
+func (r *PostgresNoteRepository) Search(ctx context.Context, userID uuid.UUID, query string, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.NoteSearchResult, int, error) {
+	pag.Normalize()
+	rows, err := r.db.Query(ctx, `
+		SELECT `+noteColumns+`, l.title, ts_rank(n.search_vector, q)::float8 AS rank
+		FROM `+noteCourseJoin+`,
+		     websearch_to_tsquery('russian', $2) q
+		WHERE n.user_id = $1 AND n.search_vector @@ q AND `+tenant.Visible("c.org_id", 5)+`
+		ORDER BY rank DESC, n.updated_at DESC
+		LIMIT $3 OFFSET $4
+	`, userID, query, pag.Limit, pag.Offset, viewerOrg)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	results := []*entity.NoteSearchResult{}
+	for rows.Next() {
+		res := &entity.NoteSearchResult{}
+		var start, end *int
+		err := rows.Scan(
+			&res.ID, &res.UserID, &res.LessonID, &res.Kind, &res.Body, &res.Quote, &start, &end,
+			&res.Color, &res.CreatedAt, &res.UpdatedAt, &res.LessonTitle, &res.Rank,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		res.Anchor = anchorFromValues(start, end)
+		results = append(results, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM `+noteCourseJoin+`
+		WHERE n.user_id = $1 AND n.search_vector @@ websearch_to_tsquery('russian', $2) AND `+tenant.Visible("c.org_id", 3),
+		userID, query, viewerOrg).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// ListByCourse возвращает заметки пользователя по курсу в порядке прохождения
+func (r *PostgresNoteRepository) ListByCourse(ctx context.Context, userID, courseID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.CourseNote, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+noteColumns+`, m.title, l.title
+		FROM `+noteCourseJoin+`
+		WHERE n.user_id = $1 AND c.id = $2 AND l.deleted_at IS NULL AND m.deleted_at IS NULL
+			AND `+tenant.Visible("c.org_id", 3)+`
+		ORDER BY m.ordinal, l.ordinal, n.anchor_start NULLS LAST, n.created_at
+	`, userID, courseID, viewerOrg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	notes := []*entity.CourseNote{}
+	for rows.Next() {
+		cn := &entity.CourseNote{}
+		var start, end *int
+		err := rows.Scan(
+			&cn.ID, &cn.UserID, &cn.LessonID, &cn.Kind, &cn.Body, &cn.Quote, &start, &end,
+			&cn.Color, &cn.CreatedAt, &cn.UpdatedAt, &cn.ModuleTitle, &cn.LessonTitle,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cn.Anchor = anchorFromValues(start, end)
+		notes = append(notes, cn)
+	}
+	return notes, rows.Err()
+}
+
+func (r *PostgresNoteRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT c.org_id FROM `+noteCourseJoin+` WHERE n.id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
+
+func scanNote(row pgx.Row) (*entity.Note, error) {
+	n := &entity.Note{}
+	var start, end *int
+	err := row.Scan(&n.ID, &n.UserID, &n.LessonID, &n.Kind, &n.Body, &n.Quote, &start, &end, &n.Color, &n.CreatedAt, &n.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	n.Anchor = anchorFromValues(start, end)
+	return n, nil
+}
+
+func anchorValues(a *entity.Anchor) (*int, *int) {
+	if a == nil {
+		return nil, nil
+	}
+	return &a.Start, &a.End
+}
+
+func anchorFromValues(start, end *int) *entity.Anchor {
+	if start == nil || end == nil {
+		return nil
+	}
+	return &entity.Anchor{Start: *start, End: *end}
+}
@@ -0,0 +1,243 @@
+// internal/note/transport/http/note_handler.go
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/note/entity"
+	"github.com/kostinp/edu-platform-backend/internal/note/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type NoteHandler struct {
+	usecase usecase.NoteUsecase
+}
+
+func NewNoteHandler(uc usecase.NoteUsecase) *NoteHandler {
+	return &NoteHandler{usecase: uc}
+}
+
+// CreateNoteRequest — заметка или выделение; для выделения anchor обязателен
+type CreateNoteRequest struct {
+	Kind   entity.NoteKind `json:"kind" example:"highlight"`
+	Body   string          `json:"body" example:"Запомнить: срезы ссылаются на массив"`
+	Anchor *entity.Anchor  `json:"anchor,omitempty"`
+	Color  string          `json:"color,omitempty" example:"yellow"`
+}
+
+// UpdateNoteRequest — новый текст, цвет и (опционально) якорь
+type UpdateNoteRequest struct {
+	Body   string         `json:"body"`
+	Anchor *entity.Anchor `json:"anchor,omitempty"`
+	Color  string         `json:"color,omitempty"`
+}
+
+// ListNotes godoc
+// @Summary Мои заметки к уроку
+// @Tags Notes
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID урока"
+// @Success 200 {array} entity.Note
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /lessons/{id}/notes [get]
+func (h *NoteHandler) ListByLesson(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	lessonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid lesson id"})
+	}
+	notes, err := h.usecase.ListByLesson(c.Request().Context(), userID, lessonID, middleware.CurrentOrgID(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, notes)
+}
+
+// CreateNote godoc
+// @Summary Создать заметку или выделение в уроке
+// @Tags Notes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID урока"
+// @Param request body CreateNoteRequest true "Заметка"
+// @Success 201 {object} entity.Note
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /lessons/{id}/notes [post]
+func (h *NoteHandler) Create(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	lessonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid lesson id"})
+	}
+	req := new(CreateNoteRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	note := &entity.Note{
+		LessonID: lessonID,
+		Kind:     req.Kind,
+		Body:     req.Body,
+		Anchor:   req.Anchor,
+		Color:    req.Color,
+	}
+	if err := h.usecase.Create(c.Request().Context(), note, userID, middleware.CurrentOrgID(c)); err != nil {
+		return noteError(c, err)
+	}
+	return c.JSON(http.StatusCreated, note)
+}
+
+// UpdateNote godoc
+// @Summary Изменить свою заметку
+// @Tags Notes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID заметки"
+// @Param request body UpdateNoteRequest true "Изменения"
+// @Success 200 {object} entity.Note
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /notes/{id} [put]
+func (h *NoteHandler) Update(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid note id"})
+	}
+	req := new(UpdateNoteRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	note, err := h.usecase.Update(c.Request().Context(), &entity.Note{
+		ID:     id,
+		Body:   req.Body,
+		Anchor: req.Anchor,
+		Color:  req.Color,
+	}, userID, middleware.CurrentOrgID(c))
+	if err != nil {
+		return noteError(c, err)
+	}
+	return c.JSON(http.StatusOK, note)
+}
+
+// DeleteNote godoc
+// @Summary Удалить свою заметку
+// @Tags Notes
+// @Security BearerAuth
+// @Param id path string true "ID заметки"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /notes/{id} [delete]
+func (h *NoteHandler) Delete(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid note id"})
+	}
+	if err := h.usecase.Delete(c.Request().Context(), userID, id); err != nil {
+		return noteError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SearchNotes godoc
+// @Summary Полнотекстовый поиск по моим заметкам
+// @Tags Notes
+// @Security BearerAuth
+// @Produce json
+// @Param q query string true "Поисковый запрос"
+// @Param limit query int false "Лимит"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} dto.PaginatedResponse[*entity.NoteSearchResult]
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /me/notes/search [get]
+func (h *NoteHandler) Search(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	results, total, err := h.usecase.Search(c.Request().Context(), userID, c.QueryParam("q"), middleware.CurrentOrgID(c), pag)
+	if err != nil {
+		return noteError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.NoteSearchResult]{
+		Items:  results,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// ExportNotes godoc
+// @Summary Экспорт моих заметок по курсу в Markdown
+// @Tags Notes
+// @Security BearerAuth
+// @Produce text/markdown
+// @Param id path string true "ID курса"
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /courses/{id}/notes/export [get]
+func (h *NoteHandler) ExportCourse(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid course id"})
+	}
+	md, err := h.usecase.ExportCourseMarkdown(c.Request().Context(), userID, courseID, middleware.CurrentOrgID(c))
+	if err != nil {
+		return noteError(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="notes-%s.md"`, courseID))
+	return c.Blob(http.StatusOK, "text/markdown; charset=utf-8", []byte(md))
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func noteError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrNoteNotFound), errors.Is(err, usecase.ErrLessonNotFound),
+		errors.Is(err, usecase.ErrCourseNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidKind), errors.Is(err, usecase.ErrInvalidAnchor),
+		errors.Is(err, usecase.ErrEmptyNote), errors.Is(err, usecase.ErrNoteTooLong),
+		errors.Is(err, usecase.ErrEmptyQuery):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
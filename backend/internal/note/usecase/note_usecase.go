@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	courseEntity "github.com/kostinp/edu-platform-backend/internal/course/entity"
+	lessonEntity "github.com/kostinp/edu-platform-backend/internal/lesson/entity"
+	"github.com/kostinp/edu-platform-backend/internal/note/entity"
+	"github.com/kostinp/edu-platform-backend/internal/note/repository"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+)
+
+const MaxNoteLength = 20000
+
+var (
+	ErrNoteNotFound   = repository.ErrNoteNotFound
+	ErrLessonNotFound = errors.New("lesson not found")
+	ErrCourseNotFound = errors.New("course not found")
+	ErrInvalidKind    = errors.New("kind must be note or highlight")
+	ErrInvalidAnchor  = errors.New("anchor is outside of lesson content")
+	ErrEmptyNote      = errors.New("note must have text or highlighted fragment")
+	ErrNoteTooLong    = errors.New("note is too long")
+	ErrEmptyQuery     = errors.New("search query is empty")
+)
+
+// LessonReader — доступ к тексту урока для проверки якорей;
+// уроки курсов чужих организаций не находятся
+type LessonReader interface {
+	GetByID(ctx context.Context, id uuid.UUID, viewerOrg *uuid.UUID) (*lessonEntity.Lesson, error)
+}
+
+// CourseReader — название курса для экспорта
+type CourseReader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*courseEntity.Course, error)
+}
+
+type NoteUsecase interface {
+	// viewerOrg — организация пользователя: уроки курсов других организаций не видны
+	Create(ctx context.Context, note *entity.Note, userID uuid.UUID, viewerOrg *uuid.UUID) error
+	Update(ctx context.Context, note *entity.Note, userID uuid.UUID, viewerOrg *uuid.UUID) (*entity.Note, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	ListByLesson(ctx context.Context, userID, lessonID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Note, error)
+	Search(ctx context.Context, userID uuid.UUID, query string, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.NoteSearchResult, int, error)
+	ExportCourseMarkdown(ctx context.Context, userID, courseID uuid.UUID, viewerOrg *uuid.UUID) (string, error)
+}
+
+type noteUsecase struct {
+	repo    repository.NoteRepository
+	lessons LessonReader
+	courses CourseReader
+}
+
+func NewNoteUsecase(repo repository.NoteRepository, lessons LessonReader, courses CourseReader) NoteUsecase {
+	return &noteUsecase{repo: repo, lessons: lessons, courses: courses}
+}
+
+func (u *noteUsecase) Create(ctx context.Context, note *entity.Note, userID uuid.UUID, viewerOrg *uuid.UUID) error {
+	if note.Kind == "" {
+		note.Kind = entity.NoteKindNote
+	}
+	if note.Kind != entity.NoteKindNote && note.Kind != entity.NoteKindHighlight {
+		return ErrInvalidKind
+	}
+	lesson, err := u.lessons.GetByID(ctx, note.LessonID, viewerOrg)
+	if err != nil {
+		return ErrLessonNotFound
+	}
+	if err := applyAnchor(note, lesson.Content); err != nil {
+		return err
+	}
+	if err := validateNote(note); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	note.ID = uuid.New()
+	note.UserID = userID
+	note.CreatedAt = now
+	note.UpdatedAt = now
+	return u.repo.Create(ctx, note)
+}
+
+// Update меняет текст, цвет и якорь; тип заметки и урок не меняются
+func (u *noteUsecase) Update(ctx context.Context, changes *entity.Note, userID uuid.UUID, viewerOrg *uuid.UUID) (*entity.Note, error) {
+	note, err := u.repo.GetByID(ctx, userID, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	note.Body = changes.Body
+	note.Color = changes.Color
+	if changes.Anchor != nil {
+		lesson, err := u.lessons.GetByID(ctx, note.LessonID, viewerOrg)
+		if err != nil {
+			return nil, ErrLessonNotFound
+		}
+		note.Anchor = changes.Anchor
+		if err := applyAnchor(note, lesson.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateNote(note); err != nil {
+		return nil, err
+	}
+	note.UpdatedAt = time.Now().UTC()
+	if err := u.repo.Update(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (u *noteUsecase) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	return u.repo.Delete(ctx, userID, id)
+}
+
+func (u *noteUsecase) ListByLesson(ctx context.Context, userID, lessonID uuid.UUID, viewerOrg *uuid.UUID) ([]*entity.Note, error) {
+	return u.repo.ListByLesson(ctx, userID, lessonID, viewerOrg)
+}
+
+func (u *noteUsecase) Search(ctx context.Context, userID uuid.UUID, query string, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.NoteSearchResult, int, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, 0, ErrEmptyQuery
+	}
+	return u.repo.Search(ctx, userID, query, viewerOrg, pag)
+}
+
+// ExportCourseMarkdown собирает все заметки пользователя по курсу в Markdown,
+// сгруппированные по модулям и урокам
+func (u *noteUsecase) ExportCourseMarkdown(ctx context.Context, userID, courseID uuid.UUID, viewerOrg *uuid.UUID) (string, error) {
+	course, err := u.courses.GetByID(ctx, courseID)
+	if err != nil {
+		return "", ErrCourseNotFound
+	}
+	notes, err := u.repo.ListByCourse(ctx, userID, courseID, viewerOrg)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", course.Title)
+	if len(notes) == 0 {
+		b.WriteString("\n_Заметок пока нет._\n")
+		return b.String(), nil
+	}
+	var module, lesson string
+	for _, n := range notes {
+		if n.ModuleTitle != module {
+			module = n.ModuleTitle
+			lesson = ""
+			fmt.Fprintf(&b, "\n## %s\n", module)
+		}
+		if n.LessonTitle != lesson {
+			lesson = n.LessonTitle
+			fmt.Fprintf(&b, "\n### %s\n", lesson)
+		}
+		b.WriteString("\n")
+		if n.Quote != "" {
+			for _, line := range strings.Split(n.Quote, "\n") {
+				fmt.Fprintf(&b, "> %s\n", line)
+			}
+			if n.Body != "" {
+				b.WriteString("\n")
+			}
+		}
+		if n.Body != "" {
+			b.WriteString(n.Body)
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// applyAnchor проверяет якорь и берёт цитату из текста урока,
+// чтобы выделение всегда соответствовало содержимому
+func applyAnchor(note *entity.Note, content string) error {
+	if note.Anchor == nil {
+		if note.Kind == entity.NoteKindHighlight {
+			return ErrInvalidAnchor
+		}
+		note.Quote = ""
+		return nil
+	}
+	runes := []rune(content)
+	if note.Anchor.Start < 0 || note.Anchor.End <= note.Anchor.Start || note.Anchor.End > len(runes) {
+		return ErrInvalidAnchor
+	}
+	note.Quote = string(runes[note.Anchor.Start:note.Anchor.End])
+	return nil
+}
+
+func validateNote(note *entity.Note) error {
+	note.Body = strings.TrimSpace(note.Body)
+	if note.Body == "" && note.Quote == "" {
+		return ErrEmptyNote
+	}
+	if utf8.RuneCountInString(note.Body) > MaxNoteLength {
+		return ErrNoteTooLong
+	}
+	return nil
+}
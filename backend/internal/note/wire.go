@@ -0,0 +1,22 @@
+// internal/note/wire.go
+package note
+
+import (
+	"github.com/google/wire"
+	courseUsecase "github.com/kostinp/edu-platform-backend/internal/course/usecase"
+	lessonUsecase "github.com/kostinp/edu-platform-backend/internal/lesson/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/note/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/note/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/note/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+)
+
+var NoteSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresNoteRepository,
+	wire.Bind(new(repository.NoteRepository), new(*repository.PostgresNoteRepository)),
+	wire.Bind(new(usecase.LessonReader), new(lessonUsecase.LessonUsecase)),
+	wire.Bind(new(usecase.CourseReader), new(courseUsecase.CourseUsecase)),
+	usecase.NewNoteUsecase,
+	http.NewNoteHandler,
+)
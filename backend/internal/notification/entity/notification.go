@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Kind string
+
+const (
+	KindInactivityReminder Kind = "inactivity_reminder"
+	KindNewModule          Kind = "new_module"
+	KindAssignmentDue      Kind = "assignment_due"
+	KindCertificateIssued  Kind = "certificate_issued"
+)
+
+func (k Kind) Valid() bool {
+	switch k {
+	case KindInactivityReminder, KindNewModule, KindAssignmentDue, KindCertificateIssued:
+		return true
+	}
+	return false
+}
+
+type Language string
+
+const (
+	LanguageRU Language = "ru"
+	LanguageEN Language = "en"
+)
+
+const DefaultInactivityDays = 3
+
+// Preferences — настройки уведомлений пользователя
+type Preferences struct {
+	UserID             uuid.UUID  `json:"user_id"`
+	Language           Language   `json:"language"`
+	Enabled            bool       `json:"enabled"`
+	InactivityReminder bool       `json:"inactivity_reminder"`
+	InactivityDays     int        `json:"inactivity_days"`
+	NewModule          bool       `json:"new_module"`
+	AssignmentDue      bool       `json:"assignment_due"`
+	CertificateIssued  bool       `json:"certificate_issued"`
+	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultPreferences — настройки для пользователя, который их ещё не менял
+func DefaultPreferences(userID uuid.UUID) *Preferences {
+	return &Preferences{
+		UserID:             userID,
+		Language:           LanguageRU,
+		Enabled:            true,
+		InactivityReminder: true,
+		InactivityDays:     DefaultInactivityDays,
+		NewModule:          true,
+		AssignmentDue:      true,
+		CertificateIssued:  true,
+	}
+}
+
+// Allows — разрешён ли пользователю данный вид уведомлений
+func (p *Preferences) Allows(kind Kind) bool {
+	if !p.Enabled {
+		return false
+	}
+	switch kind {
+	case KindInactivityReminder:
+		return p.InactivityReminder
+	case KindNewModule:
+		return p.NewModule
+	case KindAssignmentDue:
+		return p.AssignmentDue
+	case KindCertificateIssued:
+		return p.CertificateIssued
+	}
+	return false
+}
+
+// Event — событие, о котором нужно уведомить пользователя.
+// DedupKey защищает от повторной отправки одного и того же события;
+// Data — параметры шаблона сообщения
+type Event struct {
+	Kind     Kind              `json:"kind"`
+	UserID   uuid.UUID         `json:"user_id"`
+	DedupKey string            `json:"dedup_key,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliverySent        DeliveryStatus = "sent"
+	DeliveryFailed      DeliveryStatus = "failed"
+	DeliveryRateLimited DeliveryStatus = "rate_limited"
+)
+
+// Delivery — запись журнала доставки
+type Delivery struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    uuid.UUID      `json:"user_id"`
+	ChatID    int64          `json:"chat_id"`
+	Kind      Kind           `json:"kind"`
+	DedupKey  string         `json:"dedup_key,omitempty"`
+	Status    DeliveryStatus `json:"status"`
+	Error     *string        `json:"error,omitempty"`
+	Text      string         `json:"text"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// InactiveLearner — пользователь, который давно не занимался
+type InactiveLearner struct {
+	UserID        uuid.UUID
+	LastActiveDay time.Time
+}
+
+// ModuleRelease — новый модуль, о котором ещё не узнал записанный на курс
+type ModuleRelease struct {
+	UserID      uuid.UUID
+	ModuleID    uuid.UUID
+	ModuleTitle string
+	CourseTitle string
+	CourseSlug  string
+}
+
+// DueAssignment — курс, назначенный группе со сроком, который участник ещё не прошёл.
+// DedupKey включает срок: перенос дедлайна даёт новое напоминание
+type DueAssignment struct {
+	UserID      uuid.UUID
+	GroupName   string
+	CourseTitle string
+	CourseSlug  string
+	DueAt       time.Time
+	DedupKey    string
+}
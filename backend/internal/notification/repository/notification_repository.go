@@ -0,0 +1,278 @@
+// internal/notification/repository/notification_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/notification/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+)
+
+var (
+	ErrPreferencesNotFound = errors.New("notification preferences not found")
+	ErrUserNotFound        = errors.New("user not found")
+)
+
+type NotificationRepository interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error)
+	SavePreferences(ctx context.Context, prefs *entity.Preferences) error
+	// GetChatID возвращает Telegram ID пользователя; nil — Telegram не привязан
+	GetChatID(ctx context.Context, userID uuid.UUID) (*int64, error)
+	WasDelivered(ctx context.Context, userID uuid.UUID, kind entity.Kind, dedupKey string) (bool, error)
+	LogDelivery(ctx context.Context, d *entity.Delivery) error
+	ListDeliveries(ctx context.Context, userID uuid.UUID, pag pagination.Params) ([]*entity.Delivery, int, error)
+	// ListInactiveLearners, ListModuleReleases и ListDueAssignments отдают не больше limit неотправленных
+	// событий; после неудачной попытки событие возвращается не раньше чем через retryAfter
+	ListInactiveLearners(ctx context.Context, today time.Time, retryAfter time.Duration, limit int) ([]*entity.InactiveLearner, error)
+	ListModuleReleases(ctx context.Context, since time.Time, retryAfter time.Duration, limit int) ([]*entity.ModuleRelease, error)
+	ListDueAssignments(ctx context.Context, until time.Time, retryAfter time.Duration, limit int) ([]*entity.DueAssignment, error)
+}
+
+type PostgresNotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresNotificationRepository(db *pgxpool.Pool) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{db: db}
+}
+
+func (r *PostgresNotificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error) {
+	p := &entity.Preferences{}
+	err := r.db.QueryRow(ctx, `
+		SELECT user_id, language, enabled, inactivity_reminder, inactivity_days,
+			new_module, assignment_due, certificate_issued, updated_at
+		FROM notification_preferences WHERE user_id = $1
+	`, userID).Scan(
+		&p.UserID, &p.Language, &p.Enabled, &p.InactivityReminder, &p.InactivityDays,
+		&p.NewModule, &p.AssignmentDue, &p.CertificateIssued, &p.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPreferencesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PostgresNotificationRepository) SavePreferences(ctx context.Context, p *entity.Preferences) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO notification_preferences (user_id, language, enabled, inactivity_reminder, inactivity_days,
+			new_module, assignment_due, certificate_issued, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			language = EXCLUDED.language,
+			enabled = EXCLUDED.enabled,
+			inactivity_reminder = EXCLUDED.inactivity_reminder,
+			inactivity_days = EXCLUDED.inactivity_days,
+			new_module = EXCLUDED.new_module,
+			assignment_due = EXCLUDED.assignment_due,
+			certificate_issued = EXCLUDED.certificate_issued,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`, p.UserID, p.Language, p.Enabled, p.InactivityReminder, p.InactivityDays,
+		p.NewModule, p.AssignmentDue, p.CertificateIssued).Scan(&p.UpdatedAt)
+}
+
+func (r *PostgresNotificationRepository) GetChatID(ctx context.Context, userID uuid.UUID) (*int64, error) {
+	var chatID *int64
+	err := r.db.QueryRow(ctx, `
+		SELECT telegram_id FROM users WHERE id = $1 AND deleted_at IS NULL
+	`, userID).Scan(&chatID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	return chatID, err
+}
+
+func (r *PostgresNotificationRepository) WasDelivered(ctx context.Context, userID uuid.UUID, kind entity.Kind, dedupKey string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM notification_deliveries
+			WHERE user_id = $1 AND kind = $2 AND dedup_key = $3 AND status = 'sent'
+		)
+	`, userID, kind, dedupKey).Scan(&exists)
+	return exists, err
+}
+
+// LogDelivery пишет запись в журнал. Повторная успешная доставка того же
+// события (гонка двух отправителей) не считается ошибкой
+func (r *PostgresNotificationRepository) LogDelivery(ctx context.Context, d *entity.Delivery) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO notification_deliveries (id, user_id, chat_id, kind, dedup_key, status, error, text)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`, d.ID, d.UserID, d.ChatID, d.Kind, d.DedupKey, d.Status, d.Error, d.Text).Scan(&d.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *PostgresNotificationRepository) ListDeliveries(ctx context.Context, userID uuid.UUID, pag pagination.Params) ([]*entity.Delivery, int, error) {
+	pag.Normalize()
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, chat_id, kind, dedup_key, status, error, text, created_at
+		FROM notification_deliveries
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, pag.Limit, pag.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := []*entity.Delivery{}
+	for rows.Next() {
+		d := &entity.Delivery{}
+		if err := rows.Scan(&d.ID, &d.UserID, &d.ChatID, &d.Kind, &d.DedupKey, &d.Status, &d.Error, &d.Text, &d.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notification_deliveries WHERE user_id = $1`, userID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ListInactiveLearners — пользователи с привязанным Telegram и записями на курсы,
+// которые не занимались дольше своего порога и ещё не получили напоминание
+// за текущий перерыв. Последней активностью считается и сама запись на курс
+func (r *PostgresNotificationRepository) ListInactiveLearners(ctx context.Context, today time.Time, retryAfter time.Duration, limit int) ([]*entity.InactiveLearner, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT u.id, la.day
+		FROM users u
+		JOIN (
+			SELECT user_id, MAX(day) AS day FROM (
+				SELECT user_id, day FROM learning_activity_days
+				UNION ALL
+				SELECT user_id, created_at::date FROM course_enrollments
+			) a
+			GROUP BY user_id
+		) la ON la.user_id = u.id
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE u.deleted_at IS NULL
+		  AND u.telegram_id IS NOT NULL
+		  AND EXISTS (SELECT 1 FROM course_enrollments e WHERE e.user_id = u.id)
+		  AND COALESCE(p.enabled, TRUE) AND COALESCE(p.inactivity_reminder, TRUE)
+		  AND la.day <= $1::date - COALESCE(p.inactivity_days, $2)
+		  AND NOT EXISTS (
+			SELECT 1 FROM notification_deliveries d
+			WHERE d.user_id = u.id AND d.kind = $3 AND d.dedup_key = la.day::text
+			  AND (d.status = 'sent' OR d.created_at > NOW() - make_interval(secs => $4))
+		  )
+		ORDER BY la.day, u.id
+		LIMIT $5
+	`, today, entity.DefaultInactivityDays, entity.KindInactivityReminder, retryAfter.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*entity.InactiveLearner{}
+	for rows.Next() {
+		l := &entity.InactiveLearner{}
+		if err := rows.Scan(&l.UserID, &l.LastActiveDay); err != nil {
+			return nil, err
+		}
+		items = append(items, l)
+	}
+	return items, rows.Err()
+}
+
+// ListModuleReleases — пары «модуль, созданный после since × записанный до его выхода»,
+// о которых ещё не сообщили. Записавшимся позже новость о модуле не нужна
+func (r *PostgresNotificationRepository) ListModuleReleases(ctx context.Context, since time.Time, retryAfter time.Duration, limit int) ([]*entity.ModuleRelease, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT u.id, m.id, m.title, c.title, c.slug
+		FROM modules m
+		JOIN courses c ON c.id = m.course_id AND c.deleted_at IS NULL
+		JOIN course_enrollments e ON e.course_id = m.course_id AND e.created_at < m.created_at
+		JOIN users u ON u.id = e.user_id AND u.deleted_at IS NULL AND u.telegram_id IS NOT NULL
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE m.deleted_at IS NULL AND m.created_at >= $1
+		  AND COALESCE(p.enabled, TRUE) AND COALESCE(p.new_module, TRUE)
+		  AND NOT EXISTS (
+			SELECT 1 FROM notification_deliveries d
+			WHERE d.user_id = u.id AND d.kind = $2 AND d.dedup_key = m.id::text
+			  AND (d.status = 'sent' OR d.created_at > NOW() - make_interval(secs => $3))
+		  )
+		ORDER BY m.created_at, u.id
+		LIMIT $4
+	`, since, entity.KindNewModule, retryAfter.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*entity.ModuleRelease{}
+	for rows.Next() {
+		m := &entity.ModuleRelease{}
+		if err := rows.Scan(&m.UserID, &m.ModuleID, &m.ModuleTitle, &m.CourseTitle, &m.CourseSlug); err != nil {
+			return nil, err
+		}
+		items = append(items, m)
+	}
+	return items, rows.Err()
+}
+
+// ListDueAssignments — участники групп, у которых срок курса наступает до until,
+// а курс ещё не пройден. Прошедшие сроки не напоминаются
+func (r *PostgresNotificationRepository) ListDueAssignments(ctx context.Context, until time.Time, retryAfter time.Duration, limit int) ([]*entity.DueAssignment, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH due AS (
+			SELECT gm.user_id, g.name AS group_name, c.title, c.slug, gc.course_id, gc.due_at,
+				gc.group_id::text || ':' || gc.course_id::text || ':' || to_char(gc.due_at, 'YYYY-MM-DD"T"HH24:MI') AS dedup_key
+			FROM study_group_courses gc
+			JOIN study_groups g ON g.id = gc.group_id
+			JOIN courses c ON c.id = gc.course_id AND c.deleted_at IS NULL
+			JOIN study_group_members gm ON gm.group_id = gc.group_id
+			WHERE gc.due_at > NOW() AND gc.due_at <= $1
+		)
+		SELECT due.user_id, due.group_name, due.title, due.slug, due.due_at, due.dedup_key
+		FROM due
+		JOIN users u ON u.id = due.user_id AND u.deleted_at IS NULL AND u.telegram_id IS NOT NULL
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE COALESCE(p.enabled, TRUE) AND COALESCE(p.assignment_due, TRUE)
+		  AND (SELECT COUNT(*) FROM lesson_progress lp JOIN lessons l ON l.id = lp.lesson_id AND l.deleted_at IS NULL
+				WHERE lp.user_id = u.id AND lp.course_id = due.course_id AND lp.completed_at IS NOT NULL)
+			< (SELECT COUNT(*) FROM lessons l JOIN modules m ON m.id = l.module_id
+				WHERE m.course_id = due.course_id AND l.deleted_at IS NULL AND m.deleted_at IS NULL)
+		  AND NOT EXISTS (
+			SELECT 1 FROM notification_deliveries d
+			WHERE d.user_id = u.id AND d.kind = $2 AND d.dedup_key = due.dedup_key
+			  AND (d.status = 'sent' OR d.created_at > NOW() - make_interval(secs => $3))
+		  )
+		ORDER BY due.due_at, due.user_id
+		LIMIT $4
+	`, until, entity.KindAssignmentDue, retryAfter.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*entity.DueAssignment{}
+	for rows.Next() {
+		a := &entity.DueAssignment{}
+		if err := rows.Scan(&a.UserID, &a.GroupName, &a.CourseTitle, &a.CourseSlug, &a.DueAt, &a.DedupKey); err != nil {
+			return nil, err
+		}
+		items = append(items, a)
+	}
+	return items, rows.Err()
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/notification/entity"
+	"github.com/kostinp/edu-platform-backend/internal/notification/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	usecase usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(uc usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{usecase: uc}
+}
+
+// GetPreferences godoc
+// @Summary Мои настройки уведомлений
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} entity.Preferences
+// @Failure 500 {object} map[string]string
+// @Router /me/notification-preferences [get]
+func (h *NotificationHandler) GetPreferences(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	prefs, err := h.usecase.GetPreferences(c.Request().Context(), userID)
+	if err != nil {
+		return notificationError(c, err)
+	}
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences godoc
+// @Summary Изменить настройки уведомлений
+// @Description Передаются только изменяемые поля
+// @Tags Notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body usecase.PreferencesPatch true "Настройки"
+// @Success 200 {object} entity.Preferences
+// @Failure 400 {object} map[string]string
+// @Router /me/notification-preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	var patch usecase.PreferencesPatch
+	if err := c.Bind(&patch); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	prefs, err := h.usecase.UpdatePreferences(c.Request().Context(), userID, &patch)
+	if err != nil {
+		return notificationError(c, err)
+	}
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// ListDeliveries godoc
+// @Summary Журнал моих уведомлений
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Лимит"
+// @Param offset query int false "Смещение"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Delivery]
+// @Failure 500 {object} map[string]string
+// @Router /me/notifications [get]
+func (h *NotificationHandler) ListDeliveries(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	items, total, err := h.usecase.ListDeliveries(c.Request().Context(), userID, pag)
+	if err != nil {
+		return notificationError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Delivery]{
+		Items:  items,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// Send godoc
+// @Summary Отправить уведомление пользователю
+// @Description Точка входа для внешних систем. Поля data зависят от kind:
+// @Description new_module — CourseTitle, ModuleTitle, URL; assignment_due — CourseTitle, GroupName, DueAt, URL;
+// @Description certificate_issued — CourseTitle, CertificateURL
+// @Tags Notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body entity.Event true "Событие"
+// @Success 200 {object} entity.Delivery
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /notifications/send [post]
+func (h *NotificationHandler) Send(c echo.Context) error {
+	var ev entity.Event
+	if err := c.Bind(&ev); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	delivery, err := h.usecase.Notify(c.Request().Context(), &ev)
+	if delivery != nil && delivery.Status == entity.DeliveryFailed {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return notificationError(c, err)
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func notificationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidKind), errors.Is(err, usecase.ErrInvalidEventData),
+		errors.Is(err, usecase.ErrInvalidLanguage), errors.Is(err, usecase.ErrInvalidDays),
+		errors.Is(err, usecase.ErrTelegramNotLinked):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrNotificationDisabled), errors.Is(err, usecase.ErrAlreadyDelivered):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrRateLimited):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	botUsecase "github.com/kostinp/edu-platform-backend/internal/bot/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/notification/entity"
+	"github.com/kostinp/edu-platform-backend/internal/notification/repository"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+)
+
+// Фоновые рассылки идут пачками: за один запуск планировщика — не больше batchSize сообщений
+// каждого вида, остальное уйдёт на следующих запусках
+const (
+	batchSize = 500
+	// retryAfter — пауза перед повтором неудачной или упёршейся в лимит отправки
+	retryAfter = time.Hour
+	// moduleReleaseWindow — сколько после выхода модуля о нём ещё стоит сообщать
+	moduleReleaseWindow = 7 * 24 * time.Hour
+	// assignmentDueLead — за сколько до срока курса группы приходит напоминание
+	assignmentDueLead = 24 * time.Hour
+)
+
+var (
+	ErrUserNotFound         = repository.ErrUserNotFound
+	ErrInvalidKind          = errors.New("unknown notification kind")
+	ErrInvalidEventData     = errors.New("invalid notification data")
+	ErrInvalidLanguage      = errors.New("language must be ru or en")
+	ErrInvalidDays          = errors.New("inactivity_days must be between 1 and 30")
+	ErrTelegramNotLinked    = errors.New("user has no linked telegram account")
+	ErrNotificationDisabled = errors.New("notification disabled by user preferences")
+	ErrAlreadyDelivered     = errors.New("notification already delivered")
+	ErrRateLimited          = errors.New("notification rate limit exceeded for chat")
+)
+
+// PreferencesPatch — частичное обновление настроек; nil-поля не меняются
+type PreferencesPatch struct {
+	Language           *entity.Language `json:"language"`
+	Enabled            *bool            `json:"enabled"`
+	InactivityReminder *bool            `json:"inactivity_reminder"`
+	InactivityDays     *int             `json:"inactivity_days"`
+	NewModule          *bool            `json:"new_module"`
+	AssignmentDue      *bool            `json:"assignment_due"`
+	CertificateIssued  *bool            `json:"certificate_issued"`
+}
+
+type NotificationUsecase interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, patch *PreferencesPatch) (*entity.Preferences, error)
+	ListDeliveries(ctx context.Context, userID uuid.UUID, pag pagination.Params) ([]*entity.Delivery, int, error)
+	// Notify отправляет одно уведомление с учётом настроек, дедупликации и лимитов.
+	// Попытка фиксируется в журнале, если дело дошло до отправки
+	Notify(ctx context.Context, ev *entity.Event) (*entity.Delivery, error)
+	SendInactivityReminders(ctx context.Context) (int, error)
+	// SendModuleReleases сообщает записанным о модулях, вышедших за последнюю неделю
+	SendModuleReleases(ctx context.Context) (int, error)
+	// SendAssignmentDueReminders напоминает участникам групп о сроке курса за сутки до него
+	SendAssignmentDueReminders(ctx context.Context) (int, error)
+	// NotifyCertificateIssued сообщает о выданном сертификате. Вызывается доменом
+	// сертификатов после выдачи; повторный вызов для того же сертификата не отправляет ничего
+	NotifyCertificateIssued(ctx context.Context, userID, certificateID uuid.UUID, courseTitle, certificateURL string) (*entity.Delivery, error)
+}
+
+type notificationUsecase struct {
+	repo      repository.NotificationRepository
+	sender    botUsecase.BotAPI
+	limiter   RateLimiter
+	templates *Templates
+	siteURL   string
+}
+
+func NewNotificationUsecase(
+	repo repository.NotificationRepository,
+	sender botUsecase.BotAPI,
+	limiter RateLimiter,
+	templates *Templates,
+	siteURL botUsecase.SiteURL,
+) NotificationUsecase {
+	return &notificationUsecase{
+		repo:      repo,
+		sender:    sender,
+		limiter:   limiter,
+		templates: templates,
+		siteURL:   strings.TrimRight(string(siteURL), "/"),
+	}
+}
+
+func (u *notificationUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error) {
+	prefs, err := u.repo.GetPreferences(ctx, userID)
+	if errors.Is(err, repository.ErrPreferencesNotFound) {
+		return entity.DefaultPreferences(userID), nil
+	}
+	return prefs, err
+}
+
+func (u *notificationUsecase) UpdatePreferences(ctx context.Context, userID uuid.UUID, patch *PreferencesPatch) (*entity.Preferences, error) {
+	prefs, err := u.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if patch.Language != nil {
+		if *patch.Language != entity.LanguageRU && *patch.Language != entity.LanguageEN {
+			return nil, ErrInvalidLanguage
+		}
+		prefs.Language = *patch.Language
+	}
+	if patch.InactivityDays != nil {
+		if *patch.InactivityDays < 1 || *patch.InactivityDays > 30 {
+			return nil, ErrInvalidDays
+		}
+		prefs.InactivityDays = *patch.InactivityDays
+	}
+	setBool(&prefs.Enabled, patch.Enabled)
+	setBool(&prefs.InactivityReminder, patch.InactivityReminder)
+	setBool(&prefs.NewModule, patch.NewModule)
+	setBool(&prefs.AssignmentDue, patch.AssignmentDue)
+	setBool(&prefs.CertificateIssued, patch.CertificateIssued)
+
+	if err := u.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (u *notificationUsecase) ListDeliveries(ctx context.Context, userID uuid.UUID, pag pagination.Params) ([]*entity.Delivery, int, error) {
+	return u.repo.ListDeliveries(ctx, userID, pag)
+}
+
+func (u *notificationUsecase) Notify(ctx context.Context, ev *entity.Event) (*entity.Delivery, error) {
+	if !ev.Kind.Valid() {
+		return nil, ErrInvalidKind
+	}
+	chatID, err := u.repo.GetChatID(ctx, ev.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if chatID == nil {
+		return nil, ErrTelegramNotLinked
+	}
+	prefs, err := u.GetPreferences(ctx, ev.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !prefs.Allows(ev.Kind) {
+		return nil, ErrNotificationDisabled
+	}
+	if ev.DedupKey != "" {
+		delivered, err := u.repo.WasDelivered(ctx, ev.UserID, ev.Kind, ev.DedupKey)
+		if err != nil {
+			return nil, err
+		}
+		if delivered {
+			return nil, ErrAlreadyDelivered
+		}
+	}
+	text, err := u.templates.Render(ev.Kind, prefs.Language, ev.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &entity.Delivery{
+		ID:       uuid.New(),
+		UserID:   ev.UserID,
+		ChatID:   *chatID,
+		Kind:     ev.Kind,
+		DedupKey: ev.DedupKey,
+		Text:     text,
+	}
+	allowed, err := u.limiter.Allow(ctx, strconv.FormatInt(*chatID, 10))
+	if err != nil {
+		return nil, err
+	}
+	var sendErr error
+	switch {
+	case !allowed:
+		delivery.Status = entity.DeliveryRateLimited
+		sendErr = ErrRateLimited
+	default:
+		if sendErr = u.sender.SendMessage(ctx, *chatID, text); sendErr != nil {
+			delivery.Status = entity.DeliveryFailed
+			msg := sendErr.Error()
+			delivery.Error = &msg
+		} else {
+			delivery.Status = entity.DeliverySent
+		}
+	}
+	if err := u.repo.LogDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, sendErr
+}
+
+// SendInactivityReminders напоминает о занятиях тем, кто давно не заходил.
+// Ключ дедупликации — день последней активности, поэтому за один перерыв
+// уходит одно напоминание; не отправленные из-за лимита повторятся не раньше чем через retryAfter
+func (u *notificationUsecase) SendInactivityReminders(ctx context.Context) (int, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	learners, err := u.repo.ListInactiveLearners(ctx, today, retryAfter, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, l := range learners {
+		days := int(today.Sub(l.LastActiveDay.UTC().Truncate(24*time.Hour)).Hours() / 24)
+		_, err := u.Notify(ctx, &entity.Event{
+			Kind:     entity.KindInactivityReminder,
+			UserID:   l.UserID,
+			DedupKey: l.LastActiveDay.Format("2006-01-02"),
+			Data:     map[string]string{"Days": strconv.Itoa(days)},
+		})
+		if err != nil {
+			if !isSkip(err) {
+				log.Printf("inactivity reminder for %s: %v", l.UserID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (u *notificationUsecase) SendModuleReleases(ctx context.Context) (int, error) {
+	since := time.Now().UTC().Add(-moduleReleaseWindow)
+	releases, err := u.repo.ListModuleReleases(ctx, since, retryAfter, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, r := range releases {
+		_, err := u.Notify(ctx, &entity.Event{
+			Kind:     entity.KindNewModule,
+			UserID:   r.UserID,
+			DedupKey: r.ModuleID.String(),
+			Data: map[string]string{
+				"CourseTitle": r.CourseTitle,
+				"ModuleTitle": r.ModuleTitle,
+				"URL":         u.siteURL + "/courses/" + r.CourseSlug,
+			},
+		})
+		if err != nil {
+			if !isSkip(err) {
+				log.Printf("new module notification for %s: %v", r.UserID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (u *notificationUsecase) SendAssignmentDueReminders(ctx context.Context) (int, error) {
+	until := time.Now().UTC().Add(assignmentDueLead)
+	due, err := u.repo.ListDueAssignments(ctx, until, retryAfter, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, a := range due {
+		_, err := u.Notify(ctx, &entity.Event{
+			Kind:     entity.KindAssignmentDue,
+			UserID:   a.UserID,
+			DedupKey: a.DedupKey,
+			Data: map[string]string{
+				"CourseTitle": a.CourseTitle,
+				"GroupName":   a.GroupName,
+				"DueAt":       a.DueAt.UTC().Format("02.01.2006 15:04 UTC"),
+				"URL":         u.siteURL + "/courses/" + a.CourseSlug,
+			},
+		})
+		if err != nil {
+			if !isSkip(err) {
+				log.Printf("assignment due notification for %s: %v", a.UserID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (u *notificationUsecase) NotifyCertificateIssued(ctx context.Context, userID, certificateID uuid.UUID, courseTitle, certificateURL string) (*entity.Delivery, error) {
+	return u.Notify(ctx, &entity.Event{
+		Kind:     entity.KindCertificateIssued,
+		UserID:   userID,
+		DedupKey: certificateID.String(),
+		Data: map[string]string{
+			"CourseTitle":    courseTitle,
+			"CertificateURL": certificateURL,
+		},
+	})
+}
+
+// isSkip — ожидаемые причины не отправлять уведомление, не требующие логирования
+func isSkip(err error) bool {
+	return errors.Is(err, ErrNotificationDisabled) || errors.Is(err, ErrAlreadyDelivered) ||
+		errors.Is(err, ErrTelegramNotLinked) || errors.Is(err, ErrRateLimited)
+}
+
+func setBool(dst *bool, v *bool) {
+	if v != nil {
+		*dst = *v
+	}
+}
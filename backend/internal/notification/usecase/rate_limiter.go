@@ -0,0 +1,11 @@
+package usecase
+
+import (
+	"context"
+)
+
+// RateLimiter ограничивает число уведомлений в один чат за окно времени;
+// ключ — Telegram chat ID (реализации — в shared/ratelimit)
+type RateLimiter interface {
+	Allow(ctx context.Context, chatKey string) (bool, error)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// StartNotificationScheduler периодически рассылает фоновые уведомления:
+// напоминания о перерыве, новости о модулях и сроки курсов групп.
+// Останавливается вместе с ctx; текущая пачка прерывается через тот же ctx
+func StartNotificationScheduler(ctx context.Context, uc NotificationUsecase, interval time.Duration) {
+	jobs := []struct {
+		name string
+		run  func(context.Context) (int, error)
+	}{
+		{"inactivity reminders", uc.SendInactivityReminders},
+		{"module releases", uc.SendModuleReleases},
+		{"assignment due reminders", uc.SendAssignmentDueReminders},
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for _, job := range jobs {
+					if ctx.Err() != nil {
+						break
+					}
+					sent, err := job.run(ctx)
+					if err != nil {
+						log.Printf("notification scheduler: %s: %v", job.name, err)
+					} else if sent > 0 {
+						log.Printf("notification scheduler: %d %s sent", sent, job.name)
+					}
+				}
+			case <-ctx.Done():
+				log.Printf("notification scheduler stopped")
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"github.com/kostinp/edu-platform-backend/internal/notification/entity"
+)
+
+// Тексты уведомлений (HTML-режим Telegram). html/template экранирует параметры,
+// поэтому названия курсов и заданий можно подставлять как есть
+var messageTemplates = map[entity.Kind]map[entity.Language]string{
+	entity.KindInactivityReminder: {
+		entity.LanguageRU: `👋 Вы не занимались уже {{days .Days}}. Отправьте /continue, чтобы продолжить с того места, где остановились.`,
+		entity.LanguageEN: `👋 You haven't studied for {{days .Days}}. Send /continue to pick up where you left off.`,
+	},
+	entity.KindNewModule: {
+		entity.LanguageRU: `📚 В курсе «{{.CourseTitle}}» вышел новый модуль: <b>{{.ModuleTitle}}</b>
+<a href="{{.URL}}">Открыть курс</a>`,
+		entity.LanguageEN: `📚 A new module is out in “{{.CourseTitle}}”: <b>{{.ModuleTitle}}</b>
+<a href="{{.URL}}">Open the course</a>`,
+	},
+	entity.KindAssignmentDue: {
+		entity.LanguageRU: `⏰ Скоро дедлайн: курс <b>{{.CourseTitle}}</b> (группа «{{.GroupName}}») нужно пройти до {{.DueAt}}.
+<a href="{{.URL}}">Продолжить</a>`,
+		entity.LanguageEN: `⏰ Deadline soon: finish <b>{{.CourseTitle}}</b> (group “{{.GroupName}}”) by {{.DueAt}}.
+<a href="{{.URL}}">Continue</a>`,
+	},
+	entity.KindCertificateIssued: {
+		entity.LanguageRU: `🎓 Поздравляем! Сертификат за курс «{{.CourseTitle}}» готов: <a href="{{.CertificateURL}}">открыть</a>`,
+		entity.LanguageEN: `🎓 Congratulations! Your certificate for “{{.CourseTitle}}” is ready: <a href="{{.CertificateURL}}">open</a>`,
+	},
+}
+
+// Templates — разобранные шаблоны сообщений по видам и языкам
+type Templates struct {
+	byKind map[entity.Kind]map[entity.Language]*template.Template
+}
+
+func NewTemplates() *Templates {
+	t := &Templates{byKind: map[entity.Kind]map[entity.Language]*template.Template{}}
+	for kind, langs := range messageTemplates {
+		t.byKind[kind] = map[entity.Language]*template.Template{}
+		for lang, text := range langs {
+			t.byKind[kind][lang] = template.Must(
+				template.New(string(kind) + "." + string(lang)).
+					Option("missingkey=error").
+					Funcs(template.FuncMap{"days": daysFunc(lang)}).
+					Parse(text),
+			)
+		}
+	}
+	return t
+}
+
+// Render подставляет данные события в шаблон; неизвестный язык заменяется русским
+func (t *Templates) Render(kind entity.Kind, lang entity.Language, data map[string]string) (string, error) {
+	langs, ok := t.byKind[kind]
+	if !ok {
+		return "", ErrInvalidKind
+	}
+	tmpl, ok := langs[lang]
+	if !ok {
+		tmpl = langs[entity.LanguageRU]
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidEventData, err)
+	}
+	return buf.String(), nil
+}
+
+// daysFunc — «3 дня» / «3 days» с учётом языка
+func daysFunc(lang entity.Language) func(string) string {
+	return func(s string) string {
+		n, _ := strconv.Atoi(s)
+		if lang == entity.LanguageEN {
+			if n == 1 {
+				return "1 day"
+			}
+			return fmt.Sprintf("%d days", n)
+		}
+		word := "дней"
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			word = "день"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			word = "дня"
+		}
+		return fmt.Sprintf("%d %s", n, word)
+	}
+}
@@ -0,0 +1,55 @@
+// internal/notification/wire.go
+package notification
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/notification/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/notification/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/notification/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/config"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+	"github.com/kostinp/edu-platform-backend/internal/shared/ratelimit"
+)
+
+var (
+	memoryLimiterOnce sync.Once
+	memoryLimiter     *ratelimit.Memory
+)
+
+// ProvideRateLimiter выбирает Redis, если он настроен, иначе лимитер в памяти.
+// Лимитер в памяти один на процесс: HTTP-сервер и планировщик делят квоты чатов
+func ProvideRateLimiter(cfg *config.Config) usecase.RateLimiter {
+	limit := cfg.Notifications.PerChatLimit
+	if limit <= 0 {
+		limit = 3
+	}
+	window := time.Duration(cfg.Notifications.PerChatWindowMinutes) * time.Minute
+	if window <= 0 {
+		window = time.Hour
+	}
+	if cfg.Redis.URL != "" {
+		r, err := db.NewRedis(cfg.Redis.URL)
+		if err == nil {
+			return ratelimit.NewRedis(r.Client, "notify_rl:", limit, window)
+		}
+		logger.Error("Некорректный REDIS_URL, лимит уведомлений будет в памяти", err)
+	}
+	memoryLimiterOnce.Do(func() {
+		memoryLimiter = ratelimit.NewMemory(limit, window)
+	})
+	return memoryLimiter
+}
+
+var NotificationSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresNotificationRepository,
+	wire.Bind(new(repository.NotificationRepository), new(*repository.PostgresNotificationRepository)),
+	ProvideRateLimiter,
+	usecase.NewTemplates,
+	usecase.NewNotificationUsecase,
+	http.NewNotificationHandler,
+)
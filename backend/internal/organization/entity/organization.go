@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+// Organization — закрытое пространство школы: её курсы, категории и теги
+// видны только участникам
+type Organization struct {
+	ID        uuid.UUID  `json:"id"`
+	Slug      string     `json:"slug"`
+	Name      string     `json:"name"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Заполняется в списках и карточке организации
+	MembersCount int `json:"members_count"`
+}
+
+// Member — участник организации. Role — роль на платформе, OrgRole — в организации
+type Member struct {
+	UserID   uuid.UUID          `json:"user_id"`
+	Username string             `json:"username,omitempty"`
+	FullName string             `json:"full_name,omitempty"`
+	Email    string             `json:"email,omitempty"`
+	Role     userEntity.Role    `json:"role"`
+	OrgRole  userEntity.OrgRole `json:"org_role"`
+	JoinedAt *time.Time         `json:"joined_at,omitempty"`
+}
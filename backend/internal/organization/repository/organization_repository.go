@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/organization/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationNotEmpty  = errors.New("organization still has courses, categories or tags")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrAlreadyInOrganization = errors.New("user already belongs to an organization")
+	ErrMemberNotFound        = errors.New("user is not a member of this organization")
+	ErrLastOwner             = errors.New("organization must keep at least one owner")
+)
+
+type OrganizationRepository interface {
+	// Create сохраняет организацию и в той же транзакции делает ownerID её владельцем
+	Create(ctx context.Context, org *entity.Organization, ownerID uuid.UUID) error
+	Update(ctx context.Context, org *entity.Organization) error
+	// Delete удаляет пустую организацию; участники остаются на платформе без организации
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error)
+	List(ctx context.Context, pag pagination.Params) ([]*entity.Organization, int, error)
+	SlugExists(ctx context.Context, s string, excludeID uuid.UUID) (bool, error)
+
+	ListMembers(ctx context.Context, orgID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error)
+	GetMember(ctx context.Context, orgID, userID uuid.UUID) (*entity.Member, error)
+	// FindUserByEmail — id активного пользователя по email без учёта регистра
+	FindUserByEmail(ctx context.Context, email string) (uuid.UUID, error)
+	// AddMember добавляет пользователя без организации; ErrAlreadyInOrganization, если он уже где-то состоит
+	AddMember(ctx context.Context, orgID, userID uuid.UUID, role userEntity.OrgRole) error
+	// SetMemberRole меняет роль участника и возвращает прежнюю. Последнего владельца понизить нельзя
+	SetMemberRole(ctx context.Context, orgID, userID uuid.UUID, role userEntity.OrgRole) (userEntity.OrgRole, error)
+	// RemoveMember исключает участника. Последнего владельца исключить нельзя
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error
+}
+
+type PostgresOrganizationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresOrganizationRepository(db *pgxpool.Pool) *PostgresOrganizationRepository {
+	return &PostgresOrganizationRepository{db: db}
+}
+
+const organizationColumns = `o.id, o.slug, o.name, o.created_by, o.created_at, o.updated_at,
+	(SELECT COUNT(*) FROM users u WHERE u.org_id = o.id AND u.deleted_at IS NULL)`
+
+const memberColumns = `id, COALESCE(username, ''), COALESCE(first_name, ''), COALESCE(last_name, ''),
+	COALESCE(email, ''), role, org_role, org_joined_at`
+
+var organizationSortFields = map[string]string{
+	"name":       "o.name",
+	"created_at": "o.created_at",
+}
+
+var memberSortFields = map[string]string{
+	"joined_at": "org_joined_at",
+	"username":  "username",
+	"email":     "email",
+}
+
+func scanOrganization(row pgx.Row) (*entity.Organization, error) {
+	o := &entity.Organization{}
+	err := row.Scan(&o.ID, &o.Slug, &o.Name, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt, &o.MembersCount)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func scanMember(row pgx.Row) (*entity.Member, error) {
+	m := &entity.Member{}
+	var firstName, lastName string
+	err := row.Scan(&m.UserID, &m.Username, &firstName, &lastName, &m.Email, &m.Role, &m.OrgRole, &m.JoinedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.FullName = strings.TrimSpace(firstName + " " + lastName)
+	return m, nil
+}
+
+func (r *PostgresOrganizationRepository) Create(ctx context.Context, org *entity.Organization, ownerID uuid.UUID) error {
+	if org.ID == uuid.Nil {
+		org.ID = uuid.New()
+	}
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO organizations (id, slug, name, created_by)
+			VALUES ($1, $2, $3, $4)
+			RETURNING created_at, updated_at
+		`, org.ID, org.Slug, org.Name, org.CreatedBy).Scan(&org.CreatedAt, &org.UpdatedAt)
+		if isUniqueViolation(err) {
+			return slug.ErrSlugTaken
+		}
+		if err != nil {
+			return err
+		}
+		if err := joinOrganization(ctx, tx, org.ID, ownerID, userEntity.OrgRoleOwner); err != nil {
+			return err
+		}
+		org.MembersCount = 1
+		return nil
+	})
+}
+
+func (r *PostgresOrganizationRepository) Update(ctx context.Context, org *entity.Organization) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE organizations SET slug = $2, name = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, org.ID, org.Slug, org.Name).Scan(&org.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrOrganizationNotFound
+	}
+	if isUniqueViolation(err) {
+		return slug.ErrSlugTaken
+	}
+	return err
+}
+
+func (r *PostgresOrganizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockOrganization(ctx, tx, id); err != nil {
+			return err
+		}
+		var hasContent bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM courses WHERE org_id = $1)
+				OR EXISTS (SELECT 1 FROM categories WHERE org_id = $1)
+				OR EXISTS (SELECT 1 FROM tags WHERE org_id = $1)
+		`, id).Scan(&hasContent)
+		if err != nil {
+			return err
+		}
+		if hasContent {
+			return ErrOrganizationNotEmpty
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE users SET org_id = NULL, org_role = NULL, org_joined_at = NULL, updated_at = NOW()
+			WHERE org_id = $1
+		`, id); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM organizations WHERE id = $1`, id)
+		return err
+	})
+}
+
+func (r *PostgresOrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error) {
+	org, err := scanOrganization(r.db.QueryRow(ctx,
+		`SELECT `+organizationColumns+` FROM organizations o WHERE o.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, err
+}
+
+func (r *PostgresOrganizationRepository) List(ctx context.Context, pag pagination.Params) ([]*entity.Organization, int, error) {
+	query, args := pagination.SQLWithPagination(
+		`SELECT `+organizationColumns+` FROM organizations o`, pag, organizationSortFields)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	orgs := []*entity.Organization{}
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM organizations`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return orgs, total, nil
+}
+
+func (r *PostgresOrganizationRepository) SlugExists(ctx context.Context, s string, excludeID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM organizations WHERE slug = $1 AND id <> $2)`, s, excludeID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresOrganizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error) {
+	if pag.SortBy == "" {
+		pag.SortBy = "joined_at"
+		pag.Order = "asc"
+	}
+	query, args := pagination.SQLWithPagination(
+		`SELECT `+memberColumns+` FROM users WHERE org_id = $3 AND deleted_at IS NULL`, pag, memberSortFields)
+	args = append(args, orgID)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	members := []*entity.Member{}
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE org_id = $1 AND deleted_at IS NULL`, orgID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return members, total, nil
+}
+
+func (r *PostgresOrganizationRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*entity.Member, error) {
+	m, err := scanMember(r.db.QueryRow(ctx, `
+		SELECT `+memberColumns+` FROM users
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+	`, userID, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemberNotFound
+	}
+	return m, err
+}
+
+func (r *PostgresOrganizationRepository) FindUserByEmail(ctx context.Context, email string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, `
+		SELECT id FROM users WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL
+	`, email).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrUserNotFound
+	}
+	return id, err
+}
+
+func (r *PostgresOrganizationRepository) AddMember(ctx context.Context, orgID, userID uuid.UUID, role userEntity.OrgRole) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockOrganization(ctx, tx, orgID); err != nil {
+			return err
+		}
+		return joinOrganization(ctx, tx, orgID, userID, role)
+	})
+}
+
+func (r *PostgresOrganizationRepository) SetMemberRole(ctx context.Context, orgID, userID uuid.UUID, role userEntity.OrgRole) (userEntity.OrgRole, error) {
+	var previous userEntity.OrgRole
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		previous, err = lockMember(ctx, tx, orgID, userID)
+		if err != nil {
+			return err
+		}
+		if previous == userEntity.OrgRoleOwner && role != userEntity.OrgRoleOwner {
+			if err := ensureAnotherOwner(ctx, tx, orgID); err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec(ctx, `UPDATE users SET org_role = $2, updated_at = NOW() WHERE id = $1`,
+			userID, string(role))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
+func (r *PostgresOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		role, err := lockMember(ctx, tx, orgID, userID)
+		if err != nil {
+			return err
+		}
+		if role == userEntity.OrgRoleOwner {
+			if err := ensureAnotherOwner(ctx, tx, orgID); err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE users SET org_id = NULL, org_role = NULL, org_joined_at = NULL, updated_at = NOW()
+			WHERE id = $1
+		`, userID)
+		return err
+	})
+}
+
+// lockOrganization блокирует строку организации: изменения состава участников
+// одной организации выполняются по очереди, и проверка последнего владельца не гоняется
+func lockOrganization(ctx context.Context, tx pgx.Tx, orgID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM organizations WHERE id = $1 FOR UPDATE`, orgID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrOrganizationNotFound
+	}
+	return err
+}
+
+func lockMember(ctx context.Context, tx pgx.Tx, orgID, userID uuid.UUID) (userEntity.OrgRole, error) {
+	if err := lockOrganization(ctx, tx, orgID); err != nil {
+		return "", err
+	}
+	var role userEntity.OrgRole
+	err := tx.QueryRow(ctx, `
+		SELECT org_role FROM users WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL FOR UPDATE
+	`, userID, orgID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrMemberNotFound
+	}
+	return role, err
+}
+
+func ensureAnotherOwner(ctx context.Context, tx pgx.Tx, orgID uuid.UUID) error {
+	var owners int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM users WHERE org_id = $1 AND org_role = $2 AND deleted_at IS NULL
+	`, orgID, string(userEntity.OrgRoleOwner)).Scan(&owners)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func joinOrganization(ctx context.Context, tx pgx.Tx, orgID, userID uuid.UUID, role userEntity.OrgRole) error {
+	var current *uuid.UUID
+	err := tx.QueryRow(ctx, `
+		SELECT org_id FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, userID).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		return ErrAlreadyInOrganization
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE users SET org_id = $2, org_role = $3, org_joined_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, userID, orgID, string(role))
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
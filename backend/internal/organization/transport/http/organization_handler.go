@@ -0,0 +1,369 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/organization/entity"
+	"github.com/kostinp/edu-platform-backend/internal/organization/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/labstack/echo/v4"
+)
+
+type OrganizationHandler struct {
+	usecase usecase.OrganizationUsecase
+}
+
+func NewOrganizationHandler(uc usecase.OrganizationUsecase) *OrganizationHandler {
+	return &OrganizationHandler{usecase: uc}
+}
+
+// CreateOrganization godoc
+// @Summary Создать организацию
+// @Description Только администраторы платформы. Владелец — owner_id, owner_email или сам создатель;
+// @Description он не должен состоять в другой организации
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body usecase.CreateOrganizationInput true "Организация"
+// @Success 201 {object} entity.Organization
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations [post]
+func (h *OrganizationHandler) Create(c echo.Context) error {
+	actor, ok := currentActor(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.CreateOrganizationInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	org, err := h.usecase.Create(c.Request().Context(), actor, req)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, org)
+}
+
+// ListOrganizations godoc
+// @Summary Все организации
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param sort_by query string false "name, created_at"
+// @Param order query string false "asc / desc"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Organization]
+// @Router /admin/organizations [get]
+func (h *OrganizationHandler) List(c echo.Context) error {
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	orgs, total, err := h.usecase.List(c.Request().Context(), pag)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Organization]{
+		Items:  orgs,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// GetOrganization godoc
+// @Summary Организация
+// @Description Участникам организации и администраторам платформы
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID организации"
+// @Success 200 {object} entity.Organization
+// @Failure 404 {object} map[string]string
+// @Router /organizations/{id} [get]
+func (h *OrganizationHandler) Get(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid organization id"})
+	}
+	org, err := h.usecase.Get(c.Request().Context(), id)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, org)
+}
+
+// GetMyOrganization godoc
+// @Summary Моя организация
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} entity.Organization
+// @Failure 404 {object} map[string]string
+// @Router /me/organization [get]
+func (h *OrganizationHandler) GetMine(c echo.Context) error {
+	orgID := middleware.CurrentOrgID(c)
+	if orgID == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": usecase.ErrNotInOrganization.Error()})
+	}
+	org, err := h.usecase.Get(c.Request().Context(), *orgID)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, org)
+}
+
+// UpdateOrganization godoc
+// @Summary Переименовать организацию
+// @Description Владельцам и администраторам организации
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID организации"
+// @Param request body usecase.UpdateOrganizationInput true "Название и slug"
+// @Success 200 {object} entity.Organization
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id} [put]
+func (h *OrganizationHandler) Update(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid organization id"})
+	}
+	var req usecase.UpdateOrganizationInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	org, err := h.usecase.Update(c.Request().Context(), id, req)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, org)
+}
+
+// DeleteOrganization godoc
+// @Summary Удалить организацию
+// @Description Только без курсов, категорий и тегов; участники остаются на платформе
+// @Tags organizations
+// @Security BearerAuth
+// @Param id path string true "ID организации"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id} [delete]
+func (h *OrganizationHandler) Delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid organization id"})
+	}
+	if err := h.usecase.Delete(c.Request().Context(), id); err != nil {
+		return organizationError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListMembers godoc
+// @Summary Участники организации
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID организации"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param sort_by query string false "joined_at, username, email"
+// @Param order query string false "asc / desc"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Member]
+// @Failure 400 {object} map[string]string
+// @Router /organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid organization id"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	members, total, err := h.usecase.ListMembers(c.Request().Context(), id, pag)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Member]{
+		Items:  members,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// AddMember godoc
+// @Summary Добавить участника
+// @Description Пользователь по id или email; назначать владельцев могут только владельцы
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID организации"
+// @Param request body usecase.AddMemberInput true "Пользователь и роль"
+// @Success 201 {object} entity.Member
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id}/members [post]
+func (h *OrganizationHandler) AddMember(c echo.Context) error {
+	actor, ok := currentActor(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid organization id"})
+	}
+	var req usecase.AddMemberInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	member, err := h.usecase.AddMember(c.Request().Context(), actor, id, req)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, member)
+}
+
+// SetMemberRole godoc
+// @Summary Изменить роль участника
+// @Description Роль owner выдают и снимают только владельцы; последнего владельца понизить нельзя
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID организации"
+// @Param user_id path string true "ID пользователя"
+// @Param request body usecase.SetMemberRoleInput true "Роль"
+// @Success 200 {object} entity.Member
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id}/members/{user_id} [put]
+func (h *OrganizationHandler) SetMemberRole(c echo.Context) error {
+	actor, ok := currentActor(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, userID, err := memberParams(c)
+	if err != nil {
+		return err
+	}
+	var req usecase.SetMemberRoleInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	member, err := h.usecase.SetMemberRole(c.Request().Context(), actor, id, userID, req.OrgRole)
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember godoc
+// @Summary Исключить участника
+// @Description Контент, созданный участником, остаётся в организации
+// @Tags organizations
+// @Security BearerAuth
+// @Param id path string true "ID организации"
+// @Param user_id path string true "ID пользователя"
+// @Success 204 {string} string "No Content"
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /organizations/{id}/members/{user_id} [delete]
+func (h *OrganizationHandler) RemoveMember(c echo.Context) error {
+	actor, ok := currentActor(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, userID, err := memberParams(c)
+	if err != nil {
+		return err
+	}
+	if err := h.usecase.RemoveMember(c.Request().Context(), actor, id, userID); err != nil {
+		return organizationError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// LeaveOrganization godoc
+// @Summary Выйти из организации
+// @Description Последний владелец выйти не может — сначала нужно назначить другого
+// @Tags organizations
+// @Security BearerAuth
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/organization [delete]
+func (h *OrganizationHandler) Leave(c echo.Context) error {
+	actor, ok := currentActor(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	if err := h.usecase.Leave(c.Request().Context(), actor.UserID, middleware.CurrentOrgID(c)); err != nil {
+		return organizationError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// currentActor собирает Actor из пользователя, которого загрузил LoadCurrentUser
+func currentActor(c echo.Context) (usecase.Actor, bool) {
+	user, ok := c.Get(middleware.UserKey).(*userEntity.User)
+	if !ok {
+		return usecase.Actor{}, false
+	}
+	return usecase.Actor{
+		UserID:        user.ID,
+		PlatformAdmin: user.Role == userEntity.RoleAdmin,
+		OrgRole:       user.OrgRole,
+	}, true
+}
+
+func memberParams(c echo.Context) (uuid.UUID, uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, "invalid organization id")
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, "invalid user id")
+	}
+	return id, userID, nil
+}
+
+func organizationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrOrganizationName),
+		errors.Is(err, usecase.ErrInvalidOrgRole),
+		errors.Is(err, usecase.ErrMemberRequired),
+		errors.Is(err, slug.ErrInvalidSlug):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrOwnerOnly):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrOrganizationNotFound),
+		errors.Is(err, usecase.ErrMemberNotFound),
+		errors.Is(err, usecase.ErrUserNotFound),
+		errors.Is(err, usecase.ErrNotInOrganization):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAlreadyInOrganization),
+		errors.Is(err, usecase.ErrLastOwner),
+		errors.Is(err, usecase.ErrOrganizationNotEmpty),
+		errors.Is(err, slug.ErrSlugTaken):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/organization/entity"
+	"github.com/kostinp/edu-platform-backend/internal/organization/repository"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/slug"
+	userEntity "github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var (
+	ErrOrganizationNotFound  = repository.ErrOrganizationNotFound
+	ErrOrganizationNotEmpty  = repository.ErrOrganizationNotEmpty
+	ErrUserNotFound          = repository.ErrUserNotFound
+	ErrAlreadyInOrganization = repository.ErrAlreadyInOrganization
+	ErrMemberNotFound        = repository.ErrMemberNotFound
+	ErrLastOwner             = repository.ErrLastOwner
+	ErrOrganizationName      = errors.New("organization name must be between 2 and 200 characters")
+	ErrInvalidOrgRole        = errors.New("org_role must be one of owner, admin, member")
+	ErrMemberRequired        = errors.New("user_id or email is required")
+	ErrOwnerOnly             = errors.New("only organization owners can grant, change or revoke the owner role")
+	ErrNotInOrganization     = errors.New("you are not a member of any organization")
+)
+
+const (
+	orgNameMinLen = 2
+	orgNameMaxLen = 200
+)
+
+// Actor — кто выполняет действие. Доступ к организации уже проверен ABAC;
+// usecase отвечает только за правила внутри неё (кто может трогать владельцев)
+type Actor struct {
+	UserID uuid.UUID
+	// Администратор платформы управляет любой организацией без ограничений
+	PlatformAdmin bool
+	OrgRole       userEntity.OrgRole
+}
+
+// CreateOrganizationInput — новая организация. Владелец по умолчанию — создатель
+type CreateOrganizationInput struct {
+	Name string `json:"name" example:"Школа №57"`
+	// Пусто — сгенерировать из названия
+	Slug       string     `json:"slug,omitempty" example:"school-57"`
+	OwnerID    *uuid.UUID `json:"owner_id,omitempty"`
+	OwnerEmail string     `json:"owner_email,omitempty" example:"director@school57.ru"`
+}
+
+// UpdateOrganizationInput — переименование; пустой slug сохраняет текущий
+type UpdateOrganizationInput struct {
+	Name string `json:"name" example:"Школа №57"`
+	Slug string `json:"slug,omitempty" example:"school-57"`
+}
+
+// AddMemberInput — пользователь по id или email; роль по умолчанию member
+type AddMemberInput struct {
+	UserID  *uuid.UUID         `json:"user_id,omitempty"`
+	Email   string             `json:"email,omitempty" example:"student@school57.ru"`
+	OrgRole userEntity.OrgRole `json:"org_role,omitempty" example:"member"`
+}
+
+// SetMemberRoleInput — новая роль участника в организации
+type SetMemberRoleInput struct {
+	OrgRole userEntity.OrgRole `json:"org_role" example:"admin"`
+}
+
+type OrganizationUsecase interface {
+	Create(ctx context.Context, actor Actor, in CreateOrganizationInput) (*entity.Organization, error)
+	Update(ctx context.Context, id uuid.UUID, in UpdateOrganizationInput) (*entity.Organization, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Get(ctx context.Context, id uuid.UUID) (*entity.Organization, error)
+	List(ctx context.Context, pag pagination.Params) ([]*entity.Organization, int, error)
+
+	ListMembers(ctx context.Context, orgID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error)
+	AddMember(ctx context.Context, actor Actor, orgID uuid.UUID, in AddMemberInput) (*entity.Member, error)
+	SetMemberRole(ctx context.Context, actor Actor, orgID, userID uuid.UUID, role userEntity.OrgRole) (*entity.Member, error)
+	RemoveMember(ctx context.Context, actor Actor, orgID, userID uuid.UUID) error
+	// Leave — выход пользователя из своей организации
+	Leave(ctx context.Context, userID uuid.UUID, orgID *uuid.UUID) error
+}
+
+type organizationUsecase struct {
+	repo repository.OrganizationRepository
+}
+
+func NewOrganizationUsecase(repo repository.OrganizationRepository) OrganizationUsecase {
+	return &organizationUsecase{repo: repo}
+}
+
+func (u *organizationUsecase) Create(ctx context.Context, actor Actor, in CreateOrganizationInput) (*entity.Organization, error) {
+	name, err := validOrgName(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	ownerID := actor.UserID
+	switch {
+	case in.OwnerID != nil:
+		ownerID = *in.OwnerID
+	case strings.TrimSpace(in.OwnerEmail) != "":
+		if ownerID, err = u.repo.FindUserByEmail(ctx, strings.TrimSpace(in.OwnerEmail)); err != nil {
+			return nil, err
+		}
+	}
+
+	org := &entity.Organization{ID: uuid.New(), Name: name, CreatedBy: &actor.UserID}
+	if err := u.assignSlug(ctx, org, in.Slug); err != nil {
+		return nil, err
+	}
+	if err := u.repo.Create(ctx, org, ownerID); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (u *organizationUsecase) Update(ctx context.Context, id uuid.UUID, in UpdateOrganizationInput) (*entity.Organization, error) {
+	name, err := validOrgName(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	org, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	org.Name = name
+	if strings.TrimSpace(in.Slug) != "" && in.Slug != org.Slug {
+		if err := u.assignSlug(ctx, org, in.Slug); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.repo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (u *organizationUsecase) Delete(ctx context.Context, id uuid.UUID) error {
+	return u.repo.Delete(ctx, id)
+}
+
+func (u *organizationUsecase) Get(ctx context.Context, id uuid.UUID) (*entity.Organization, error) {
+	return u.repo.GetByID(ctx, id)
+}
+
+func (u *organizationUsecase) List(ctx context.Context, pag pagination.Params) ([]*entity.Organization, int, error) {
+	return u.repo.List(ctx, pag)
+}
+
+func (u *organizationUsecase) ListMembers(ctx context.Context, orgID uuid.UUID, pag pagination.Params) ([]*entity.Member, int, error) {
+	return u.repo.ListMembers(ctx, orgID, pag)
+}
+
+func (u *organizationUsecase) AddMember(ctx context.Context, actor Actor, orgID uuid.UUID, in AddMemberInput) (*entity.Member, error) {
+	role := in.OrgRole
+	if role == "" {
+		role = userEntity.OrgRoleMember
+	}
+	if !role.Valid() {
+		return nil, ErrInvalidOrgRole
+	}
+	if role == userEntity.OrgRoleOwner && !actor.canManageOwners() {
+		return nil, ErrOwnerOnly
+	}
+
+	var userID uuid.UUID
+	switch {
+	case in.UserID != nil:
+		userID = *in.UserID
+	case strings.TrimSpace(in.Email) != "":
+		id, err := u.repo.FindUserByEmail(ctx, strings.TrimSpace(in.Email))
+		if err != nil {
+			return nil, err
+		}
+		userID = id
+	default:
+		return nil, ErrMemberRequired
+	}
+
+	if err := u.repo.AddMember(ctx, orgID, userID, role); err != nil {
+		return nil, err
+	}
+	return u.repo.GetMember(ctx, orgID, userID)
+}
+
+func (u *organizationUsecase) SetMemberRole(ctx context.Context, actor Actor, orgID, userID uuid.UUID, role userEntity.OrgRole) (*entity.Member, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidOrgRole
+	}
+	member, err := u.repo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if (role == userEntity.OrgRoleOwner || member.OrgRole == userEntity.OrgRoleOwner) && !actor.canManageOwners() {
+		return nil, ErrOwnerOnly
+	}
+	if _, err := u.repo.SetMemberRole(ctx, orgID, userID, role); err != nil {
+		return nil, err
+	}
+	member.OrgRole = role
+	return member, nil
+}
+
+func (u *organizationUsecase) RemoveMember(ctx context.Context, actor Actor, orgID, userID uuid.UUID) error {
+	member, err := u.repo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member.OrgRole == userEntity.OrgRoleOwner && !actor.canManageOwners() {
+		return ErrOwnerOnly
+	}
+	return u.repo.RemoveMember(ctx, orgID, userID)
+}
+
+func (u *organizationUsecase) Leave(ctx context.Context, userID uuid.UUID, orgID *uuid.UUID) error {
+	if orgID == nil {
+		return ErrNotInOrganization
+	}
+	return u.repo.RemoveMember(ctx, *orgID, userID)
+}
+
+// assignSlug проверяет явно переданный slug или генерирует его из названия
+func (u *organizationUsecase) assignSlug(ctx context.Context, org *entity.Organization, requested string) error {
+	exists := func(ctx context.Context, s string) (bool, error) {
+		return u.repo.SlugExists(ctx, s, org.ID)
+	}
+	if requested = strings.TrimSpace(requested); requested != "" {
+		if !slug.Valid(requested) {
+			return slug.ErrInvalidSlug
+		}
+		taken, err := exists(ctx, requested)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return slug.ErrSlugTaken
+		}
+		org.Slug = requested
+		return nil
+	}
+	generated, err := slug.Unique(ctx, slug.Make(org.Name), "organization", exists)
+	if err != nil {
+		return err
+	}
+	org.Slug = generated
+	return nil
+}
+
+func (a Actor) canManageOwners() bool {
+	return a.PlatformAdmin || a.OrgRole == userEntity.OrgRoleOwner
+}
+
+func validOrgName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if n := utf8.RuneCountInString(name); n < orgNameMinLen || n > orgNameMaxLen {
+		return "", ErrOrganizationName
+	}
+	return name, nil
+}
@@ -0,0 +1,18 @@
+// internal/organization/wire.go
+package organization
+
+import (
+	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/organization/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/organization/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/organization/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+)
+
+var OrganizationSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresOrganizationRepository,
+	wire.Bind(new(repository.OrganizationRepository), new(*repository.PostgresOrganizationRepository)),
+	usecase.NewOrganizationUsecase,
+	http.NewOrganizationHandler,
+)
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LessonProgress — отметка о просмотре и прохождении урока
+type LessonProgress struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	LessonID     uuid.UUID  `json:"lesson_id"`
+	CourseID     uuid.UUID  `json:"course_id"`
+	LastViewedAt time.Time  `json:"last_viewed_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// CourseProgress — сводка по курсу, на который записан пользователь
+type CourseProgress struct {
+	CourseID         uuid.UUID  `json:"course_id"`
+	CourseTitle      string     `json:"course_title"`
+	CourseSlug       string     `json:"course_slug"`
+	TotalLessons     int        `json:"total_lessons"`
+	CompletedLessons int        `json:"completed_lessons"`
+	Percent          int        `json:"percent"`
+	LastActivityAt   *time.Time `json:"last_activity_at,omitempty"`
+}
+
+// ContinuePoint — урок, с которого стоит продолжить обучение
+type ContinuePoint struct {
+	CourseID    uuid.UUID `json:"course_id"`
+	CourseTitle string    `json:"course_title"`
+	LessonID    uuid.UUID `json:"lesson_id"`
+	LessonTitle string    `json:"lesson_title"`
+	ModuleTitle string    `json:"module_title"`
+}
+
+// Streak — серия дней подряд с занятиями
+type Streak struct {
+	Current     int        `json:"current"`
+	Longest     int        `json:"longest"`
+	ActiveToday bool       `json:"active_today"`
+	LastActive  *time.Time `json:"last_active,omitempty"`
+}
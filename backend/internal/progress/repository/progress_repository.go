@@ -0,0 +1,164 @@
+// internal/progress/repository/progress_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/progress/entity"
+)
+
+var (
+	ErrLessonNotFound  = errors.New("lesson not found")
+	ErrNothingToResume = errors.New("nothing to continue")
+)
+
+type ProgressRepository interface {
+	MarkViewed(ctx context.Context, userID, lessonID uuid.UUID, at time.Time) (*entity.LessonProgress, error)
+	MarkCompleted(ctx context.Context, userID, lessonID uuid.UUID, at time.Time) (*entity.LessonProgress, error)
+	ListCourseProgress(ctx context.Context, userID uuid.UUID) ([]*entity.CourseProgress, error)
+	GetContinuePoint(ctx context.Context, userID uuid.UUID) (*entity.ContinuePoint, error)
+	ListActivityDays(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error)
+}
+
+type PostgresProgressRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresProgressRepository(db *pgxpool.Pool) *PostgresProgressRepository {
+	return &PostgresProgressRepository{db: db}
+}
+
+func (r *PostgresProgressRepository) MarkViewed(ctx context.Context, userID, lessonID uuid.UUID, at time.Time) (*entity.LessonProgress, error) {
+	return r.upsert(ctx, userID, lessonID, at, false)
+}
+
+func (r *PostgresProgressRepository) MarkCompleted(ctx context.Context, userID, lessonID uuid.UUID, at time.Time) (*entity.LessonProgress, error) {
+	return r.upsert(ctx, userID, lessonID, at, true)
+}
+
+// upsert обновляет прогресс и отмечает день активности в одной транзакции.
+// Курс урока определяется через модуль; удалённые уроки не учитываются
+func (r *PostgresProgressRepository) upsert(ctx context.Context, userID, lessonID uuid.UUID, at time.Time, completed bool) (*entity.LessonProgress, error) {
+	p := &entity.LessonProgress{}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO lesson_progress (user_id, lesson_id, course_id, last_viewed_at, completed_at)
+			SELECT $1, l.id, m.course_id, $3, CASE WHEN $4 THEN $3::timestamp END
+			FROM lessons l
+			JOIN modules m ON m.id = l.module_id
+			WHERE l.id = $2 AND l.deleted_at IS NULL
+			ON CONFLICT (user_id, lesson_id) DO UPDATE
+			SET last_viewed_at = EXCLUDED.last_viewed_at,
+				completed_at = COALESCE(lesson_progress.completed_at, EXCLUDED.completed_at)
+			RETURNING user_id, lesson_id, course_id, last_viewed_at, completed_at
+		`, userID, lessonID, at, completed).Scan(&p.UserID, &p.LessonID, &p.CourseID, &p.LastViewedAt, &p.CompletedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrLessonNotFound
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO learning_activity_days (user_id, day) VALUES ($1, $2::date)
+			ON CONFLICT DO NOTHING
+		`, userID, at)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ListCourseProgress — прогресс по всем курсам, на которые записан пользователь,
+// сначала недавно открытые
+func (r *PostgresProgressRepository) ListCourseProgress(ctx context.Context, userID uuid.UUID) ([]*entity.CourseProgress, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.id, c.title, c.slug,
+			(SELECT COUNT(*) FROM lessons l JOIN modules m ON m.id = l.module_id
+				WHERE m.course_id = c.id AND l.deleted_at IS NULL AND m.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM lesson_progress p JOIN lessons l ON l.id = p.lesson_id AND l.deleted_at IS NULL
+				WHERE p.user_id = e.user_id AND p.course_id = c.id AND p.completed_at IS NOT NULL),
+			(SELECT MAX(p.last_viewed_at) FROM lesson_progress p WHERE p.user_id = e.user_id AND p.course_id = c.id)
+		FROM course_enrollments e
+		JOIN courses c ON c.id = e.course_id AND c.deleted_at IS NULL
+		WHERE e.user_id = $1
+		ORDER BY 6 DESC NULLS LAST, e.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*entity.CourseProgress{}
+	for rows.Next() {
+		cp := &entity.CourseProgress{}
+		if err := rows.Scan(&cp.CourseID, &cp.CourseTitle, &cp.CourseSlug, &cp.TotalLessons, &cp.CompletedLessons, &cp.LastActivityAt); err != nil {
+			return nil, err
+		}
+		if cp.TotalLessons > 0 {
+			cp.Percent = cp.CompletedLessons * 100 / cp.TotalLessons
+		}
+		items = append(items, cp)
+	}
+	return items, rows.Err()
+}
+
+// GetContinuePoint — первый непройденный урок (по порядку модулей и уроков)
+// в курсе, который пользователь открывал последним
+func (r *PostgresProgressRepository) GetContinuePoint(ctx context.Context, userID uuid.UUID) (*entity.ContinuePoint, error) {
+	cp := &entity.ContinuePoint{}
+	err := r.db.QueryRow(ctx, `
+		WITH last_course AS (
+			SELECT p.course_id
+			FROM lesson_progress p
+			JOIN courses c ON c.id = p.course_id AND c.deleted_at IS NULL
+			WHERE p.user_id = $1
+			ORDER BY p.last_viewed_at DESC
+			LIMIT 1
+		)
+		SELECT c.id, c.title, l.id, l.title, m.title
+		FROM last_course lc
+		JOIN courses c ON c.id = lc.course_id
+		JOIN modules m ON m.course_id = c.id AND m.deleted_at IS NULL
+		JOIN lessons l ON l.module_id = m.id AND l.deleted_at IS NULL
+		LEFT JOIN lesson_progress p ON p.lesson_id = l.id AND p.user_id = $1
+		WHERE p.completed_at IS NULL
+		ORDER BY m.ordinal, l.ordinal
+		LIMIT 1
+	`, userID).Scan(&cp.CourseID, &cp.CourseTitle, &cp.LessonID, &cp.LessonTitle, &cp.ModuleTitle)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNothingToResume
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (r *PostgresProgressRepository) ListActivityDays(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT day FROM learning_activity_days
+		WHERE user_id = $1 AND day >= $2::date
+		ORDER BY day DESC
+	`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []time.Time{}
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
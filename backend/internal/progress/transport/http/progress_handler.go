@@ -0,0 +1,150 @@
+// internal/progress/transport/http/progress_handler.go
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/progress/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type ProgressHandler struct {
+	usecase usecase.ProgressUsecase
+}
+
+func NewProgressHandler(uc usecase.ProgressUsecase) *ProgressHandler {
+	return &ProgressHandler{usecase: uc}
+}
+
+// MarkViewed godoc
+// @Summary Отметить просмотр урока
+// @Tags Progress
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID урока"
+// @Success 200 {object} entity.LessonProgress
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /lessons/{id}/view [post]
+func (h *ProgressHandler) MarkViewed(c echo.Context) error {
+	userID, lessonID, err := userAndLesson(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	progress, err := h.usecase.MarkViewed(c.Request().Context(), userID, lessonID)
+	if err != nil {
+		return progressError(c, err)
+	}
+	return c.JSON(http.StatusOK, progress)
+}
+
+// MarkCompleted godoc
+// @Summary Отметить урок пройденным
+// @Tags Progress
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID урока"
+// @Success 200 {object} entity.LessonProgress
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /lessons/{id}/complete [post]
+func (h *ProgressHandler) MarkCompleted(c echo.Context) error {
+	userID, lessonID, err := userAndLesson(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	progress, err := h.usecase.MarkCompleted(c.Request().Context(), userID, lessonID)
+	if err != nil {
+		return progressError(c, err)
+	}
+	return c.JSON(http.StatusOK, progress)
+}
+
+// ListMyProgress godoc
+// @Summary Мой прогресс по курсам
+// @Tags Progress
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.CourseProgress
+// @Failure 500 {object} map[string]string
+// @Router /me/progress [get]
+func (h *ProgressHandler) ListMine(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	items, err := h.usecase.ListCourseProgress(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// Continue godoc
+// @Summary Урок, с которого продолжить обучение
+// @Tags Progress
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} entity.ContinuePoint
+// @Failure 404 {object} map[string]string
+// @Router /me/progress/continue [get]
+func (h *ProgressHandler) Continue(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	point, err := h.usecase.Continue(c.Request().Context(), userID)
+	if err != nil {
+		return progressError(c, err)
+	}
+	return c.JSON(http.StatusOK, point)
+}
+
+// Streak godoc
+// @Summary Серия дней с занятиями
+// @Tags Progress
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} entity.Streak
+// @Failure 500 {object} map[string]string
+// @Router /me/streak [get]
+func (h *ProgressHandler) Streak(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	streak, err := h.usecase.Streak(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, streak)
+}
+
+func userAndLesson(c echo.Context) (uuid.UUID, uuid.UUID, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	lessonID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("invalid lesson id")
+	}
+	return userID, lessonID, nil
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func progressError(c echo.Context, err error) error {
+	if errors.Is(err, usecase.ErrLessonNotFound) || errors.Is(err, usecase.ErrNothingToResume) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
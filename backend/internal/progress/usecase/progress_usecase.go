@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/progress/entity"
+	"github.com/kostinp/edu-platform-backend/internal/progress/repository"
+)
+
+// streakWindow — за какой период считается самая длинная серия
+const streakWindow = 366 * 24 * time.Hour
+
+var (
+	ErrLessonNotFound  = repository.ErrLessonNotFound
+	ErrNothingToResume = repository.ErrNothingToResume
+)
+
+type ProgressUsecase interface {
+	MarkViewed(ctx context.Context, userID, lessonID uuid.UUID) (*entity.LessonProgress, error)
+	MarkCompleted(ctx context.Context, userID, lessonID uuid.UUID) (*entity.LessonProgress, error)
+	ListCourseProgress(ctx context.Context, userID uuid.UUID) ([]*entity.CourseProgress, error)
+	Continue(ctx context.Context, userID uuid.UUID) (*entity.ContinuePoint, error)
+	Streak(ctx context.Context, userID uuid.UUID) (*entity.Streak, error)
+}
+
+type progressUsecase struct {
+	repo repository.ProgressRepository
+	now  func() time.Time
+}
+
+func NewProgressUsecase(repo repository.ProgressRepository) ProgressUsecase {
+	return &progressUsecase{repo: repo, now: time.Now}
+}
+
+func (u *progressUsecase) MarkViewed(ctx context.Context, userID, lessonID uuid.UUID) (*entity.LessonProgress, error) {
+	return u.repo.MarkViewed(ctx, userID, lessonID, u.now().UTC())
+}
+
+func (u *progressUsecase) MarkCompleted(ctx context.Context, userID, lessonID uuid.UUID) (*entity.LessonProgress, error) {
+	return u.repo.MarkCompleted(ctx, userID, lessonID, u.now().UTC())
+}
+
+func (u *progressUsecase) ListCourseProgress(ctx context.Context, userID uuid.UUID) ([]*entity.CourseProgress, error) {
+	return u.repo.ListCourseProgress(ctx, userID)
+}
+
+func (u *progressUsecase) Continue(ctx context.Context, userID uuid.UUID) (*entity.ContinuePoint, error) {
+	return u.repo.GetContinuePoint(ctx, userID)
+}
+
+// Streak считает серию по дням UTC. Текущая серия не прерывается,
+// пока сегодня ещё не было занятий, но вчера были
+func (u *progressUsecase) Streak(ctx context.Context, userID uuid.UUID) (*entity.Streak, error) {
+	today := truncateDay(u.now().UTC())
+	days, err := u.repo.ListActivityDays(ctx, userID, today.Add(-streakWindow))
+	if err != nil {
+		return nil, err
+	}
+	return calculateStreak(days, today), nil
+}
+
+// calculateStreak ожидает дни в порядке убывания
+func calculateStreak(days []time.Time, today time.Time) *entity.Streak {
+	streak := &entity.Streak{}
+	if len(days) == 0 {
+		return streak
+	}
+	last := truncateDay(days[0])
+	streak.LastActive = &last
+	streak.ActiveToday = last.Equal(today)
+
+	run := 1
+	for i := 1; i < len(days); i++ {
+		if truncateDay(days[i-1]).Sub(truncateDay(days[i])) == 24*time.Hour {
+			run++
+			continue
+		}
+		if streak.Current == 0 && isCurrent(last, today) {
+			streak.Current = run
+		}
+		streak.Longest = max(streak.Longest, run)
+		run = 1
+		last = time.Time{}
+	}
+	if streak.Current == 0 && isCurrent(last, today) {
+		streak.Current = run
+	}
+	streak.Longest = max(streak.Longest, run)
+	return streak
+}
+
+// isCurrent — первая серия продолжается, если последний день активности сегодня или вчера
+func isCurrent(last, today time.Time) bool {
+	return !last.IsZero() && today.Sub(last) <= 24*time.Hour
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateStreak(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time {
+		// Время внутри дня не должно влиять на серию
+		return today.AddDate(0, 0, -offset).Add(15 * time.Hour)
+	}
+	tests := []struct {
+		name        string
+		days        []time.Time
+		current     int
+		longest     int
+		activeToday bool
+	}{
+		{"no activity", nil, 0, 0, false},
+		{"today only", []time.Time{day(0)}, 1, 1, true},
+		{"yesterday keeps streak", []time.Time{day(1), day(2)}, 2, 2, false},
+		{"two days ago breaks streak", []time.Time{day(2), day(3), day(4)}, 0, 3, false},
+		{"gap day", []time.Time{day(0), day(1), day(3), day(4), day(5)}, 2, 3, true},
+		{"longer run in the past", []time.Time{day(1), day(5), day(6), day(7), day(8)}, 1, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateStreak(tt.days, today)
+			if got.Current != tt.current || got.Longest != tt.longest || got.ActiveToday != tt.activeToday {
+				t.Fatalf("calculateStreak() = current %d, longest %d, active today %v; want %d, %d, %v",
+					got.Current, got.Longest, got.ActiveToday, tt.current, tt.longest, tt.activeToday)
+			}
+		})
+	}
+}
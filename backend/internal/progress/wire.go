@@ -0,0 +1,18 @@
+// internal/progress/wire.go
+package progress
+
+import (
+	"github.com/google/wire"
+	"github.com/kostinp/edu-platform-backend/internal/progress/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/progress/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/progress/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+)
+
+var ProgressSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresProgressRepository,
+	wire.Bind(new(repository.ProgressRepository), new(*repository.PostgresProgressRepository)),
+	usecase.NewProgressUsecase,
+	http.NewProgressHandler,
+)
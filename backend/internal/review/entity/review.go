@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/entity"
+)
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Review — отзыв пользователя о курсе. AuthorID — автор отзыва.
+type Review struct {
+	entity.Base
+
+	CourseID        uuid.UUID    `json:"course_id"`
+	Rating          int          `json:"rating"`
+	Text            string       `json:"text"`
+	Status          ReviewStatus `json:"status"`
+	RejectionReason string       `json:"rejection_reason,omitempty"`
+	ModeratedBy     *uuid.UUID   `json:"moderated_by,omitempty"`
+	ModeratedAt     *time.Time   `json:"moderated_at,omitempty"`
+}
@@ -0,0 +1,220 @@
+// internal/review/repository/review_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/review/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
+)
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrReviewExists   = errors.New("review for this course already exists")
+)
+
+type ReviewRepository interface {
+	// Create возвращает ErrReviewExists, если автор уже оставил отзыв о курсе
+	Create(ctx context.Context, review *entity.Review) error
+	Update(ctx context.Context, review *entity.Review) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Review, error)
+	GetByCourseAndAuthor(ctx context.Context, courseID, authorID uuid.UUID) (*entity.Review, error)
+	// ListByCourse и ListByStatus видят отзывы о курсах общего каталога и организации viewerOrg
+	ListByCourse(ctx context.Context, courseID uuid.UUID, status entity.ReviewStatus, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error)
+	ListByStatus(ctx context.Context, status entity.ReviewStatus, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error)
+	GetAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	// GetOrgID — организация курса, о котором отзыв (resource.org_id в ABAC); nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
+}
+
+type PostgresReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+var allowedSortFields = map[string]string{
+	"created_at": "cr.created_at",
+	"rating":     "cr.rating",
+}
+
+const reviewColumns = `cr.id, cr.course_id, cr.author_id, cr.rating, COALESCE(cr.text, ''), cr.status,
+	COALESCE(cr.rejection_reason, ''), cr.moderated_by, cr.moderated_at, cr.created_at, cr.updated_at`
+
+// reviewCourseJoin — отзыв вместе с курсом, по которому проверяется организация
+const reviewCourseJoin = `course_reviews cr JOIN courses c ON c.id = cr.course_id`
+
+func NewPostgresReviewRepository(db *pgxpool.Pool) *PostgresReviewRepository {
+	return &PostgresReviewRepository{db: db}
+}
+
+func (r *PostgresReviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO course_reviews (id, course_id, author_id, rating, text, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, review.ID, review.CourseID, review.AuthorID, review.Rating, review.Text, review.Status, review.CreatedAt, review.UpdatedAt)
+	// Параллельная отправка второго отзыва упирается в UNIQUE (course_id, author_id)
+	if isUniqueViolation(err) {
+		return ErrReviewExists
+	}
+	return err
+}
+
+// Update сохраняет отзыв и пересчитывает рейтинг курса в одной транзакции:
+// смена статуса (модерация или повторная отправка) меняет набор учитываемых отзывов.
+func (r *PostgresReviewRepository) Update(ctx context.Context, review *entity.Review) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx, `
+			UPDATE course_reviews
+			SET rating = $1, text = $2, status = $3, rejection_reason = $4, moderated_by = $5, moderated_at = $6, updated_at = $7
+			WHERE id = $8
+		`, review.Rating, review.Text, review.Status, review.RejectionReason, review.ModeratedBy, review.ModeratedAt, review.UpdatedAt, review.ID)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return ErrReviewNotFound
+		}
+		return recalculateCourseRating(ctx, tx, review.CourseID)
+	})
+}
+
+func (r *PostgresReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var courseID uuid.UUID
+		err := tx.QueryRow(ctx, `DELETE FROM course_reviews WHERE id = $1 RETURNING course_id`, id).Scan(&courseID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReviewNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return recalculateCourseRating(ctx, tx, courseID)
+	})
+}
+
+func (r *PostgresReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Review, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+reviewColumns+` FROM course_reviews cr WHERE cr.id = $1`, id)
+	review, err := scanReview(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrReviewNotFound
+	}
+	return review, err
+}
+
+func (r *PostgresReviewRepository) GetByCourseAndAuthor(ctx context.Context, courseID, authorID uuid.UUID) (*entity.Review, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+reviewColumns+` FROM course_reviews cr WHERE cr.course_id = $1 AND cr.author_id = $2
+	`, courseID, authorID)
+	review, err := scanReview(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrReviewNotFound
+	}
+	return review, err
+}
+
+func (r *PostgresReviewRepository) ListByCourse(ctx context.Context, courseID uuid.UUID, status entity.ReviewStatus, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error) {
+	baseQuery := `SELECT ` + reviewColumns + ` FROM ` + reviewCourseJoin + `
+		WHERE cr.course_id = $3 AND cr.status = $4 AND ` + tenant.Visible("c.org_id", 5)
+	query, args := pagination.SQLWithPagination(baseQuery, pag, allowedSortFields)
+	args = append(args, courseID, status, viewerOrg)
+	reviews, err := r.queryReviews(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM `+reviewCourseJoin+`
+		WHERE cr.course_id = $1 AND cr.status = $2 AND `+tenant.Visible("c.org_id", 3),
+		courseID, status, viewerOrg).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *PostgresReviewRepository) ListByStatus(ctx context.Context, status entity.ReviewStatus, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error) {
+	// Очередь модерации по умолчанию — от старых к новым
+	if pag.SortBy == "" {
+		pag.SortBy = "created_at"
+	}
+	baseQuery := `SELECT ` + reviewColumns + ` FROM ` + reviewCourseJoin + `
+		WHERE cr.status = $3 AND ` + tenant.Visible("c.org_id", 4)
+	query, args := pagination.SQLWithPagination(baseQuery, pag, allowedSortFields)
+	args = append(args, status, viewerOrg)
+	reviews, err := r.queryReviews(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM `+reviewCourseJoin+` WHERE cr.status = $1 AND `+tenant.Visible("c.org_id", 2),
+		status, viewerOrg).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// GetAuthorID используется SetResourceAuthorMiddleware для ABAC-проверок
+func (r *PostgresReviewRepository) GetAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var authorID uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT author_id FROM course_reviews WHERE id = $1`, id).Scan(&authorID)
+	return authorID, err
+}
+
+func (r *PostgresReviewRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT c.org_id FROM `+reviewCourseJoin+` WHERE cr.id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
+
+func (r *PostgresReviewRepository) queryReviews(ctx context.Context, query string, args ...interface{}) ([]*entity.Review, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reviews := []*entity.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+func scanReview(row pgx.Row) (*entity.Review, error) {
+	review := &entity.Review{}
+	err := row.Scan(
+		&review.ID, &review.CourseID, &review.AuthorID, &review.Rating, &review.Text, &review.Status,
+		&review.RejectionReason, &review.ModeratedBy, &review.ModeratedAt, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// recalculateCourseRating обновляет rating_avg/rating_count курса по одобренным отзывам
+func recalculateCourseRating(ctx context.Context, tx pgx.Tx, courseID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE courses SET
+			rating_avg = COALESCE((SELECT ROUND(AVG(rating), 2) FROM course_reviews WHERE course_id = $1 AND status = 'approved'), 0),
+			rating_count = (SELECT COUNT(*) FROM course_reviews WHERE course_id = $1 AND status = 'approved')
+		WHERE id = $1
+	`, courseID)
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,256 @@
+// internal/review/transport/http/review_handler.go
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/review/entity"
+	"github.com/kostinp/edu-platform-backend/internal/review/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/labstack/echo/v4"
+)
+
+type ReviewHandler struct {
+	usecase usecase.ReviewUsecase
+}
+
+func NewReviewHandler(uc usecase.ReviewUsecase) *ReviewHandler {
+	return &ReviewHandler{usecase: uc}
+}
+
+// ReviewRequest — тело запроса на создание/изменение отзыва
+type ReviewRequest struct {
+	Rating int    `json:"rating" example:"5"`
+	Text   string `json:"text" example:"Отличный курс"`
+}
+
+// RejectReviewRequest — причина отклонения отзыва
+type RejectReviewRequest struct {
+	Reason string `json:"reason" example:"Нецензурная лексика"`
+}
+
+// CreateReview godoc
+// @Summary Leave a review for a course
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param review body ReviewRequest true "Rating (1-5) and text"
+// @Success 201 {object} entity.Review
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /courses/{id}/reviews [post]
+func (h *ReviewHandler) Create(c echo.Context) error {
+	authorID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	}
+	req := new(ReviewRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	review := &entity.Review{CourseID: courseID, Rating: req.Rating, Text: req.Text}
+	if err := h.usecase.Create(c.Request().Context(), review, authorID); err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusCreated, review)
+}
+
+// ListCourseReviews godoc
+// @Summary List approved reviews of a course
+// @Tags reviews
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param sort_by query string false "Sort by (created_at, rating)"
+// @Param order query string false "Order"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Review]
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /courses/{id}/reviews [get]
+func (h *ReviewHandler) ListByCourse(c echo.Context) error {
+	courseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	reviews, total, err := h.usecase.ListApprovedByCourse(c.Request().Context(), courseID, middleware.CurrentOrgID(c), pag)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Review]{
+		Items:  reviews,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// UpdateReview godoc
+// @Summary Update own review (sends it back to moderation)
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param review body ReviewRequest true "Rating (1-5) and text"
+// @Success 200 {object} entity.Review
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reviews/{id} [put]
+func (h *ReviewHandler) Update(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	}
+	req := new(ReviewRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	review, err := h.usecase.Update(c.Request().Context(), id, req.Rating, req.Text)
+	if err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview godoc
+// @Summary Delete review
+// @Tags reviews
+// @Security BearerAuth
+// @Param id path string true "Review ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /reviews/{id} [delete]
+func (h *ReviewHandler) Delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	}
+	if err := h.usecase.Delete(c.Request().Context(), id); err != nil {
+		return reviewError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListModerationQueue godoc
+// @Summary List reviews awaiting moderation
+// @Tags reviews
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param order query string false "Order"
+// @Success 200 {object} dto.PaginatedResponse[*entity.Review]
+// @Failure 500 {object} map[string]string
+// @Router /reviews/moderation [get]
+func (h *ReviewHandler) ListModerationQueue(c echo.Context) error {
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	reviews, total, err := h.usecase.ListPending(c.Request().Context(), middleware.CurrentOrgID(c), pag)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.Review]{
+		Items:  reviews,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// ApproveReview godoc
+// @Summary Approve review
+// @Tags reviews
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Review ID"
+// @Success 200 {object} entity.Review
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /reviews/{id}/approve [post]
+func (h *ReviewHandler) Approve(c echo.Context) error {
+	moderatorID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	}
+	review, err := h.usecase.Approve(c.Request().Context(), id, moderatorID)
+	if err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+// RejectReview godoc
+// @Summary Reject review
+// @Tags reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param request body RejectReviewRequest false "Rejection reason"
+// @Success 200 {object} entity.Review
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /reviews/{id}/reject [post]
+func (h *ReviewHandler) Reject(c echo.Context) error {
+	moderatorID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	}
+	req := new(RejectReviewRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	review, err := h.usecase.Reject(c.Request().Context(), id, moderatorID, req.Reason)
+	if err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func reviewError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidRating):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrNotEnrolled):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrReviewExists), errors.Is(err, usecase.ErrNotPending):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrReviewNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "review not found"})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
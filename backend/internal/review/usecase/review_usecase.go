@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/review/entity"
+	"github.com/kostinp/edu-platform-backend/internal/review/repository"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+)
+
+var (
+	ErrReviewNotFound = repository.ErrReviewNotFound
+	ErrInvalidRating  = errors.New("rating must be between 1 and 5")
+	ErrNotEnrolled    = errors.New("user is not enrolled in the course")
+	ErrReviewExists   = repository.ErrReviewExists
+	ErrNotPending     = errors.New("review is not awaiting moderation")
+)
+
+// EnrollmentChecker — проверка записи на курс (реализуется course usecase)
+type EnrollmentChecker interface {
+	IsEnrolled(ctx context.Context, courseID, userID uuid.UUID) (bool, error)
+}
+
+type ReviewUsecase interface {
+	Create(ctx context.Context, review *entity.Review, authorID uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, rating int, text string) (*entity.Review, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Review, error)
+	// ListApprovedByCourse и ListPending — отзывы о курсах общего каталога и организации viewerOrg
+	ListApprovedByCourse(ctx context.Context, courseID uuid.UUID, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error)
+	ListPending(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error)
+	Approve(ctx context.Context, id, moderatorID uuid.UUID) (*entity.Review, error)
+	Reject(ctx context.Context, id, moderatorID uuid.UUID, reason string) (*entity.Review, error)
+}
+
+type reviewUsecase struct {
+	repo        repository.ReviewRepository
+	enrollments EnrollmentChecker
+}
+
+func NewReviewUsecase(repo repository.ReviewRepository, enrollments EnrollmentChecker) ReviewUsecase {
+	return &reviewUsecase{repo: repo, enrollments: enrollments}
+}
+
+func (u *reviewUsecase) Create(ctx context.Context, review *entity.Review, authorID uuid.UUID) error {
+	if !validRating(review.Rating) {
+		return ErrInvalidRating
+	}
+	enrolled, err := u.enrollments.IsEnrolled(ctx, review.CourseID, authorID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return ErrNotEnrolled
+	}
+	if _, err := u.repo.GetByCourseAndAuthor(ctx, review.CourseID, authorID); err == nil {
+		return ErrReviewExists
+	} else if !errors.Is(err, repository.ErrReviewNotFound) {
+		return err
+	}
+
+	review.Init(authorID)
+	review.Status = entity.ReviewStatusPending
+	review.RejectionReason = ""
+	review.ModeratedBy = nil
+	review.ModeratedAt = nil
+	return u.repo.Create(ctx, review)
+}
+
+// Update меняет оценку и текст; отредактированный отзыв снова уходит на модерацию
+func (u *reviewUsecase) Update(ctx context.Context, id uuid.UUID, rating int, text string) (*entity.Review, error) {
+	if !validRating(rating) {
+		return nil, ErrInvalidRating
+	}
+	review, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	review.Rating = rating
+	review.Text = text
+	review.Status = entity.ReviewStatusPending
+	review.RejectionReason = ""
+	review.ModeratedBy = nil
+	review.ModeratedAt = nil
+	review.Touch()
+	if err := u.repo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (u *reviewUsecase) Delete(ctx context.Context, id uuid.UUID) error {
+	return u.repo.Delete(ctx, id)
+}
+
+func (u *reviewUsecase) GetByID(ctx context.Context, id uuid.UUID) (*entity.Review, error) {
+	return u.repo.GetByID(ctx, id)
+}
+
+func (u *reviewUsecase) ListApprovedByCourse(ctx context.Context, courseID uuid.UUID, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error) {
+	return u.repo.ListByCourse(ctx, courseID, entity.ReviewStatusApproved, viewerOrg, pag)
+}
+
+func (u *reviewUsecase) ListPending(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Review, int, error) {
+	return u.repo.ListByStatus(ctx, entity.ReviewStatusPending, viewerOrg, pag)
+}
+
+func (u *reviewUsecase) Approve(ctx context.Context, id, moderatorID uuid.UUID) (*entity.Review, error) {
+	return u.moderate(ctx, id, moderatorID, entity.ReviewStatusApproved, "")
+}
+
+func (u *reviewUsecase) Reject(ctx context.Context, id, moderatorID uuid.UUID, reason string) (*entity.Review, error) {
+	return u.moderate(ctx, id, moderatorID, entity.ReviewStatusRejected, reason)
+}
+
+func (u *reviewUsecase) moderate(ctx context.Context, id, moderatorID uuid.UUID, status entity.ReviewStatus, reason string) (*entity.Review, error) {
+	review, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != entity.ReviewStatusPending {
+		return nil, ErrNotPending
+	}
+	now := time.Now().UTC()
+	review.Status = status
+	review.RejectionReason = reason
+	review.ModeratedBy = &moderatorID
+	review.ModeratedAt = &now
+	review.UpdatedAt = now
+	if err := u.repo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func validRating(rating int) bool {
+	return rating >= entity.MinRating && rating <= entity.MaxRating
+}
@@ -0,0 +1,20 @@
+// internal/review/wire.go
+package review
+
+import (
+	"github.com/google/wire"
+	courseUsecase "github.com/kostinp/edu-platform-backend/internal/course/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/review/repository"
+	http "github.com/kostinp/edu-platform-backend/internal/review/transport/http"
+	"github.com/kostinp/edu-platform-backend/internal/review/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+)
+
+var ReviewSet = wire.NewSet(
+	db.ConnectPostgres,
+	repository.NewPostgresReviewRepository,
+	wire.Bind(new(repository.ReviewRepository), new(*repository.PostgresReviewRepository)),
+	wire.Bind(new(usecase.EnrollmentChecker), new(courseUsecase.EnrollmentUsecase)),
+	usecase.NewReviewUsecase,
+	http.NewReviewHandler,
+)
@@ -23,6 +23,9 @@ type SearchEntity struct {
 	Price    *int       `json:"price,omitempty"`
 	Duration *int       `json:"duration,omitempty"`
 	AuthorID *uuid.UUID `json:"author_id,omitempty"`
+	// Рейтинг (только для курсов)
+	RatingAvg   *float64 `json:"rating_avg,omitempty"`
+	RatingCount *int     `json:"rating_count,omitempty"`
 	// Релевантность
 	Relevance float64 `json:"relevance"`
 }
@@ -36,11 +39,25 @@ type SearchFilters struct {
 	PriceMax    *int       `json:"price_max,omitempty"`
 	Level       []string   `json:"level,omitempty"`
 	AuthorID    *uuid.UUID `json:"author_id,omitempty"`
+	RatingMin   *float64   `json:"rating_min,omitempty"`
+	// Организация зрителя: кроме общего каталога виден только её контент.
+	// Заполняется из текущего пользователя, не из запроса
+	OrgID *uuid.UUID `json:"-"`
+	// Сортировка: newest (по умолчанию), rating, price_asc, price_desc
+	SortBy string `json:"sort_by,omitempty"`
 	// Пагинация
 	Limit  int `json:"limit" validate:"min=1,max=100"`
 	Offset int `json:"offset" validate:"min=0"`
 }
 
+// Допустимые значения SearchFilters.SortBy
+const (
+	SortNewest    = "newest"
+	SortRating    = "rating"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+)
+
 type SearchResult struct {
 	Entities []*SearchEntity `json:"entities"`
 	Total    int             `json:"total"`
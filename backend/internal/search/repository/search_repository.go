@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/search/entity"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
 )
 
 type SearchRepository interface {
@@ -33,6 +34,10 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, filters entity.Se
 	argIndex := 1
 	// Базовые условия
 	conditions = append(conditions, "deleted_at IS NULL")
+	// Чужие организации не видны
+	conditions = append(conditions, tenant.Visible("org_id", argIndex))
+	args = append(args, filters.OrgID)
+	argIndex++
 	// Поиск по тексту
 	if filters.Query != "" {
 		conditions = append(conditions,
@@ -72,6 +77,12 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, filters entity.Se
 		args = append(args, *filters.PriceMax)
 		argIndex++
 	}
+	// Фильтр по рейтингу
+	if filters.RatingMin != nil {
+		conditions = append(conditions, fmt.Sprintf("rating_avg >= $%d", argIndex))
+		args = append(args, *filters.RatingMin)
+		argIndex++
+	}
 	// Определяем таблицу для поиска
 	// Упрощенный пример - ищем только курсы
 	whereClause := ""
@@ -89,13 +100,14 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, filters entity.Se
 	query := fmt.Sprintf(`
 		SELECT
 			id, title, description, price, author_id, created_at, updated_at,
+			rating_avg::float8, rating_count,
 			'course' as type,
 			1.0 as relevance
 		FROM courses
 		%s
-		ORDER BY created_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+	`, whereClause, orderByClause(filters.SortBy), argIndex, argIndex+1)
 	args = append(args, filters.Limit, filters.Offset)
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -109,7 +121,7 @@ func (r *PostgresSearchRepository) Search(ctx context.Context, filters entity.Se
 		var authorID *uuid.UUID
 		err := rows.Scan(
 			&e.ID, &e.Title, &e.Description, &price, &authorID,
-			&e.CreatedAt, &e.UpdatedAt, &e.Type, &e.Relevance,
+			&e.CreatedAt, &e.UpdatedAt, &e.RatingAvg, &e.RatingCount, &e.Type, &e.Relevance,
 		)
 		if err != nil {
 			return nil, err
@@ -141,27 +153,35 @@ func (r *PostgresSearchRepository) SearchAdvanced(ctx context.Context, filters e
 		args = append(args, "%"+filters.Query+"%")
 		argIndex++
 	}
+	// Чужие организации не видны; у уроков организация — организация их курса
+	orgVisible := tenant.Visible("org_id", argIndex)
+	args = append(args, filters.OrgID)
+	argIndex++
 	// Курсы
 	coursesQuery := fmt.Sprintf(`
 		SELECT
 			id, title, description, price as price, author_id,
-			created_at, updated_at, 'course' as type, 1.0 as relevance
+			created_at, updated_at, rating_avg::float8 as rating_avg, rating_count,
+			'course' as type, 1.0 as relevance
 		FROM courses
-		WHERE %s
-	`, strings.Join(baseConditions, " AND "))
+		WHERE %s AND %s
+	`, strings.Join(baseConditions, " AND "), orgVisible)
 	queries = append(queries, coursesQuery)
 	// Уроки
 	lessonsQuery := fmt.Sprintf(`
 		SELECT
 			id, title, content as description, NULL as price, author_id,
-			created_at, updated_at, 'lesson' as type, 1.0 as relevance
+			created_at, updated_at, NULL::float8 as rating_avg, NULL::int as rating_count,
+			'lesson' as type, 1.0 as relevance
 		FROM lessons
-		WHERE %s
-	`, strings.Join(baseConditions, " AND "))
+		WHERE %s AND module_id IN (
+			SELECT m.id FROM modules m JOIN courses ON courses.id = m.course_id WHERE %s
+		)
+	`, strings.Join(baseConditions, " AND "), orgVisible)
 	queries = append(queries, lessonsQuery)
 	// Объединяем запросы
 	fullQuery := "(" + strings.Join(queries, ") UNION ALL (") + ")"
-	fullQuery += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	fullQuery += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", orderByClause(filters.SortBy), argIndex, argIndex+1)
 	args = append(args, filters.Limit, filters.Offset)
 	rows, err := r.db.Query(ctx, fullQuery, args...)
 	if err != nil {
@@ -175,7 +195,7 @@ func (r *PostgresSearchRepository) SearchAdvanced(ctx context.Context, filters e
 		var authorID *uuid.UUID
 		err := rows.Scan(
 			&e.ID, &e.Title, &e.Description, &price, &authorID,
-			&e.CreatedAt, &e.UpdatedAt, &e.Type, &e.Relevance,
+			&e.CreatedAt, &e.UpdatedAt, &e.RatingAvg, &e.RatingCount, &e.Type, &e.Relevance,
 		)
 		if err != nil {
 			return nil, err
@@ -194,6 +214,20 @@ func (r *PostgresSearchRepository) SearchAdvanced(ctx context.Context, filters e
 	}, nil
 }
 
+// orderByClause переводит SearchFilters.SortBy в ORDER BY; неизвестные значения — по дате
+func orderByClause(sortBy string) string {
+	switch sortBy {
+	case entity.SortRating:
+		return "rating_avg DESC NULLS LAST, rating_count DESC NULLS LAST, created_at DESC"
+	case entity.SortPriceAsc:
+		return "price ASC NULLS LAST, created_at DESC"
+	case entity.SortPriceDesc:
+		return "price DESC NULLS LAST, created_at DESC"
+	default:
+		return "created_at DESC"
+	}
+}
+
 func (r *PostgresSearchRepository) GetAutocomplete(ctx context.Context, query string, limit int) ([]string, error) {
 	// TODO: реализовать автодополнение, например, с использованием LIKE или полнотекстового поиска
 	return []string{}, nil
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kostinp/edu-platform-backend/internal/search/entity"
 	"github.com/kostinp/edu-platform-backend/internal/search/usecase"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	"github.com/labstack/echo/v4"
 )
 
@@ -21,6 +22,7 @@ func NewSearchHandler(uc usecase.SearchUsecase) *SearchHandler {
 
 // Search godoc
 // @Summary Поиск контента
+// @Description Общий каталог; вошедшим участникам организации — ещё и её закрытые курсы
 // @Tags Search
 // @Accept json
 // @Produce json
@@ -30,6 +32,8 @@ func NewSearchHandler(uc usecase.SearchUsecase) *SearchHandler {
 // @Param content_type query string false "Типы контента через запятую (course,lesson,module)"
 // @Param price_min query int false "Минимальная цена"
 // @Param price_max query int false "Максимальная цена"
+// @Param rating_min query number false "Минимальный рейтинг"
+// @Param sort_by query string false "Сортировка (newest, rating, price_asc, price_desc)" default(newest)
 // @Param limit query int false "Лимит" default(20)
 // @Param offset query int false "Смещение" default(0)
 // @Success 200 {object} entity.SearchResult
@@ -63,6 +67,12 @@ func (h *SearchHandler) Search(c echo.Context) error {
 			filters.PriceMax = &val
 		}
 	}
+	if ratingMin := c.QueryParam("rating_min"); ratingMin != "" {
+		if val, err := strconv.ParseFloat(ratingMin, 64); err == nil {
+			filters.RatingMin = &val
+		}
+	}
+	filters.SortBy = c.QueryParam("sort_by")
 	limit, _ := strconv.Atoi(c.QueryParam("limit"))
 	if limit <= 0 {
 		limit = 20
@@ -73,6 +83,7 @@ func (h *SearchHandler) Search(c echo.Context) error {
 		offset = 0
 	}
 	filters.Offset = offset
+	filters.OrgID = middleware.CurrentOrgID(c)
 	result, err := h.usecase.Search(c.Request().Context(), filters)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -84,6 +95,7 @@ func (h *SearchHandler) Search(c echo.Context) error {
 
 // SearchAdvanced godoc
 // @Summary Расширенный поиск
+// @Description Курсы и уроки; контент чужих организаций не возвращается
 // @Tags Search
 // @Accept json
 // @Produce json
@@ -99,6 +111,7 @@ func (h *SearchHandler) SearchAdvanced(c echo.Context) error {
 			"error": "Некорректные параметры поиска",
 		})
 	}
+	filters.OrgID = middleware.CurrentOrgID(c)
 	result, err := h.usecase.SearchAdvanced(c.Request().Context(), filters)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -33,6 +33,133 @@ func GetDefaultPolicies() []Policy {
 			Effect:     "allow",
 			Priority:   200,
 		},
+		// 2.0 Аналитика — только после второго фактора в текущей сессии (перекрывает admin_full_access)
+		{
+			ID:         "analytics_require_mfa",
+			Name:       "Analytics Requires MFA",
+			Target:     Target{Resource: "analytics", Action: "*"},
+			Conditions: []Condition{{Attribute: "env.mfa", Operator: "eq", Value: false}},
+			Effect:     "deny",
+			Priority:   1100,
+		},
+		// ========== АДМИНИСТРИРОВАНИЕ ПОЛЬЗОВАТЕЛЕЙ ==========
+		// Вход от имени пользователя — только после 2FA и только из браузерной сессии
+		{
+			ID:         "impersonate_require_mfa",
+			Name:       "Impersonation Requires MFA",
+			Target:     Target{Resource: "user", Action: "impersonate"},
+			Conditions: []Condition{{Attribute: "env.mfa", Operator: "eq", Value: false}},
+			Effect:     "deny",
+			Priority:   1100,
+		},
+		{
+			ID:         "impersonate_deny_api_token",
+			Name:       "No Impersonation With API Tokens",
+			Target:     Target{Resource: "user", Action: "impersonate"},
+			Conditions: []Condition{{Attribute: "env.auth", Operator: "eq", Value: "api_token"}},
+			Effect:     "deny",
+			Priority:   1100,
+		},
+		// В сессии от имени пользователя нельзя трогать его учётные данные и админку
+		{
+			ID:     "impersonated_session_restrictions",
+			Name:   "Impersonated Session Restrictions",
+			Target: Target{Resource: "*", Action: "*"},
+			Conditions: []Condition{
+				{Attribute: "env.impersonated", Operator: "eq", Value: true},
+				{Attribute: "resource.type", Operator: "in", Value: []string{
+					"account", "mfa", "api_token", "identity", "account_merge", "user_sessions", "user", "analytics",
+				}},
+			},
+			Effect:   "deny",
+			Priority: 1100,
+		},
+		// Профиль можно смотреть, но не менять (в том числе email для входа)
+		{
+			ID:         "impersonated_profile_read_only",
+			Name:       "Impersonated Profile Read Only",
+			Target:     Target{Resource: "profile", Action: "update"},
+			Conditions: []Condition{{Attribute: "env.impersonated", Operator: "eq", Value: true}},
+			Effect:     "deny",
+			Priority:   1100,
+		},
+		// ========== ОРГАНИЗАЦИИ ==========
+		// Закрытый контент организации недоступен остальным (перекрывает всё, кроме admin_full_access).
+		// Для общего каталога resource.org_id не задан, и условие не срабатывает
+		{
+			ID:         "org_content_isolation",
+			Name:       "Organization Content Isolation",
+			Target:     Target{Resource: "*", Action: "*"},
+			Conditions: []Condition{{Attribute: "resource.org_id", Operator: "ne", Value: "user.org_id"}},
+			Effect:     "deny",
+			Priority:   900,
+		},
+		// Карточку и участников своей организации видят все её участники
+		{
+			ID:         "organization_read_own",
+			Name:       "Read Own Organization",
+			Target:     Target{Resource: "organization", Action: "read"},
+			Conditions: []Condition{{Attribute: "resource.id", Operator: "eq", Value: "user.org_id"}},
+			Effect:     "allow",
+			Priority:   100,
+		},
+		// Владельцы и администраторы организации переименовывают её и управляют участниками
+		{
+			ID:     "organization_update_own",
+			Name:   "Update Own Organization",
+			Target: Target{Resource: "organization", Action: "update"},
+			Conditions: []Condition{
+				{Attribute: "resource.id", Operator: "eq", Value: "user.org_id"},
+				{Attribute: "user.org_role", Operator: "in", Value: []string{"owner", "admin"}},
+			},
+			Effect:   "allow",
+			Priority: 150,
+		},
+		{
+			ID:     "organization_members_manage_own",
+			Name:   "Manage Own Organization Members",
+			Target: Target{Resource: "organization", Action: "manage_members"},
+			Conditions: []Condition{
+				{Attribute: "resource.id", Operator: "eq", Value: "user.org_id"},
+				{Attribute: "user.org_role", Operator: "in", Value: []string{"owner", "admin"}},
+			},
+			Effect:   "allow",
+			Priority: 150,
+		},
+		// Своё членство (посмотреть, выйти) — все авторизованные
+		{
+			ID:         "organization_membership_own",
+			Name:       "Own Organization Membership",
+			Target:     Target{Resource: "organization_membership", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// Владельцы и администраторы организации создают курсы, категории и теги в ней
+		{
+			ID:     "org_content_create",
+			Name:   "Create Organization Content",
+			Target: Target{Resource: "*", Action: "create"},
+			Conditions: []Condition{
+				{Attribute: "resource.type", Operator: "in", Value: []string{"course", "category", "tag"}},
+				{Attribute: "user.org_role", Operator: "in", Value: []string{"owner", "admin"}},
+			},
+			Effect:   "allow",
+			Priority: 100,
+		},
+		// ...и управляют любым контентом своей организации, не только своим
+		{
+			ID:     "org_content_manage",
+			Name:   "Manage Organization Content",
+			Target: Target{Resource: "*", Action: "*"},
+			Conditions: []Condition{
+				{Attribute: "resource.type", Operator: "in", Value: []string{"course", "category", "tag", "study_group"}},
+				{Attribute: "resource.org_id", Operator: "eq", Value: "user.org_id"},
+				{Attribute: "user.org_role", Operator: "in", Value: []string{"owner", "admin"}},
+			},
+			Effect:   "allow",
+			Priority: 150,
+		},
 		// ========== КУРСЫ ==========
 		// 2.1 Создание курсов — teacher/admin
 		{
@@ -61,6 +188,62 @@ func GetDefaultPolicies() []Policy {
 			Effect:     "allow",
 			Priority:   150,
 		},
+		// 2.4 Запись на курс — все авторизованные
+		{
+			ID:         "course_enroll",
+			Name:       "Enroll Into Course",
+			Target:     Target{Resource: "course", Action: "enroll"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// 2.5 Свои записи на курсы — все авторизованные
+		{
+			ID:         "enrollment_read_own",
+			Name:       "Read Own Enrollments",
+			Target:     Target{Resource: "enrollment", Action: "read"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// ========== ОТЗЫВЫ ==========
+		// Оставить отзыв может любой авторизованный (запись на курс проверяется в usecase)
+		{
+			ID:         "course_review_create",
+			Name:       "Create Course Reviews",
+			Target:     Target{Resource: "course_review", Action: "create"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   100,
+		},
+		// Редактировать и удалять — только свой отзыв
+		{
+			ID:     "course_review_manage_own",
+			Name:   "Manage Own Course Review",
+			Target: Target{Resource: "course_review", Action: "*"},
+			Conditions: []Condition{
+				{Attribute: "resource.author_id", Operator: "eq", Value: "user.id"},
+			},
+			Effect:   "allow",
+			Priority: 150,
+		},
+		// Модерация — только админы; автор не может одобрить свой отзыв
+		{
+			ID:         "course_review_moderate",
+			Name:       "Moderate Course Reviews",
+			Target:     Target{Resource: "course_review", Action: "moderate"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "eq", Value: "admin"}},
+			Effect:     "allow",
+			Priority:   200,
+		},
+		{
+			ID:         "course_review_self_moderation_deny",
+			Name:       "Deny Self Moderation",
+			Target:     Target{Resource: "course_review", Action: "moderate"},
+			Conditions: []Condition{{Attribute: "resource.author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "deny",
+			Priority:   2000,
+		},
 		// ========== МОДУЛИ ==========
 		{
 			ID:         "module_create_update_delete",
@@ -95,6 +278,175 @@ func GetDefaultPolicies() []Policy {
 			Effect:     "allow",
 			Priority:   50,
 		},
+		// ========== ОБСУЖДЕНИЯ УРОКОВ ==========
+		{
+			ID:         "lesson_comment_read",
+			Name:       "Read Lesson Discussions",
+			Target:     Target{Resource: "lesson_comment", Action: "read"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		{
+			ID:         "lesson_comment_create",
+			Name:       "Create Lesson Comments",
+			Target:     Target{Resource: "lesson_comment", Action: "create"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   100,
+		},
+		// Автор может редактировать, удалять и смотреть историю своего комментария
+		{
+			ID:         "lesson_comment_update_own",
+			Name:       "Edit Own Lesson Comment",
+			Target:     Target{Resource: "lesson_comment", Action: "update"},
+			Conditions: []Condition{{Attribute: "resource.author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "allow",
+			Priority:   150,
+		},
+		{
+			ID:         "lesson_comment_delete_own",
+			Name:       "Delete Own Lesson Comment",
+			Target:     Target{Resource: "lesson_comment", Action: "delete"},
+			Conditions: []Condition{{Attribute: "resource.author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "allow",
+			Priority:   150,
+		},
+		{
+			ID:         "lesson_comment_history_own",
+			Name:       "Read Own Lesson Comment History",
+			Target:     Target{Resource: "lesson_comment", Action: "history"},
+			Conditions: []Condition{{Attribute: "resource.author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "allow",
+			Priority:   150,
+		},
+		// Преподаватель (автор курса) модерирует обсуждения своих уроков
+		{
+			ID:         "lesson_comment_moderate_course_teacher",
+			Name:       "Moderate Course Discussions",
+			Target:     Target{Resource: "lesson_comment", Action: "moderate"},
+			Conditions: []Condition{{Attribute: "resource.course_author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "allow",
+			Priority:   150,
+		},
+		{
+			ID:         "lesson_comment_delete_course_teacher",
+			Name:       "Delete Comments In Own Course",
+			Target:     Target{Resource: "lesson_comment", Action: "delete"},
+			Conditions: []Condition{{Attribute: "resource.course_author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "allow",
+			Priority:   150,
+		},
+		{
+			ID:         "lesson_comment_history_course_teacher",
+			Name:       "Read Comment History In Own Course",
+			Target:     Target{Resource: "lesson_comment", Action: "history"},
+			Conditions: []Condition{{Attribute: "resource.course_author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "allow",
+			Priority:   150,
+		},
+		// ========== ЛИЧНЫЕ ЗАМЕТКИ ==========
+		// Заметки видит только владелец: выборки ограничены user_id в репозитории
+		{
+			ID:         "note_manage_own",
+			Name:       "Manage Own Notes",
+			Target:     Target{Resource: "note", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// ========== ПРОГРЕСС ОБУЧЕНИЯ ==========
+		{
+			ID:         "progress_manage_own",
+			Name:       "Track Own Learning Progress",
+			Target:     Target{Resource: "progress", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// ========== УВЕДОМЛЕНИЯ ==========
+		// Отправка произвольных уведомлений (notification/send) — только admin_full_access
+		{
+			ID:         "notification_settings_manage_own",
+			Name:       "Manage Own Notification Settings",
+			Target:     Target{Resource: "notification_settings", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// ========== СЛИЯНИЕ АККАУНТОВ ==========
+		// Присоединить к себе свой второй аккаунт (владение подтверждается его токеном)
+		{
+			ID:         "account_merge_own",
+			Name:       "Merge Own Accounts",
+			Target:     Target{Resource: "account_merge", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// ========== ВНЕШНИЕ АККАУНТЫ ==========
+		{
+			ID:         "identity_manage_own",
+			Name:       "Manage Own Linked Identities",
+			Target:     Target{Resource: "identity", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// ========== ДВУХФАКТОРНАЯ АУТЕНТИФИКАЦИЯ ==========
+		{
+			ID:         "mfa_manage_own",
+			Name:       "Manage Own Two-Factor Authentication",
+			Target:     Target{Resource: "mfa", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// Свой профиль — все авторизованные
+		{
+			ID:         "profile_manage_own",
+			Name:       "Manage Own Profile",
+			Target:     Target{Resource: "profile", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// Выгрузка своих данных и удаление аккаунта
+		{
+			ID:         "account_manage_own",
+			Name:       "Export Or Delete Own Account",
+			Target:     Target{Resource: "account", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// Персональные API-токены — только свои (права токена ограничены его scopes)
+		{
+			ID:         "api_token_manage_own",
+			Name:       "Manage Own API Tokens",
+			Target:     Target{Resource: "api_token", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// Заявка на роль преподавателя — свои (подать может только студент, проверяется в usecase)
+		{
+			ID:         "teacher_application_manage_own",
+			Name:       "Manage Own Teacher Applications",
+			Target:     Target{Resource: "teacher_application", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
+		// ========== ЗАКЛАДКИ ==========
+		{
+			ID:         "bookmark_manage_own",
+			Name:       "Manage Own Bookmarks",
+			Target:     Target{Resource: "bookmark", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
 		// ========== КАТЕГОРИИ ==========
 		// 7.1 Создание категорий — teacher/admin
 		{
@@ -148,5 +500,42 @@ func GetDefaultPolicies() []Policy {
 			Effect:     "allow",
 			Priority:   50,
 		},
+
+		// ========== УЧЕБНЫЕ ГРУППЫ ==========
+		// Создают группы и видят список своих — преподаватели
+		{
+			ID:         "study_group_create",
+			Name:       "Create Study Groups",
+			Target:     Target{Resource: "study_group", Action: "create"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   100,
+		},
+		{
+			ID:         "study_group_list_own",
+			Name:       "List Own Study Groups",
+			Target:     Target{Resource: "study_group", Action: "list"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   100,
+		},
+		// Состав, курсы и прогресс группы — только её преподаватель (или админ организации, см. org_content_manage)
+		{
+			ID:         "study_group_manage_own",
+			Name:       "Manage Own Study Group",
+			Target:     Target{Resource: "study_group", Action: "*"},
+			Conditions: []Condition{{Attribute: "resource.author_id", Operator: "eq", Value: "user.id"}},
+			Effect:     "allow",
+			Priority:   150,
+		},
+		// Вступление, выход и просмотр своих групп — все авторизованные
+		{
+			ID:         "study_group_membership_own",
+			Name:       "Own Study Group Membership",
+			Target:     Target{Resource: "study_group_membership", Action: "*"},
+			Conditions: []Condition{{Attribute: "user.role", Operator: "in", Value: []string{"student", "teacher", "admin"}}},
+			Effect:     "allow",
+			Priority:   50,
+		},
 	}
 }
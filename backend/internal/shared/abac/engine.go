@@ -107,17 +107,37 @@ func (e *Engine) evalCondition(c Condition, ctx Context) bool {
 		return false
 	}
 
+	expected := resolveValue(c.Value, ctx)
+
 	switch c.Operator {
 	case "eq":
-		return compareEqual(actual, c.Value)
+		return compareEqual(actual, expected)
+	case "ne":
+		return !compareEqual(actual, expected)
 	case "in":
-		return compareIn(actual, c.Value)
+		return compareIn(actual, expected)
 	case "gt", "lt", "gte", "lte":
-		return compareNumeric(actual, c.Value, c.Operator)
+		return compareNumeric(actual, expected, c.Operator)
 	}
 	return false
 }
 
+// resolveValue подставляет значение атрибута, если в условии указана ссылка
+// вида "user.id" (например, resource.author_id eq user.id)
+func resolveValue(value interface{}, ctx Context) interface{} {
+	ref, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if !strings.HasPrefix(ref, "user.") && !strings.HasPrefix(ref, "resource.") && !strings.HasPrefix(ref, "env.") {
+		return value
+	}
+	if attr, ok := extractAttribute(ref, ctx); ok {
+		return attr
+	}
+	return value
+}
+
 func getString(m map[string]interface{}, key string) string {
 	if val, ok := m[key].(string); ok {
 		return val
@@ -141,6 +161,14 @@ func extractAttribute(attr string, ctx Context) (interface{}, bool) {
 			return ctx.User.ID.String(), true
 		case "role":
 			return string(ctx.User.Role), true
+		case "org_id":
+			// Без организации — пустая строка: не совпадает ни с одним resource.org_id
+			if ctx.User.OrgID == nil {
+				return "", true
+			}
+			return ctx.User.OrgID.String(), true
+		case "org_role":
+			return string(ctx.User.OrgRole), true
 		}
 	case "resource":
 		if val, ok := ctx.Resource[parts[1]]; ok {
@@ -11,7 +11,7 @@ type Target struct {
 
 type Condition struct {
 	Attribute string      `json:"attribute"` // user.role, resource.author_id, env.time.hour
-	Operator  string      `json:"operator"`  // eq, in, gt, lt, contains
+	Operator  string      `json:"operator"`  // eq, ne, in, gt, lt, contains
 	Value     interface{} `json:"value"`
 }
 
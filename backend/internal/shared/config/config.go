@@ -8,21 +8,32 @@ import (
 )
 
 type Config struct {
-	App        AppConfig        `yaml:"app"`
-	Database   DBConfig         `yaml:"database"`
-	Clickhouse ClickhouseConfig `yaml:"clickhouse"`
-	Analytics  AnalyticsConfig  `yaml:"analytics"`
-	Telegram   Telegram         `yaml:"telegram"`
-	JWT        JWTConfig        `yaml:"jwt"`
-	Container  ContainerConfig  `yaml:"container"`
-	Logging    LoggingConfig    `yaml:"logging"`
-	Cors       CorsConfig       `yaml:"cors"`
-	Mode       string
+	App           AppConfig           `yaml:"app"`
+	Database      DBConfig            `yaml:"database"`
+	Clickhouse    ClickhouseConfig    `yaml:"clickhouse"`
+	Analytics     AnalyticsConfig     `yaml:"analytics"`
+	Telegram      Telegram            `yaml:"telegram"`
+	Redis         RedisConfig         `yaml:"redis"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Mail          MailConfig          `yaml:"mail"`
+	OIDC          OIDCConfig          `yaml:"oidc"`
+	JWT           JWTConfig           `yaml:"jwt"`
+	GeoIP         GeoIPConfig         `yaml:"geoip"`
+	LoginRisk     LoginRiskConfig     `yaml:"login_risk"`
+	AuthRateLimit AuthRateLimitConfig `yaml:"auth_rate_limit"`
+	MFA           MFAConfig           `yaml:"mfa"`
+	Container     ContainerConfig     `yaml:"container"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	Cors          CorsConfig          `yaml:"cors"`
+	Mode          string
 }
 
 type AppConfig struct {
 	Env  string `yaml:"env"`
 	Port int    `yaml:"port"`
+	// Обратные прокси (IP или CIDR), которым доверяем X-Forwarded-For и X-Real-IP.
+	// Пусто — IP клиента берётся из соединения, заголовки игнорируются
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type DBConfig struct {
@@ -49,10 +60,112 @@ type AnalyticsConfig struct {
 type Telegram struct {
 	Token         string `yaml:"token"`
 	WebhookDomain string `yaml:"webhook_domain"`
+	// Секретный сегмент пути webhook бота; пустой — бот отключён
+	WebhookSecret string `yaml:"webhook_secret"`
+	// Максимальный возраст auth_date для Login Widget (0 — 24 часа)
+	AuthMaxAgeSeconds int `yaml:"auth_max_age_seconds"`
+}
+
+// RedisConfig — пустой URL означает работу без Redis (кэши в памяти процесса)
+type RedisConfig struct {
+	URL string `yaml:"url"`
+}
+
+// NotificationsConfig — уведомления через Telegram-бота
+type NotificationsConfig struct {
+	// Интервал запуска планировщика напоминаний (0 — 15 минут)
+	SchedulerIntervalMinutes int `yaml:"scheduler_interval_minutes"`
+	// Не больше PerChatLimit сообщений в один чат за PerChatWindowMinutes
+	PerChatLimit         int `yaml:"per_chat_limit"`
+	PerChatWindowMinutes int `yaml:"per_chat_window_minutes"`
+}
+
+// MailConfig — исходящая почта. Driver: "smtp" или "file" (письма пишутся в FileDir)
+type MailConfig struct {
+	Driver  string     `yaml:"driver"`
+	From    string     `yaml:"from"`
+	FileDir string     `yaml:"file_dir"`
+	SMTP    SMTPConfig `yaml:"smtp"`
+	// Адрес фронтенда для ссылок в письмах
+	LinkBaseURL string `yaml:"link_base_url"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// OIDCConfig — вход через внешних провайдеров OpenID Connect.
+// Провайдеры без client_id пропускаются
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig `yaml:"providers"`
+	Mock      OIDCMockConfig       `yaml:"mock"`
+}
+
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// OIDCMockConfig — встроенный тестовый провайдер "mock" на /api/dev/oidc, только для разработки
+type OIDCMockConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Issuer      string `yaml:"issuer"`
+	RedirectURL string `yaml:"redirect_url"`
 }
 
 type JWTConfig struct {
+	// Общий секрет HS256; используется, если keys_dir не задан
 	Secret string `yaml:"secret"`
+	// Каталог ключей RS256/EdDSA (см. cmd/jwtkeys). Если задан, токены подписываются
+	// асимметричным ключом с kid, открытые ключи публикуются в /.well-known/jwks.json
+	KeysDir string `yaml:"keys_dir"`
+	// Срок жизни access-токена; по умолчанию 15 минут
+	AccessTTLMinutes int `yaml:"access_ttl_minutes"`
+	// Срок жизни refresh-токена, продлевается при каждой ротации; по умолчанию 30 дней
+	RefreshTTLDays int `yaml:"refresh_ttl_days"`
+}
+
+type GeoIPConfig struct {
+	// Путь к базе MaxMind DB (GeoLite2-City.mmdb); пусто — геолокация сессий отключена.
+	// Файл можно заменять на лету, он перечитывается раз в минуту
+	DatabasePath string `yaml:"database_path"`
+	// Язык названий стран и городов; по умолчанию en
+	Language string `yaml:"language"`
+}
+
+type LoginRiskConfig struct {
+	// С этого score сессия помечается подозрительной; по умолчанию 50
+	FlagScore int `yaml:"flag_score"`
+	// С этого score вход нужно подтвердить кодом из письма или Telegram; 0 — не требовать
+	StepUpScore int `yaml:"step_up_score"`
+	// Вход из другой страны раньше этого срока после активности в прежней
+	// считается невозможным перемещением; по умолчанию 120 минут
+	ImpossibleTravelMinutes int `yaml:"impossible_travel_minutes"`
+}
+
+// AuthRateLimitConfig — лимиты входа по email отдельно по IP и по адресу (0 — значения по умолчанию).
+// Login — попытки входа по паролю, Mail — запросы, отправляющие письмо
+// (регистрация, повтор подтверждения, сброс пароля, magic link).
+// Лимиты по IP заданы с запасом: класс школы часто выходит в сеть с одного адреса
+type AuthRateLimitConfig struct {
+	LoginPerEmail      int `yaml:"login_per_email"`
+	LoginPerIP         int `yaml:"login_per_ip"`
+	LoginWindowMinutes int `yaml:"login_window_minutes"`
+	MailPerEmail       int `yaml:"mail_per_email"`
+	MailPerIP          int `yaml:"mail_per_ip"`
+	MailWindowMinutes  int `yaml:"mail_window_minutes"`
+}
+
+type MFAConfig struct {
+	// Название сервиса в приложении-аутентификаторе; по умолчанию Edu Platform
+	Issuer string `yaml:"issuer"`
 }
 
 type ContainerConfig struct {
@@ -1,4 +1,3 @@
 package config
 
 type BotToken string
-type JwtSecret string
@@ -1,6 +1,196 @@
+// Package geo — определение страны и города по IP по локальной базе MaxMind DB
+// (GeoLite2-City, GeoIP2-City и совместимые). Файл перечитывается при замене,
+// результаты кэшируются
 package geo
 
-// Простая заглушка для локальной разработки
-func Lookup(ip string) (country, city string) {
-	return "Unknown", "Unknown"
+import (
+	"container/list"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+)
+
+const (
+	Unknown = "Unknown"
+
+	// Как часто проверяем, не заменили ли файл базы (обновления GeoLite2 — раз в неделю)
+	reloadInterval = time.Minute
+	cacheSize      = 10000
+)
+
+type location struct {
+	country, city string
+}
+
+// Locator ищет адреса в базе; без базы всегда возвращает Unknown
+type Locator struct {
+	path     string
+	language string
+
+	mu        sync.RWMutex
+	db        *mmdbReader
+	modTime   time.Time
+	checkedAt time.Time
+
+	cacheMu sync.Mutex
+	cache   map[string]*list.Element
+	order   *list.List // от недавно использованных к давним
+}
+
+type cacheEntry struct {
+	ip  string
+	loc location
+}
+
+// NewLocator открывает базу по пути path; пустой путь — геолокация отключена.
+// language — ключ в names базы (en, ru, de, ...), при отсутствии перевода берётся en
+func NewLocator(path, language string) (*Locator, error) {
+	if language == "" {
+		language = "en"
+	}
+	l := &Locator{
+		path:     path,
+		language: language,
+		cache:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+	if path == "" {
+		return l, nil
+	}
+	if err := l.reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Lookup возвращает страну и город; Unknown, если адрес не найден
+// (в том числе для локальных и частных сетей)
+func (l *Locator) Lookup(ip string) (country, city string) {
+	if l == nil || l.path == "" {
+		return Unknown, Unknown
+	}
+	l.maybeReload()
+
+	if loc, ok := l.cached(ip); ok {
+		return loc.country, loc.city
+	}
+	loc := l.lookup(ip)
+	l.store(ip, loc)
+	return loc.country, loc.city
+}
+
+func (l *Locator) lookup(ip string) location {
+	loc := location{country: Unknown, city: Unknown}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return loc
+	}
+	l.mu.RLock()
+	db := l.db
+	l.mu.RUnlock()
+
+	record, err := db.lookup(parsed)
+	if err != nil {
+		return loc
+	}
+	m, _ := record.(map[string]any)
+	if name := l.name(m["country"]); name != "" {
+		loc.country = name
+	} else if name := l.name(m["registered_country"]); name != "" {
+		loc.country = name
+	}
+	if name := l.name(m["city"]); name != "" {
+		loc.city = name
+	}
+	return loc
+}
+
+// name достаёт names[language] из записи country/city
+func (l *Locator) name(v any) string {
+	m, _ := v.(map[string]any)
+	names, _ := m["names"].(map[string]any)
+	if s, ok := names[l.language].(string); ok {
+		return s
+	}
+	s, _ := names["en"].(string)
+	return s
+}
+
+func (l *Locator) cached(ip string) (location, bool) {
+	l.cacheMu.Lock()
+	defer l.cacheMu.Unlock()
+	el, ok := l.cache[ip]
+	if !ok {
+		return location{}, false
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).loc, true
+}
+
+func (l *Locator) store(ip string, loc location) {
+	l.cacheMu.Lock()
+	defer l.cacheMu.Unlock()
+	if el, ok := l.cache[ip]; ok {
+		el.Value.(*cacheEntry).loc = loc
+		l.order.MoveToFront(el)
+		return
+	}
+	l.cache[ip] = l.order.PushFront(&cacheEntry{ip: ip, loc: loc})
+	if l.order.Len() > cacheSize {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.cache, oldest.Value.(*cacheEntry).ip)
+	}
+}
+
+func (l *Locator) maybeReload() {
+	l.mu.RLock()
+	due := time.Since(l.checkedAt) >= reloadInterval
+	l.mu.RUnlock()
+	if !due {
+		return
+	}
+	// Битый или недописанный файл не мешает работать со старой базой
+	if err := l.reload(); err != nil {
+		logger.Error("Не удалось перечитать базу GeoIP", err)
+	}
+}
+
+func (l *Locator) reload() error {
+	l.mu.Lock()
+	l.checkedAt = time.Now()
+	l.mu.Unlock()
+
+	st, err := os.Stat(l.path)
+	if err != nil {
+		return err
+	}
+	l.mu.RLock()
+	unchanged := l.db != nil && st.ModTime().Equal(l.modTime)
+	l.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	buf, err := os.ReadFile(l.path)
+	if err != nil {
+		return err
+	}
+	db, err := openMMDB(buf)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.db, l.modTime = db, st.ModTime()
+	l.mu.Unlock()
+
+	l.cacheMu.Lock()
+	l.cache = make(map[string]*list.Element)
+	l.order.Init()
+	l.cacheMu.Unlock()
+	return nil
 }
@@ -0,0 +1,369 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+// Чтение баз в формате MaxMind DB (https://maxmind.github.io/MaxMind-DB/):
+// бинарное дерево поиска по битам адреса, за ним секция данных, в конце метаданные
+
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+const (
+	// Метаданные лежат в последних 128 КиБ файла
+	metadataMaxSize = 128 * 1024
+	// Глубина вложенности map и array; в настоящих базах — несколько уровней
+	maxDecodeDepth = 32
+	// Сколько значений разбирается за один decode: указатели на общие
+	// подструктуры не должны раздувать одну запись без предела
+	maxDecodeValues = 1 << 16
+)
+
+var (
+	ErrInvalidDatabase = errors.New("geo: invalid MaxMind DB file")
+	errNotFound        = errors.New("geo: address not found")
+)
+
+// Типы полей секции данных
+const (
+	typeExtended = 0
+	typePointer  = 1
+	typeString   = 2
+	typeDouble   = 3
+	typeBytes    = 4
+	typeUint16   = 5
+	typeUint32   = 6
+	typeMap      = 7
+	typeInt32    = 8
+	typeUint64   = 9
+	typeUint128  = 10
+	typeArray    = 11
+	typeBool     = 14
+	typeFloat    = 15
+)
+
+type mmdbReader struct {
+	buf        []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	treeSize   uint
+	data       []byte // секция данных
+	ipv4Start  uint   // узел, с которого начинаются IPv4-адреса в IPv6-дереве
+}
+
+func openMMDB(buf []byte) (*mmdbReader, error) {
+	tail := 0
+	if len(buf) > metadataMaxSize {
+		tail = len(buf) - metadataMaxSize
+	}
+	pos := bytes.LastIndex(buf[tail:], metadataMarker)
+	if pos < 0 {
+		return nil, ErrInvalidDatabase
+	}
+	pos += tail
+	meta := &decoder{buf: buf[pos+len(metadataMarker):]}
+	raw, _, err := meta.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata: %v", ErrInvalidDatabase, err)
+	}
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidDatabase
+	}
+	nodeCount, recordSize, ipVersion := asUint(m["node_count"]), asUint(m["record_size"]), asUint(m["ip_version"])
+	switch recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, recordSize)
+	}
+	if ipVersion != 4 && ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported ip version %d", ErrInvalidDatabase, ipVersion)
+	}
+	// Дерево и 16 нулевых байт разделителя должны поместиться перед метаданными.
+	// Делим, а не умножаем: node_count из файла может переполнить произведение
+	nodeBytes := recordSize / 4
+	if nodeCount == 0 || nodeCount > uint64(pos)/nodeBytes {
+		return nil, fmt.Errorf("%w: node count %d does not fit the file", ErrInvalidDatabase, nodeCount)
+	}
+	r := &mmdbReader{
+		buf:        buf,
+		nodeCount:  uint(nodeCount),
+		recordSize: uint(recordSize),
+		ipVersion:  uint(ipVersion),
+		treeSize:   uint(nodeCount * nodeBytes),
+	}
+	if r.treeSize+16 > uint(pos) {
+		return nil, ErrInvalidDatabase
+	}
+	r.data = buf[r.treeSize+16 : pos]
+
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < r.nodeCount; i++ {
+			node = r.readNode(node, 0)
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// lookup возвращает запись секции данных для адреса
+func (r *mmdbReader) lookup(ip net.IP) (any, error) {
+	node := uint(0)
+	bitCount := 128
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		bitCount = 32
+		if r.ipVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else if r.ipVersion == 4 {
+		return nil, errNotFound
+	}
+
+	for i := 0; i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-uint(i&7))) & 1
+		node = r.readNode(node, bit)
+	}
+	if node == r.nodeCount {
+		return nil, errNotFound
+	}
+	// Записи за пределами дерева указывают в секцию данных со сдвигом на разделитель
+	if node < r.nodeCount+16 {
+		return nil, ErrInvalidDatabase
+	}
+	offset := node - r.nodeCount - 16
+	if offset >= uint(len(r.data)) {
+		return nil, ErrInvalidDatabase
+	}
+	d := &decoder{buf: r.data}
+	value, _, err := d.decode(offset)
+	return value, err
+}
+
+// readNode читает запись узла; node < nodeCount, а дерево целиком лежит в buf —
+// это проверено в openMMDB
+func (r *mmdbReader) readNode(node, bit uint) uint {
+	b := r.buf[node*r.recordSize/4:]
+	switch r.recordSize {
+	case 24:
+		off := bit * 3
+		return uint(b[off])<<16 | uint(b[off+1])<<8 | uint(b[off+2])
+	case 28:
+		if bit == 0 {
+			return (uint(b[3])&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return (uint(b[3])&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+type decoder struct {
+	buf []byte
+	// values — сколько значений разобрано в текущем decode
+	values int
+}
+
+// decode читает значение по смещению и возвращает его и смещение следующего значения
+func (d *decoder) decode(offset uint) (any, uint, error) {
+	d.values = 0
+	return d.decodeValue(offset, 0, true)
+}
+
+// followPointer=false запрещает указатель: по формату указатель не может
+// ссылаться на другой указатель, а цепочка из них зациклила бы разбор
+func (d *decoder) decodeValue(offset uint, depth int, followPointer bool) (any, uint, error) {
+	if offset >= uint(len(d.buf)) {
+		return nil, 0, ErrInvalidDatabase
+	}
+	if depth > maxDecodeDepth {
+		return nil, 0, fmt.Errorf("%w: data nested too deep", ErrInvalidDatabase)
+	}
+	d.values++
+	if d.values > maxDecodeValues {
+		return nil, 0, fmt.Errorf("%w: record too large", ErrInvalidDatabase)
+	}
+	ctrl := d.buf[offset]
+	offset++
+	typ := int(ctrl >> 5)
+
+	if typ == typePointer {
+		if !followPointer {
+			return nil, 0, fmt.Errorf("%w: pointer to pointer", ErrInvalidDatabase)
+		}
+		ptr, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decodeValue(ptr, depth, false)
+		return value, next, err
+	}
+
+	if typ == typeExtended {
+		if offset >= uint(len(d.buf)) {
+			return nil, 0, ErrInvalidDatabase
+		}
+		typ = 7 + int(d.buf[offset])
+		offset++
+	}
+
+	size, offset, err := d.size(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Каждое значение занимает хотя бы байт: размер больше остатка буфера —
+	// битый файл, а не повод выделять память под миллионы элементов
+	if size > uint(len(d.buf))-offset && typ != typeBool {
+		return nil, 0, ErrInvalidDatabase
+	}
+
+	switch typ {
+	case typeMap:
+		m := make(map[string]any, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decodeValue(offset, depth+1, true)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, ErrInvalidDatabase
+			}
+			value, next, err := d.decodeValue(next, depth+1, true)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[k] = value
+			offset = next
+		}
+		return m, offset, nil
+	case typeArray:
+		items := make([]any, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decodeValue(offset, depth+1, true)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, value)
+			offset = next
+		}
+		return items, offset, nil
+	case typeBool:
+		if size > 1 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		return size != 0, offset, nil
+	}
+
+	end := offset + size
+	raw := d.buf[offset:end]
+	switch typ {
+	case typeString:
+		return string(raw), end, nil
+	case typeBytes:
+		return append([]byte(nil), raw...), end, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), end, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(raw))), end, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > uintSize(typ) {
+			return nil, 0, ErrInvalidDatabase
+		}
+		var v uint64
+		for _, b := range raw {
+			v = v<<8 | uint64(b)
+		}
+		return v, end, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		var v uint32
+		for _, b := range raw {
+			v = v<<8 | uint32(b)
+		}
+		return int64(int32(v)), end, nil
+	case typeUint128:
+		if size > 16 {
+			return nil, 0, ErrInvalidDatabase
+		}
+		// Геоданным не нужен; отдаём как байты
+		return append([]byte(nil), raw...), end, nil
+	}
+	return nil, 0, fmt.Errorf("%w: unknown data type %d", ErrInvalidDatabase, typ)
+}
+
+func (d *decoder) size(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+	n := size - 28
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, ErrInvalidDatabase
+	}
+	var v uint
+	for _, b := range d.buf[offset : offset+n] {
+		v = v<<8 | uint(b)
+	}
+	switch size {
+	case 29:
+		size = 29 + v
+	case 30:
+		size = 285 + v
+	default:
+		size = 65821 + v
+	}
+	return size, offset + n, nil
+}
+
+func (d *decoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	n := uint((ctrl>>3)&0x3) + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, ErrInvalidDatabase
+	}
+	var v uint
+	if n < 4 {
+		v = uint(ctrl & 0x7)
+	}
+	for _, b := range d.buf[offset : offset+n] {
+		v = v<<8 | uint(b)
+	}
+	switch n {
+	case 2:
+		v += 2048
+	case 3:
+		v += 526336
+	}
+	return v, offset + n, nil
+}
+
+// uintSize — наибольший размер беззнакового целого типа в байтах
+func uintSize(typ int) uint {
+	switch typ {
+	case typeUint16:
+		return 2
+	case typeUint32:
+		return 4
+	}
+	return 8
+}
+
+func asUint(v any) uint64 {
+	u, _ := v.(uint64)
+	return u
+}
@@ -0,0 +1,361 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// Небольшая база в формате MaxMind DB собирается прямо в тесте: так видно,
+// какие сети и записи в ней лежат, и её легко испортить в нужном месте
+
+func encodeValue(v any) []byte {
+	switch v := v.(type) {
+	case string:
+		return append(encodeCtrl(typeString, len(v)), v...)
+	case uint16:
+		return encodeUint(typeUint16, uint64(v))
+	case uint32:
+		return encodeUint(typeUint32, uint64(v))
+	case uint64:
+		return encodeUint(typeUint64, v)
+	case bool:
+		n := 0
+		if v {
+			n = 1
+		}
+		return encodeCtrl(typeBool, n)
+	case float64:
+		out := encodeCtrl(typeDouble, 8)
+		return binary.BigEndian.AppendUint64(out, math.Float64bits(v))
+	case []any:
+		out := encodeCtrl(typeArray, len(v))
+		for _, item := range v {
+			out = append(out, encodeValue(item)...)
+		}
+		return out
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := encodeCtrl(typeMap, len(v))
+		for _, k := range keys {
+			out = append(out, encodeValue(k)...)
+			out = append(out, encodeValue(v[k])...)
+		}
+		return out
+	}
+	panic("unsupported fixture value")
+}
+
+func encodeUint(typ int, v uint64) []byte {
+	var raw []byte
+	for ; v > 0; v >>= 8 {
+		raw = append([]byte{byte(v)}, raw...)
+	}
+	return append(encodeCtrl(typ, len(raw)), raw...)
+}
+
+func encodeCtrl(typ, size int) []byte {
+	var out []byte
+	var extra []byte
+	switch {
+	case size < 29:
+	case size < 285:
+		extra = []byte{byte(size - 29)}
+		size = 29
+	case size < 65821:
+		extra = binary.BigEndian.AppendUint16(nil, uint16(size-285))
+		size = 30
+	default:
+		v := size - 65821
+		extra = []byte{byte(v >> 16), byte(v >> 8), byte(v)}
+		size = 31
+	}
+	if typ <= 7 {
+		out = []byte{byte(typ<<5 | size)}
+	} else {
+		out = []byte{byte(size), byte(typ - 7)}
+	}
+	return append(out, extra...)
+}
+
+type trieNode struct {
+	child [2]*trieNode
+	data  [2]int // смещение записи + 1; 0 — адрес не найден
+}
+
+type fixtureNetwork struct {
+	cidr   string
+	record map[string]any
+}
+
+// buildMMDB собирает базу с деревом на 24-битных записях. Для ipVersion 6
+// IPv4-сети кладутся в ::/96, как в настоящих базах
+func buildMMDB(t testing.TB, ipVersion uint16, networks []fixtureNetwork) []byte {
+	t.Helper()
+	root := &trieNode{}
+	var data []byte
+	for _, n := range networks {
+		_, ipNet, err := net.ParseCIDR(n.cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ip, ones := []byte(ipNet.IP), 0
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			ip = ip4
+			ones, _ = ipNet.Mask.Size()
+			if ipVersion == 6 {
+				ip = append(make([]byte, 12), ip4...)
+				ones += 96
+			}
+		} else {
+			ones, _ = ipNet.Mask.Size()
+		}
+		offset := len(data)
+		data = append(data, encodeValue(n.record)...)
+
+		node := root
+		for i := 0; i < ones; i++ {
+			bit := ip[i>>3] >> (7 - uint(i&7)) & 1
+			if i == ones-1 {
+				node.data[bit] = offset + 1
+				break
+			}
+			if node.child[bit] == nil {
+				node.child[bit] = &trieNode{}
+			}
+			node = node.child[bit]
+		}
+	}
+
+	// Нумеруем узлы в ширину: корень — узел 0
+	nodes := []*trieNode{root}
+	index := map[*trieNode]int{root: 0}
+	for i := 0; i < len(nodes); i++ {
+		for _, c := range nodes[i].child {
+			if c != nil {
+				index[c] = len(nodes)
+				nodes = append(nodes, c)
+			}
+		}
+	}
+	nodeCount := len(nodes)
+	var tree []byte
+	for _, n := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			record := nodeCount
+			switch {
+			case n.child[bit] != nil:
+				record = index[n.child[bit]]
+			case n.data[bit] != 0:
+				record = nodeCount + 16 + n.data[bit] - 1
+			}
+			tree = append(tree, byte(record>>16), byte(record>>8), byte(record))
+		}
+	}
+
+	out := append(tree, make([]byte, 16)...)
+	out = append(out, data...)
+	out = append(out, metadataMarker...)
+	return append(out, encodeValue(map[string]any{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"database_type":               "GeoLite2-City",
+		"ip_version":                  ipVersion,
+		"languages":                   []any{"en", "ru"},
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(24),
+	})...)
+}
+
+var fixtureNetworks = []fixtureNetwork{
+	{"81.2.69.0/24", map[string]any{
+		"city":    map[string]any{"names": map[string]any{"en": "London", "ru": "Лондон"}},
+		"country": map[string]any{"iso_code": "GB", "names": map[string]any{"en": "United Kingdom", "ru": "Великобритания"}},
+		"location": map[string]any{
+			"latitude": 51.5142, "longitude": -0.0931, "accuracy_radius": uint16(10),
+		},
+	}},
+	{"2.125.160.0/24", map[string]any{
+		"registered_country": map[string]any{"names": map[string]any{"en": "United Kingdom"}},
+	}},
+	{"2001:218::/32", map[string]any{
+		"country":    map[string]any{"names": map[string]any{"en": "Japan", "ru": "Япония"}},
+		"is_anycast": false,
+	}},
+}
+
+func writeFixture(t *testing.T, buf []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLocatorLookup(t *testing.T) {
+	v6 := writeFixture(t, buildMMDB(t, 6, fixtureNetworks))
+	v4 := writeFixture(t, buildMMDB(t, 4, fixtureNetworks[:2]))
+
+	tests := []struct {
+		name, path, language, ip string
+		country, city            string
+	}{
+		{"city in ipv6 db", v6, "en", "81.2.69.160", "United Kingdom", "London"},
+		{"translated names", v6, "ru", "81.2.69.160", "Великобритания", "Лондон"},
+		{"registered country fallback", v6, "ru", "2.125.160.216", "United Kingdom", Unknown},
+		{"ipv6 address", v6, "en", "2001:218:1::1", "Japan", Unknown},
+		{"ipv4-mapped ipv6", v6, "en", "::ffff:81.2.69.1", "United Kingdom", "London"},
+		{"not in db", v6, "en", "10.0.0.1", Unknown, Unknown},
+		{"city in ipv4 db", v4, "en", "81.2.69.1", "United Kingdom", "London"},
+		{"ipv6 address in ipv4 db", v4, "en", "2001:218:1::1", Unknown, Unknown},
+		{"not an ip", v4, "en", "localhost", Unknown, Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewLocator(tt.path, tt.language)
+			if err != nil {
+				t.Fatalf("NewLocator: %v", err)
+			}
+			country, city := l.Lookup(tt.ip)
+			if country != tt.country || city != tt.city {
+				t.Fatalf("Lookup(%s) = %q, %q; want %q, %q", tt.ip, country, city, tt.country, tt.city)
+			}
+		})
+	}
+}
+
+func TestOpenMMDBRejectsBrokenFiles(t *testing.T) {
+	valid := buildMMDB(t, 6, fixtureNetworks)
+	metaAt := bytes.LastIndex(valid, metadataMarker)
+	withMeta := func(meta map[string]any) []byte {
+		out := append([]byte(nil), valid[:metaAt+len(metadataMarker)]...)
+		return append(out, encodeValue(meta)...)
+	}
+	meta := func(nodeCount uint64, recordSize, ipVersion uint16) map[string]any {
+		return map[string]any{"node_count": nodeCount, "record_size": recordSize, "ip_version": ipVersion}
+	}
+
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{"empty", nil},
+		{"no metadata", valid[:metaAt]},
+		{"truncated metadata", valid[:len(valid)-3]},
+		{"metadata is not a map", append(append([]byte(nil), valid[:metaAt+len(metadataMarker)]...), encodeValue("db")...)},
+		{"unsupported record size", withMeta(meta(10, 20, 6))},
+		{"unsupported ip version", withMeta(meta(10, 24, 5))},
+		{"zero nodes", withMeta(meta(0, 24, 6))},
+		{"tree larger than file", withMeta(meta(uint64(metaAt), 24, 6))},
+		{"node count overflows tree size", withMeta(meta(math.MaxUint64/3, 32, 6))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := openMMDB(tt.buf); !errors.Is(err, ErrInvalidDatabase) {
+				t.Fatalf("err = %v, want ErrInvalidDatabase", err)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	selfMap := append(encodeCtrl(typeMap, 1), encodeValue("a")...)
+	selfMap = append(selfMap, 0x20, 0x00) // значение — указатель на саму map
+
+	tests := []struct {
+		name    string
+		buf     []byte
+		want    any
+		wantErr bool
+	}{
+		{name: "string", buf: encodeValue("London"), want: "London"},
+		{name: "long string", buf: encodeValue(string(make([]byte, 300))), want: string(make([]byte, 300))},
+		{name: "uint16", buf: encodeValue(uint16(443)), want: uint64(443)},
+		{name: "uint64", buf: encodeValue(uint64(1 << 40)), want: uint64(1 << 40)},
+		{name: "double", buf: encodeValue(51.5), want: 51.5},
+		{name: "bool", buf: encodeValue(true), want: true},
+		{name: "pointer", buf: []byte{0x20, 0x02, 0x41, 'x'}, want: "x"},
+		{name: "empty buffer", buf: nil, wantErr: true},
+		{name: "pointer to pointer", buf: []byte{0x20, 0x02, 0x20, 0x00}, wantErr: true},
+		{name: "pointer out of range", buf: []byte{0x27, 0xff}, wantErr: true},
+		{name: "cycle through map", buf: selfMap, wantErr: true},
+		{name: "truncated string", buf: []byte{0x45, 'a', 'b'}, wantErr: true},
+		{name: "truncated size", buf: []byte{0x5e, 0x01}, wantErr: true},
+		{name: "huge map", buf: []byte{0xff, 0xff, 0xff, 0xff}, wantErr: true},
+		{name: "huge array", buf: []byte{0x1f, 0x04, 0xff, 0xff, 0xff}, wantErr: true},
+		{name: "uint16 too wide", buf: []byte{0xa3, 1, 2, 3}, wantErr: true},
+		{name: "double of wrong size", buf: []byte{0x64, 1, 2, 3, 4}, wantErr: true},
+		{name: "bool with payload size", buf: []byte{0x02, 0x07}, wantErr: true},
+		{name: "unknown extended type", buf: []byte{0x00, 0x14}, wantErr: true},
+		{name: "map key is not a string", buf: append(encodeCtrl(typeMap, 1), append(encodeValue(uint16(1)), encodeValue("v")...)...), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := (&decoder{buf: tt.buf}).decode(0)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDatabase) {
+					t.Fatalf("err = %v, want ErrInvalidDatabase", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("decode = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeDepthLimit(t *testing.T) {
+	value := any("leaf")
+	for i := 0; i < maxDecodeDepth; i++ {
+		value = []any{value}
+	}
+	if _, _, err := (&decoder{buf: encodeValue(value)}).decode(0); err != nil {
+		t.Fatalf("depth %d: %v", maxDecodeDepth, err)
+	}
+	value = []any{value}
+	if _, _, err := (&decoder{buf: encodeValue(value)}).decode(0); !errors.Is(err, ErrInvalidDatabase) {
+		t.Fatalf("depth %d: err = %v, want ErrInvalidDatabase", maxDecodeDepth+1, err)
+	}
+}
+
+// FuzzOpenMMDB проверяет, что испорченная база приводит к ошибке, а не к панике
+func FuzzOpenMMDB(f *testing.F) {
+	f.Add(buildMMDB(f, 6, fixtureNetworks))
+	f.Add(buildMMDB(f, 4, fixtureNetworks[:2]))
+	ips := []net.IP{
+		net.ParseIP("81.2.69.160"), net.ParseIP("2.125.160.216"),
+		net.ParseIP("2001:218:1::1"), net.ParseIP("10.0.0.1"),
+	}
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		db, err := openMMDB(buf)
+		if err != nil {
+			return
+		}
+		for _, ip := range ips {
+			_, _ = db.lookup(ip)
+		}
+	})
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add(encodeValue(fixtureNetworks[0].record))
+	f.Add([]byte{0x20, 0x02, 0x20, 0x00})
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		_, _, _ = (&decoder{buf: buf}).decode(0)
+	})
+}
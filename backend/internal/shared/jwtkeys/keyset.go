@@ -0,0 +1,300 @@
+// Package jwtkeys — ключи подписи JWT: HS256 по общему секрету или RS256/EdDSA
+// из каталога с ротацией. Каталог содержит keyset.json и по одному PEM-файлу
+// (PKCS#8) на ключ. Подписывает самый новый ключ, чей not_before уже наступил;
+// все ключи из каталога принимаются при проверке и публикуются в JWKS
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	manifestFile = "keyset.json"
+	// Как часто проверяем, не изменился ли каталог ключей (ротация без перезапуска)
+	reloadInterval = time.Minute
+)
+
+var (
+	ErrNoSigningKey = errors.New("no active signing key")
+	ErrUnknownKey   = errors.New("unknown key id")
+)
+
+// KeyInfo — запись о ключе в keyset.json
+type KeyInfo struct {
+	ID        string    `json:"kid"`
+	Algorithm string    `json:"alg"`
+	CreatedAt time.Time `json:"created_at"`
+	// С этого момента ключ подписывает токены. До него ключ уже публикуется в JWKS,
+	// чтобы все экземпляры сервиса и внешние потребители успели его получить
+	NotBefore time.Time `json:"not_before"`
+}
+
+type manifest struct {
+	Keys []KeyInfo `json:"keys"`
+}
+
+type key struct {
+	info    KeyInfo
+	private crypto.Signer
+}
+
+// KeySet — набор ключей для подписи и проверки токенов
+type KeySet struct {
+	dir    string
+	secret []byte
+
+	mu        sync.RWMutex
+	keys      map[string]*key
+	ordered   []*key // по not_before, от старых к новым
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewHMAC — режим с общим секретом (HS256), без kid и JWKS
+func NewHMAC(secret []byte) *KeySet {
+	return &KeySet{secret: secret}
+}
+
+// Load читает ключи из каталога
+func Load(dir string) (*KeySet, error) {
+	ks := &KeySet{dir: dir}
+	if err := ks.reload(); err != nil {
+		return nil, err
+	}
+	if len(ks.ordered) == 0 {
+		return nil, fmt.Errorf("jwtkeys: no keys in %s", dir)
+	}
+	return ks, nil
+}
+
+// Sign подписывает claims активным ключом и проставляет kid
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.dir == "" {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+	ks.maybeReload()
+	k := ks.signingKey(time.Now())
+	if k == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(signingMethod(k.info.Algorithm), claims)
+	token.Header["kid"] = k.info.ID
+	return token.SignedString(k.private)
+}
+
+// Keyfunc для jwt.Parse: ключ выбирается по kid из заголовка
+func (ks *KeySet) Keyfunc(t *jwt.Token) (any, error) {
+	if ks.dir == "" {
+		return ks.secret, nil
+	}
+	ks.maybeReload()
+	kid, _ := t.Header["kid"].(string)
+	ks.mu.RLock()
+	k, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if t.Method.Alg() != k.info.Algorithm {
+		return nil, fmt.Errorf("jwtkeys: key %s is not for %s", kid, t.Method.Alg())
+	}
+	return k.private.Public(), nil
+}
+
+// ValidMethods — алгоритмы, которые принимаются при проверке
+func (ks *KeySet) ValidMethods() []string {
+	if ks.dir == "" {
+		return []string{AlgHS256}
+	}
+	return []string{AlgRS256, AlgEdDSA}
+}
+
+// JWK — открытый ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS — все открытые ключи, включая ещё не активные и уже заменённые
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if ks.dir == "" {
+		return set
+	}
+	ks.maybeReload()
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for _, k := range ks.ordered {
+		if jwk, err := publicJWK(k); err == nil {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+func (ks *KeySet) signingKey(now time.Time) *key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for i := len(ks.ordered) - 1; i >= 0; i-- {
+		if !ks.ordered[i].info.NotBefore.After(now) {
+			return ks.ordered[i]
+		}
+	}
+	return nil
+}
+
+func (ks *KeySet) maybeReload() {
+	ks.mu.RLock()
+	due := time.Since(ks.checkedAt) >= reloadInterval
+	ks.mu.RUnlock()
+	if !due {
+		return
+	}
+	// Ошибку перечитывания игнорируем: продолжаем работать со старым набором
+	_ = ks.reload()
+}
+
+func (ks *KeySet) reload() error {
+	ks.mu.Lock()
+	ks.checkedAt = time.Now()
+	ks.mu.Unlock()
+
+	st, err := os.Stat(filepath.Join(ks.dir, manifestFile))
+	if err != nil {
+		return err
+	}
+	ks.mu.RLock()
+	unchanged := st.ModTime().Equal(ks.modTime) && ks.keys != nil
+	ks.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	m, err := readManifest(ks.dir)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]*key, len(m.Keys))
+	ordered := make([]*key, 0, len(m.Keys))
+	for _, info := range m.Keys {
+		private, err := readPrivateKey(filepath.Join(ks.dir, info.ID+".pem"))
+		if err != nil {
+			return fmt.Errorf("jwtkeys: key %s: %w", info.ID, err)
+		}
+		if err := checkAlgorithm(info.Algorithm, private); err != nil {
+			return fmt.Errorf("jwtkeys: key %s: %w", info.ID, err)
+		}
+		k := &key{info: info, private: private}
+		keys[info.ID] = k
+		ordered = append(ordered, k)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].info.NotBefore.Before(ordered[j].info.NotBefore) })
+
+	ks.mu.Lock()
+	ks.keys, ks.ordered, ks.modTime = keys, ordered, st.ModTime()
+	ks.mu.Unlock()
+	return nil
+}
+
+func signingMethod(alg string) jwt.SigningMethod {
+	if alg == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+func checkAlgorithm(alg string, private crypto.Signer) error {
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		if alg == AlgRS256 {
+			return nil
+		}
+	case ed25519.PrivateKey:
+		if alg == AlgEdDSA {
+			return nil
+		}
+	}
+	return fmt.Errorf("key type does not match alg %q", alg)
+}
+
+func publicJWK(k *key) (JWK, error) {
+	jwk := JWK{Kid: k.info.ID, Use: "sig", Alg: k.info.Algorithm}
+	switch pub := k.private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", pub)
+	}
+	return jwk, nil
+}
+
+func readManifest(dir string) (*manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("jwtkeys: %s: %w", manifestFile, err)
+	}
+	return m, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %T", parsed)
+	}
+	return signer, nil
+}
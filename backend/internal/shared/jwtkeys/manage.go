@@ -0,0 +1,149 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Generate создаёт ключ алгоритма alg и добавляет его в каталог. Ключ начнёт
+// подписывать токены через activateIn; до этого он только публикуется в JWKS
+func Generate(dir, alg string, activateIn time.Duration) (KeyInfo, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return KeyInfo{}, fmt.Errorf("jwtkeys: unsupported alg %q (use %s or %s)", alg, AlgRS256, AlgEdDSA)
+	}
+	if err != nil {
+		return KeyInfo{}, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return KeyInfo{}, err
+	}
+	m, err := readManifest(dir)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+
+	now := time.Now().UTC()
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return KeyInfo{}, err
+	}
+	info := KeyInfo{
+		ID:        now.Format("20060102") + "-" + hex.EncodeToString(suffix),
+		Algorithm: alg,
+		CreatedAt: now,
+		NotBefore: now.Add(activateIn),
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, info.ID+".pem"), pemData, 0o600); err != nil {
+		return KeyInfo{}, err
+	}
+
+	m.Keys = append(m.Keys, info)
+	if err := writeManifest(dir, m); err != nil {
+		return KeyInfo{}, err
+	}
+	return info, nil
+}
+
+// Current — ключ, который подписывает токены сейчас
+func Current(dir string) (*KeyInfo, error) {
+	keys, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := len(keys) - 1; i >= 0; i-- {
+		if !keys[i].NotBefore.After(now) {
+			return &keys[i], nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// List — ключи каталога по not_before, от старых к новым
+func List(dir string) ([]KeyInfo, error) {
+	m, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := append([]KeyInfo(nil), m.Keys...)
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].NotBefore.Before(keys[j].NotBefore) })
+	return keys, nil
+}
+
+// Prune удаляет ключи, которые заменены новым активным ключом дольше чем olderThan
+// назад. olderThan должен быть больше срока жизни access-токена
+func Prune(dir string, olderThan time.Duration) ([]string, error) {
+	keys, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	keep := make([]KeyInfo, 0, len(keys))
+	var removed []string
+	for i, k := range keys {
+		// Ключ заменён, когда активировался следующий за ним
+		if i+1 < len(keys) && !keys[i+1].NotBefore.After(now) && now.Sub(keys[i+1].NotBefore) > olderThan {
+			removed = append(removed, k.ID)
+			continue
+		}
+		keep = append(keep, k)
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	if err := writeManifest(dir, &manifest{Keys: keep}); err != nil {
+		return nil, err
+	}
+	for _, kid := range removed {
+		if err := os.Remove(filepath.Join(dir, kid+".pem")); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// writeManifest пишет keyset.json атомарно: работающий сервис не должен увидеть его наполовину
+func writeManifest(dir string, m *manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, manifestFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, manifestFile))
+}
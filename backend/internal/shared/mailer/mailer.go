@@ -0,0 +1,103 @@
+// Package mailer — отправка писем. Реализации: SMTP для окружений с почтовым
+// сервером и запись в файлы для локальной разработки и тестов
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Message — письмо в виде простого текста
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// render собирает письмо в формате RFC 5322
+func render(from string, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// validHeader отсекает попытки внедрить заголовки через адрес или тему
+func validHeader(values ...string) error {
+	for _, v := range values {
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("mailer: header contains line break")
+		}
+	}
+	return nil
+}
+
+// FileMailer складывает письма в каталог как .eml — по одному файлу на письмо
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%04d.eml", time.Now().Format("20060102T150405.000"), m.seq.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), render(m.from, msg), 0o644)
+}
+
+// SMTPMailer отправляет письма через SMTP с PLAIN-аутентификацией (STARTTLS,
+// если сервер его поддерживает)
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+	// envelope — голый адрес отправителя для MAIL FROM
+	envelope string
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	envelope := from
+	if addr, err := mail.ParseAddress(from); err == nil {
+		envelope = addr.Address
+	}
+	return &SMTPMailer{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from, envelope: envelope}
+}
+
+func (m *SMTPMailer) Send(_ context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.envelope, []string{msg.To}, render(m.from, msg))
+}
@@ -20,11 +20,30 @@ func ABACMiddleware(engine *abac.Engine, resourceType, action string) echo.Middl
 			}
 
 			userID, _ := uuid.Parse(userIDStr)
-			user := &entity.User{ID: userID} // потом можно подгружать полные данные
+			// Полные данные пользователя (роль и т.д.) кладёт LoadCurrentUser
+			user, ok := c.Get(UserKey).(*entity.User)
+			if !ok || user.ID != userID {
+				user = &entity.User{ID: userID}
+			}
+
+			mfa, _ := c.Get(MFAKey).(bool)
+			_, impersonated := c.Get(ImpersonatorKey).(string)
+
+			// Персональный токен ограничен своими scopes поверх политик
+			auth := "session"
+			if token, ok := c.Get(APITokenKey).(*entity.APIToken); ok {
+				if !token.Allows(resourceType, action) {
+					return echo.NewHTTPError(http.StatusForbidden, "api token scope does not allow "+resourceType+":"+action)
+				}
+				auth = "api_token"
+			}
 
 			// Подгружаем автора ресурса, если нужно
 			resourceAuthorID := c.Get("resource_author_id")
 			targetAuthorID := c.Get("target_author_id")
+			courseAuthorID := c.Get("course_author_id")
+			// Организация ресурса (см. SetResourceOrgMiddleware); nil — общий каталог
+			resourceOrgID := c.Get("resource_org_id")
 
 			ctx := abac.Context{
 				User: user,
@@ -33,6 +52,8 @@ func ABACMiddleware(engine *abac.Engine, resourceType, action string) echo.Middl
 					"id":               c.Param("id"),
 					"author_id":        resourceAuthorID,
 					"target_author_id": targetAuthorID,
+					"course_author_id": courseAuthorID,
+					"org_id":           resourceOrgID,
 					"user_id":          userIDStr,
 				},
 				Action: action,
@@ -42,6 +63,12 @@ func ABACMiddleware(engine *abac.Engine, resourceType, action string) echo.Middl
 						"weekday": int(time.Now().Weekday()),
 					},
 					"ip": c.RealIP(),
+					// Второй фактор пройден в текущей сессии
+					"mfa": mfa,
+					// Способ входа: session или api_token
+					"auth": auth,
+					// Сессия открыта администратором от имени пользователя
+					"impersonated": impersonated,
 				},
 			}
 
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// APITokenMiddleware принимает персональные API-токены (Bearer edu_pat_...) и ставится
+// перед JWTMiddleware: запросы с обычным access-токеном проходят дальше без изменений.
+// Права токена ограничивает ABACMiddleware по его scopes
+func APITokenMiddleware(tokens *usecase.APITokenService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			raw, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			if !ok || !strings.HasPrefix(raw, usecase.APITokenPrefix) {
+				return next(c)
+			}
+
+			token, err := tokens.Authenticate(c.Request().Context(), raw, c.RealIP())
+			if errors.Is(err, usecase.ErrAPITokenInvalid) {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+			if err != nil {
+				c.Logger().Errorf("failed to check api token: %v", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to check api token")
+			}
+
+			c.Set(UserIDKey, token.UserID.String())
+			c.Set(APITokenKey, token)
+			c.Set(MFAKey, token.MFA)
+			return next(c)
+		}
+	}
+}
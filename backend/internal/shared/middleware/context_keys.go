@@ -1,3 +1,15 @@
 package middleware
 
 const UserIDKey = "user_id"
+
+// UserKey — загруженный *entity.User текущего пользователя (см. LoadCurrentUser)
+const UserKey = "current_user"
+
+// MFAKey — пройден ли в текущей сессии второй фактор (env.mfa в ABAC)
+const MFAKey = "mfa"
+
+// APITokenKey — *entity.APIToken, если запрос авторизован персональным токеном
+const APITokenKey = "api_token"
+
+// ImpersonatorKey — ID администратора (string), если сессия открыта им от имени пользователя
+const ImpersonatorKey = "impersonator_id"
@@ -0,0 +1,51 @@
+// internal/shared/middleware/current_user.go
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// LoadCurrentUser подгружает пользователя по user_id из JWT и кладёт его в контекст,
+// чтобы ABAC-политики могли опираться на роль. Ошибки загрузки не прерывают запрос,
+// запросы заблокированных пользователей отклоняются.
+func LoadCurrentUser(userService *usecase.UserService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userIDStr, ok := c.Get(UserIDKey).(string)
+			if !ok {
+				return next(c)
+			}
+
+			userID, err := uuid.Parse(userIDStr)
+			if err != nil {
+				return next(c)
+			}
+
+			user, err := userService.GetUserByID(c.Request().Context(), userID)
+			if err == nil && user != nil {
+				// Блокировка действует и на уже выданные токены
+				if user.IsBanned(time.Now()) {
+					return echo.NewHTTPError(http.StatusForbidden, "account is suspended")
+				}
+				c.Set(UserKey, user)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// CurrentOrgID — организация текущего пользователя: фильтр каталога и владелец нового контента;
+// nil — пользователь не вошёл или не состоит в организации (виден только общий каталог)
+func CurrentOrgID(c echo.Context) *uuid.UUID {
+	if user, ok := c.Get(UserKey).(*entity.User); ok {
+		return user.OrgID
+	}
+	return nil
+}
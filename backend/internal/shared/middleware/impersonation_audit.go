@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// ImpersonationAudit записывает в журнал администраторов каждый запрос, выполненный
+// в сессии, открытой от имени пользователя. Ставится после JWTMiddleware
+func ImpersonationAudit(admin *usecase.AdminUserService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			impersonator, ok := c.Get(ImpersonatorKey).(string)
+			if !ok {
+				return next(c)
+			}
+
+			err := next(c)
+
+			status := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok && !c.Response().Committed {
+				status = he.Code
+			}
+			// Значения положил JWTMiddleware из сессии, они всегда корректны
+			userIDStr, _ := c.Get(UserIDKey).(string)
+			sessionIDStr, _ := c.Get("session_id").(string)
+			impersonatorID, _ := uuid.Parse(impersonator)
+			userID, _ := uuid.Parse(userIDStr)
+			sessionID, _ := uuid.Parse(sessionIDStr)
+			record := usecase.ImpersonatedRequest{
+				ImpersonatorID: impersonatorID,
+				UserID:         userID,
+				SessionID:      sessionID,
+				Method:         c.Request().Method,
+				Path:           c.Request().URL.RequestURI(),
+				Status:         status,
+				IP:             c.RealIP(),
+			}
+			if auditErr := admin.RecordImpersonatedRequest(c.Request().Context(), record); auditErr != nil {
+				logger.Error("Не удалось записать запрос от имени пользователя в журнал", auditErr)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// optionalAuthPassedKey — запрос прошёл всю цепочку OptionalAuth
+const optionalAuthPassedKey = "optional_auth_passed"
+
+// OptionalAuth применяет цепочку авторизации к публичному маршруту, только если
+// клиент прислал заголовок Authorization. Ошибка авторизации не закрывает маршрут:
+// запрос обрабатывается как анонимный. Нужен публичному каталогу, чтобы вошедшие
+// участники организаций видели и её закрытый контент
+func OptionalAuth(chain ...echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		authenticated := echo.HandlerFunc(func(c echo.Context) error {
+			c.Set(optionalAuthPassedKey, true)
+			return next(c)
+		})
+		for i := len(chain) - 1; i >= 0; i-- {
+			authenticated = chain[i](authenticated)
+		}
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(echo.HeaderAuthorization) == "" {
+				return next(c)
+			}
+			err := authenticated(c)
+			if passed, _ := c.Get(optionalAuthPassedKey).(bool); err != nil && !passed {
+				return next(c)
+			}
+			return err
+		}
+	}
+}
@@ -23,3 +23,40 @@ func SetResourceAuthorMiddleware(repo interface {
 		}
 	}
 }
+
+// SetCourseAuthorMiddleware кладёт в контекст автора курса, к которому относится ресурс,
+// чтобы ABAC-политики могли разрешать действия преподавателю курса
+func SetCourseAuthorMiddleware(repo interface {
+	GetCourseAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+}) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idStr := c.Param("id")
+			id, _ := uuid.Parse(idStr)
+			authorID, err := repo.GetCourseAuthorID(c.Request().Context(), id)
+			if err == nil {
+				c.Set("course_author_id", authorID.String())
+			}
+			return next(c)
+		}
+	}
+}
+
+// SetResourceOrgMiddleware кладёт в контекст организацию ресурса (resource.org_id в ABAC),
+// чтобы политики не пускали к закрытому контенту участников других организаций.
+// Для ресурсов общего каталога значение не ставится
+func SetResourceOrgMiddleware(repo interface {
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
+}) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idStr := c.Param("id")
+			id, _ := uuid.Parse(idStr)
+			orgID, err := repo.GetOrgID(c.Request().Context(), id)
+			if err == nil && orgID != nil {
+				c.Set("resource_org_id", orgID.String())
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrStateNotFound = errors.New("oidc state not found or expired")
+
+// AuthState — данные, сохранённые между редиректом на провайдера и callback.
+// Binding — значение HttpOnly-cookie браузера, начавшего вход: без него чужой
+// state не завершить. LinkUserID заполнен, когда уже вошедший пользователь
+// привязывает новый аккаунт
+type AuthState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	Binding      string `json:"binding"`
+	LinkUserID   string `json:"link_user_id,omitempty"`
+}
+
+// StateStore хранит state одноразово: Take удаляет запись
+type StateStore interface {
+	Save(ctx context.Context, state string, data *AuthState, ttl time.Duration) error
+	Take(ctx context.Context, state string) (*AuthState, error)
+}
+
+// MemoryStateStore — хранилище в памяти процесса; подходит для одного инстанса
+type MemoryStateStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryState
+	sweepAt time.Time
+}
+
+type memoryState struct {
+	data      *AuthState
+	expiresAt time.Time
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{entries: make(map[string]memoryState)}
+}
+
+func (s *MemoryStateStore) Save(_ context.Context, state string, data *AuthState, ttl time.Duration) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Просроченные записи вычищаем не чаще раза в минуту
+	if now.After(s.sweepAt) {
+		for k, e := range s.entries {
+			if now.After(e.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.sweepAt = now.Add(time.Minute)
+	}
+	s.entries[state] = memoryState{data: data, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStateStore) Take(_ context.Context, state string) (*AuthState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[state]
+	delete(s.entries, state)
+	if !ok || time.Now().After(e.expiresAt) {
+		return nil, ErrStateNotFound
+	}
+	return e.data, nil
+}
+
+// RedisStateStore — общее хранилище для нескольких инстансов
+type RedisStateStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStateStore(client *redis.Client, prefix string) *RedisStateStore {
+	return &RedisStateStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStateStore) Save(ctx context.Context, state string, data *AuthState, ttl time.Duration) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.prefix+state, b, ttl).Err()
+}
+
+func (s *RedisStateStore) Take(ctx context.Context, state string) (*AuthState, error) {
+	b, err := s.client.GetDel(ctx, s.prefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	data := &AuthState{}
+	if err := json.Unmarshal(b, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
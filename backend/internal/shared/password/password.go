@@ -0,0 +1,113 @@
+// Package password — хеширование паролей argon2id в формате PHC:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash> (base64 без паддинга)
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	MinLength = 8
+	// MaxLength ограничивает стоимость хеширования заведомо длинных строк
+	MaxLength = 128
+)
+
+// Параметры по рекомендации OWASP для argon2id
+const (
+	memory     uint32 = 64 * 1024
+	iterations uint32 = 3
+	threads    uint8  = 2
+	saltLen           = 16
+	keyLen     uint32 = 32
+)
+
+var (
+	ErrTooShort     = fmt.Errorf("password must be at least %d characters", MinLength)
+	ErrTooLong      = fmt.Errorf("password must be at most %d characters", MaxLength)
+	ErrInvalidHash  = errors.New("invalid password hash format")
+	ErrIncompatible = errors.New("incompatible argon2 version")
+	ErrMismatch     = errors.New("password does not match")
+)
+
+// Validate проверяет требования к паролю
+func Validate(pw string) error {
+	n := len([]rune(pw))
+	switch {
+	case n < MinLength:
+		return ErrTooShort
+	case n > MaxLength:
+		return ErrTooLong
+	}
+	return nil
+}
+
+// Hash возвращает PHC-строку argon2id со случайной солью
+func Hash(pw string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(pw), salt, iterations, memory, threads, keyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, memory, iterations, threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify сравнивает пароль с хешем за постоянное время.
+// Параметры берутся из самого хеша, поэтому старые хеши продолжают работать
+// после смены параметров по умолчанию
+func Verify(pw, encoded string) error {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return ErrInvalidHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return ErrIncompatible
+	}
+	var m, t uint32
+	var p uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &m, &t, &p); err != nil {
+		return ErrInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return ErrInvalidHash
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return ErrInvalidHash
+	}
+	got := argon2.IDKey([]byte(pw), salt, t, m, p, uint32(len(want)))
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+var (
+	dummyOnce sync.Once
+	dummyHash string
+)
+
+// VerifyDummy выполняет холостую проверку пароля, чтобы вход с несуществующим
+// email занимал столько же времени, сколько с существующим
+func VerifyDummy(pw string) {
+	dummyOnce.Do(func() {
+		dummyHash, _ = Hash("dummy-password-for-timing")
+	})
+	_ = Verify(pw, dummyHash)
+}
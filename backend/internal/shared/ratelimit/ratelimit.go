@@ -0,0 +1,90 @@
+// internal/shared/ratelimit/ratelimit.go
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limiter ограничивает число событий по ключу (чат, IP, email) за окно времени
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// Memory — скользящее окно в памяти процесса; подходит для одного инстанса
+type Memory struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	seen    map[string][]time.Time
+	sweepAt time.Time
+}
+
+func NewMemory(limit int, window time.Duration) *Memory {
+	return &Memory{limit: limit, window: window, seen: map[string][]time.Time{}}
+}
+
+func (l *Memory) Allow(_ context.Context, key string) (bool, error) {
+	now := time.Now()
+	from := now.Add(-l.window)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Ключи без свежих событий вычищаем не чаще раза за окно
+	if now.After(l.sweepAt) {
+		for k, times := range l.seen {
+			if len(times) == 0 || times[len(times)-1].Before(from) {
+				delete(l.seen, k)
+			}
+		}
+		l.sweepAt = now.Add(l.window)
+	}
+
+	times := l.seen[key]
+	i := 0
+	for i < len(times) && times[i].Before(from) {
+		i++
+	}
+	times = times[i:]
+	if len(times) >= l.limit {
+		l.seen[key] = times
+		return false, nil
+	}
+	l.seen[key] = append(times, now)
+	return true, nil
+}
+
+// Redis — фиксированное окно в Redis, общее для нескольких инстансов
+type Redis struct {
+	client *redis.Client
+	prefix string
+	limit  int
+	window time.Duration
+}
+
+func NewRedis(client *redis.Client, prefix string, limit int, window time.Duration) *Redis {
+	return &Redis{client: client, prefix: prefix, limit: limit, window: window}
+}
+
+// Allow увеличивает счётчик окна и ставит TTL одной транзакцией MULTI/EXEC:
+// ключ без TTL (сбой между INCR и EXPIRE) заблокировал бы его навсегда.
+// EXPIRE NX не продлевает окно при каждом запросе
+func (l *Redis) Allow(ctx context.Context, key string) (bool, error) {
+	slot := time.Now().UnixNano() / int64(l.window)
+	k := l.prefix + key + ":" + strconv.FormatInt(slot, 10)
+	var incr *redis.IntCmd
+	_, err := l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, k)
+		pipe.ExpireNX(ctx, k, l.window)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return incr.Val() <= int64(l.limit), nil
+}
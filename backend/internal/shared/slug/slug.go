@@ -0,0 +1,98 @@
+// internal/shared/slug/slug.go
+package slug
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxLength — максимальная длина генерируемого slug
+const MaxLength = 80
+
+// maxAttempts — сколько суффиксов (-2, -3, ...) перебирать до отказа
+const maxAttempts = 100
+
+var (
+	ErrInvalidSlug = errors.New("slug must contain only lowercase latin letters, digits and hyphens")
+	ErrSlugTaken   = errors.New("slug is already taken")
+
+	validSlug = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+)
+
+// Транслитерация кириллицы (упрощённая схема, как в URL Яндекса/Википедии)
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+	// Украинские и белорусские буквы встречаются в названиях курсов
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// Make строит slug из заголовка: транслитерация, нижний регистр,
+// всё кроме букв и цифр заменяется дефисом
+func Make(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if t, ok := translit[r]; ok {
+			b.WriteString(t)
+			dash = false
+			continue
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	s := strings.Trim(b.String(), "-")
+	if len(s) > MaxLength {
+		s = s[:MaxLength]
+		if i := strings.LastIndexByte(s, '-'); i > MaxLength/2 {
+			s = s[:i]
+		}
+		s = strings.Trim(s, "-")
+	}
+	return s
+}
+
+// Valid проверяет, что slug задан в каноничной форме
+func Valid(s string) bool {
+	return len(s) <= MaxLength && validSlug.MatchString(s)
+}
+
+// Unique подбирает свободный slug на основе base, добавляя числовой суффикс.
+// fallback используется, если из заголовка не удалось получить ни одного символа
+func Unique(ctx context.Context, base, fallback string, exists func(ctx context.Context, s string) (bool, error)) (string, error) {
+	if base == "" {
+		base = fallback
+	}
+	for i := 1; i <= maxAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := "-" + strconv.Itoa(i)
+			if len(candidate)+len(suffix) > MaxLength {
+				candidate = strings.TrimRight(candidate[:MaxLength-len(suffix)], "-")
+			}
+			candidate += suffix
+		}
+		taken, err := exists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", ErrSlugTaken
+}
@@ -1,40 +1,150 @@
 package telegram
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
+	"errors"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAuthMaxAge — срок годности данных Login Widget, если в конфиге не задан свой
+const DefaultAuthMaxAge = 24 * time.Hour
+
+var (
+	ErrInvalidHash     = errors.New("telegram auth hash mismatch")
+	ErrAuthDateMissing = errors.New("telegram auth_date is missing")
+	ErrAuthExpired     = errors.New("telegram auth data is too old")
+	ErrAuthReplayed    = errors.New("telegram auth data has already been used")
 )
 
 type AuthData struct {
 	ID        int64
 	Username  string
 	FirstName string
+	LastName  string
+	PhotoURL  string
+	AuthDate  int64
 	Hash      string
+	// Fields — все полученные поля, кроме hash; по ним строится data-check-string,
+	// чтобы новые поля от Telegram не ломали проверку
+	Fields map[string]string
 }
 
 func ParseTelegramAuth(r *http.Request) AuthData {
+	_ = r.ParseForm()
+	fields := make(map[string]string, len(r.Form))
+	for key, values := range r.Form {
+		if key == "hash" || len(values) == 0 {
+			continue
+		}
+		fields[key] = values[0]
+	}
 	return AuthData{
-		ID:        parseInt64(r.FormValue("id")),
-		Username:  r.FormValue("username"),
-		FirstName: r.FormValue("first_name"),
-		Hash:      r.FormValue("hash"),
+		ID:        parseInt64(fields["id"]),
+		Username:  fields["username"],
+		FirstName: fields["first_name"],
+		LastName:  fields["last_name"],
+		PhotoURL:  fields["photo_url"],
+		AuthDate:  parseInt64(fields["auth_date"]),
+		Hash:      r.Form.Get("hash"),
+		Fields:    fields,
+	}
+}
+
+// FullName — имя и фамилия из профиля Telegram
+func (a AuthData) FullName() string {
+	return strings.TrimSpace(a.FirstName + " " + a.LastName)
+}
+
+// DataCheckString — поля в алфавитном порядке в формате key=value, разделённые \n
+func DataCheckString(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + fields[key]
+	}
+	return strings.Join(pairs, "\n")
 }
 
+// VerifyTelegramAuth проверяет подпись Login Widget: HMAC-SHA256 от data-check-string
+// с ключом SHA256(bot_token)
 func VerifyTelegramAuth(authData AuthData, botToken string) bool {
 	secretKey := sha256.Sum256([]byte(botToken))
+	return checkHash(secretKey[:], authData.Fields, authData.Hash)
+}
+
+func checkHash(secretKey []byte, fields map[string]string, hash string) bool {
+	expected, err := hex.DecodeString(hash)
+	if err != nil || len(expected) != sha256.Size {
+		return false
+	}
+	h := hmac.New(sha256.New, secretKey)
+	h.Write([]byte(DataCheckString(fields)))
+	return hmac.Equal(h.Sum(nil), expected)
+}
 
-	dataCheckString := fmt.Sprintf("first_name=%s\nid=%d\nusername=%s", authData.FirstName, authData.ID, authData.Username)
+// LoginVerifier — полная проверка входа через Login Widget:
+// подпись, свежесть auth_date и одноразовость hash
+type LoginVerifier struct {
+	botToken string
+	maxAge   time.Duration
+	replay   ReplayCache
+	now      func() time.Time
+}
+
+func NewLoginVerifier(botToken string, maxAge time.Duration, replay ReplayCache) *LoginVerifier {
+	if maxAge <= 0 {
+		maxAge = DefaultAuthMaxAge
+	}
+	return &LoginVerifier{botToken: botToken, maxAge: maxAge, replay: replay, now: time.Now}
+}
+
+func (v *LoginVerifier) Verify(ctx context.Context, authData AuthData) error {
+	if !VerifyTelegramAuth(authData, v.botToken) {
+		return ErrInvalidHash
+	}
+	if err := v.checkAuthDate(authData.AuthDate); err != nil {
+		return err
+	}
+	return v.remember(ctx, authData.Hash)
+}
 
-	h := hmac.New(sha256.New, secretKey[:])
-	h.Write([]byte(dataCheckString))
-	hash := hex.EncodeToString(h.Sum(nil))
+func (v *LoginVerifier) checkAuthDate(authDate int64) error {
+	if authDate <= 0 {
+		return ErrAuthDateMissing
+	}
+	issued := time.Unix(authDate, 0)
+	// Небольшой допуск на расхождение часов с серверами Telegram
+	if v.now().Sub(issued) > v.maxAge || issued.Sub(v.now()) > time.Minute {
+		return ErrAuthExpired
+	}
+	return nil
+}
 
-	return hash == authData.Hash
+// remember помечает hash использованным; держать его дольше maxAge не нужно —
+// после этого данные отклоняются по auth_date
+func (v *LoginVerifier) remember(ctx context.Context, hash string) error {
+	if v.replay == nil {
+		return nil
+	}
+	fresh, err := v.replay.Remember(ctx, hash, v.maxAge)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrAuthReplayed
+	}
+	return nil
 }
 
 func parseInt64(s string) int64 {
@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ReplayCache запоминает уже использованные подписи.
+// Remember возвращает false, если ключ уже встречался в пределах ttl
+type ReplayCache interface {
+	Remember(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// MemoryReplayCache — кэш в памяти процесса; подходит для одного инстанса
+type MemoryReplayCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	sweepAt time.Time
+}
+
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{entries: make(map[string]time.Time)}
+}
+
+func (c *MemoryReplayCache) Remember(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Просроченные записи вычищаем не чаще раза в минуту
+	if now.After(c.sweepAt) {
+		for k, exp := range c.entries {
+			if now.After(exp) {
+				delete(c.entries, k)
+			}
+		}
+		c.sweepAt = now.Add(time.Minute)
+	}
+
+	if exp, ok := c.entries[key]; ok && now.Before(exp) {
+		return false, nil
+	}
+	c.entries[key] = now.Add(ttl)
+	return true, nil
+}
+
+// RedisReplayCache — общий кэш для нескольких инстансов
+type RedisReplayCache struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisReplayCache(client *redis.Client, prefix string) *RedisReplayCache {
+	return &RedisReplayCache{client: client, prefix: prefix}
+}
+
+func (c *RedisReplayCache) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, c.prefix+key, 1, ttl).Result()
+}
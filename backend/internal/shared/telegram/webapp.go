@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/url"
+)
+
+var ErrInvalidInitData = errors.New("telegram init data is malformed")
+
+// WebAppUser — поле user из initData Mini App
+type WebAppUser struct {
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Username     string `json:"username"`
+	LanguageCode string `json:"language_code"`
+	PhotoURL     string `json:"photo_url"`
+	IsPremium    bool   `json:"is_premium"`
+}
+
+// WebAppInitData — разобранная строка Telegram.WebApp.initData
+type WebAppInitData struct {
+	User     WebAppUser
+	AuthDate int64
+	QueryID  string
+	Hash     string
+	Fields   map[string]string
+}
+
+// ParseWebAppInitData разбирает initData (query-строку) Mini App
+func ParseWebAppInitData(raw string) (WebAppInitData, error) {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return WebAppInitData{}, ErrInvalidInitData
+	}
+	fields := make(map[string]string, len(values))
+	for key, vals := range values {
+		if key == "hash" || len(vals) == 0 {
+			continue
+		}
+		fields[key] = vals[0]
+	}
+	data := WebAppInitData{
+		AuthDate: parseInt64(fields["auth_date"]),
+		QueryID:  fields["query_id"],
+		Hash:     values.Get("hash"),
+		Fields:   fields,
+	}
+	if fields["user"] == "" {
+		return data, ErrInvalidInitData
+	}
+	if err := json.Unmarshal([]byte(fields["user"]), &data.User); err != nil || data.User.ID == 0 {
+		return data, ErrInvalidInitData
+	}
+	return data, nil
+}
+
+// AuthData приводит данные Mini App к формату Login Widget для создания пользователя
+func (d WebAppInitData) AuthData() AuthData {
+	return AuthData{
+		ID:        d.User.ID,
+		Username:  d.User.Username,
+		FirstName: d.User.FirstName,
+		LastName:  d.User.LastName,
+		PhotoURL:  d.User.PhotoURL,
+		AuthDate:  d.AuthDate,
+		Hash:      d.Hash,
+		Fields:    d.Fields,
+	}
+}
+
+// VerifyWebAppInitData проверяет подпись initData: ключ — HMAC-SHA256("WebAppData", bot_token),
+// подпись — HMAC-SHA256 от data-check-string с этим ключом
+func VerifyWebAppInitData(data WebAppInitData, botToken string) bool {
+	h := hmac.New(sha256.New, []byte("WebAppData"))
+	h.Write([]byte(botToken))
+	return checkHash(h.Sum(nil), data.Fields, data.Hash)
+}
+
+// VerifyWebApp — проверка initData Mini App: подпись и свежесть auth_date.
+// Replay-кэш не применяется: Mini App отдаёт одну и ту же initData
+// на всё время открытия и повторно авторизуется при перезагрузке страницы
+func (v *LoginVerifier) VerifyWebApp(_ context.Context, data WebAppInitData) error {
+	if !VerifyWebAppInitData(data, v.botToken) {
+		return ErrInvalidHash
+	}
+	return v.checkAuthDate(data.AuthDate)
+}
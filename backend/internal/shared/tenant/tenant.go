@@ -0,0 +1,11 @@
+// Package tenant — разделение контента между организациями
+package tenant
+
+import "fmt"
+
+// Visible возвращает условие видимости строк с колонкой org_id: общий каталог
+// (org_id IS NULL) и контент организации зрителя из параметра $arg.
+// Зрителю без организации (NULL в $arg) виден только общий каталог
+func Visible(column string, arg int) string {
+	return fmt.Sprintf("(%s IS NULL OR %s = $%d)", column, column, arg)
+}
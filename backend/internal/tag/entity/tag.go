@@ -1,10 +1,15 @@
 package entity
 
-import "github.com/kostinp/edu-platform-backend/internal/shared/entity"
+import (
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/entity"
+)
 
 type Tag struct {
 	entity.Base
 
 	Name        string `json:"name" db:"name"`
 	Description string `json:"description" db:"description"`
+	// Организация, которой принадлежит тег; nil — общий каталог
+	OrgID *uuid.UUID `json:"org_id,omitempty" db:"org_id"`
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/shared/tenant"
 	"github.com/kostinp/edu-platform-backend/internal/tag/entity"
 )
 
@@ -18,8 +19,11 @@ type TagRepository interface {
 	Create(ctx context.Context, tag *entity.Tag) error
 	Update(ctx context.Context, tag *entity.Tag) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error)
-	List(ctx context.Context, pag pagination.Params) ([]*entity.Tag, int, error)
+	// List — общий каталог и теги организации viewerOrg (nil — только общий каталог)
+	List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Tag, int, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// GetOrgID — организация тега (resource.org_id в ABAC); nil — общий каталог
+	GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error)
 }
 
 // PostgresTagRepository — реализация TagRepository для PostgreSQL
@@ -41,9 +45,9 @@ func NewPostgresTagRepository(db *pgxpool.Pool) *PostgresTagRepository {
 // Create добавляет новый тег в базу
 func (r *PostgresTagRepository) Create(ctx context.Context, tag *entity.Tag) error {
 	_, err := r.db.Exec(ctx, `
-		INSERT INTO tags (id, name, description, author_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, tag.ID, tag.Name, tag.Description, tag.AuthorID, tag.CreatedAt, tag.UpdatedAt)
+		INSERT INTO tags (id, name, description, author_id, created_at, updated_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, tag.ID, tag.Name, tag.Description, tag.AuthorID, tag.CreatedAt, tag.UpdatedAt, tag.OrgID)
 	return err
 }
 
@@ -76,12 +80,12 @@ func (r *PostgresTagRepository) Delete(ctx context.Context, tagID uuid.UUID) err
 // GetByID возвращает тег по ID
 func (r *PostgresTagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error) {
 	row := r.db.QueryRow(ctx, `
-		SELECT id, name, description, author_id, created_at, updated_at
+		SELECT id, name, description, author_id, created_at, updated_at, org_id
 		FROM tags WHERE id = $1
 	`, id)
 
 	tag := &entity.Tag{}
-	err := row.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.AuthorID, &tag.CreatedAt, &tag.UpdatedAt)
+	err := row.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.AuthorID, &tag.CreatedAt, &tag.UpdatedAt, &tag.OrgID)
 	if err != nil {
 		return nil, err
 	}
@@ -89,12 +93,12 @@ func (r *PostgresTagRepository) GetByID(ctx context.Context, id uuid.UUID) (*ent
 }
 
 // List возвращает список тегов с пагинацией
-func (r *PostgresTagRepository) List(ctx context.Context, pag pagination.Params) ([]*entity.Tag, int, error) {
+func (r *PostgresTagRepository) List(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Tag, int, error) {
 	baseQuery := `
-		SELECT id, name, description, author_id, created_at, updated_at
-		FROM tags
-	`
+		SELECT id, name, description, author_id, created_at, updated_at, org_id
+		FROM tags WHERE ` + tenant.Visible("org_id", 3)
 	query, args := pagination.SQLWithPagination(baseQuery, pag, allowedSortFields)
+	args = append(args, viewerOrg)
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
@@ -103,17 +107,24 @@ func (r *PostgresTagRepository) List(ctx context.Context, pag pagination.Params)
 	var tags []*entity.Tag
 	for rows.Next() {
 		tag := &entity.Tag{}
-		err := rows.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.AuthorID, &tag.CreatedAt, &tag.UpdatedAt)
+		err := rows.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.AuthorID, &tag.CreatedAt, &tag.UpdatedAt, &tag.OrgID)
 		if err != nil {
 			return nil, 0, err
 		}
 		tags = append(tags, tag)
 	}
-	countQuery := `SELECT COUNT(*) FROM tags`
+	countQuery := `SELECT COUNT(*) FROM tags WHERE ` + tenant.Visible("org_id", 1)
 	var total int
-	err = r.db.QueryRow(ctx, countQuery).Scan(&total)
+	err = r.db.QueryRow(ctx, countQuery, viewerOrg).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 	return tags, total, nil
 }
+
+// GetOrgID возвращает организацию тега
+func (r *PostgresTagRepository) GetOrgID(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT org_id FROM tags WHERE id = $1`, id).Scan(&orgID)
+	return orgID, err
+}
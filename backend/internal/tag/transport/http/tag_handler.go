@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
 	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
 	"github.com/kostinp/edu-platform-backend/internal/tag/entity"
 	"github.com/kostinp/edu-platform-backend/internal/tag/usecase"
@@ -26,6 +27,7 @@ func NewTagHandler(tu usecase.TagUsecase, tau usecase.TagAssignmentUsecase) *Tag
 
 // CreateTag
 // @Summary Создать тег
+// @Description Тег участника организации виден только её участникам
 // @Tags Tag
 // @Security BearerAuth
 // @Accept json
@@ -46,7 +48,7 @@ func (h *TagHandler) CreateTag(c echo.Context) error {
 	if err := c.Bind(tag); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
-	err = h.TagUsecase.CreateTag(c.Request().Context(), tag, authorID)
+	err = h.TagUsecase.CreateTag(c.Request().Context(), tag, authorID, middleware.CurrentOrgID(c))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create tag"})
 	}
@@ -55,6 +57,7 @@ func (h *TagHandler) CreateTag(c echo.Context) error {
 
 // ListTags
 // @Summary Получить список тегов
+// @Description Общий каталог и теги организации текущего пользователя
 // @Tags Tag
 // @Security BearerAuth
 // @Produce json
@@ -69,7 +72,7 @@ func (h *TagHandler) CreateTag(c echo.Context) error {
 func (h *TagHandler) ListTags(c echo.Context) error {
 	pagQuery := pagination.ParsePaginationParams(c)
 	pag := pagQuery.ToDomainParams()
-	tags, total, err := h.TagUsecase.ListTags(c.Request().Context(), pag)
+	tags, total, err := h.TagUsecase.ListTags(c.Request().Context(), middleware.CurrentOrgID(c), pag)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "failed to list tags",
@@ -13,11 +13,13 @@ import (
 var ErrTagNotFound = errors.New("tag not found")
 
 type TagUsecase interface {
-	CreateTag(ctx context.Context, tag *entity.Tag, authorID uuid.UUID) error
+	// CreateTag сохраняет тег в организации автора (nil — в общем каталоге)
+	CreateTag(ctx context.Context, tag *entity.Tag, authorID uuid.UUID, orgID *uuid.UUID) error
 	UpdateTag(ctx context.Context, tag *entity.Tag) error
 	DeleteTag(ctx context.Context, tagID uuid.UUID) error
 	GetTagByID(ctx context.Context, tagID uuid.UUID) (*entity.Tag, error)
-	ListTags(ctx context.Context, pag pagination.Params) ([]*entity.Tag, int, error)
+	// ListTags — общий каталог и теги организации viewerOrg
+	ListTags(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Tag, int, error)
 }
 
 type tagUsecase struct {
@@ -28,8 +30,9 @@ func NewTagUsecase(tr repository.TagRepository) TagUsecase {
 	return &tagUsecase{tagRepo: tr}
 }
 
-func (u *tagUsecase) CreateTag(ctx context.Context, tag *entity.Tag, authorID uuid.UUID) error {
+func (u *tagUsecase) CreateTag(ctx context.Context, tag *entity.Tag, authorID uuid.UUID, orgID *uuid.UUID) error {
 	tag.Init(authorID)
+	tag.OrgID = orgID
 	return u.tagRepo.Create(ctx, tag)
 }
 
@@ -46,6 +49,6 @@ func (u *tagUsecase) GetTagByID(ctx context.Context, tagID uuid.UUID) (*entity.T
 	return u.tagRepo.GetByID(ctx, tagID)
 }
 
-func (u *tagUsecase) ListTags(ctx context.Context, pag pagination.Params) ([]*entity.Tag, int, error) {
-	return u.tagRepo.List(ctx, pag)
+func (u *tagUsecase) ListTags(ctx context.Context, viewerOrg *uuid.UUID, pag pagination.Params) ([]*entity.Tag, int, error) {
+	return u.tagRepo.List(ctx, viewerOrg, pag)
 }
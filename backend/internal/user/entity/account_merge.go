@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MergeReason — почему аккаунты были объединены
+type MergeReason string
+
+const (
+	// Гость вошёл через Telegram, а у этого Telegram уже есть пользователь
+	MergeReasonTelegramLogin MergeReason = "telegram_login"
+	// Пользователь сам объединил два своих аккаунта
+	MergeReasonSelf  MergeReason = "self"
+	MergeReasonAdmin MergeReason = "admin"
+)
+
+// AccountMerge — запись аудита о слиянии SourceUserID в TargetUserID
+type AccountMerge struct {
+	ID             uuid.UUID        `json:"id"`
+	SourceUserID   uuid.UUID        `json:"source_user_id"`
+	TargetUserID   uuid.UUID        `json:"target_user_id"`
+	InitiatedBy    *uuid.UUID       `json:"initiated_by,omitempty"`
+	Reason         MergeReason      `json:"reason"`
+	SourceSnapshot map[string]any   `json:"source_snapshot"`
+	Moved          map[string]int64 `json:"moved"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
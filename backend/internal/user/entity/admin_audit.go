@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Действия администраторов, которые попадают в журнал
+const (
+	AuditRoleChange          = "role_change"
+	AuditBan                 = "ban"
+	AuditUnban               = "unban"
+	AuditRevokeSessions      = "revoke_sessions"
+	AuditImpersonationStart  = "impersonation_start"
+	AuditImpersonatedRequest = "impersonated_request"
+	AuditTeacherApproved     = "teacher_application_approved"
+	AuditTeacherRejected     = "teacher_application_rejected"
+)
+
+// AdminAuditEntry — запись журнала действий администратора над пользователем
+type AdminAuditEntry struct {
+	ID           uuid.UUID      `json:"id"`
+	ActorID      uuid.UUID      `json:"actor_id"`
+	Action       string         `json:"action" example:"ban"`
+	TargetUserID *uuid.UUID     `json:"target_user_id,omitempty"`
+	Details      map[string]any `json:"details"`
+	IPAddress    string         `json:"ip_address" example:"192.168.1.1"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APITokenSessionOnlyResources — ресурсы ABAC, недоступные по API-токену: управление
+// самими токенами, 2FA, сессиями, привязками, выгрузка данных и удаление аккаунта
+// требуют входа в браузере
+var APITokenSessionOnlyResources = []string{"api_token", "mfa", "user_sessions", "identity", "account_merge", "account"}
+
+// APIToken — персональный токен для скриптов и интеграций. Сам токен показывается
+// один раз при создании, в БД хранится только хэш
+type APIToken struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name" example:"course import"`
+	// Начало токена — чтобы узнать его в списке
+	Prefix    string `json:"prefix" example:"edu_pat_Xk2f"`
+	TokenHash string `json:"-"`
+	// Пары resource:action из ABAC; * — любой ресурс или действие
+	Scopes []string `json:"scopes" example:"course:create,analytics:read"`
+	// Выпущен из сессии, прошедшей 2FA: запросы с ним получают env.mfa
+	MFA        bool       `json:"mfa"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Allows проверяет, покрывают ли scopes токена действие action над ресурсом resource.
+// Роль пользователя и остальные политики ABAC проверяются как обычно
+func (t *APIToken) Allows(resource, action string) bool {
+	for _, r := range APITokenSessionOnlyResources {
+		if r == resource {
+			return false
+		}
+	}
+	for _, scope := range t.Scopes {
+		res, act, ok := strings.Cut(scope, ":")
+		if !ok {
+			continue
+		}
+		if (res == "*" || res == resource) && (act == "*" || act == action) {
+			return true
+		}
+	}
+	return false
+}
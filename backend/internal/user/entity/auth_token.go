@@ -0,0 +1,10 @@
+package entity
+
+// TokenPurpose — назначение одноразового токена из письма
+type TokenPurpose string
+
+const (
+	TokenEmailVerification TokenPurpose = "email_verification"
+	TokenPasswordReset     TokenPurpose = "password_reset"
+	TokenMagicLink         TokenPurpose = "magic_link"
+)
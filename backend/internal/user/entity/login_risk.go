@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Признаки риска входа (user_sessions.risk_reasons)
+const (
+	RiskNewDevice        = "new_device"
+	RiskNewCountry       = "new_country"
+	RiskImpossibleTravel = "impossible_travel"
+	RiskFailedAttempts   = "failed_attempts"
+	RiskIPFailedAttempts = "ip_failed_attempts"
+	// Вход подтверждён кодом после проверки
+	RiskStepUpVerified = "step_up_verified"
+)
+
+// Причины неудачных попыток входа (login_attempts.reason)
+const (
+	LoginFailurePassword = "password"
+	LoginFailureTelegram = "telegram"
+	LoginFailureStepUp   = "step_up_code"
+)
+
+// LoginAttempt — неудачная попытка входа
+type LoginAttempt struct {
+	ID         uuid.UUID
+	UserID     *uuid.UUID
+	Identifier string
+	IPAddress  string
+	Reason     string
+	CreatedAt  time.Time
+}
+
+// LoginHistory — что известно о прошлых входах пользователя
+type LoginHistory struct {
+	// Пользователь уже входил раньше; без истории новые устройство и страна не подозрительны
+	HasLogins    bool
+	KnownDevice  bool
+	KnownCountry bool
+	// Страна и время последней активности в самой свежей сессии
+	LastCountry  string
+	LastActiveAt time.Time
+}
+
+// LoginChallenge — вход с высоким риском, ожидающий подтверждения кодом
+type LoginChallenge struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	CodeHash    string
+	Channel     string
+	RiskScore   int
+	RiskReasons []string
+	DeviceID    string
+	Attempts    int
+	ExpiresAt   time.Time
+	ConsumedAt  *time.Time
+	CreatedAt   time.Time
+}
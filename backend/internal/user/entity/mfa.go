@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTP — подключённое приложение-аутентификатор
+type TOTP struct {
+	UserID uuid.UUID
+	Secret string
+	// nil — подключение не подтверждено кодом
+	EnabledAt      *time.Time
+	LastUsedStep   int64
+	FailedAttempts int
+	LockedUntil    *time.Time
+	CreatedAt      time.Time
+}
+
+// RecoveryCode — одноразовый код на случай потери аутентификатора; хранится только хэш
+type RecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
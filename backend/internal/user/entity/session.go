@@ -18,4 +18,33 @@ type UserSession struct {
 	CreatedAt    time.Time `json:"created_at" example:"2025-07-24T18:25:43.511Z"`
 	LastActiveAt time.Time `json:"last_active_at" example:"2025-07-25T10:15:00.000Z"`
 	ExpiresAt    time.Time `json:"expires_at" example:"2025-08-24T18:25:43.511Z"`
+	// Все ротации refresh-токена одного входа
+	FamilyID         uuid.UUID  `json:"family_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RefreshTokenHash string     `json:"-"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason     string     `json:"revoke_reason,omitempty" example:"logout"`
+	// Отпечаток устройства и оценка риска входа
+	DeviceID    string   `json:"device_id,omitempty"`
+	RiskScore   int      `json:"risk_score" example:"0"`
+	RiskReasons []string `json:"risk_reasons,omitempty"`
+	Suspicious  bool     `json:"suspicious" example:"false"`
+	// Когда пройден второй фактор (TOTP или код восстановления)
+	MFAVerifiedAt *time.Time `json:"mfa_verified_at,omitempty"`
+	// Администратор, открывший сессию от имени пользователя
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
+	// Сессия, с которой сделан текущий запрос; в БД не хранится
+	IsCurrent bool `json:"is_current" example:"true"`
 }
+
+// Причины отзыва сессии (user_sessions.revoke_reason)
+const (
+	RevokeReasonLogout        = "logout"
+	RevokeReasonLogoutOthers  = "logout_others"
+	RevokeReasonLogoutAll     = "logout_all"
+	RevokeReasonRotated       = "rotated"
+	RevokeReasonRefreshReuse  = "refresh_reuse"
+	RevokeReasonInactivity    = "inactivity"
+	RevokeReasonPasswordReset = "password_reset"
+	RevokeReasonAccountDelete = "account_deleted"
+	RevokeReasonBanned        = "banned"
+)
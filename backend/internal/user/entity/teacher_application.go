@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeacherApplicationStatus string
+
+const (
+	TeacherApplicationPending   TeacherApplicationStatus = "pending"
+	TeacherApplicationApproved  TeacherApplicationStatus = "approved"
+	TeacherApplicationRejected  TeacherApplicationStatus = "rejected"
+	TeacherApplicationWithdrawn TeacherApplicationStatus = "withdrawn"
+)
+
+func (s TeacherApplicationStatus) Valid() bool {
+	switch s {
+	case TeacherApplicationPending, TeacherApplicationApproved, TeacherApplicationRejected, TeacherApplicationWithdrawn:
+		return true
+	}
+	return false
+}
+
+// TeacherApplication — заявка студента на роль преподавателя. Одобрение
+// администратором меняет роль пользователя на teacher
+type TeacherApplication struct {
+	ID             uuid.UUID                `json:"id"`
+	UserID         uuid.UUID                `json:"user_id"`
+	Bio            string                   `json:"bio" example:"Backend developer, 8 years of Go"`
+	PortfolioLinks []string                 `json:"portfolio_links" example:"https://github.com/example"`
+	Status         TeacherApplicationStatus `json:"status" example:"pending"`
+	ReviewedBy     *uuid.UUID               `json:"reviewed_by,omitempty"`
+	// Комментарий администратора; при отказе обязателен и виден заявителю
+	ReviewComment *string    `json:"review_comment,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
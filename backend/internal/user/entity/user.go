@@ -16,6 +16,15 @@ const (
 	RoleAdmin       Role = "admin"
 )
 
+// OrgRole — роль участника внутри организации (не зависит от роли на платформе)
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleMember OrgRole = "member"
+)
+
 type User struct {
 	ID              uuid.UUID  `json:"id"`
 	VisitorID       *uuid.UUID `json:"visitor_id,omitempty"`
@@ -24,9 +33,51 @@ type User struct {
 	FullName        *string    `json:"full_name,omitempty"`
 	PhotoURL        *string    `json:"photo_url,omitempty"`
 	Email           *string    `json:"email,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
 	SubscribeToNews bool       `json:"subscribe_to_newsletter"`
 	Role            Role       `json:"role"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	// Блокировка администратором; BannedUntil nil — бессрочно
+	BannedAt    *time.Time `json:"banned_at,omitempty"`
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
+	BanReason   *string    `json:"ban_reason,omitempty"`
+	// Организация пользователя; nil — только общий каталог
+	OrgID   *uuid.UUID `json:"org_id,omitempty"`
+	OrgRole OrgRole    `json:"org_role,omitempty"`
+}
+
+// Roles — роли, которые может назначить администратор
+var Roles = []Role{RoleGuest, RoleStudent, RoleTeacher, RoleAdmin}
+
+func (r Role) Valid() bool {
+	for _, role := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// OrgRoles — роли, которые можно назначить участнику организации
+var OrgRoles = []OrgRole{OrgRoleOwner, OrgRoleAdmin, OrgRoleMember}
+
+func (r OrgRole) Valid() bool {
+	for _, role := range OrgRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManage — может ли участник с этой ролью управлять организацией и её участниками
+func (r OrgRole) CanManage() bool {
+	return r == OrgRoleOwner || r == OrgRoleAdmin
+}
+
+// IsBanned — действует ли блокировка в момент now
+func (u *User) IsBanned(now time.Time) bool {
+	return u.BannedAt != nil && (u.BannedUntil == nil || now.Before(*u.BannedUntil))
 }
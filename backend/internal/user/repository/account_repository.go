@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+type AccountRepository interface {
+	// UpdateProfile сохраняет редактируемые пользователем поля. Смена email
+	// сбрасывает его подтверждение
+	UpdateProfile(ctx context.Context, user *entity.User) error
+	// Export возвращает персональные данные по разделам; каждый раздел — JSON-массив
+	Export(ctx context.Context, userID uuid.UUID) (map[string]json.RawMessage, error)
+	// Anonymize удаляет персональные данные и помечает аккаунт удалённым.
+	// Возвращает visitor_id, по которому хранятся события в ClickHouse
+	Anonymize(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error)
+}
+
+type PostgresAccountRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAccountRepository(db *pgxpool.Pool) *PostgresAccountRepository {
+	return &PostgresAccountRepository{db: db}
+}
+
+// exportSection — один файл архива с данными. Запрос получает $1 = пользователь
+type exportSection struct {
+	key   string
+	query string
+}
+
+// Секреты (хэши паролей, токенов, кодов, TOTP) в выгрузку не попадают
+var exportSections = []exportSection{
+	{"profile", `SELECT id, username, first_name, last_name, photo_url, email, email_verified_at,
+		subscribe_to_newsletter, role, telegram_id, visitor_id, banned_at, banned_until, ban_reason,
+		created_at, updated_at
+		FROM users WHERE id = $1`},
+	{"organization", `SELECT o.id, o.slug, o.name, u.org_role, u.org_joined_at
+		FROM users u JOIN organizations o ON o.id = u.org_id WHERE u.id = $1`},
+	{"sessions", `SELECT id, user_agent, ip_address, country, city, device_id, risk_score, risk_reasons,
+		suspicious, mfa_verified_at, impersonator_id, created_at, last_active_at, expires_at, revoked_at, revoke_reason
+		FROM user_sessions WHERE user_id = $1 ORDER BY created_at`},
+	{"inactivity_timeout", `SELECT timeout_seconds FROM user_inactivity_timeout WHERE user_id = $1`},
+	{"devices", `SELECT device_id, user_agent, first_seen_at, last_seen_at
+		FROM user_devices WHERE user_id = $1 ORDER BY first_seen_at`},
+	{"login_countries", `SELECT country, first_seen_at, last_seen_at
+		FROM user_login_countries WHERE user_id = $1 ORDER BY first_seen_at`},
+	{"login_attempts", `SELECT identifier, ip_address, reason, created_at
+		FROM login_attempts WHERE user_id = $1 ORDER BY created_at`},
+	{"identities", `SELECT provider, subject, email, created_at, last_login_at
+		FROM user_identities WHERE user_id = $1`},
+	{"mfa", `SELECT enabled_at FROM user_totp WHERE user_id = $1 AND enabled_at IS NOT NULL`},
+	{"api_tokens", `SELECT id, name, prefix, scopes, mfa, expires_at, last_used_at, last_used_ip, created_at, revoked_at
+		FROM api_tokens WHERE user_id = $1 ORDER BY created_at`},
+	{"teacher_applications", `SELECT id, bio, portfolio_links, status, review_comment, reviewed_at, created_at, updated_at
+		FROM teacher_applications WHERE user_id = $1 ORDER BY created_at`},
+	{"study_groups", `SELECT g.id, g.name, m.joined_at
+		FROM study_group_members m JOIN study_groups g ON g.id = m.group_id WHERE m.user_id = $1 ORDER BY m.joined_at`},
+	{"enrollments", `SELECT * FROM course_enrollments WHERE user_id = $1`},
+	{"progress", `SELECT * FROM lesson_progress WHERE user_id = $1`},
+	{"activity_days", `SELECT day FROM learning_activity_days WHERE user_id = $1 ORDER BY day`},
+	{"notes", `SELECT id, lesson_id, kind, body, quote, anchor_start, anchor_end, color, created_at, updated_at
+		FROM lesson_notes WHERE user_id = $1 ORDER BY created_at`},
+	{"bookmark_folders", `SELECT * FROM bookmark_folders WHERE user_id = $1`},
+	{"bookmarks", `SELECT * FROM bookmarks WHERE user_id = $1`},
+	{"reviews", `SELECT * FROM course_reviews WHERE author_id = $1`},
+	{"comments", `SELECT id, lesson_id, parent_id, body, is_pinned, is_answer, edited_at, deleted_at, created_at
+		FROM lesson_comments WHERE author_id = $1 ORDER BY created_at`},
+	{"notification_preferences", `SELECT * FROM notification_preferences WHERE user_id = $1`},
+	{"notification_deliveries", `SELECT * FROM notification_deliveries WHERE user_id = $1 ORDER BY created_at`},
+	{"merges", `SELECT * FROM user_merges WHERE source_user_id = $1 OR target_user_id = $1`},
+}
+
+// Удаление аккаунта: личные данные удаляются, а то, что видели другие (курсы,
+// комментарии в обсуждениях), остаётся за обезличенной строкой users
+var anonymizeQueries = []string{
+	`DELETE FROM user_sessions WHERE user_id = $1`,
+	`DELETE FROM user_inactivity_timeout WHERE user_id = $1`,
+	`DELETE FROM user_devices WHERE user_id = $1`,
+	`DELETE FROM user_login_countries WHERE user_id = $1`,
+	`DELETE FROM login_attempts WHERE user_id = $1`,
+	`DELETE FROM login_challenges WHERE user_id = $1`,
+	`DELETE FROM user_recovery_codes WHERE user_id = $1`,
+	`DELETE FROM user_totp WHERE user_id = $1`,
+	`DELETE FROM auth_tokens WHERE user_id = $1`,
+	`DELETE FROM user_identities WHERE user_id = $1`,
+	`DELETE FROM api_tokens WHERE user_id = $1`,
+	`DELETE FROM teacher_applications WHERE user_id = $1`,
+	`DELETE FROM lesson_notes WHERE user_id = $1`,
+	`DELETE FROM bookmarks WHERE user_id = $1`,
+	`DELETE FROM bookmark_folders WHERE user_id = $1`,
+	`DELETE FROM lesson_progress WHERE user_id = $1`,
+	`DELETE FROM learning_activity_days WHERE user_id = $1`,
+	`DELETE FROM study_group_members WHERE user_id = $1`,
+	`DELETE FROM course_enrollments WHERE user_id = $1`,
+	`DELETE FROM notification_preferences WHERE user_id = $1`,
+	`DELETE FROM notification_deliveries WHERE user_id = $1`,
+	// Комментарии остаются в ветках обсуждений как удалённые, без текста и истории правок
+	`DELETE FROM lesson_comment_revisions
+	 WHERE comment_id IN (SELECT id FROM lesson_comments WHERE author_id = $1)`,
+	`UPDATE lesson_comments SET body = '', deleted_at = COALESCE(deleted_at, NOW()),
+		deleted_by = COALESCE(deleted_by, $1), updated_at = NOW()
+	 WHERE author_id = $1`,
+	`UPDATE user_merges SET source_snapshot = '{}' WHERE source_user_id = $1 OR target_user_id = $1`,
+}
+
+func (r *PostgresAccountRepository) UpdateProfile(ctx context.Context, user *entity.User) error {
+	var firstName, lastName string
+	if user.FullName != nil {
+		firstName, lastName, _ = strings.Cut(strings.Join(strings.Fields(*user.FullName), " "), " ")
+	}
+	err := r.db.QueryRow(ctx, `
+		UPDATE users SET
+			first_name = $2,
+			last_name = $3,
+			photo_url = $4,
+			subscribe_to_newsletter = $5,
+			email_verified_at = CASE WHEN lower(email) IS NOT DISTINCT FROM lower($6) THEN email_verified_at END,
+			email = $6,
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING email_verified_at, updated_at
+	`, user.ID, firstName, lastName, user.PhotoURL, user.SubscribeToNews, user.Email).
+		Scan(&user.EmailVerifiedAt, &user.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if isUniqueViolation(err) {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+func (r *PostgresAccountRepository) Export(ctx context.Context, userID uuid.UUID) (map[string]json.RawMessage, error) {
+	data := make(map[string]json.RawMessage, len(exportSections))
+	// Все разделы читаются из одного снимка базы
+	err := pgx.BeginTxFunc(ctx, r.db, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, func(tx pgx.Tx) error {
+		for _, section := range exportSections {
+			var raw []byte
+			err := tx.QueryRow(ctx,
+				`SELECT COALESCE(json_agg(t), '[]'::json) FROM (`+section.query+`) t`, userID).Scan(&raw)
+			if err != nil {
+				return err
+			}
+			data[section.key] = raw
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *PostgresAccountRepository) Anonymize(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error) {
+	var visitorID *uuid.UUID
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT visitor_id FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		`, userID).Scan(&visitorID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// Курсы, чьи отзывы удаляются, нужно пересчитать
+		rows, err := tx.Query(ctx, `DELETE FROM course_reviews WHERE author_id = $1 RETURNING course_id`, userID)
+		if err != nil {
+			return err
+		}
+		courseIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE courses c SET
+				rating_avg = COALESCE((SELECT ROUND(AVG(rating), 2) FROM course_reviews WHERE course_id = c.id AND status = 'approved'), 0),
+				rating_count = (SELECT COUNT(*) FROM course_reviews WHERE course_id = c.id AND status = 'approved')
+			WHERE c.id = ANY($1)
+		`, courseIDs); err != nil {
+			return err
+		}
+
+		for _, q := range anonymizeQueries {
+			if _, err := tx.Exec(ctx, q, userID); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE users SET
+				telegram_id = NULL,
+				first_name = '',
+				last_name = '',
+				username = NULL,
+				photo_url = NULL,
+				email = NULL,
+				email_verified_at = NULL,
+				password_hash = NULL,
+				visitor_id = NULL,
+				subscribe_to_newsletter = FALSE,
+				attributes = '{}',
+				org_id = NULL,
+				org_role = NULL,
+				org_joined_at = NULL,
+				deleted_at = NOW(),
+				updated_at = NOW()
+			WHERE id = $1
+		`, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return visitorID, nil
+}
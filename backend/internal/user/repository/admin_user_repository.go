@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+// Статусы для фильтра списка пользователей
+const (
+	UserStatusActive  = "active"
+	UserStatusBanned  = "banned"
+	UserStatusDeleted = "deleted"
+	UserStatusAll     = "all"
+)
+
+// UserFilter — поиск пользователей в админке
+type UserFilter struct {
+	// Подстрока email, username или имени; точное совпадение id или telegram_id
+	Query string
+	Role  entity.Role
+	// Пусто — все, кроме удалённых
+	Status string
+}
+
+// AuditFilter — выборка журнала по пользователю и/или администратору
+type AuditFilter struct {
+	TargetUserID *uuid.UUID
+	ActorID      *uuid.UUID
+}
+
+// AdminUserRepository — действия администратора над пользователями. Каждое
+// изменение записывается в журнал в той же транзакции: действие без записи
+// в журнале (или запись без действия) невозможно
+type AdminUserRepository interface {
+	Search(ctx context.Context, f UserFilter, pag pagination.Params) ([]*entity.User, int, error)
+	// SetRole меняет роль и возвращает прежнюю; она же попадает в entry.Details["from"]
+	SetRole(ctx context.Context, userID uuid.UUID, role entity.Role, entry *entity.AdminAuditEntry) (entity.Role, error)
+	// Ban блокирует пользователя и завершает его сессии; возвращает число завершённых
+	// семейств сессий, оно же попадает в entry.Details["revoked_sessions"]
+	Ban(ctx context.Context, userID, bannedBy uuid.UUID, reason string, until *time.Time, entry *entity.AdminAuditEntry) (int, error)
+	Unban(ctx context.Context, userID uuid.UUID, entry *entity.AdminAuditEntry) error
+	// RevokeSessions завершает все сессии пользователя; число — как в Ban
+	RevokeSessions(ctx context.Context, userID uuid.UUID, reason string, entry *entity.AdminAuditEntry) (int, error)
+	// StartImpersonation сохраняет сессию поддержки вместе с записью журнала
+	StartImpersonation(ctx context.Context, session *entity.UserSession, entry *entity.AdminAuditEntry) error
+	RecordAudit(ctx context.Context, entry *entity.AdminAuditEntry) error
+	ListAudit(ctx context.Context, f AuditFilter, pag pagination.Params) ([]*entity.AdminAuditEntry, int, error)
+}
+
+type PostgresAdminUserRepository struct {
+	db          *pgxpool.Pool
+	revocations RevocationCache
+}
+
+func NewPostgresAdminUserRepository(db *pgxpool.Pool, revocations RevocationCache) *PostgresAdminUserRepository {
+	return &PostgresAdminUserRepository{db: db, revocations: revocations}
+}
+
+const adminUserColumns = `id, visitor_id, telegram_id, COALESCE(first_name, ''), COALESCE(last_name, ''), username, photo_url,
+	created_at, updated_at, deleted_at, email, subscribe_to_newsletter, role, email_verified_at,
+	banned_at, banned_until, ban_reason, org_id, COALESCE(org_role, '')`
+
+var userSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"email":      "email",
+	"role":       "role",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// userFilterSQL строит условие WHERE; плейсхолдеры нумеруются с first
+func userFilterSQL(f UserFilter, first int) (string, []any) {
+	var conds []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", first+len(args)-1)
+	}
+
+	switch f.Status {
+	case UserStatusAll:
+	case UserStatusDeleted:
+		conds = append(conds, "deleted_at IS NOT NULL")
+	case UserStatusBanned:
+		conds = append(conds, "deleted_at IS NULL AND banned_at IS NOT NULL AND (banned_until IS NULL OR banned_until > NOW())")
+	case UserStatusActive:
+		conds = append(conds, "deleted_at IS NULL AND (banned_at IS NULL OR banned_until <= NOW())")
+	default:
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if f.Role != "" {
+		conds = append(conds, "role = "+arg(string(f.Role)))
+	}
+	if q := strings.TrimSpace(f.Query); q != "" {
+		like := arg("%" + likeEscaper.Replace(strings.ToLower(q)) + "%")
+		exact := arg(q)
+		conds = append(conds, `(lower(email) LIKE `+like+` OR lower(username) LIKE `+like+`
+			OR lower(COALESCE(first_name, '') || ' ' || COALESCE(last_name, '')) LIKE `+like+`
+			OR id::text = `+exact+` OR telegram_id::text = `+exact+`)`)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func (r *PostgresAdminUserRepository) Search(ctx context.Context, f UserFilter, pag pagination.Params) ([]*entity.User, int, error) {
+	if pag.SortBy == "" {
+		pag.SortBy = "created_at"
+	}
+	where, filterArgs := userFilterSQL(f, 3)
+	query, args := pagination.SQLWithPagination(`SELECT `+adminUserColumns+` FROM users`+where, pag, userSortFields)
+	args = append(args, filterArgs...)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	users := []*entity.User{}
+	for rows.Next() {
+		u := &entity.User{}
+		var firstName, lastName string
+		if err := rows.Scan(&u.ID, &u.VisitorID, &u.TelegramID, &firstName, &lastName, &u.Username, &u.PhotoURL,
+			&u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.Email, &u.SubscribeToNews, &u.Role, &u.EmailVerifiedAt,
+			&u.BannedAt, &u.BannedUntil, &u.BanReason, &u.OrgID, &u.OrgRole); err != nil {
+			return nil, 0, err
+		}
+		fullName := combineFullName(firstName, lastName)
+		u.FullName = &fullName
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	where, filterArgs = userFilterSQL(f, 1)
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, filterArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *PostgresAdminUserRepository) SetRole(ctx context.Context, userID uuid.UUID, role entity.Role, entry *entity.AdminAuditEntry) (entity.Role, error) {
+	var previous entity.Role
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE users u SET role = $2, updated_at = NOW()
+			FROM (SELECT id, role FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE) old
+			WHERE u.id = old.id
+			RETURNING old.role
+		`, userID, string(role)).Scan(&previous)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		setAuditDetail(entry, "from", previous)
+		return insertAudit(ctx, tx, entry)
+	})
+	return previous, err
+}
+
+func (r *PostgresAdminUserRepository) Ban(ctx context.Context, userID, bannedBy uuid.UUID, reason string, until *time.Time, entry *entity.AdminAuditEntry) (int, error) {
+	var families []uuid.UUID
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE users SET banned_at = NOW(), banned_until = $3, ban_reason = $4, banned_by = $2, updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL
+		`, userID, bannedBy, until, reason)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		families, err = revokeUserSessions(ctx, tx, userID, entity.RevokeReasonBanned)
+		if err != nil {
+			return err
+		}
+		setAuditDetail(entry, "revoked_sessions", len(families))
+		return insertAudit(ctx, tx, entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.markRevoked(ctx, families)
+	return len(families), nil
+}
+
+func (r *PostgresAdminUserRepository) Unban(ctx context.Context, userID uuid.UUID, entry *entity.AdminAuditEntry) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE users SET banned_at = NULL, banned_until = NULL, ban_reason = NULL, banned_by = NULL, updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL
+		`, userID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return insertAudit(ctx, tx, entry)
+	})
+}
+
+func (r *PostgresAdminUserRepository) RevokeSessions(ctx context.Context, userID uuid.UUID, reason string, entry *entity.AdminAuditEntry) (int, error) {
+	var families []uuid.UUID
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		families, err = revokeUserSessions(ctx, tx, userID, reason)
+		if err != nil {
+			return err
+		}
+		setAuditDetail(entry, "revoked_sessions", len(families))
+		return insertAudit(ctx, tx, entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.markRevoked(ctx, families)
+	return len(families), nil
+}
+
+func (r *PostgresAdminUserRepository) StartImpersonation(ctx context.Context, session *entity.UserSession, entry *entity.AdminAuditEntry) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := insertSession(ctx, tx, session); err != nil {
+			return err
+		}
+		return insertAudit(ctx, tx, entry)
+	})
+}
+
+func (r *PostgresAdminUserRepository) RecordAudit(ctx context.Context, e *entity.AdminAuditEntry) error {
+	return insertAudit(ctx, r.db, e)
+}
+
+// markRevoked сообщает другим инстансам об отзыве после коммита. Ошибка только
+// логируется: отзыв уже в БД, кэши сессий увидят его не позже sessionCacheTTL
+func (r *PostgresAdminUserRepository) markRevoked(ctx context.Context, families []uuid.UUID) {
+	if len(families) == 0 || r.revocations == nil {
+		return
+	}
+	if err := r.revocations.MarkRevoked(ctx, families, revocationTTL); err != nil {
+		logger.Error("Не удалось записать отзыв сессий в кэш", err)
+	}
+}
+
+func revokeUserSessions(ctx context.Context, tx pgx.Tx, userID uuid.UUID, reason string) ([]uuid.UUID, error) {
+	return collectFamilies(tx.Query(ctx, `
+		UPDATE user_sessions SET revoked_at = NOW(), is_current = FALSE, revoke_reason = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING family_id
+	`, userID, reason))
+}
+
+func setAuditDetail(e *entity.AdminAuditEntry, key string, value any) {
+	if e.Details == nil {
+		e.Details = map[string]any{}
+	}
+	e.Details[key] = value
+}
+
+// auditQuerier — пул или транзакция
+type auditQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertAudit(ctx context.Context, q auditQuerier, e *entity.AdminAuditEntry) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.Details == nil {
+		e.Details = map[string]any{}
+	}
+	details, err := json.Marshal(e.Details)
+	if err != nil {
+		return err
+	}
+	return q.QueryRow(ctx, `
+		INSERT INTO admin_audit_log (id, actor_id, action, target_user_id, details, ip_address)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, e.ID, e.ActorID, e.Action, e.TargetUserID, details, e.IPAddress).Scan(&e.CreatedAt)
+}
+
+func (r *PostgresAdminUserRepository) ListAudit(ctx context.Context, f AuditFilter, pag pagination.Params) ([]*entity.AdminAuditEntry, int, error) {
+	// Журнал всегда от новых записей к старым
+	where := ` WHERE ($1::uuid IS NULL OR target_user_id = $1) AND ($2::uuid IS NULL OR actor_id = $2)`
+	rows, err := r.db.Query(ctx, `
+		SELECT id, actor_id, action, target_user_id, details, ip_address, created_at
+		FROM admin_audit_log`+where+`
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`, f.TargetUserID, f.ActorID, pag.Limit, pag.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	entries := []*entity.AdminAuditEntry{}
+	for rows.Next() {
+		e := &entity.AdminAuditEntry{}
+		var details []byte
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.TargetUserID, &details, &e.IPAddress, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal(details, &e.Details); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM admin_audit_log`+where, f.TargetUserID, f.ActorID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var ErrAPITokenNotFound = errors.New("api token not found")
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *entity.APIToken) error
+	// ListByUserID возвращает неотозванные токены, включая истёкшие
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.APIToken, error)
+	CountActive(ctx context.Context, userID uuid.UUID) (int, error)
+	// FindByHash ищет неотозванный токен
+	FindByHash(ctx context.Context, hash string) (*entity.APIToken, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, ip string, at time.Time) error
+}
+
+type PostgresAPITokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAPITokenRepository(db *pgxpool.Pool) *PostgresAPITokenRepository {
+	return &PostgresAPITokenRepository{db: db}
+}
+
+const apiTokenColumns = `id, user_id, name, prefix, token_hash, scopes, mfa, expires_at, last_used_at,
+	COALESCE(last_used_ip, ''), created_at, revoked_at`
+
+func scanAPIToken(row pgx.Row) (*entity.APIToken, error) {
+	t := &entity.APIToken{}
+	err := row.Scan(
+		&t.ID, &t.UserID, &t.Name, &t.Prefix, &t.TokenHash, &t.Scopes, &t.MFA, &t.ExpiresAt, &t.LastUsedAt,
+		&t.LastUsedIP, &t.CreatedAt, &t.RevokedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPITokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *PostgresAPITokenRepository) Create(ctx context.Context, t *entity.APIToken) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO api_tokens (id, user_id, name, prefix, token_hash, scopes, mfa, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, t.ID, t.UserID, t.Name, t.Prefix, t.TokenHash, t.Scopes, t.MFA, t.ExpiresAt, t.CreatedAt)
+	return err
+}
+
+func (r *PostgresAPITokenRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.APIToken, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+apiTokenColumns+` FROM api_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []*entity.APIToken{}
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *PostgresAPITokenRepository) CountActive(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM api_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresAPITokenRepository) FindByHash(ctx context.Context, hash string) (*entity.APIToken, error) {
+	return scanAPIToken(r.db.QueryRow(ctx, `
+		SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = $1 AND revoked_at IS NULL
+	`, hash))
+}
+
+func (r *PostgresAPITokenRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE api_tokens SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+func (r *PostgresAPITokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE api_tokens SET last_used_at = $2, last_used_ip = $3 WHERE id = $1
+	`, id, at, ip)
+	return err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var ErrTokenInvalid = errors.New("token is invalid or expired")
+
+type AuthTokenRepository interface {
+	// Create сохраняет хеш нового токена и гасит прежние неиспользованные
+	// токены того же назначения, чтобы действовала только последняя ссылка
+	Create(ctx context.Context, userID uuid.UUID, purpose entity.TokenPurpose, tokenHash string, expiresAt time.Time) error
+	// Consume атомарно помечает токен использованным и возвращает владельца
+	Consume(ctx context.Context, purpose entity.TokenPurpose, tokenHash string) (uuid.UUID, error)
+}
+
+type PostgresAuthTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAuthTokenRepository(db *pgxpool.Pool) *PostgresAuthTokenRepository {
+	return &PostgresAuthTokenRepository{db: db}
+}
+
+func (r *PostgresAuthTokenRepository) Create(ctx context.Context, userID uuid.UUID, purpose entity.TokenPurpose, tokenHash string, expiresAt time.Time) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE auth_tokens SET used_at = NOW()
+			WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+		`, userID, purpose)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO auth_tokens (user_id, purpose, token_hash, expires_at)
+			VALUES ($1, $2, $3, $4)
+		`, userID, purpose, tokenHash, expiresAt)
+		return err
+	})
+}
+
+func (r *PostgresAuthTokenRepository) Consume(ctx context.Context, purpose entity.TokenPurpose, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.QueryRow(ctx, `
+		UPDATE auth_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`, tokenHash, purpose).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrTokenInvalid
+	}
+	return userID, err
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+const (
+	// Сколько сессия живёт в локальном кэше инстанса. Отзыв виден сразу через
+	// RevocationCache; без Redis другие инстансы увидят его не позже чем через это время
+	sessionCacheTTL = 30 * time.Second
+	// Записи об отзыве должны пережить локальные кэши всех инстансов
+	revocationTTL = 5 * time.Minute
+	// last_active_at пишется в БД не чаще этого интервала
+	lastActiveWriteInterval = time.Minute
+)
+
+type cachedSession struct {
+	session   entity.UserSession
+	expiresAt time.Time
+	// Когда last_active_at последний раз записан в БД
+	writtenAt time.Time
+}
+
+// CachedSessionRepository избавляет JWTMiddleware от запросов в Postgres на каждый
+// запрос: действующие сессии кэшируются в памяти, отзывы записываются в RevocationCache
+type CachedSessionRepository struct {
+	SessionRepository
+	revocations RevocationCache
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*cachedSession
+	sweepAt  time.Time
+}
+
+func NewCachedSessionRepository(postgres *PostgresSessionRepository, revocations RevocationCache) *CachedSessionRepository {
+	return &CachedSessionRepository{
+		SessionRepository: postgres,
+		revocations:       revocations,
+		sessions:          make(map[uuid.UUID]*cachedSession),
+	}
+}
+
+func (r *CachedSessionRepository) FindByID(ctx context.Context, sessionID uuid.UUID) (*entity.UserSession, error) {
+	now := time.Now()
+	r.mu.Lock()
+	cached, ok := r.sessions[sessionID]
+	var copied entity.UserSession
+	if ok {
+		copied = cached.session
+		ok = now.Before(cached.expiresAt)
+	}
+	r.mu.Unlock()
+
+	if ok {
+		revoked, err := r.revocations.AnyRevoked(ctx, sessionID, copied.FamilyID)
+		if err == nil {
+			if revoked {
+				r.forget(sessionID)
+				return nil, ErrSessionNotFound
+			}
+			return &copied, nil
+		}
+		// Кэш отзывов недоступен — проверяем по БД
+		logger.Error("Кэш отзыва сессий недоступен", err)
+	}
+
+	session, err := r.SessionRepository.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	r.remember(session, now)
+	return session, nil
+}
+
+// UpdateLastActive сразу обновляет LastActiveAt в кэше — по нему CheckInactivity
+// проверяет следующий запрос, — а в БД пишет не чаще lastActiveWriteInterval
+func (r *CachedSessionRepository) UpdateLastActive(ctx context.Context, sessionID uuid.UUID) error {
+	now := time.Now()
+	r.mu.Lock()
+	cached, ok := r.sessions[sessionID]
+	if ok {
+		cached.session.LastActiveAt = now
+		if now.Sub(cached.writtenAt) < lastActiveWriteInterval {
+			r.mu.Unlock()
+			return nil
+		}
+	}
+	r.mu.Unlock()
+
+	if err := r.SessionRepository.UpdateLastActive(ctx, sessionID); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	if cached, ok := r.sessions[sessionID]; ok {
+		cached.writtenAt = now
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *CachedSessionRepository) Revoke(ctx context.Context, userID, sessionID uuid.UUID, reason string) (uuid.UUID, error) {
+	familyID, err := r.SessionRepository.Revoke(ctx, userID, sessionID, reason)
+	if err != nil {
+		return familyID, err
+	}
+	r.markRevoked(ctx, familyID)
+	return familyID, nil
+}
+
+func (r *CachedSessionRepository) RevokeByUserID(ctx context.Context, userID, exceptFamilyID uuid.UUID, reason string) ([]uuid.UUID, error) {
+	families, err := r.SessionRepository.RevokeByUserID(ctx, userID, exceptFamilyID, reason)
+	if err != nil {
+		return nil, err
+	}
+	r.markRevoked(ctx, families...)
+	return families, nil
+}
+
+func (r *CachedSessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, reason string) error {
+	if err := r.SessionRepository.RevokeFamily(ctx, familyID, reason); err != nil {
+		return err
+	}
+	r.markRevoked(ctx, familyID)
+	return nil
+}
+
+func (r *CachedSessionRepository) RevokeInactiveSessions(ctx context.Context) ([]uuid.UUID, error) {
+	families, err := r.SessionRepository.RevokeInactiveSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.markRevoked(ctx, families...)
+	return families, nil
+}
+
+// Rotate отзывает только старую строку: семейство продолжает жить в новой сессии
+func (r *CachedSessionRepository) Rotate(ctx context.Context, oldID uuid.UUID, next *entity.UserSession) error {
+	if err := r.SessionRepository.Rotate(ctx, oldID, next); err != nil {
+		return err
+	}
+	r.markRevoked(ctx, oldID)
+	return nil
+}
+
+// SetMFAVerified сбрасывает локальный кэш сессий пользователя; другие инстансы
+// увидят изменение не позже чем через sessionCacheTTL
+func (r *CachedSessionRepository) SetMFAVerified(ctx context.Context, userID, familyID uuid.UUID, at *time.Time) error {
+	if err := r.SessionRepository.SetMFAVerified(ctx, userID, familyID, at); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	for id, c := range r.sessions {
+		if c.session.UserID == userID {
+			delete(r.sessions, id)
+		}
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// markRevoked не возвращает ошибку: отзыв уже записан в БД, без кэша он
+// просто дойдёт до других инстансов с задержкой до sessionCacheTTL
+func (r *CachedSessionRepository) markRevoked(ctx context.Context, ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	if err := r.revocations.MarkRevoked(ctx, ids, revocationTTL); err != nil {
+		logger.Error("Не удалось записать отзыв сессии в кэш", err)
+	}
+}
+
+func (r *CachedSessionRepository) remember(session *entity.UserSession, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Просроченные записи вычищаем не чаще раза в минуту
+	if now.After(r.sweepAt) {
+		for id, c := range r.sessions {
+			if now.After(c.expiresAt) {
+				delete(r.sessions, id)
+			}
+		}
+		r.sweepAt = now.Add(time.Minute)
+	}
+	r.sessions[session.ID] = &cachedSession{
+		session:   *session,
+		expiresAt: now.Add(sessionCacheTTL),
+		writtenAt: session.LastActiveAt,
+	}
+}
+
+func (r *CachedSessionRepository) forget(sessionID uuid.UUID) {
+	r.mu.Lock()
+	delete(r.sessions, sessionID)
+	r.mu.Unlock()
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var (
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrIdentityExists   = errors.New("identity already linked")
+)
+
+type IdentityRepository interface {
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.Identity, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Identity, error)
+	Create(ctx context.Context, identity *entity.Identity) error
+	// CreateWithUser создаёт пользователя и его первый внешний аккаунт в одной транзакции
+	CreateWithUser(ctx context.Context, user *entity.User, identity *entity.Identity) error
+	TouchLogin(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID, provider string) error
+}
+
+type PostgresIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresIdentityRepository(db *pgxpool.Pool) *PostgresIdentityRepository {
+	return &PostgresIdentityRepository{db: db}
+}
+
+const identityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func scanIdentity(row pgx.Row) (*entity.Identity, error) {
+	i := &entity.Identity{}
+	err := row.Scan(&i.ID, &i.UserID, &i.Provider, &i.Subject, &i.Email, &i.CreatedAt, &i.LastLoginAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrIdentityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *PostgresIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.Identity, error) {
+	return scanIdentity(r.db.QueryRow(ctx, `
+		SELECT `+identityColumns+` FROM user_identities WHERE provider = $1 AND subject = $2
+	`, provider, subject))
+}
+
+func (r *PostgresIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Identity, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+identityColumns+` FROM user_identities WHERE user_id = $1 ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*entity.Identity{}
+	for rows.Next() {
+		i, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (r *PostgresIdentityRepository) Create(ctx context.Context, identity *entity.Identity) error {
+	return insertIdentity(ctx, r.db, identity)
+}
+
+func (r *PostgresIdentityRepository) CreateWithUser(ctx context.Context, user *entity.User, identity *entity.Identity) error {
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	var firstName, lastName string
+	if user.FullName != nil {
+		names := splitFullName(*user.FullName)
+		if len(names) > 0 {
+			firstName = names[0]
+		}
+		if len(names) > 1 {
+			lastName = names[1]
+		}
+	}
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO users (id, email, email_verified_at, first_name, last_name, photo_url, role, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, user.ID, user.Email, user.EmailVerifiedAt, firstName, lastName, user.PhotoURL,
+			string(user.Role), user.CreatedAt, user.UpdatedAt)
+		if isUniqueViolation(err) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return insertIdentity(ctx, tx, identity)
+	})
+}
+
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertIdentity(ctx context.Context, q rowQuerier, identity *entity.Identity) error {
+	if identity.ID == uuid.Nil {
+		identity.ID = uuid.New()
+	}
+	err := q.QueryRow(ctx, `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.LastLoginAt).
+		Scan(&identity.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrIdentityExists
+	}
+	return err
+}
+
+func (r *PostgresIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE user_identities SET last_login_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+func (r *PostgresIdentityRepository) Delete(ctx context.Context, userID uuid.UUID, provider string) error {
+	cmdTag, err := r.db.Exec(ctx, `
+		DELETE FROM user_identities WHERE user_id = $1 AND provider = $2
+	`, userID, provider)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
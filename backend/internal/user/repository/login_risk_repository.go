@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var ErrChallengeNotFound = errors.New("login challenge not found")
+
+// LoginRiskRepository — история входов для оценки риска и подтверждения входа кодом
+type LoginRiskRepository interface {
+	RecordFailure(ctx context.Context, attempt *entity.LoginAttempt) error
+	// CountFailures считает неудачные попытки с since: по пользователю и по IP
+	CountFailures(ctx context.Context, userID uuid.UUID, ip string, since time.Time) (byUser, byIP int, err error)
+	History(ctx context.Context, userID uuid.UUID, deviceID, country string) (*entity.LoginHistory, error)
+	// RememberLogin запоминает устройство и страну успешного входа
+	RememberLogin(ctx context.Context, userID uuid.UUID, deviceID, userAgent, country string, at time.Time) error
+
+	CreateChallenge(ctx context.Context, ch *entity.LoginChallenge) error
+	// GetChallenge возвращает неиспользованный и не истёкший запрос подтверждения
+	GetChallenge(ctx context.Context, id uuid.UUID) (*entity.LoginChallenge, error)
+	// UseChallengeAttempt атомарно засчитывает попытку ввода кода, пока их меньше
+	// maxAttempts, и возвращает их новое число. ErrChallengeNotFound — попытки
+	// исчерпаны или запрос уже использован либо истёк
+	UseChallengeAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) (int, error)
+	// ConsumeChallenge помечает запрос использованным; ErrChallengeNotFound, если это уже сделал параллельный запрос
+	ConsumeChallenge(ctx context.Context, id uuid.UUID) error
+}
+
+type PostgresLoginRiskRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresLoginRiskRepository(db *pgxpool.Pool) *PostgresLoginRiskRepository {
+	return &PostgresLoginRiskRepository{db: db}
+}
+
+func (r *PostgresLoginRiskRepository) RecordFailure(ctx context.Context, a *entity.LoginAttempt) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO login_attempts (id, user_id, identifier, ip_address, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, a.ID, a.UserID, a.Identifier, a.IPAddress, a.Reason, a.CreatedAt)
+	return err
+}
+
+func (r *PostgresLoginRiskRepository) CountFailures(ctx context.Context, userID uuid.UUID, ip string, since time.Time) (int, int, error) {
+	var byUser, byIP int
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE user_id = $1),
+			COUNT(*) FILTER (WHERE ip_address = $2)
+		FROM login_attempts
+		WHERE created_at >= $3 AND (user_id = $1 OR ip_address = $2)
+	`, userID, ip, since).Scan(&byUser, &byIP)
+	return byUser, byIP, err
+}
+
+func (r *PostgresLoginRiskRepository) History(ctx context.Context, userID uuid.UUID, deviceID, country string) (*entity.LoginHistory, error) {
+	h := &entity.LoginHistory{}
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM user_devices WHERE user_id = $1),
+			EXISTS (SELECT 1 FROM user_devices WHERE user_id = $1 AND device_id = $2),
+			EXISTS (SELECT 1 FROM user_login_countries WHERE user_id = $1 AND country = $3)
+	`, userID, deviceID, country).Scan(&h.HasLogins, &h.KnownDevice, &h.KnownCountry)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, `
+		SELECT country, last_active_at FROM user_sessions
+		WHERE user_id = $1 AND country <> '' AND country <> 'Unknown'
+		ORDER BY last_active_at DESC
+		LIMIT 1
+	`, userID).Scan(&h.LastCountry, &h.LastActiveAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (r *PostgresLoginRiskRepository) RememberLogin(ctx context.Context, userID uuid.UUID, deviceID, userAgent, country string, at time.Time) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO user_devices (user_id, device_id, user_agent, first_seen_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (user_id, device_id) DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at, user_agent = EXCLUDED.user_agent
+		`, userID, deviceID, userAgent, at); err != nil {
+			return err
+		}
+		if country == "" || country == "Unknown" {
+			return nil
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO user_login_countries (user_id, country, first_seen_at, last_seen_at)
+			VALUES ($1, $2, $3, $3)
+			ON CONFLICT (user_id, country) DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at
+		`, userID, country, at)
+		return err
+	})
+}
+
+func (r *PostgresLoginRiskRepository) CreateChallenge(ctx context.Context, ch *entity.LoginChallenge) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO login_challenges (id, user_id, code_hash, channel, risk_score, risk_reasons, device_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, ch.ID, ch.UserID, ch.CodeHash, ch.Channel, ch.RiskScore, ch.RiskReasons, ch.DeviceID, ch.ExpiresAt, ch.CreatedAt)
+	return err
+}
+
+func (r *PostgresLoginRiskRepository) GetChallenge(ctx context.Context, id uuid.UUID) (*entity.LoginChallenge, error) {
+	ch := &entity.LoginChallenge{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, user_id, code_hash, channel, risk_score, risk_reasons, device_id, attempts, expires_at, consumed_at, created_at
+		FROM login_challenges
+		WHERE id = $1 AND consumed_at IS NULL AND expires_at > NOW()
+	`, id).Scan(
+		&ch.ID, &ch.UserID, &ch.CodeHash, &ch.Channel, &ch.RiskScore, &ch.RiskReasons, &ch.DeviceID,
+		&ch.Attempts, &ch.ExpiresAt, &ch.ConsumedAt, &ch.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (r *PostgresLoginRiskRepository) UseChallengeAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) (int, error) {
+	var attempts int
+	err := r.db.QueryRow(ctx, `
+		UPDATE login_challenges SET attempts = attempts + 1
+		WHERE id = $1 AND attempts < $2 AND consumed_at IS NULL AND expires_at > NOW()
+		RETURNING attempts
+	`, id, maxAttempts).Scan(&attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrChallengeNotFound
+	}
+	return attempts, err
+}
+
+func (r *PostgresLoginRiskRepository) ConsumeChallenge(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE login_challenges SET consumed_at = NOW()
+		WHERE id = $1 AND consumed_at IS NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrChallengeNotFound
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+type MergeRepository interface {
+	// Merge переносит все данные источника в целевой аккаунт и помечает источник
+	// удалённым в одной транзакции. Заполняет ID, SourceSnapshot, Moved и CreatedAt
+	Merge(ctx context.Context, merge *entity.AccountMerge) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.AccountMerge, error)
+}
+
+type PostgresMergeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresMergeRepository(db *pgxpool.Pool) *PostgresMergeRepository {
+	return &PostgresMergeRepository{db: db}
+}
+
+// mergeStep — перенос одного вида данных. Запросы получают $1 = источник, $2 = цель;
+// в Moved попадает число строк, затронутых первым запросом шага
+type mergeStep struct {
+	key     string
+	queries []string
+}
+
+// Где у цели уже есть такая же запись (уникальный ключ), оставляем запись цели,
+// а дубль источника удаляем
+var mergeSteps = []mergeStep{
+	{"sessions", []string{
+		`UPDATE user_sessions SET user_id = $2 WHERE user_id = $1`,
+	}},
+	{"inactivity_timeout", []string{
+		`UPDATE user_inactivity_timeout SET user_id = $2
+		 WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM user_inactivity_timeout WHERE user_id = $2)`,
+		`DELETE FROM user_inactivity_timeout WHERE user_id = $1`,
+	}},
+	{"login_devices", []string{
+		`INSERT INTO user_devices (user_id, device_id, user_agent, first_seen_at, last_seen_at)
+		 SELECT $2, device_id, user_agent, first_seen_at, last_seen_at
+		 FROM user_devices WHERE user_id = $1
+		 ON CONFLICT (user_id, device_id) DO UPDATE SET
+			first_seen_at = LEAST(user_devices.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at = GREATEST(user_devices.last_seen_at, EXCLUDED.last_seen_at)`,
+		`DELETE FROM user_devices WHERE user_id = $1`,
+		`INSERT INTO user_login_countries (user_id, country, first_seen_at, last_seen_at)
+		 SELECT $2, country, first_seen_at, last_seen_at
+		 FROM user_login_countries WHERE user_id = $1
+		 ON CONFLICT (user_id, country) DO UPDATE SET
+			first_seen_at = LEAST(user_login_countries.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at = GREATEST(user_login_countries.last_seen_at, EXCLUDED.last_seen_at)`,
+		`DELETE FROM user_login_countries WHERE user_id = $1`,
+	}},
+	{"login_attempts", []string{
+		`UPDATE login_attempts SET user_id = $2 WHERE user_id = $1`,
+		`DELETE FROM login_challenges WHERE user_id = $1`,
+	}},
+	{"mfa", []string{
+		// 2FA переносится целиком, только если у цели её нет
+		`UPDATE user_recovery_codes SET user_id = $2
+		 WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM user_totp WHERE user_id = $2)`,
+		`UPDATE user_totp SET user_id = $2
+		 WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM user_totp WHERE user_id = $2)`,
+		`DELETE FROM user_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_totp WHERE user_id = $1`,
+	}},
+	{"identities", []string{
+		`UPDATE user_identities i SET user_id = $2
+		 WHERE i.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM user_identities x WHERE x.user_id = $2 AND x.provider = i.provider)`,
+		`DELETE FROM user_identities WHERE user_id = $1`,
+	}},
+	{"auth_tokens", []string{
+		`DELETE FROM auth_tokens WHERE user_id = $1`,
+	}},
+	// API-токены не переносятся: их scopes выдавались под права исходного аккаунта
+	{"api_tokens", []string{
+		`DELETE FROM api_tokens WHERE user_id = $1`,
+	}},
+	{"enrollments", []string{
+		`UPDATE course_enrollments e SET user_id = $2
+		 WHERE e.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM course_enrollments x WHERE x.user_id = $2 AND x.course_id = e.course_id)`,
+		`DELETE FROM course_enrollments WHERE user_id = $1`,
+	}},
+	{"progress", []string{
+		// Прогресс объединяем: последний просмотр — самый поздний, завершение — самое раннее
+		`INSERT INTO lesson_progress (user_id, lesson_id, course_id, last_viewed_at, completed_at, created_at)
+		 SELECT $2, lesson_id, course_id, last_viewed_at, completed_at, created_at
+		 FROM lesson_progress WHERE user_id = $1
+		 ON CONFLICT (user_id, lesson_id) DO UPDATE SET
+			last_viewed_at = GREATEST(lesson_progress.last_viewed_at, EXCLUDED.last_viewed_at),
+			completed_at = LEAST(lesson_progress.completed_at, EXCLUDED.completed_at),
+			created_at = LEAST(lesson_progress.created_at, EXCLUDED.created_at)`,
+		`DELETE FROM lesson_progress WHERE user_id = $1`,
+	}},
+	{"activity_days", []string{
+		`INSERT INTO learning_activity_days (user_id, day)
+		 SELECT $2, day FROM learning_activity_days WHERE user_id = $1
+		 ON CONFLICT DO NOTHING`,
+		`DELETE FROM learning_activity_days WHERE user_id = $1`,
+	}},
+	{"bookmark_folders", []string{
+		`UPDATE bookmark_folders f SET user_id = $2
+		 WHERE f.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM bookmark_folders x WHERE x.user_id = $2 AND x.name = f.name)`,
+		// Закладки из одноимённой папки источника переезжают в папку цели
+		`UPDATE bookmarks b SET folder_id = tf.id
+		 FROM bookmark_folders sf
+		 JOIN bookmark_folders tf ON tf.user_id = $2 AND tf.name = sf.name
+		 WHERE sf.user_id = $1 AND b.folder_id = sf.id`,
+		`DELETE FROM bookmark_folders WHERE user_id = $1`,
+	}},
+	{"bookmarks", []string{
+		`UPDATE bookmarks b SET user_id = $2
+		 WHERE b.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM bookmarks x WHERE x.user_id = $2 AND x.target_type = b.target_type AND x.target_id = b.target_id)`,
+		`DELETE FROM bookmarks WHERE user_id = $1`,
+	}},
+	{"notes", []string{
+		`UPDATE lesson_notes SET user_id = $2 WHERE user_id = $1`,
+	}},
+	{"reviews", []string{
+		`UPDATE course_reviews r SET author_id = $2
+		 WHERE r.author_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM course_reviews x WHERE x.author_id = $2 AND x.course_id = r.course_id)`,
+		`DELETE FROM course_reviews WHERE author_id = $1`,
+		`UPDATE course_reviews SET moderated_by = $2 WHERE moderated_by = $1`,
+	}},
+	{"comments", []string{
+		`UPDATE lesson_comments SET author_id = $2 WHERE author_id = $1`,
+		`UPDATE lesson_comments SET deleted_by = $2 WHERE deleted_by = $1`,
+		`UPDATE lesson_comment_revisions SET edited_by = $2 WHERE edited_by = $1`,
+	}},
+	{"notification_preferences", []string{
+		`UPDATE notification_preferences SET user_id = $2
+		 WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM notification_preferences WHERE user_id = $2)`,
+		`DELETE FROM notification_preferences WHERE user_id = $1`,
+	}},
+	{"notification_deliveries", []string{
+		`UPDATE notification_deliveries d SET user_id = $2
+		 WHERE d.user_id = $1 AND NOT (d.status = 'sent' AND d.dedup_key <> '' AND EXISTS (
+			SELECT 1 FROM notification_deliveries x
+			WHERE x.user_id = $2 AND x.kind = d.kind AND x.dedup_key = d.dedup_key AND x.status = 'sent'))`,
+		`DELETE FROM notification_deliveries WHERE user_id = $1`,
+	}},
+	// На рассмотрении может быть только одна заявка: остаётся заявка цели
+	{"teacher_applications", []string{
+		`UPDATE teacher_applications a SET user_id = $2
+		 WHERE a.user_id = $1 AND NOT (a.status = 'pending' AND EXISTS (
+			SELECT 1 FROM teacher_applications x WHERE x.user_id = $2 AND x.status = 'pending'))`,
+		`DELETE FROM teacher_applications WHERE user_id = $1`,
+		`UPDATE teacher_applications SET reviewed_by = $2 WHERE reviewed_by = $1`,
+	}},
+	// Членство в организации переходит к цели, только если цель ни в одной не состоит
+	{"organization", []string{
+		`UPDATE users t SET org_id = s.org_id, org_role = s.org_role, org_joined_at = s.org_joined_at
+		 FROM users s
+		 WHERE t.id = $2 AND s.id = $1 AND s.org_id IS NOT NULL AND t.org_id IS NULL`,
+		`UPDATE users SET org_id = NULL, org_role = NULL, org_joined_at = NULL WHERE id = $1`,
+		`UPDATE organizations SET created_by = $2 WHERE created_by = $1`,
+	}},
+	{"study_groups", []string{
+		`UPDATE study_group_members m SET user_id = $2
+		 WHERE m.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM study_group_members x WHERE x.user_id = $2 AND x.group_id = m.group_id)`,
+		`DELETE FROM study_group_members WHERE user_id = $1`,
+		`UPDATE study_groups SET author_id = $2 WHERE author_id = $1`,
+		`UPDATE study_group_courses SET assigned_by = $2 WHERE assigned_by = $1`,
+	}},
+	{"courses", []string{`UPDATE courses SET author_id = $2 WHERE author_id = $1`}},
+	{"modules", []string{`UPDATE modules SET author_id = $2 WHERE author_id = $1`}},
+	{"lessons", []string{`UPDATE lessons SET author_id = $2 WHERE author_id = $1`}},
+	{"categories", []string{
+		`UPDATE categories SET author_id = $2 WHERE author_id = $1`,
+		`UPDATE category_assignments SET author_id = $2 WHERE author_id = $1`,
+	}},
+	{"tags", []string{
+		`UPDATE tags SET author_id = $2 WHERE author_id = $1`,
+		`UPDATE tag_assignments SET author_id = $2 WHERE author_id = $1`,
+	}},
+	{"abac_policies", []string{
+		`UPDATE abac_policies SET created_by = $2 WHERE created_by = $1`,
+		`UPDATE abac_policies SET updated_by = $2 WHERE updated_by = $1`,
+	}},
+}
+
+// Чем выше ранг, тем больше прав; после слияния у цели остаётся старшая роль
+var roleRank = map[entity.Role]int{
+	entity.RoleUnspecified: 0,
+	entity.RoleGuest:       1,
+	entity.RoleStudent:     2,
+	entity.RoleTeacher:     3,
+	entity.RoleAdmin:       4,
+}
+
+type mergeUser struct {
+	role            entity.Role
+	telegramID      *int64
+	email           *string
+	emailVerifiedAt *time.Time
+	passwordHash    *string
+	visitorID       *uuid.UUID
+	username        *string
+	photoURL        *string
+	firstName       *string
+	lastName        *string
+}
+
+func lockMergeUser(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*mergeUser, error) {
+	u := &mergeUser{}
+	err := tx.QueryRow(ctx, `
+		SELECT role, telegram_id, email, email_verified_at, password_hash, visitor_id,
+		       username, photo_url, first_name, last_name
+		FROM users WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, id).Scan(&u.role, &u.telegramID, &u.email, &u.emailVerifiedAt, &u.passwordHash, &u.visitorID,
+		&u.username, &u.photoURL, &u.firstName, &u.lastName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	return u, err
+}
+
+func (r *PostgresMergeRepository) Merge(ctx context.Context, merge *entity.AccountMerge) error {
+	if merge.ID == uuid.Nil {
+		merge.ID = uuid.New()
+	}
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// Блокируем строки в порядке id, чтобы встречные слияния не взаимоблокировались
+		first, second := merge.SourceUserID, merge.TargetUserID
+		if second.String() < first.String() {
+			first, second = second, first
+		}
+		locked := map[uuid.UUID]*mergeUser{}
+		for _, id := range []uuid.UUID{first, second} {
+			u, err := lockMergeUser(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			locked[id] = u
+		}
+		source, target := locked[merge.SourceUserID], locked[merge.TargetUserID]
+
+		merge.SourceSnapshot = map[string]any{
+			"role":        source.role,
+			"telegram_id": source.telegramID,
+			"email":       source.email,
+			"visitor_id":  source.visitorID,
+			"username":    source.username,
+		}
+
+		// telegram_id уникален без учёта deleted_at — освобождаем его до обновления цели
+		if _, err := tx.Exec(ctx, `
+			UPDATE users SET telegram_id = NULL, deleted_at = NOW(), updated_at = NOW() WHERE id = $1
+		`, merge.SourceUserID); err != nil {
+			return err
+		}
+
+		role := target.role
+		if roleRank[source.role] > roleRank[role] {
+			role = source.role
+		}
+		// Email переносим вместе с подтверждением, только если у цели адреса нет
+		email, emailVerifiedAt := target.email, target.emailVerifiedAt
+		if email == nil || *email == "" {
+			email, emailVerifiedAt = source.email, source.emailVerifiedAt
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE users SET
+				role = $2,
+				telegram_id = COALESCE(telegram_id, $3),
+				email = $4,
+				email_verified_at = $5,
+				password_hash = COALESCE(password_hash, $6),
+				visitor_id = COALESCE(visitor_id, $7),
+				username = COALESCE(NULLIF(username, ''), $8),
+				photo_url = COALESCE(NULLIF(photo_url, ''), $9),
+				first_name = COALESCE(NULLIF(first_name, ''), $10),
+				last_name = COALESCE(NULLIF(last_name, ''), $11),
+				updated_at = NOW()
+			WHERE id = $1
+		`, merge.TargetUserID, string(role), source.telegramID, email, emailVerifiedAt,
+			source.passwordHash, source.visitorID, source.username, source.photoURL,
+			source.firstName, source.lastName); err != nil {
+			if isUniqueViolation(err) {
+				return ErrEmailTaken
+			}
+			return err
+		}
+
+		merge.Moved = make(map[string]int64, len(mergeSteps))
+		for _, step := range mergeSteps {
+			for i, q := range step.queries {
+				tag, err := tx.Exec(ctx, q, merge.SourceUserID, merge.TargetUserID)
+				if err != nil {
+					return err
+				}
+				if i == 0 && tag.RowsAffected() > 0 {
+					merge.Moved[step.key] = tag.RowsAffected()
+				}
+			}
+		}
+
+		// Удалённые дубли отзывов меняют рейтинг курсов цели
+		if _, err := tx.Exec(ctx, `
+			UPDATE courses c SET
+				rating_avg = COALESCE((SELECT ROUND(AVG(rating), 2) FROM course_reviews WHERE course_id = c.id AND status = 'approved'), 0),
+				rating_count = (SELECT COUNT(*) FROM course_reviews WHERE course_id = c.id AND status = 'approved')
+			WHERE c.id IN (SELECT course_id FROM course_reviews WHERE author_id = $1)
+		`, merge.TargetUserID); err != nil {
+			return err
+		}
+
+		snapshot, err := json.Marshal(merge.SourceSnapshot)
+		if err != nil {
+			return err
+		}
+		moved, err := json.Marshal(merge.Moved)
+		if err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, `
+			INSERT INTO user_merges (id, source_user_id, target_user_id, initiated_by, reason, source_snapshot, moved)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at
+		`, merge.ID, merge.SourceUserID, merge.TargetUserID, merge.InitiatedBy, string(merge.Reason), snapshot, moved).
+			Scan(&merge.CreatedAt)
+	})
+}
+
+func (r *PostgresMergeRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.AccountMerge, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, source_user_id, target_user_id, initiated_by, reason, source_snapshot, moved, created_at
+		FROM user_merges
+		WHERE source_user_id = $1 OR target_user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*entity.AccountMerge{}
+	for rows.Next() {
+		m := &entity.AccountMerge{}
+		var reason string
+		if err := rows.Scan(&m.ID, &m.SourceUserID, &m.TargetUserID, &m.InitiatedBy, &reason,
+			&m.SourceSnapshot, &m.Moved, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Reason = entity.MergeReason(reason)
+		items = append(items, m)
+	}
+	return items, rows.Err()
+}
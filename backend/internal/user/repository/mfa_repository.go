@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var (
+	ErrTOTPNotFound        = errors.New("totp not configured")
+	ErrTOTPStepUsed        = errors.New("totp code already used")
+	ErrRecoveryCodeInvalid = errors.New("recovery code not found or already used")
+	ErrTOTPLocked          = errors.New("totp verification locked")
+)
+
+// MFARepository — секреты TOTP и коды восстановления
+type MFARepository interface {
+	GetTOTP(ctx context.Context, userID uuid.UUID) (*entity.TOTP, error)
+	// SaveTOTPSecret начинает подключение заново; уже включённый TOTP не трогает
+	SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	// EnableTOTP включает TOTP и заменяет коды восстановления
+	EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, codes []*entity.RecoveryCode) error
+	// UseTOTPStep запоминает принятый интервал; ErrTOTPStepUsed, если он не новее последнего
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error
+	// ReserveTOTPAttempt атомарно засчитывает попытку ввода кода до его проверки;
+	// maxAttempts-я попытка подряд блокирует следующие до lockUntil.
+	// ErrTOTPLocked — проверка уже заблокирована
+	ReserveTOTPAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockUntil time.Time) error
+	// ResetTOTPAttempts обнуляет счётчик попыток и снимает блокировку после верного кода
+	ResetTOTPAttempts(ctx context.Context, userID uuid.UUID) error
+	DeleteTOTP(ctx context.Context, userID uuid.UUID) error
+
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []*entity.RecoveryCode) error
+	// UseRecoveryCode гасит код; ErrRecoveryCodeInvalid, если его нет или он использован
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+type PostgresMFARepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresMFARepository(db *pgxpool.Pool) *PostgresMFARepository {
+	return &PostgresMFARepository{db: db}
+}
+
+func (r *PostgresMFARepository) GetTOTP(ctx context.Context, userID uuid.UUID) (*entity.TOTP, error) {
+	t := &entity.TOTP{}
+	err := r.db.QueryRow(ctx, `
+		SELECT user_id, secret, enabled_at, last_used_step, failed_attempts, locked_until, created_at
+		FROM user_totp WHERE user_id = $1
+	`, userID).Scan(&t.UserID, &t.Secret, &t.EnabledAt, &t.LastUsedStep, &t.FailedAttempts, &t.LockedUntil, &t.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTOTPNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *PostgresMFARepository) SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = EXCLUDED.secret, last_used_step = 0, failed_attempts = 0, locked_until = NULL,
+			created_at = NOW(), updated_at = NOW()
+		WHERE user_totp.enabled_at IS NULL
+	`, userID, secret)
+	return err
+}
+
+func (r *PostgresMFARepository) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, codes []*entity.RecoveryCode) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE user_totp SET enabled_at = NOW(), last_used_step = $2, failed_attempts = 0, locked_until = NULL, updated_at = NOW()
+			WHERE user_id = $1 AND enabled_at IS NULL
+		`, userID, step)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrTOTPNotFound
+		}
+		return replaceRecoveryCodes(ctx, tx, userID, codes)
+	})
+}
+
+func (r *PostgresMFARepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE user_totp SET last_used_step = $2, failed_attempts = 0, locked_until = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND last_used_step < $2
+	`, userID, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTOTPStepUsed
+	}
+	return nil
+}
+
+func (r *PostgresMFARepository) ReserveTOTPAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockUntil time.Time) error {
+	// Истёкшая блокировка начинает новую серию попыток
+	tag, err := r.db.Exec(ctx, `
+		UPDATE user_totp SET
+			failed_attempts = CASE WHEN locked_until IS NULL THEN failed_attempts + 1 ELSE 1 END,
+			locked_until = CASE
+				WHEN (CASE WHEN locked_until IS NULL THEN failed_attempts + 1 ELSE 1 END) >= $2 THEN $3
+				ELSE NULL
+			END,
+			updated_at = NOW()
+		WHERE user_id = $1 AND (locked_until IS NULL OR locked_until <= NOW())
+	`, userID, maxAttempts, lockUntil)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTOTPLocked
+	}
+	return nil
+}
+
+func (r *PostgresMFARepository) ResetTOTPAttempts(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE user_totp SET failed_attempts = 0, locked_until = NULL, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID)
+	return err
+}
+
+func (r *PostgresMFARepository) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID)
+		return err
+	})
+}
+
+func (r *PostgresMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []*entity.RecoveryCode) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, userID, codes)
+	})
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID uuid.UUID, codes []*entity.RecoveryCode) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, c := range codes {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO user_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, $4)
+		`, c.ID, userID, c.CodeHash, c.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE user_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRecoveryCodeInvalid
+	}
+	return nil
+}
+
+func (r *PostgresMFARepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL
+	`, userID).Scan(&n)
+	return n, err
+}
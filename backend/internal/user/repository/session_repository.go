@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/user/entity"
 )
@@ -14,13 +16,36 @@ type SessionRepository interface {
 	Save(ctx context.Context, session *entity.UserSession) error
 	UpdateLastActive(ctx context.Context, sessionID uuid.UUID) error
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserSession, error)
-	Delete(ctx context.Context, userID, sessionID uuid.UUID) error
+	// Revoke отзывает сессию пользователя вместе с её прошлыми ротациями и возвращает family_id
+	Revoke(ctx context.Context, userID, sessionID uuid.UUID, reason string) (uuid.UUID, error)
+	// RevokeByUserID отзывает все сессии пользователя, кроме семейства exceptFamilyID
+	// (uuid.Nil — без исключений), и возвращает отозванные family_id
+	RevokeByUserID(ctx context.Context, userID, exceptFamilyID uuid.UUID, reason string) ([]uuid.UUID, error)
 	SaveInactivityTimeout(ctx context.Context, userID uuid.UUID, timeout time.Duration) error
 	GetInactivityTimeout(ctx context.Context, userID uuid.UUID) (time.Duration, error)
 	FindByID(ctx context.Context, sessionID uuid.UUID) (*entity.UserSession, error)
 	DeleteExpiredSessions(ctx context.Context) error
+	// RevokeInactiveSessions отзывает сессии, простаивающие дольше таймаута
+	// неактивности их владельца, и возвращает отозванные family_id
+	RevokeInactiveSessions(ctx context.Context) ([]uuid.UUID, error)
+	// FindByRefreshHash ищет сессию по хэшу refresh-токена, включая отозванные
+	FindByRefreshHash(ctx context.Context, hash string) (*entity.UserSession, error)
+	// Rotate отзывает сессию oldID и сохраняет next в одной транзакции.
+	// ErrSessionRotated — если oldID уже отозвана (токен использован повторно)
+	Rotate(ctx context.Context, oldID uuid.UUID, next *entity.UserSession) error
+	// FindFamilyHead возвращает действующую сессию семейства — последнюю ротацию
+	FindFamilyHead(ctx context.Context, familyID uuid.UUID) (*entity.UserSession, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, reason string) error
+	// SetMFAVerified отмечает прохождение второго фактора (at == nil — снимает отметку)
+	// в действующих сессиях семейства familyID или, если он uuid.Nil, во всех сессиях пользователя
+	SetMFAVerified(ctx context.Context, userID, familyID uuid.UUID, at *time.Time) error
 }
 
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRotated  = errors.New("session already rotated")
+)
+
 type PostgresSessionRepository struct {
 	db *pgxpool.Pool
 }
@@ -31,13 +56,35 @@ func NewPostgresSessionRepository(db *pgxpool.Pool) *PostgresSessionRepository {
 
 // Сохраняем сессию
 func (r *PostgresSessionRepository) Save(ctx context.Context, s *entity.UserSession) error {
-	_, err := r.db.Exec(ctx, `
+	return insertSession(ctx, r.db, s)
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func insertSession(ctx context.Context, db execer, s *entity.UserSession) error {
+	if s.FamilyID == uuid.Nil {
+		s.FamilyID = s.ID
+	}
+	var refreshHash *string
+	if s.RefreshTokenHash != "" {
+		refreshHash = &s.RefreshTokenHash
+	}
+	if s.RiskReasons == nil {
+		s.RiskReasons = []string{}
+	}
+	_, err := db.Exec(ctx, `
 		INSERT INTO user_sessions (
-			id, user_id, token, user_agent, ip_address, country, city, created_at, last_active_at, expires_at
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			id, user_id, token, user_agent, ip_address, country, city, created_at, last_active_at, expires_at,
+			family_id, refresh_token_hash, device_id, risk_score, risk_reasons, suspicious, mfa_verified_at,
+			impersonator_id
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
 	`,
 		s.ID, s.UserID, s.Token, s.UserAgent, s.IPAddress, s.Country, s.City,
-		s.CreatedAt, s.LastActiveAt, s.ExpiresAt,
+		s.CreatedAt, s.LastActiveAt, s.ExpiresAt, s.FamilyID, refreshHash,
+		s.DeviceID, s.RiskScore, s.RiskReasons, s.Suspicious, s.MFAVerifiedAt,
+		s.ImpersonatorID,
 	)
 	return err
 }
@@ -55,9 +102,10 @@ func (r *PostgresSessionRepository) UpdateLastActive(ctx context.Context, sessio
 // Получаем список сессий пользователя
 func (r *PostgresSessionRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.UserSession, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, token, user_agent, ip_address, country, city, created_at, last_active_at, expires_at
+		SELECT id, token, user_agent, ip_address, country, city, created_at, last_active_at, expires_at, family_id,
+			COALESCE(device_id, ''), risk_score, risk_reasons, suspicious, mfa_verified_at, impersonator_id
 		FROM user_sessions
-		WHERE user_id = $1
+		WHERE user_id = $1 AND revoked_at IS NULL
 		ORDER BY created_at DESC
 	`, userID)
 	if err != nil {
@@ -70,7 +118,8 @@ func (r *PostgresSessionRepository) FindByUserID(ctx context.Context, userID uui
 		var s entity.UserSession
 		err := rows.Scan(
 			&s.ID, &s.Token, &s.UserAgent, &s.IPAddress, &s.Country, &s.City,
-			&s.CreatedAt, &s.LastActiveAt, &s.ExpiresAt,
+			&s.CreatedAt, &s.LastActiveAt, &s.ExpiresAt, &s.FamilyID,
+			&s.DeviceID, &s.RiskScore, &s.RiskReasons, &s.Suspicious, &s.MFAVerifiedAt, &s.ImpersonatorID,
 		)
 		if err != nil {
 			return nil, err
@@ -82,13 +131,44 @@ func (r *PostgresSessionRepository) FindByUserID(ctx context.Context, userID uui
 	return sessions, nil
 }
 
-// Удаление конкретной сессии
-func (r *PostgresSessionRepository) Delete(ctx context.Context, userID, sessionID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
-		DELETE FROM user_sessions
-		WHERE id = $1 AND user_id = $2
-	`, sessionID, userID)
-	return err
+func (r *PostgresSessionRepository) Revoke(ctx context.Context, userID, sessionID uuid.UUID, reason string) (uuid.UUID, error) {
+	var familyID uuid.UUID
+	err := r.db.QueryRow(ctx, `
+		SELECT family_id FROM user_sessions WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, sessionID, userID).Scan(&familyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return familyID, r.RevokeFamily(ctx, familyID, reason)
+}
+
+// RevokeByUserID завершает сессии пользователя (выход на всех устройствах, сброс пароля)
+func (r *PostgresSessionRepository) RevokeByUserID(ctx context.Context, userID, exceptFamilyID uuid.UUID, reason string) ([]uuid.UUID, error) {
+	return collectFamilies(r.db.Query(ctx, `
+		UPDATE user_sessions SET revoked_at = NOW(), is_current = FALSE, revoke_reason = $3
+		WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL
+		RETURNING family_id
+	`, userID, exceptFamilyID, reason))
+}
+
+func collectFamilies(rows pgx.Rows, err error) ([]uuid.UUID, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
 
 // Храним таймаут неактивности (в секундах)
@@ -108,7 +188,7 @@ func (r *PostgresSessionRepository) GetInactivityTimeout(ctx context.Context, us
 	`, userID).Scan(&seconds)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			// По умолчанию 6 месяцев
+			// По умолчанию 6 месяцев; 0 в таблице означает, что таймаут отключён
 			return 4380 * time.Hour, nil
 		}
 		return 0, err
@@ -116,26 +196,110 @@ func (r *PostgresSessionRepository) GetInactivityTimeout(ctx context.Context, us
 	return time.Duration(seconds) * time.Second, nil
 }
 
-func (r *PostgresSessionRepository) FindByID(ctx context.Context, sessionID uuid.UUID) (*entity.UserSession, error) {
-	row := r.db.QueryRow(ctx, `
-		SELECT id, user_id, token, user_agent, ip_address, country, city, created_at, last_active_at, expires_at
-		FROM user_sessions
-		WHERE id = $1
-	`, sessionID)
+const sessionColumns = `id, user_id, token, user_agent, ip_address, country, city, created_at, last_active_at, expires_at,
+	family_id, COALESCE(refresh_token_hash, ''), revoked_at, COALESCE(revoke_reason, ''),
+	COALESCE(device_id, ''), risk_score, risk_reasons, suspicious, mfa_verified_at, impersonator_id`
 
+func scanSession(row pgx.Row) (*entity.UserSession, error) {
 	var s entity.UserSession
 	err := row.Scan(
 		&s.ID, &s.UserID, &s.Token, &s.UserAgent, &s.IPAddress, &s.Country, &s.City,
-		&s.CreatedAt, &s.LastActiveAt, &s.ExpiresAt,
+		&s.CreatedAt, &s.LastActiveAt, &s.ExpiresAt, &s.FamilyID, &s.RefreshTokenHash, &s.RevokedAt, &s.RevokeReason,
+		&s.DeviceID, &s.RiskScore, &s.RiskReasons, &s.Suspicious, &s.MFAVerifiedAt, &s.ImpersonatorID,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &s, nil
 }
 
+// FindByID возвращает только действующую (не отозванную) сессию
+func (r *PostgresSessionRepository) FindByID(ctx context.Context, sessionID uuid.UUID) (*entity.UserSession, error) {
+	return scanSession(r.db.QueryRow(ctx, `
+		SELECT `+sessionColumns+` FROM user_sessions WHERE id = $1 AND revoked_at IS NULL
+	`, sessionID))
+}
+
+func (r *PostgresSessionRepository) FindByRefreshHash(ctx context.Context, hash string) (*entity.UserSession, error) {
+	return scanSession(r.db.QueryRow(ctx, `
+		SELECT `+sessionColumns+` FROM user_sessions WHERE refresh_token_hash = $1
+	`, hash))
+}
+
+func (r *PostgresSessionRepository) Rotate(ctx context.Context, oldID uuid.UUID, next *entity.UserSession) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE user_sessions SET revoked_at = NOW(), is_current = FALSE, revoke_reason = $2
+			WHERE id = $1 AND revoked_at IS NULL
+		`, oldID, entity.RevokeReasonRotated)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrSessionRotated
+		}
+		return insertSession(ctx, tx, next)
+	})
+}
+
+func (r *PostgresSessionRepository) FindFamilyHead(ctx context.Context, familyID uuid.UUID) (*entity.UserSession, error) {
+	return scanSession(r.db.QueryRow(ctx, `
+		SELECT `+sessionColumns+` FROM user_sessions
+		WHERE family_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, familyID))
+}
+
+func (r *PostgresSessionRepository) SetMFAVerified(ctx context.Context, userID, familyID uuid.UUID, at *time.Time) error {
+	if familyID == uuid.Nil {
+		_, err := r.db.Exec(ctx, `
+			UPDATE user_sessions SET mfa_verified_at = $2
+			WHERE user_id = $1 AND revoked_at IS NULL
+		`, userID, at)
+		return err
+	}
+	_, err := r.db.Exec(ctx, `
+		UPDATE user_sessions SET mfa_verified_at = $3
+		WHERE user_id = $1 AND family_id = $2 AND revoked_at IS NULL
+	`, userID, familyID, at)
+	return err
+}
+
+func (r *PostgresSessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, reason string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE user_sessions SET revoked_at = NOW(), is_current = FALSE, revoke_reason = $2
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`, familyID, reason)
+	return err
+}
+
+func (r *PostgresSessionRepository) RevokeInactiveSessions(ctx context.Context) ([]uuid.UUID, error) {
+	// Пользователи без записи в user_inactivity_timeout получают таймаут по умолчанию
+	// (6 месяцев) — он больше срока жизни refresh-токена, такие сессии истекут раньше
+	return collectFamilies(r.db.Query(ctx, `
+		UPDATE user_sessions s SET revoked_at = NOW(), is_current = FALSE, revoke_reason = $1
+		FROM user_inactivity_timeout t
+		WHERE t.user_id = s.user_id
+		  AND t.timeout_seconds > 0
+		  AND s.revoked_at IS NULL
+		  AND s.last_active_at < NOW() - make_interval(secs => t.timeout_seconds)
+		RETURNING s.family_id
+	`, entity.RevokeReasonInactivity))
+}
+
 func (r *PostgresSessionRepository) DeleteExpiredSessions(ctx context.Context) error {
 	query := `DELETE FROM user_sessions WHERE expires_at < NOW()`
-	_, err := r.db.Exec(ctx, query)
+	if _, err := r.db.Exec(ctx, query); err != nil {
+		return err
+	}
+	// Заодно чистим следы входов, которые больше не участвуют в оценке риска
+	if _, err := r.db.Exec(ctx, `DELETE FROM login_attempts WHERE created_at < NOW() - INTERVAL '1 day'`); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(ctx, `DELETE FROM login_challenges WHERE expires_at < NOW() - INTERVAL '1 day'`)
 	return err
 }
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationCache хранит недавно отозванные сессии и семейства сессий.
+// Записи нужны, пока инстансы могут держать отозванную сессию в локальном кэше,
+// после этого отзыв виден из Postgres
+type RevocationCache interface {
+	MarkRevoked(ctx context.Context, ids []uuid.UUID, ttl time.Duration) error
+	// AnyRevoked возвращает true, если отозван хотя бы один из ids
+	AnyRevoked(ctx context.Context, ids ...uuid.UUID) (bool, error)
+}
+
+// MemoryRevocationCache — кэш в памяти процесса; подходит для одного инстанса
+type MemoryRevocationCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]time.Time
+	sweepAt time.Time
+}
+
+func NewMemoryRevocationCache() *MemoryRevocationCache {
+	return &MemoryRevocationCache{entries: make(map[uuid.UUID]time.Time)}
+}
+
+func (c *MemoryRevocationCache) MarkRevoked(_ context.Context, ids []uuid.UUID, ttl time.Duration) error {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Просроченные записи вычищаем не чаще раза в минуту
+	if now.After(c.sweepAt) {
+		for id, exp := range c.entries {
+			if now.After(exp) {
+				delete(c.entries, id)
+			}
+		}
+		c.sweepAt = now.Add(time.Minute)
+	}
+
+	for _, id := range ids {
+		c.entries[id] = now.Add(ttl)
+	}
+	return nil
+}
+
+func (c *MemoryRevocationCache) AnyRevoked(_ context.Context, ids ...uuid.UUID) (bool, error) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		if exp, ok := c.entries[id]; ok && now.Before(exp) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RedisRevocationCache — общий кэш для нескольких инстансов: отзыв на одном
+// инстансе сразу виден остальным
+type RedisRevocationCache struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisRevocationCache(client *redis.Client, prefix string) *RedisRevocationCache {
+	return &RedisRevocationCache{client: client, prefix: prefix}
+}
+
+func (c *RedisRevocationCache) MarkRevoked(ctx context.Context, ids []uuid.UUID, ttl time.Duration) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	pipe := c.client.Pipeline()
+	for _, id := range ids {
+		pipe.Set(ctx, c.prefix+id.String(), 1, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisRevocationCache) AnyRevoked(ctx context.Context, ids ...uuid.UUID) (bool, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = c.prefix + id.String()
+	}
+	n, err := c.client.Exists(ctx, keys...).Result()
+	return n > 0, err
+}
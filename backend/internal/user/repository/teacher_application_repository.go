@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+var (
+	ErrTeacherApplicationNotFound   = errors.New("teacher application not found")
+	ErrTeacherApplicationPending    = errors.New("teacher application is already under review")
+	ErrTeacherApplicationNotPending = errors.New("teacher application has already been reviewed or withdrawn")
+)
+
+type TeacherApplicationRepository interface {
+	// Create сохраняет заявку; ErrTeacherApplicationPending, если другая ещё на рассмотрении
+	Create(ctx context.Context, app *entity.TeacherApplication) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.TeacherApplication, error)
+	// ListByUser — история заявок пользователя, новые первыми
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.TeacherApplication, error)
+	// List — заявки для администраторов; пустой status — все
+	List(ctx context.Context, status entity.TeacherApplicationStatus, pag pagination.Params) ([]*entity.TeacherApplication, int, error)
+	// Withdraw отзывает заявку на рассмотрении от имени её автора
+	Withdraw(ctx context.Context, id, userID uuid.UUID) error
+	// Review сохраняет решение по заявке на рассмотрении. При одобрении в той же
+	// транзакции пользователь получает роль teacher (старшие роли не понижаются),
+	// а entry пишется в журнал администратора. Возвращает роль пользователя до решения
+	Review(ctx context.Context, app *entity.TeacherApplication, entry *entity.AdminAuditEntry) (entity.Role, error)
+}
+
+type PostgresTeacherApplicationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTeacherApplicationRepository(db *pgxpool.Pool) *PostgresTeacherApplicationRepository {
+	return &PostgresTeacherApplicationRepository{db: db}
+}
+
+const teacherApplicationColumns = `id, user_id, bio, portfolio_links, status, reviewed_by, review_comment,
+	reviewed_at, created_at, updated_at`
+
+var teacherApplicationSortFields = map[string]string{
+	"created_at":  "created_at",
+	"reviewed_at": "reviewed_at",
+}
+
+func scanTeacherApplication(row pgx.Row) (*entity.TeacherApplication, error) {
+	a := &entity.TeacherApplication{}
+	err := row.Scan(&a.ID, &a.UserID, &a.Bio, &a.PortfolioLinks, &a.Status, &a.ReviewedBy, &a.ReviewComment,
+		&a.ReviewedAt, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func collectTeacherApplications(rows pgx.Rows) ([]*entity.TeacherApplication, error) {
+	defer rows.Close()
+	apps := []*entity.TeacherApplication{}
+	for rows.Next() {
+		a, err := scanTeacherApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, a)
+	}
+	return apps, rows.Err()
+}
+
+func (r *PostgresTeacherApplicationRepository) Create(ctx context.Context, app *entity.TeacherApplication) error {
+	if app.ID == uuid.Nil {
+		app.ID = uuid.New()
+	}
+	app.Status = entity.TeacherApplicationPending
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO teacher_applications (id, user_id, bio, portfolio_links, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, updated_at
+	`, app.ID, app.UserID, app.Bio, app.PortfolioLinks, string(app.Status)).Scan(&app.CreatedAt, &app.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrTeacherApplicationPending
+	}
+	return err
+}
+
+func (r *PostgresTeacherApplicationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.TeacherApplication, error) {
+	app, err := scanTeacherApplication(r.db.QueryRow(ctx,
+		`SELECT `+teacherApplicationColumns+` FROM teacher_applications WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTeacherApplicationNotFound
+	}
+	return app, err
+}
+
+func (r *PostgresTeacherApplicationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.TeacherApplication, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+teacherApplicationColumns+` FROM teacher_applications
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return collectTeacherApplications(rows)
+}
+
+func (r *PostgresTeacherApplicationRepository) List(ctx context.Context, status entity.TeacherApplicationStatus, pag pagination.Params) ([]*entity.TeacherApplication, int, error) {
+	// Очередь на рассмотрение — от старых заявок к новым
+	if pag.SortBy == "" {
+		pag.SortBy = "created_at"
+		pag.Order = "asc"
+	}
+	where := ` WHERE ($3 = '' OR status = $3)`
+	query, args := pagination.SQLWithPagination(
+		`SELECT `+teacherApplicationColumns+` FROM teacher_applications`+where, pag, teacherApplicationSortFields)
+	args = append(args, string(status))
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	apps, err := collectTeacherApplications(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int
+	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM teacher_applications WHERE ($1 = '' OR status = $1)`, string(status)).
+		Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return apps, total, nil
+}
+
+func (r *PostgresTeacherApplicationRepository) Withdraw(ctx context.Context, id, userID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE teacher_applications SET status = $3, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND status = $4
+	`, id, userID, string(entity.TeacherApplicationWithdrawn), string(entity.TeacherApplicationPending))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+	var exists bool
+	err = r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM teacher_applications WHERE id = $1 AND user_id = $2)`,
+		id, userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTeacherApplicationNotFound
+	}
+	return ErrTeacherApplicationNotPending
+}
+
+func (r *PostgresTeacherApplicationRepository) Review(ctx context.Context, app *entity.TeacherApplication, entry *entity.AdminAuditEntry) (entity.Role, error) {
+	var previous entity.Role
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var status entity.TeacherApplicationStatus
+		err := tx.QueryRow(ctx, `
+			SELECT user_id, status FROM teacher_applications WHERE id = $1 FOR UPDATE
+		`, app.ID).Scan(&app.UserID, &status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTeacherApplicationNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if status != entity.TeacherApplicationPending {
+			return ErrTeacherApplicationNotPending
+		}
+
+		err = tx.QueryRow(ctx, `
+			SELECT role FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		`, app.UserID).Scan(&previous)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if app.Status == entity.TeacherApplicationApproved {
+			if _, err := tx.Exec(ctx, `
+				UPDATE users SET role = $2, updated_at = NOW()
+				WHERE id = $1 AND role IN ($3, $4)
+			`, app.UserID, string(entity.RoleTeacher), string(entity.RoleGuest), string(entity.RoleStudent)); err != nil {
+				return err
+			}
+		}
+
+		err = tx.QueryRow(ctx, `
+			UPDATE teacher_applications SET
+				status = $2, reviewed_by = $3, review_comment = $4, reviewed_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+teacherApplicationColumns,
+			app.ID, string(app.Status), app.ReviewedBy, app.ReviewComment,
+		).Scan(&app.ID, &app.UserID, &app.Bio, &app.PortfolioLinks, &app.Status, &app.ReviewedBy, &app.ReviewComment,
+			&app.ReviewedAt, &app.CreatedAt, &app.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		entry.TargetUserID = &app.UserID
+		if app.Status == entity.TeacherApplicationApproved {
+			setAuditDetail(entry, "from", previous)
+			setAuditDetail(entry, "to", entity.RoleTeacher)
+		}
+		return insertAudit(ctx, tx, entry)
+	})
+	if err != nil {
+		return "", err
+	}
+	return previous, nil
+}
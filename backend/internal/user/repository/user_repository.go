@@ -4,12 +4,20 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kostinp/edu-platform-backend/internal/user/entity"
 )
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email already registered")
+)
+
 type PostgresUserRepository struct {
 	pool *pgxpool.Pool
 }
@@ -111,7 +119,8 @@ func (r *PostgresUserRepository) Update(ctx context.Context, user *entity.User)
 func (r *PostgresUserRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*entity.User, error) {
 	query := `
 		SELECT id, visitor_id, telegram_id, first_name, last_name, username, photo_url,
-		       created_at, updated_at, deleted_at, email, subscribe_to_newsletter, role
+		       created_at, updated_at, deleted_at, email, subscribe_to_newsletter, role, email_verified_at,
+		       banned_at, banned_until, ban_reason, org_id, COALESCE(org_role, '')
 		FROM users WHERE telegram_id = $1 AND deleted_at IS NULL
 	`
 
@@ -132,7 +141,16 @@ func (r *PostgresUserRepository) GetByTelegramID(ctx context.Context, telegramID
 		&user.Email,
 		&user.SubscribeToNews,
 		&user.Role,
+		&user.EmailVerifiedAt,
+		&user.BannedAt,
+		&user.BannedUntil,
+		&user.BanReason,
+		&user.OrgID,
+		&user.OrgRole,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +164,8 @@ func (r *PostgresUserRepository) GetByTelegramID(ctx context.Context, telegramID
 func (r *PostgresUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	query := `
 		SELECT id, visitor_id, telegram_id, first_name, last_name, username, photo_url,
-		       created_at, updated_at, deleted_at, email, subscribe_to_newsletter, role
+		       created_at, updated_at, deleted_at, email, subscribe_to_newsletter, role, email_verified_at,
+		       banned_at, banned_until, ban_reason, org_id, COALESCE(org_role, '')
 		FROM users WHERE id = $1 AND deleted_at IS NULL
 	`
 
@@ -167,6 +186,12 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*en
 		&user.Email,
 		&user.SubscribeToNews,
 		&user.Role,
+		&user.EmailVerifiedAt,
+		&user.BannedAt,
+		&user.BannedUntil,
+		&user.BanReason,
+		&user.OrgID,
+		&user.OrgRole,
 	)
 	if err != nil {
 		return nil, err
@@ -178,6 +203,88 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*en
 	return user, nil
 }
 
+// CreateWithPassword создаёт пользователя, зарегистрированного по email
+func (r *PostgresUserRepository) CreateWithPassword(ctx context.Context, user *entity.User, passwordHash string) error {
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	var firstName, lastName string
+	if user.FullName != nil {
+		names := splitFullName(*user.FullName)
+		if len(names) > 0 {
+			firstName = names[0]
+		}
+		if len(names) > 1 {
+			lastName = names[1]
+		}
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO users (id, email, first_name, last_name, role, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, user.ID, user.Email, firstName, lastName, string(user.Role), passwordHash, user.CreatedAt, user.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// GetByEmail ищет пользователя по email без учёта регистра
+func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT id FROM users WHERE lower(email) = lower($1) AND deleted_at IS NULL
+	`, email).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetPasswordHash возвращает хеш пароля; пустая строка — пароль не задан
+func (r *PostgresUserRepository) GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error) {
+	var hash *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT password_hash FROM users WHERE id = $1 AND deleted_at IS NULL
+	`, userID).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrUserNotFound
+	}
+	if err != nil || hash == nil {
+		return "", err
+	}
+	return *hash, nil
+}
+
+func (r *PostgresUserRepository) SetPasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	cmdTag, err := r.pool.Exec(ctx, `
+		UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+	`, userID, passwordHash)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// MarkEmailVerified отмечает email подтверждённым; повторный вызов не меняет дату
+func (r *PostgresUserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE users SET email_verified_at = COALESCE(email_verified_at, $2), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, userID, at)
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 func splitFullName(fullName string) []string {
 	return strings.Fields(fullName) // лучше split по пробелам с trim
 }
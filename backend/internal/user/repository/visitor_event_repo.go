@@ -25,6 +25,7 @@ type AnalyticsEvent struct {
 type VisitorEventRepository interface {
 	Create(ctx context.Context, event *entity.VisitorEvent) error
 	GetEventsByVisitor(ctx context.Context, visitorID string) ([]*entity.VisitorEvent, error)
+	DeleteEventsByVisitor(ctx context.Context, visitorID string) error
 }
 
 // AnalyticsRepository - интерфейс для аналитических методов
@@ -165,6 +166,18 @@ func (r *ClickHouseVisitorEventRepo) GetEventsByVisitor(ctx context.Context, vis
 	return events, nil
 }
 
+// DeleteEventsByVisitor удаляет события посетителя (удаление аккаунта).
+// В ClickHouse это мутация: строки исчезают асинхронно
+func (r *ClickHouseVisitorEventRepo) DeleteEventsByVisitor(ctx context.Context, visitorID string) error {
+	if r.conn == nil {
+		return fmt.Errorf("clickhouse connection is not available")
+	}
+	if err := r.conn.Exec(ctx, `ALTER TABLE visitor_events DELETE WHERE visitor_id = ?`, visitorID); err != nil {
+		return fmt.Errorf("failed to delete visitor events: %w", err)
+	}
+	return nil
+}
+
 // Аналитические методы для ClickHouse
 // GetPageViews возвращает статистику просмотров страниц
 func (r *ClickHouseVisitorEventRepo) GetPageViews(ctx context.Context, days int) ([]map[string]interface{}, error) {
@@ -308,6 +321,14 @@ func (r *VisitorEventRepoWithFallback) GetEventsByVisitor(ctx context.Context, v
 	return r.primary.GetEventsByVisitor(ctx, visitorID)
 }
 
+// DeleteEventsByVisitor удаляет события посетителя
+func (r *VisitorEventRepoWithFallback) DeleteEventsByVisitor(ctx context.Context, visitorID string) error {
+	if r.primary == nil {
+		return ErrNoRepositoryAvailable
+	}
+	return r.primary.DeleteEventsByVisitor(ctx, visitorID)
+}
+
 // Ошибки
 var (
 	ErrNoRepositoryAvailable = fmt.Errorf("no repository available for visitor events")
@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type AccountHandler struct {
+	accounts *usecase.AccountService
+}
+
+func NewAccountHandler(accounts *usecase.AccountService) *AccountHandler {
+	return &AccountHandler{accounts: accounts}
+}
+
+// @Summary Профиль текущего пользователя
+// @Tags Account
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} entity.User
+// @Failure 401 {object} map[string]string
+// @Router /me [get]
+func (h *AccountHandler) Get(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	user, err := h.accounts.Profile(c.Request().Context(), userID)
+	if err != nil {
+		return accountError(c, err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+// @Summary Изменить профиль
+// @Description Меняются только переданные поля. Новый email нужно подтвердить по ссылке из письма;
+// @Description до подтверждения вход по паролю на этот адрес недоступен
+// @Tags Account
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body usecase.UpdateProfileInput true "Поля профиля"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me [patch]
+func (h *AccountHandler) Update(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.UpdateProfileInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	user, err := h.accounts.UpdateProfile(c.Request().Context(), userID, req)
+	if err != nil {
+		return accountError(c, err)
+	}
+	return c.JSON(http.StatusOK, user)
+}
+
+// @Summary Выгрузить все свои данные
+// @Description Zip-архив с JSON-файлами: профиль, сессии, устройства, прогресс, записи на курсы,
+// @Description заметки, закладки, отзывы, комментарии, уведомления и события посетителя из аналитики
+// @Tags Account
+// @Security BearerAuth
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string
+// @Router /me/export [get]
+func (h *AccountHandler) Export(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	// Собираем архив целиком, чтобы ошибка не оборвала уже начатый ответ
+	var buf bytes.Buffer
+	if err := h.accounts.Export(c.Request().Context(), userID, &buf); err != nil {
+		return accountError(c, err)
+	}
+	filename := "edu-platform-data-" + time.Now().Format("2006-01-02") + ".zip"
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// @Summary Удалить аккаунт
+// @Description Все сессии завершаются, личные данные удаляются безвозвратно. Курсы и комментарии
+// @Description в обсуждениях остаются, но без имени автора и текста комментариев.
+// @Description Нужен пароль (если задан) и код 2FA (если включена)
+// @Tags Account
+// @Security BearerAuth
+// @Accept json
+// @Param request body usecase.DeleteAccountInput true "Подтверждение"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /me [delete]
+func (h *AccountHandler) Delete(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.DeleteAccountInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if err := h.accounts.Delete(c.Request().Context(), userID, req); err != nil {
+		return accountError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func accountError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrProfileName),
+		errors.Is(err, usecase.ErrProfilePhotoURL),
+		errors.Is(err, usecase.ErrEmailRequired),
+		errors.Is(err, usecase.ErrInvalidEmail),
+		errors.Is(err, usecase.ErrMFACodeInvalid):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidCredentials):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrEmailTaken):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrMFALocked):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
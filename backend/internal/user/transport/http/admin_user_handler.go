@@ -0,0 +1,304 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type AdminUserHandler struct {
+	admin *usecase.AdminUserService
+}
+
+func NewAdminUserHandler(admin *usecase.AdminUserService) *AdminUserHandler {
+	return &AdminUserHandler{admin: admin}
+}
+
+// SetRoleRequest — новая роль пользователя
+type SetRoleRequest struct {
+	Role entity.Role `json:"role" example:"teacher"`
+}
+
+// ImpersonateRequest — основание для входа от имени пользователя (попадает в журнал)
+type ImpersonateRequest struct {
+	Reason string `json:"reason" example:"ticket #1234: course progress is missing"`
+}
+
+// @Summary Поиск пользователей
+// @Description q — подстрока email, username или имени, либо точный id / telegram_id.
+// @Description status: active, banned, deleted, all; по умолчанию — все, кроме удалённых
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Поиск"
+// @Param role query string false "Роль"
+// @Param status query string false "Статус"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param sort_by query string false "created_at, updated_at, email, role"
+// @Param order query string false "asc / desc"
+// @Success 200 {object} dto.PaginatedResponse[*entity.User]
+// @Failure 400 {object} map[string]string
+// @Router /admin/users [get]
+func (h *AdminUserHandler) List(c echo.Context) error {
+	filter := repository.UserFilter{
+		Query:  c.QueryParam("q"),
+		Role:   entity.Role(c.QueryParam("role")),
+		Status: c.QueryParam("status"),
+	}
+	if filter.Role != "" && !filter.Role.Valid() {
+		return adminUserError(c, usecase.ErrInvalidRole)
+	}
+	switch filter.Status {
+	case "", repository.UserStatusActive, repository.UserStatusBanned, repository.UserStatusDeleted, repository.UserStatusAll:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status"})
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	users, total, err := h.admin.Search(c.Request().Context(), filter, pag)
+	if err != nil {
+		return adminUserError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.User]{
+		Items:  users,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// @Summary Изменить роль пользователя
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "ID пользователя"
+// @Param request body SetRoleRequest true "Роль"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/role [put]
+func (h *AdminUserHandler) SetRole(c echo.Context) error {
+	actor, userID, err := h.target(c)
+	if err != nil {
+		return err
+	}
+	var req SetRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if err := h.admin.SetRole(c.Request().Context(), actor, userID, req.Role); err != nil {
+		return adminUserError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Заблокировать пользователя
+// @Description Все сессии пользователя завершаются, персональные токены перестают работать.
+// @Description Без until блокировка бессрочная
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "ID пользователя"
+// @Param request body usecase.BanInput true "Причина и срок"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/ban [post]
+func (h *AdminUserHandler) Ban(c echo.Context) error {
+	actor, userID, err := h.target(c)
+	if err != nil {
+		return err
+	}
+	var req usecase.BanInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if err := h.admin.Ban(c.Request().Context(), actor, userID, req); err != nil {
+		return adminUserError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Снять блокировку
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "ID пользователя"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/ban [delete]
+func (h *AdminUserHandler) Unban(c echo.Context) error {
+	actor, userID, err := h.target(c)
+	if err != nil {
+		return err
+	}
+	if err := h.admin.Unban(c.Request().Context(), actor, userID); err != nil {
+		return adminUserError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Завершить все сессии пользователя
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID пользователя"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Router /admin/users/{id}/sessions [delete]
+func (h *AdminUserHandler) RevokeSessions(c echo.Context) error {
+	actor, userID, err := h.target(c)
+	if err != nil {
+		return err
+	}
+	revoked, err := h.admin.RevokeSessions(c.Request().Context(), actor, userID)
+	if err != nil {
+		return adminUserError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]int{"revoked": revoked})
+}
+
+// @Summary Войти от имени пользователя
+// @Description Выдаёт access-токен на 30 минут без refresh-токена. Нужна пройденная 2FA;
+// @Description администраторов и заблокированных пользователей подменять нельзя.
+// @Description Начало и каждый запрос в такой сессии пишутся в журнал действий
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID пользователя"
+// @Param request body ImpersonateRequest true "Основание"
+// @Success 200 {object} map[string]any
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/impersonate [post]
+func (h *AdminUserHandler) Impersonate(c echo.Context) error {
+	actor, userID, err := h.target(c)
+	if err != nil {
+		return err
+	}
+	var req ImpersonateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	pair, err := h.admin.Impersonate(c.Request().Context(), actor, userID, req.Reason, sessionMeta(c))
+	if err != nil {
+		return adminUserError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"token":      pair.AccessToken,
+		"expires_at": pair.AccessExpiresAt,
+		"user_id":    userID.String(),
+		"session_id": pair.Session.ID.String(),
+	})
+}
+
+// @Summary Массовая операция над пользователями
+// @Description action: set_role, ban, unban, revoke_sessions; не больше 100 пользователей.
+// @Description Операция применяется к каждому пользователю отдельно, ошибки возвращаются по каждому
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body usecase.BulkUserAction true "Операция"
+// @Success 200 {array} usecase.BulkResult
+// @Failure 400 {object} map[string]string
+// @Router /admin/users/bulk [post]
+func (h *AdminUserHandler) Bulk(c echo.Context) error {
+	adminID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.BulkUserAction
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	results, err := h.admin.Bulk(c.Request().Context(), usecase.AdminActor{ID: adminID, IP: c.RealIP()}, req)
+	if err != nil {
+		return adminUserError(c, err)
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
+// @Summary Журнал действий администраторов
+// @Description От новых записей к старым; можно отфильтровать по пользователю и по администратору
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param target_user_id query string false "ID пользователя"
+// @Param actor_id query string false "ID администратора"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} dto.PaginatedResponse[*entity.AdminAuditEntry]
+// @Failure 400 {object} map[string]string
+// @Router /admin/audit [get]
+func (h *AdminUserHandler) Audit(c echo.Context) error {
+	var filter repository.AuditFilter
+	for param, dst := range map[string]**uuid.UUID{
+		"target_user_id": &filter.TargetUserID,
+		"actor_id":       &filter.ActorID,
+	} {
+		raw := c.QueryParam(param)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid " + param})
+		}
+		*dst = &id
+	}
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	entries, total, err := h.admin.Audit(c.Request().Context(), filter, pag)
+	if err != nil {
+		return adminUserError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.AdminAuditEntry]{
+		Items:  entries,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// target возвращает администратора и пользователя из пути; ошибка — готовый *echo.HTTPError
+func (h *AdminUserHandler) target(c echo.Context) (usecase.AdminActor, uuid.UUID, error) {
+	adminID, err := currentUserID(c)
+	if err != nil {
+		return usecase.AdminActor{}, uuid.Nil, echo.NewHTTPError(http.StatusUnauthorized, "auth required")
+	}
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return usecase.AdminActor{}, uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, "invalid user id")
+	}
+	return usecase.AdminActor{ID: adminID, IP: c.RealIP()}, userID, nil
+}
+
+func adminUserError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidRole),
+		errors.Is(err, usecase.ErrBanReasonRequired),
+		errors.Is(err, usecase.ErrBanUntilPast),
+		errors.Is(err, usecase.ErrImpersonationReason),
+		errors.Is(err, usecase.ErrBulkEmpty),
+		errors.Is(err, usecase.ErrBulkTooLarge),
+		errors.Is(err, usecase.ErrBulkActionUnsupported):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAdminSelfAction),
+		errors.Is(err, usecase.ErrImpersonateAdmin),
+		errors.Is(err, usecase.ErrUserBanned):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/middleware"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type APITokenHandler struct {
+	tokens *usecase.APITokenService
+}
+
+func NewAPITokenHandler(tokens *usecase.APITokenService) *APITokenHandler {
+	return &APITokenHandler{tokens: tokens}
+}
+
+// CreatedAPIToken — новый токен; открытое значение token показывается только в этом ответе
+type CreatedAPIToken struct {
+	*entity.APIToken
+	Token string `json:"token" example:"edu_pat_..."`
+}
+
+// @Summary Список персональных API-токенов
+// @Tags API Tokens
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.APIToken
+// @Failure 401 {object} map[string]string
+// @Router /me/tokens [get]
+func (h *APITokenHandler) List(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	tokens, err := h.tokens.List(c.Request().Context(), userID)
+	if err != nil {
+		return apiTokenError(c, err)
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Выпустить персональный API-токен
+// @Description Scopes — пары resource:action из ABAC (например course:create, lesson:*).
+// @Description Токен получает только те права владельца, которые перечислены в scopes.
+// @Description Значение возвращается один раз; env.mfa токена — как у текущей сессии
+// @Tags API Tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body usecase.CreateAPITokenInput true "Параметры токена"
+// @Success 201 {object} CreatedAPIToken
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /me/tokens [post]
+func (h *APITokenHandler) Create(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.CreateAPITokenInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	mfa, _ := c.Get(middleware.MFAKey).(bool)
+	token, raw, err := h.tokens.Create(c.Request().Context(), userID, mfa, req)
+	if err != nil {
+		return apiTokenError(c, err)
+	}
+	return c.JSON(http.StatusCreated, CreatedAPIToken{APIToken: token, Token: raw})
+}
+
+// @Summary Отозвать персональный API-токен
+// @Tags API Tokens
+// @Security BearerAuth
+// @Param id path string true "ID токена"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Router /me/tokens/{id} [delete]
+func (h *APITokenHandler) Revoke(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid token id"})
+	}
+	if err := h.tokens.Revoke(c.Request().Context(), userID, id); err != nil {
+		return apiTokenError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func apiTokenError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrAPITokenName),
+		errors.Is(err, usecase.ErrAPITokenScopeInvalid),
+		errors.Is(err, usecase.ErrAPITokenExpiry):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAPITokenLimit):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAPITokenNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
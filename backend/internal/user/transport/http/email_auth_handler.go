@@ -0,0 +1,262 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+	"github.com/kostinp/edu-platform-backend/internal/shared/password"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type EmailAuthHandler struct {
+	emailAuth *usecase.EmailAuthService
+	tokens    *usecase.TokenService
+	risk      *usecase.LoginRiskService
+}
+
+func NewEmailAuthHandler(emailAuth *usecase.EmailAuthService, tokens *usecase.TokenService, risk *usecase.LoginRiskService) *EmailAuthHandler {
+	return &EmailAuthHandler{
+		emailAuth: emailAuth,
+		tokens:    tokens,
+		risk:      risk,
+	}
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	FullName string `json:"full_name"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+type TokenRequest struct {
+	Token string `json:"token"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// @Summary Регистрация по email и паролю
+// @Description Создаёт пользователя и отправляет письмо для подтверждения адреса. Войти можно после подтверждения
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RegisterRequest true "Данные регистрации"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/email/register [post]
+func (h *EmailAuthHandler) Register(c echo.Context) error {
+	req := new(RegisterRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	if err := h.emailAuth.ThrottleMail(c.Request().Context(), c.RealIP(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	user, err := h.emailAuth.Register(c.Request().Context(), req.Email, req.Password, req.FullName)
+	if err != nil {
+		return emailAuthError(c, err)
+	}
+	return c.JSON(http.StatusCreated, map[string]string{
+		"user_id": user.ID.String(),
+		"status":  "verification_sent",
+	})
+}
+
+// @Summary Вход по email и паролю
+// @Description Неудачные попытки учитываются при оценке риска следующих входов; при высоком риске
+// @Description ответ 401 содержит step_up_required и challenge_id для /auth/step-up
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "Email и пароль"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/email/login [post]
+func (h *EmailAuthHandler) Login(c echo.Context) error {
+	req := new(LoginRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	if err := h.emailAuth.ThrottleLogin(c.Request().Context(), c.RealIP(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	user, err := h.emailAuth.Login(c.Request().Context(), req.Email, req.Password)
+	if errors.Is(err, usecase.ErrInvalidCredentials) {
+		if err := h.risk.RecordFailure(c.Request().Context(), req.Email, c.RealIP(), entity.LoginFailurePassword); err != nil {
+			logger.Error("Не удалось записать неудачную попытку входа", err)
+		}
+	}
+	if err != nil {
+		return emailAuthError(c, err)
+	}
+	return issueToken(c, h.tokens, user)
+}
+
+// @Summary Подтверждение email
+// @Description Принимает токен из письма, подтверждает адрес и сразу выполняет вход
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body TokenRequest true "Токен из письма"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /auth/email/verify [post]
+func (h *EmailAuthHandler) VerifyEmail(c echo.Context) error {
+	req := new(TokenRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	user, err := h.emailAuth.VerifyEmail(c.Request().Context(), req.Token)
+	if err != nil {
+		return emailAuthError(c, err)
+	}
+	return issueToken(c, h.tokens, user)
+}
+
+// @Summary Повторно отправить письмо подтверждения
+// @Description Ответ не зависит от того, зарегистрирован ли адрес
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body EmailRequest true "Email"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/email/resend-verification [post]
+func (h *EmailAuthHandler) ResendVerification(c echo.Context) error {
+	req := new(EmailRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	if err := h.emailAuth.ThrottleMail(c.Request().Context(), c.RealIP(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	if err := h.emailAuth.ResendVerification(c.Request().Context(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	return c.JSON(http.StatusAccepted, map[string]string{"status": "sent_if_registered"})
+}
+
+// @Summary Запросить сброс пароля
+// @Description Ответ не зависит от того, зарегистрирован ли адрес
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body EmailRequest true "Email"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/password/forgot [post]
+func (h *EmailAuthHandler) ForgotPassword(c echo.Context) error {
+	req := new(EmailRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	if err := h.emailAuth.ThrottleMail(c.Request().Context(), c.RealIP(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	if err := h.emailAuth.RequestPasswordReset(c.Request().Context(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	return c.JSON(http.StatusAccepted, map[string]string{"status": "sent_if_registered"})
+}
+
+// @Summary Задать новый пароль по токену из письма
+// @Description Все прежние сессии пользователя завершаются, в ответе — новый токен
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Токен и новый пароль"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /auth/password/reset [post]
+func (h *EmailAuthHandler) ResetPassword(c echo.Context) error {
+	req := new(ResetPasswordRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	user, err := h.emailAuth.ResetPassword(c.Request().Context(), req.Token, req.Password)
+	if err != nil {
+		return emailAuthError(c, err)
+	}
+	return issueToken(c, h.tokens, user)
+}
+
+// @Summary Запросить ссылку для входа без пароля
+// @Description Ответ не зависит от того, зарегистрирован ли адрес
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body EmailRequest true "Email"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/magic-link [post]
+func (h *EmailAuthHandler) RequestMagicLink(c echo.Context) error {
+	req := new(EmailRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	if err := h.emailAuth.ThrottleMail(c.Request().Context(), c.RealIP(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	if err := h.emailAuth.RequestMagicLink(c.Request().Context(), req.Email); err != nil {
+		return emailAuthError(c, err)
+	}
+	return c.JSON(http.StatusAccepted, map[string]string{"status": "sent_if_registered"})
+}
+
+// @Summary Вход по ссылке из письма
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body TokenRequest true "Токен из письма"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /auth/magic-link/verify [post]
+func (h *EmailAuthHandler) ConsumeMagicLink(c echo.Context) error {
+	req := new(TokenRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	user, err := h.emailAuth.ConsumeMagicLink(c.Request().Context(), req.Token)
+	if err != nil {
+		return emailAuthError(c, err)
+	}
+	return issueToken(c, h.tokens, user)
+}
+
+func emailAuthError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidEmail), errors.Is(err, usecase.ErrTokenInvalid),
+		errors.Is(err, password.ErrTooShort), errors.Is(err, password.ErrTooLong):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrEmailTaken):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidCredentials):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrEmailNotVerified):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrTooManyRequests):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"net/http"
+
+	"github.com/kostinp/edu-platform-backend/internal/shared/jwtkeys"
+	"github.com/labstack/echo/v4"
+)
+
+type JWKSHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+func NewJWKSHandler(keys *jwtkeys.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// @Summary Открытые ключи подписи access-токенов (JWKS)
+// @Description Содержит активный ключ, ключи, ожидающие активации после ротации, и ещё не удалённые старые.
+// @Description Пустой список, если токены подписываются общим секретом (HS256)
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwtkeys.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(c echo.Context) error {
+	// Новый ключ публикуется минимум за 10 минут до активации (cmd/jwtkeys rotate)
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -0,0 +1,151 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type MergeHandler struct {
+	merges *usecase.MergeService
+	tokens *usecase.TokenService
+}
+
+func NewMergeHandler(merges *usecase.MergeService, tokens *usecase.TokenService) *MergeHandler {
+	return &MergeHandler{merges: merges, tokens: tokens}
+}
+
+// MergeOwnRequest — токен второго аккаунта, который нужно присоединить к текущему
+type MergeOwnRequest struct {
+	Token string `json:"token"`
+}
+
+// AdminMergeRequest — какой аккаунт (source) присоединить к какому (target)
+type AdminMergeRequest struct {
+	SourceUserID string `json:"source_user_id"`
+	TargetUserID string `json:"target_user_id"`
+}
+
+// @Summary Присоединить свой второй аккаунт
+// @Description Сессии, прогресс, закладки, заметки, отзывы и авторский контент второго аккаунта
+// @Description переносятся в текущий; второй аккаунт удаляется. Владение подтверждается его access-токеном
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body MergeOwnRequest true "Токен второго аккаунта"
+// @Success 200 {object} entity.AccountMerge
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/merge [post]
+func (h *MergeHandler) MergeOwn(c echo.Context) error {
+	targetID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	req := new(MergeOwnRequest)
+	if err := c.Bind(req); err != nil || req.Token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "token is required"})
+	}
+	claims, err := h.tokens.ParseAccessToken(strings.TrimPrefix(req.Token, "Bearer "))
+	if err != nil {
+		return mergeError(c, usecase.ErrMergeTokenInvalid)
+	}
+	merge, err := h.merges.MergeOwnAccount(c.Request().Context(), targetID, claims.UserID, claims.SessionID)
+	if err != nil {
+		return mergeError(c, err)
+	}
+	return c.JSON(http.StatusOK, merge)
+}
+
+// @Summary История слияний моего аккаунта
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.AccountMerge
+// @Router /me/merges [get]
+func (h *MergeHandler) ListMine(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	items, err := h.merges.ListByUser(c.Request().Context(), userID)
+	if err != nil {
+		return mergeError(c, err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// @Summary Объединить аккаунты (администратор)
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body AdminMergeRequest true "Источник и цель"
+// @Success 200 {object} entity.AccountMerge
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/merge [post]
+func (h *MergeHandler) AdminMerge(c echo.Context) error {
+	adminID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	req := new(AdminMergeRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
+	}
+	sourceID, err := uuid.Parse(req.SourceUserID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid source_user_id"})
+	}
+	targetID, err := uuid.Parse(req.TargetUserID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid target_user_id"})
+	}
+	merge, err := h.merges.Merge(c.Request().Context(), sourceID, targetID, entity.MergeReasonAdmin, &adminID)
+	if err != nil {
+		return mergeError(c, err)
+	}
+	return c.JSON(http.StatusOK, merge)
+}
+
+// @Summary История слияний пользователя (администратор)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID пользователя"
+// @Success 200 {array} entity.AccountMerge
+// @Failure 400 {object} map[string]string
+// @Router /admin/users/{id}/merges [get]
+func (h *MergeHandler) AdminList(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user id"})
+	}
+	items, err := h.merges.ListByUser(c.Request().Context(), userID)
+	if err != nil {
+		return mergeError(c, err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+func mergeError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrMergeSameUser):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrMergeTokenInvalid):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrEmailTaken):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось объединить аккаунты"})
+}
@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type MFAHandler struct {
+	mfa *usecase.MFAService
+}
+
+func NewMFAHandler(mfa *usecase.MFAService) *MFAHandler {
+	return &MFAHandler{mfa: mfa}
+}
+
+// MFACodeRequest — код из приложения-аутентификатора или код восстановления
+type MFACodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+// @Summary Состояние двухфакторной аутентификации
+// @Tags MFA
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} usecase.MFAStatus
+// @Failure 401 {object} map[string]string
+// @Router /me/mfa [get]
+func (h *MFAHandler) Status(c echo.Context) error {
+	userID, sessionID, err := currentSession(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	status, err := h.mfa.Status(c.Request().Context(), userID, sessionID)
+	if err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// @Summary Начать подключение TOTP
+// @Description Возвращает секрет и ссылку otpauth:// для QR-кода. 2FA включится после подтверждения кодом
+// @Tags MFA
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} usecase.TOTPEnrollment
+// @Failure 409 {object} map[string]string
+// @Router /me/mfa/totp [post]
+func (h *MFAHandler) BeginTOTP(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	enrollment, err := h.mfa.BeginTOTP(c.Request().Context(), userID)
+	if err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, enrollment)
+}
+
+// @Summary Подтвердить подключение TOTP
+// @Description Включает 2FA по первому коду из приложения и возвращает 10 кодов восстановления.
+// @Description Коды показываются один раз; текущая сессия считается прошедшей 2FA
+// @Tags MFA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "Код из приложения"
+// @Success 200 {object} map[string][]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /me/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTP(c echo.Context) error {
+	userID, sessionID, err := currentSession(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	req := new(MFACodeRequest)
+	if err := c.Bind(req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+	codes, err := h.mfa.ConfirmTOTP(c.Request().Context(), userID, sessionID, req.Code)
+	if err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string][]string{"recovery_codes": codes})
+}
+
+// @Summary Подтвердить текущую сессию вторым фактором
+// @Description Нужно для сессий, открытых до включения 2FA: действия с условием env.mfa станут доступны
+// @Tags MFA
+// @Security BearerAuth
+// @Accept json
+// @Param request body MFACodeRequest true "Код из приложения или код восстановления"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /me/mfa/verify [post]
+func (h *MFAHandler) VerifySession(c echo.Context) error {
+	userID, sessionID, err := currentSession(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	req := new(MFACodeRequest)
+	if err := c.Bind(req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+	if err := h.mfa.VerifySession(c.Request().Context(), userID, sessionID, req.Code); err != nil {
+		return mfaError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Выпустить новые коды восстановления
+// @Description Старые коды перестают действовать
+// @Tags MFA
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "Код из приложения или код восстановления"
+// @Success 200 {object} map[string][]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	req := new(MFACodeRequest)
+	if err := c.Bind(req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+	codes, err := h.mfa.RegenerateRecoveryCodes(c.Request().Context(), userID, req.Code)
+	if err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string][]string{"recovery_codes": codes})
+}
+
+// @Summary Отключить двухфакторную аутентификацию
+// @Tags MFA
+// @Security BearerAuth
+// @Accept json
+// @Param request body MFACodeRequest true "Код из приложения или код восстановления"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /me/mfa [delete]
+func (h *MFAHandler) Disable(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	req := new(MFACodeRequest)
+	if err := c.Bind(req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+	if err := h.mfa.Disable(c.Request().Context(), userID, req.Code); err != nil {
+		return mfaError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// currentSession — пользователь и сессия, с которой пришёл запрос
+func currentSession(c echo.Context) (uuid.UUID, uuid.UUID, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	sessionIDStr, _ := c.Get("session_id").(string)
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return userID, sessionID, nil
+}
+
+func mfaError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrMFACodeInvalid):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrMFAAlreadyEnabled), errors.Is(err, usecase.ErrMFANotEnabled):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrMFALocked):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
@@ -0,0 +1,238 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/oidc"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type OIDCHandler struct {
+	oidc   *usecase.OIDCService
+	tokens *usecase.TokenService
+}
+
+func NewOIDCHandler(oidcService *usecase.OIDCService, tokens *usecase.TokenService) *OIDCHandler {
+	return &OIDCHandler{
+		oidc:   oidcService,
+		tokens: tokens,
+	}
+}
+
+// oidcBindingCookie привязывает state к браузеру, который начал вход
+const oidcBindingCookie = "oidc_binding"
+
+// OIDCCallbackRequest — параметры, с которыми провайдер вернул пользователя на фронтенд
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// @Summary Доступные провайдеры входа
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} map[string][]string
+// @Router /auth/oidc/providers [get]
+func (h *OIDCHandler) Providers(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string][]string{"providers": h.oidc.Providers()})
+}
+
+// @Summary Начать вход через внешнего провайдера
+// @Description Возвращает адрес страницы входа провайдера (authorization code + PKCE).
+// @Description После входа провайдер вернёт пользователя на фронтенд с code и state.
+// @Description Ставит HttpOnly-cookie oidc_binding, без которой callback не примет state
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Имя провайдера"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /auth/oidc/{provider}/authorize [get]
+func (h *OIDCHandler) Authorize(c echo.Context) error {
+	authURL, binding, err := h.oidc.Authorize(c.Request().Context(), c.Param("provider"), nil)
+	if err != nil {
+		return oidcError(c, err)
+	}
+	setOIDCBinding(c, binding)
+	return c.JSON(http.StatusOK, map[string]string{"authorization_url": authURL})
+}
+
+// @Summary Завершить вход через внешнего провайдера
+// @Description Возвращает токен, как /telegram/auth. Запрос должен нести cookie oidc_binding из authorize
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Имя провайдера"
+// @Param request body OIDCCallbackRequest true "code и state"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/oidc/{provider}/callback [post]
+func (h *OIDCHandler) Callback(c echo.Context) error {
+	result, err := h.callback(c, nil)
+	if err != nil {
+		return oidcError(c, err)
+	}
+	return issueToken(c, h.tokens, result.User)
+}
+
+// @Summary Завершить привязку внешнего аккаунта
+// @Description Завершает привязку, начатую через POST /me/identities/{provider}, тем же пользователем.
+// @Description Запрос должен нести cookie oidc_binding
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Имя провайдера"
+// @Param request body OIDCCallbackRequest true "code и state"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/identities/{provider}/callback [post]
+func (h *OIDCHandler) LinkCallback(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	result, err := h.callback(c, &userID)
+	if err != nil {
+		return oidcError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"linked": true, "identity": result.Identity})
+}
+
+func (h *OIDCHandler) callback(c echo.Context, callerID *uuid.UUID) (*usecase.OIDCResult, error) {
+	req := new(OIDCCallbackRequest)
+	if err := c.Bind(req); err != nil || req.Code == "" || req.State == "" {
+		return nil, errOIDCBadRequest
+	}
+	binding := ""
+	if cookie, err := c.Cookie(oidcBindingCookie); err == nil {
+		binding = cookie.Value
+	}
+	// cookie одноразовая, как и state
+	clearOIDCBinding(c)
+	return h.oidc.Callback(c.Request().Context(), c.Param("provider"), req.Code, req.State, binding, callerID)
+}
+
+// @Summary Мои привязанные внешние аккаунты
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.Identity
+// @Failure 500 {object} map[string]string
+// @Router /me/identities [get]
+func (h *OIDCHandler) ListIdentities(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	items, err := h.oidc.ListIdentities(c.Request().Context(), userID)
+	if err != nil {
+		return oidcError(c, err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// @Summary Привязать внешний аккаунт
+// @Description Возвращает адрес страницы входа провайдера; привязка завершается через /me/identities/{provider}/callback
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param provider path string true "Имя провайдера"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/identities/{provider} [post]
+func (h *OIDCHandler) Link(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	authURL, binding, err := h.oidc.Authorize(c.Request().Context(), c.Param("provider"), &userID)
+	if err != nil {
+		return oidcError(c, err)
+	}
+	setOIDCBinding(c, binding)
+	return c.JSON(http.StatusOK, map[string]string{"authorization_url": authURL})
+}
+
+// @Summary Отвязать внешний аккаунт
+// @Description Нельзя отвязать единственный способ входа
+// @Tags Auth
+// @Security BearerAuth
+// @Param provider path string true "Имя провайдера"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/identities/{provider} [delete]
+func (h *OIDCHandler) Unlink(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	if err := h.oidc.Unlink(c.Request().Context(), userID, c.Param("provider")); err != nil {
+		return oidcError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+var errOIDCBadRequest = errors.New("code and state are required")
+
+func setOIDCBinding(c echo.Context, binding string) {
+	c.SetCookie(&http.Cookie{
+		Name:     oidcBindingCookie,
+		Value:    binding,
+		Path:     "/api",
+		MaxAge:   int(usecase.OIDCStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearOIDCBinding(c echo.Context) {
+	c.SetCookie(&http.Cookie{
+		Name:     oidcBindingCookie,
+		Path:     "/api",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func currentUserID(c echo.Context) (uuid.UUID, error) {
+	userIDStr, ok := c.Get("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user not found")
+	}
+	return uuid.Parse(userIDStr)
+}
+
+func oidcError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errOIDCBadRequest):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUnknownProvider), errors.Is(err, usecase.ErrIdentityNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrOIDCStateInvalid):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrLinkUserMismatch):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, oidc.ErrInvalidIDToken), errors.Is(err, oidc.ErrNonceMismatch), errors.Is(err, oidc.ErrExchange):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "external sign-in failed"})
+	case errors.Is(err, usecase.ErrIdentityLinkedElsewhere), errors.Is(err, usecase.ErrProviderAlreadyLinked),
+		errors.Is(err, usecase.ErrEmailBelongsToAccount), errors.Is(err, usecase.ErrLastLoginMethod):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, oidc.ErrDiscovery):
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": "identity provider unavailable"})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить вход"})
+}
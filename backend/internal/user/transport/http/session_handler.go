@@ -1,6 +1,8 @@
 package transport
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -21,7 +23,7 @@ func NewSessionHandler(sessionUC usecase.SessionUsecase) *SessionHandler {
 // SetInactivityTimeoutRequest — структура запроса для установки таймаута бездействия.
 // @Description Таймаут в секундах.
 type SetInactivityTimeoutRequest struct {
-	// Таймаут в секундах
+	// Таймаут в секундах: 0 — отключён, иначе не меньше 300
 	TimeoutSeconds int64 `json:"timeout_seconds" example:"3600"`
 }
 
@@ -46,22 +48,26 @@ func (h *SessionHandler) ListSessions(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
 	}
 
+	currentID, _ := c.Get("session_id").(string)
 	sessions := make([]entity.UserSession, 0, len(rawSessions))
 	for _, s := range rawSessions {
+		s.IsCurrent = s.ID.String() == currentID
 		sessions = append(sessions, *s) // разыменовываем указатель
 	}
 
 	return c.JSON(http.StatusOK, sessions)
 }
 
-// DeleteSession удаляет указанную сессию пользователя
-// @Summary Удалить сессию по ID
+// DeleteSession завершает указанную сессию пользователя (в том числе текущую — выход)
+// @Summary Завершить сессию по ID
+// @Description Сессия отзывается, а не удаляется: её access- и refresh-токены перестают приниматься
 // @Tags Session
 // @Security BearerAuth
 // @Param id path string true "ID сессии"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /me/sessions/{id} [delete]
 func (h *SessionHandler) DeleteSession(c echo.Context) error {
@@ -77,7 +83,10 @@ func (h *SessionHandler) DeleteSession(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid session id"})
 	}
 
-	err = h.SessionUsecase.DeleteSession(c.Request().Context(), userID, sessionID)
+	err = h.SessionUsecase.RevokeSession(c.Request().Context(), userID, sessionID, entity.RevokeReasonLogout)
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "session not found"})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete session"})
 	}
@@ -85,6 +94,58 @@ func (h *SessionHandler) DeleteSession(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// LogoutOthers завершает все сессии пользователя, кроме текущей
+// @Summary Выйти на всех других устройствах
+// @Tags Session
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /me/sessions/logout-others [post]
+func (h *SessionHandler) LogoutOthers(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+	sessionIDStr, _ := c.Get("session_id").(string)
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "session not found"})
+	}
+
+	revoked, err := h.SessionUsecase.RevokeOtherSessions(c.Request().Context(), userID, sessionID)
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "session not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke sessions"})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"revoked": revoked})
+}
+
+// LogoutAll завершает все сессии пользователя, включая текущую
+// @Summary Выйти на всех устройствах
+// @Tags Session
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /me/sessions/logout-all [post]
+func (h *SessionHandler) LogoutAll(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
+	}
+
+	revoked, err := h.SessionUsecase.RevokeAllSessions(c.Request().Context(), userID, entity.RevokeReasonLogoutAll)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke sessions"})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"revoked": revoked})
+}
+
 // SetInactivityTimeout устанавливает таймаут неактивности для пользователя
 // @Summary Установить таймаут неактивности
 // @Tags Session
@@ -119,6 +180,11 @@ func (h *SessionHandler) SetInactivityTimeout(c echo.Context) error {
 	}
 
 	err = h.SessionUsecase.SetInactivityTimeout(c.Request().Context(), userID, timeout)
+	if errors.Is(err, usecase.ErrTimeoutTooShort) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("timeout must be 0 or at least %d seconds", int64(usecase.MinInactivityTimeout.Seconds())),
+		})
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to set inactivity timeout"})
 	}
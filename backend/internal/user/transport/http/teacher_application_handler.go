@@ -0,0 +1,219 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/dto"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type TeacherApplicationHandler struct {
+	applications *usecase.TeacherApplicationService
+}
+
+func NewTeacherApplicationHandler(applications *usecase.TeacherApplicationService) *TeacherApplicationHandler {
+	return &TeacherApplicationHandler{applications: applications}
+}
+
+// @Summary Подать заявку на роль преподавателя
+// @Description Доступно студентам. Пока заявка на рассмотрении, новую подать нельзя;
+// @Description о решении придёт письмо и сообщение в Telegram
+// @Tags Teacher Applications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body usecase.ApplyTeacherInput true "Заявка"
+// @Success 201 {object} entity.TeacherApplication
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/teacher-applications [post]
+func (h *TeacherApplicationHandler) Apply(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	var req usecase.ApplyTeacherInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	app, err := h.applications.Apply(c.Request().Context(), userID, req)
+	if err != nil {
+		return teacherApplicationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, app)
+}
+
+// @Summary Мои заявки на роль преподавателя
+// @Description Вся история заявок с решениями и комментариями, новые первыми
+// @Tags Teacher Applications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} entity.TeacherApplication
+// @Failure 401 {object} map[string]string
+// @Router /me/teacher-applications [get]
+func (h *TeacherApplicationHandler) ListMine(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	apps, err := h.applications.ListMine(c.Request().Context(), userID)
+	if err != nil {
+		return teacherApplicationError(c, err)
+	}
+	return c.JSON(http.StatusOK, apps)
+}
+
+// @Summary Отозвать заявку
+// @Description Только заявку, которая ещё на рассмотрении
+// @Tags Teacher Applications
+// @Security BearerAuth
+// @Param id path string true "ID заявки"
+// @Success 204 {string} string "No Content"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/teacher-applications/{id} [delete]
+func (h *TeacherApplicationHandler) Withdraw(c echo.Context) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid application id"})
+	}
+	if err := h.applications.Withdraw(c.Request().Context(), userID, id); err != nil {
+		return teacherApplicationError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Заявки на роль преподавателя
+// @Description По умолчанию — все, от старых к новым; status=pending — очередь на рассмотрение
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "pending, approved, rejected, withdrawn"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param sort_by query string false "created_at, reviewed_at"
+// @Param order query string false "asc / desc"
+// @Success 200 {object} dto.PaginatedResponse[*entity.TeacherApplication]
+// @Failure 400 {object} map[string]string
+// @Router /admin/teacher-applications [get]
+func (h *TeacherApplicationHandler) List(c echo.Context) error {
+	pag := pagination.ParsePaginationParams(c).ToDomainParams()
+	status := entity.TeacherApplicationStatus(c.QueryParam("status"))
+	apps, total, err := h.applications.List(c.Request().Context(), status, pag)
+	if err != nil {
+		return teacherApplicationError(c, err)
+	}
+	return c.JSON(http.StatusOK, dto.PaginatedResponse[*entity.TeacherApplication]{
+		Items:  apps,
+		Total:  total,
+		Limit:  pag.Limit,
+		Offset: pag.Offset,
+	})
+}
+
+// @Summary Заявка на роль преподавателя
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID заявки"
+// @Success 200 {object} entity.TeacherApplication
+// @Failure 404 {object} map[string]string
+// @Router /admin/teacher-applications/{id} [get]
+func (h *TeacherApplicationHandler) Get(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid application id"})
+	}
+	app, err := h.applications.Get(c.Request().Context(), id)
+	if err != nil {
+		return teacherApplicationError(c, err)
+	}
+	return c.JSON(http.StatusOK, app)
+}
+
+// @Summary Одобрить заявку
+// @Description Пользователь получает роль teacher; решение попадает в журнал действий администраторов
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID заявки"
+// @Param request body usecase.ReviewTeacherInput false "Комментарий"
+// @Success 200 {object} entity.TeacherApplication
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/teacher-applications/{id}/approve [post]
+func (h *TeacherApplicationHandler) Approve(c echo.Context) error {
+	return h.review(c, h.applications.Approve)
+}
+
+// @Summary Отклонить заявку
+// @Description Комментарий обязателен: его увидит заявитель
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID заявки"
+// @Param request body usecase.ReviewTeacherInput true "Комментарий"
+// @Success 200 {object} entity.TeacherApplication
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/teacher-applications/{id}/reject [post]
+func (h *TeacherApplicationHandler) Reject(c echo.Context) error {
+	return h.review(c, h.applications.Reject)
+}
+
+type teacherReviewFunc func(ctx context.Context, actor usecase.AdminActor, id uuid.UUID, in usecase.ReviewTeacherInput) (*entity.TeacherApplication, error)
+
+func (h *TeacherApplicationHandler) review(c echo.Context, decide teacherReviewFunc) error {
+	adminID, err := currentUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "auth required"})
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid application id"})
+	}
+	var req usecase.ReviewTeacherInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	app, err := decide(c.Request().Context(), usecase.AdminActor{ID: adminID, IP: c.RealIP()}, id, req)
+	if err != nil {
+		return teacherApplicationError(c, err)
+	}
+	return c.JSON(http.StatusOK, app)
+}
+
+func teacherApplicationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrTeacherApplicationBio),
+		errors.Is(err, usecase.ErrTeacherApplicationLinks),
+		errors.Is(err, usecase.ErrTeacherApplicationStatus),
+		errors.Is(err, usecase.ErrReviewCommentRequired),
+		errors.Is(err, usecase.ErrReviewCommentTooLong):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrTeacherApplicationRole),
+		errors.Is(err, usecase.ErrAdminSelfAction):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrTeacherApplicationNotFound),
+		errors.Is(err, usecase.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrTeacherApplicationPending),
+		errors.Is(err, usecase.ErrTeacherApplicationNotPending):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось выполнить запрос"})
+}
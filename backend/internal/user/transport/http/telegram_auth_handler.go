@@ -1,33 +1,46 @@
 package transport
 
 import (
-	"net"
+	"errors"
 	"net/http"
-	"time"
+	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/kostinp/edu-platform-backend/internal/shared/config"
-	"github.com/kostinp/edu-platform-backend/internal/shared/geo"
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
 	"github.com/kostinp/edu-platform-backend/internal/shared/telegram"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
 	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
 	"github.com/labstack/echo/v4"
 )
 
 type TelegramAuthHandler struct {
 	userService *usecase.UserService
-	botToken    string
-	jwtSecret   []byte
+	verifier    *telegram.LoginVerifier
+	merges      *usecase.MergeService
+	tokens      *usecase.TokenService
+	risk        *usecase.LoginRiskService
 }
 
-func NewTelegramAuthHandler(userService *usecase.UserService, botToken config.BotToken, jwtSecret config.JwtSecret) *TelegramAuthHandler {
+func NewTelegramAuthHandler(
+	userService *usecase.UserService,
+	verifier *telegram.LoginVerifier,
+	merges *usecase.MergeService,
+	tokens *usecase.TokenService,
+	risk *usecase.LoginRiskService,
+) *TelegramAuthHandler {
 	return &TelegramAuthHandler{
 		userService: userService,
-		botToken:    string(botToken),
-		jwtSecret:   []byte(jwtSecret),
+		verifier:    verifier,
+		merges:      merges,
+		tokens:      tokens,
+		risk:        risk,
 	}
 }
 
 // @Summary Авторизация через Telegram
+// @Description Принимает все поля Login Widget (id, first_name, last_name, username, photo_url, auth_date, hash).
+// @Description Данные старше telegram.auth_max_age_seconds и повторно использованный hash отклоняются.
+// @Description Если запрос пришёл с токеном гостя, гость становится этим пользователем Telegram,
+// @Description а если пользователь с таким Telegram уже есть — данные гостя переносятся в него
 // @Tags Telegram
 // @Produce json
 // @Success 200 {object} map[string]interface{}
@@ -36,63 +49,166 @@ func NewTelegramAuthHandler(userService *usecase.UserService, botToken config.Bo
 // @Router /telegram/auth [post]
 func (h *TelegramAuthHandler) Auth(c echo.Context) error {
 	authData := telegram.ParseTelegramAuth(c.Request())
+	ctx := c.Request().Context()
 
-	if !telegram.VerifyTelegramAuth(authData, h.botToken) {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid telegram auth"})
+	if err := h.verifier.Verify(ctx, authData); err != nil {
+		h.recordFailure(c, err)
+		return telegramAuthError(c, err)
 	}
 
-	ctx := c.Request().Context()
-
+	guest := h.currentGuest(c)
 	user, err := h.userService.GetByTelegramID(ctx, authData.ID)
-	if err != nil {
+	notFound := errors.Is(err, usecase.ErrUserNotFound)
+	switch {
+	case err != nil && !notFound:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось найти пользователя"})
+	case notFound && guest != nil:
+		user, err = h.userService.UpgradeToTelegramUser(ctx, guest.ID, authData.ID, authData.Username, authData.FullName())
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось обновить пользователя"})
+		}
+	case notFound:
 		user, err = h.userService.CreateFromTelegramAuth(ctx, authData)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось создать пользователя"})
 		}
+	case guest != nil && guest.ID != user.ID:
+		if _, err := h.merges.Merge(ctx, guest.ID, user.ID, entity.MergeReasonTelegramLogin, nil); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось перенести данные гостя"})
+		}
 	}
 
-	// Получаем данные сессии
-	ip := getIP(c)
-	userAgent := c.Request().UserAgent()
-	country, city := geo.Lookup(ip)
+	return issueToken(c, h.tokens, user)
+}
 
-	// Создаем сессию пользователя в БД
-	session, err := h.userService.CreateUserSession(ctx, user.ID, ip, userAgent, country, city)
+// currentGuest — гость, от имени которого пришёл запрос (необязательный Bearer-токен)
+func (h *TelegramAuthHandler) currentGuest(c echo.Context) *entity.User {
+	authHeader := c.Request().Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil
+	}
+	claims, err := h.tokens.ParseAccessToken(strings.TrimPrefix(authHeader, "Bearer "))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось создать сессию"})
+		return nil
 	}
+	ctx := c.Request().Context()
+	if err := h.merges.CheckSession(ctx, claims.UserID, claims.SessionID); err != nil {
+		return nil
+	}
+	user, err := h.userService.GetUserByID(ctx, claims.UserID)
+	if err != nil || user.Role != entity.RoleGuest {
+		return nil
+	}
+	return user
+}
 
-	// По умолчанию — 6 месяцев
-	expiration := time.Now().AddDate(0, 6, 0)
+// WebAppAuthRequest — строка Telegram.WebApp.initData как есть
+type WebAppAuthRequest struct {
+	InitData string `json:"init_data" form:"init_data"`
+}
 
-	// Генерация JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":    user.ID.String(),
-		"session_id": session.ID.String(),
-		"exp":        expiration.Unix(),
-		"iat":        time.Now().Unix(),
-	})
+// @Summary Авторизация в Telegram Mini App
+// @Description Проверяет initData Mini App (ключ HMAC от "WebAppData"), создаёт или обновляет пользователя
+// @Description и возвращает тот же ответ, что и /telegram/auth
+// @Tags Telegram
+// @Accept json
+// @Produce json
+// @Param request body WebAppAuthRequest true "initData"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /telegram/webapp/auth [post]
+func (h *TelegramAuthHandler) WebAppAuth(c echo.Context) error {
+	req := new(WebAppAuthRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	initData, err := telegram.ParseWebAppInitData(req.InitData)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	ctx := c.Request().Context()
 
-	tokenString, err := token.SignedString(h.jwtSecret)
+	if err := h.verifier.VerifyWebApp(ctx, initData); err != nil {
+		h.recordFailure(c, err)
+		return telegramAuthError(c, err)
+	}
+
+	user, err := h.userService.UpsertFromTelegramAuth(ctx, initData.AuthData())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось создать пользователя"})
+	}
+
+	return issueToken(c, h.tokens, user)
+}
+
+// issueToken создаёт сессию и пару токенов — общий ответ для всех способов входа.
+// Вход с высоким риском возвращает 401 с step_up_required: клиент должен
+// отправить код из письма или Telegram в /auth/step-up
+func issueToken(c echo.Context, tokens *usecase.TokenService, user *entity.User) error {
+	pair, err := tokens.Issue(c.Request().Context(), user.ID, sessionMeta(c))
+	var stepUp *usecase.StepUpRequiredError
+	if errors.As(err, &stepUp) {
+		return c.JSON(http.StatusUnauthorized, map[string]any{
+			"error":            stepUp.Error(),
+			"step_up_required": true,
+			"challenge_id":     stepUp.ChallengeID.String(),
+			"channel":          stepUp.Channel,
+			"expires_at":       stepUp.ExpiresAt,
+		})
+	}
+	if errors.Is(err, usecase.ErrUserBanned) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось создать токен"})
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось создать сессию"})
 	}
+	return tokenResponse(c, pair, user)
+}
 
+func tokenResponse(c echo.Context, pair *usecase.TokenPair, user *entity.User) error {
 	return c.JSON(http.StatusOK, map[string]any{
-		"token":      tokenString,
-		"user_id":    user.ID.String(),
-		"session_id": session.ID.String(),
-		"username":   user.Username,
-		"full_name":  user.FullName,
-		"role":       user.Role,
+		"token":              pair.AccessToken,
+		"expires_at":         pair.AccessExpiresAt,
+		"refresh_token":      pair.RefreshToken,
+		"refresh_expires_at": pair.RefreshExpiresAt,
+		"user_id":            user.ID.String(),
+		"session_id":         pair.Session.ID.String(),
+		"username":           user.Username,
+		"full_name":          user.FullName,
+		"role":               user.Role,
 	})
 }
 
-// Получение IP-адреса из запроса
-func getIP(c echo.Context) string {
-	ip := c.Request().Header.Get("X-Forwarded-For")
-	if ip == "" {
-		ip, _, _ = net.SplitHostPort(c.Request().RemoteAddr)
+// sessionMeta — данные клиента для новой сессии; страну и город определяет TokenService
+func sessionMeta(c echo.Context) usecase.SessionMeta {
+	return usecase.SessionMeta{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+}
+
+// recordFailure учитывает поддельные и повторные данные входа. Пользователь из
+// непроверенных данных не определяется: иначе чужими запросами можно было бы
+// испортить историю входов любого аккаунта
+func (h *TelegramAuthHandler) recordFailure(c echo.Context, err error) {
+	if !errors.Is(err, telegram.ErrInvalidHash) && !errors.Is(err, telegram.ErrAuthReplayed) {
+		return
+	}
+	if err := h.risk.RecordFailure(c.Request().Context(), "", c.RealIP(), entity.LoginFailureTelegram); err != nil {
+		logger.Error("Не удалось записать неудачную попытку входа", err)
+	}
+}
+
+func telegramAuthError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, telegram.ErrAuthExpired), errors.Is(err, telegram.ErrAuthDateMissing):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "telegram auth expired"})
+	case errors.Is(err, telegram.ErrAuthReplayed):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "telegram auth already used"})
+	case errors.Is(err, telegram.ErrInvalidHash):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid telegram auth"})
 	}
-	return ip
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось проверить авторизацию"})
 }
@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type TokenHandler struct {
+	tokens      *usecase.TokenService
+	userService *usecase.UserService
+}
+
+func NewTokenHandler(tokens *usecase.TokenService, userService *usecase.UserService) *TokenHandler {
+	return &TokenHandler{tokens: tokens, userService: userService}
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// @Summary Обновить access-токен
+// @Description Обменивает refresh-токен на новую пару токенов; старый refresh-токен становится недействительным.
+// @Description Повторное использование уже обменянного refresh-токена отзывает всю цепочку сессии
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh-токен"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/refresh [post]
+func (h *TokenHandler) Refresh(c echo.Context) error {
+	req := new(RefreshRequest)
+	if err := c.Bind(req); err != nil || req.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "refresh_token is required"})
+	}
+	pair, err := h.tokens.Refresh(c.Request().Context(), req.RefreshToken, sessionMeta(c))
+	switch {
+	case errors.Is(err, usecase.ErrRefreshTokenInvalid), errors.Is(err, usecase.ErrRefreshTokenReused):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserBanned):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось обновить токен"})
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"token":              pair.AccessToken,
+		"expires_at":         pair.AccessExpiresAt,
+		"refresh_token":      pair.RefreshToken,
+		"refresh_expires_at": pair.RefreshExpiresAt,
+		"user_id":            pair.Session.UserID.String(),
+		"session_id":         pair.Session.ID.String(),
+	})
+}
+
+type StepUpRequest struct {
+	ChallengeID string `json:"challenge_id"`
+	Code        string `json:"code"`
+}
+
+// @Summary Подтвердить вход кодом
+// @Description Завершает вход, для которого /auth/email/login, /telegram/auth и другие способы входа
+// @Description вернули step_up_required: код отправлен на подтверждённый email или в Telegram.
+// @Description Если у пользователя включена 2FA (channel = totp), нужен код из приложения-аутентификатора
+// @Description или код восстановления. Код действует 10 минут, после 5 неверных попыток нужно войти заново
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body StepUpRequest true "ID запроса и код"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/step-up [post]
+func (h *TokenHandler) StepUp(c echo.Context) error {
+	req := new(StepUpRequest)
+	if err := c.Bind(req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "challenge_id and code are required"})
+	}
+	challengeID, err := uuid.Parse(req.ChallengeID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid challenge_id"})
+	}
+	ctx := c.Request().Context()
+	pair, err := h.tokens.CompleteStepUp(ctx, challengeID, req.Code, sessionMeta(c))
+	switch {
+	case errors.Is(err, usecase.ErrStepUpCodeInvalid):
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrMFALocked):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserBanned):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось создать сессию"})
+	}
+	user, err := h.userService.GetUserByID(ctx, pair.Session.UserID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "не удалось получить пользователя"})
+	}
+	return tokenResponse(c, pair, user)
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/password"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrProfileName     = errors.New("full name must be at most 100 characters")
+	ErrProfilePhotoURL = errors.New("photo url must be an absolute https url")
+	ErrEmailRequired   = errors.New("email cannot be removed, only changed")
+)
+
+const (
+	profileMaxName     = 100
+	profileMaxPhotoURL = 2048
+)
+
+// UpdateProfileInput — изменяемые поля профиля; отсутствующее поле не меняется
+type UpdateProfileInput struct {
+	FullName *string `json:"full_name,omitempty" example:"Иван Петров"`
+	// Пустая строка убирает фото
+	PhotoURL *string `json:"photo_url,omitempty" example:"https://example.com/me.jpg"`
+	// Новый адрес нужно подтвердить по ссылке из письма
+	Email           *string `json:"email,omitempty" example:"ivan@example.com"`
+	SubscribeToNews *bool   `json:"subscribe_to_newsletter,omitempty" example:"true"`
+}
+
+// DeleteAccountInput — подтверждение удаления аккаунта
+type DeleteAccountInput struct {
+	// Обязателен, если у аккаунта есть пароль
+	Password string `json:"password,omitempty"`
+	// Код 2FA или код восстановления; обязателен, если 2FA включена
+	Code string `json:"code,omitempty"`
+}
+
+// ExportManifest — описание архива с данными пользователя (manifest.json)
+type ExportManifest struct {
+	UserID      uuid.UUID `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+	// Разделы, которые не удалось выгрузить (например, недоступна аналитика)
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// AccountService — профиль пользователя, выгрузка его данных и удаление аккаунта
+type AccountService struct {
+	repo      repository.AccountRepository
+	users     CredentialsRepository
+	events    repository.VisitorEventRepository
+	sessionUC SessionUsecase
+	mfa       *MFAService
+	emailAuth *EmailAuthService
+}
+
+func NewAccountService(
+	repo repository.AccountRepository,
+	users CredentialsRepository,
+	events repository.VisitorEventRepository,
+	sessionUC SessionUsecase,
+	mfa *MFAService,
+	emailAuth *EmailAuthService,
+) *AccountService {
+	return &AccountService{
+		repo:      repo,
+		users:     users,
+		events:    events,
+		sessionUC: sessionUC,
+		mfa:       mfa,
+		emailAuth: emailAuth,
+	}
+}
+
+func (s *AccountService) Profile(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	return s.users.GetByID(ctx, userID)
+}
+
+// UpdateProfile меняет профиль. При смене email адрес теряет подтверждение
+// и на новый отправляется письмо со ссылкой
+func (s *AccountService) UpdateProfile(ctx context.Context, userID uuid.UUID, in UpdateProfileInput) (*entity.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if in.FullName != nil {
+		name := strings.TrimSpace(*in.FullName)
+		if utf8.RuneCountInString(name) > profileMaxName {
+			return nil, ErrProfileName
+		}
+		user.FullName = &name
+	}
+	if in.PhotoURL != nil {
+		photo := strings.TrimSpace(*in.PhotoURL)
+		switch {
+		case photo == "":
+			user.PhotoURL = nil
+		case !validPhotoURL(photo):
+			return nil, ErrProfilePhotoURL
+		default:
+			user.PhotoURL = &photo
+		}
+	}
+	emailChanged := false
+	if in.Email != nil {
+		if strings.TrimSpace(*in.Email) == "" {
+			if user.Email != nil {
+				return nil, ErrEmailRequired
+			}
+		} else {
+			email, err := normalizeEmail(*in.Email)
+			if err != nil {
+				return nil, err
+			}
+			emailChanged = user.Email == nil || *user.Email != email
+			user.Email = &email
+		}
+	}
+	if in.SubscribeToNews != nil {
+		user.SubscribeToNews = *in.SubscribeToNews
+	}
+
+	if err := s.repo.UpdateProfile(ctx, user); err != nil {
+		return nil, err
+	}
+	if emailChanged && user.EmailVerifiedAt == nil {
+		if err := s.emailAuth.ResendVerification(ctx, *user.Email); err != nil {
+			log.Printf("verification email for %s: %v", user.ID, err)
+		}
+	}
+	return user, nil
+}
+
+// Export пишет в w zip-архив со всеми данными пользователя: профиль, сессии,
+// прогресс и прочее из Postgres, события посетителя из ClickHouse
+func (s *AccountService) Export(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sections, err := s.repo.Export(ctx, userID)
+	if err != nil {
+		return err
+	}
+	manifest := ExportManifest{UserID: userID, GeneratedAt: time.Now().UTC()}
+
+	if user.VisitorID != nil {
+		events, err := s.events.GetEventsByVisitor(ctx, user.VisitorID.String())
+		if err != nil {
+			log.Printf("export visitor events for %s: %v", userID, err)
+			manifest.Unavailable = append(manifest.Unavailable, "visitor_events")
+		} else {
+			if events == nil {
+				events = []*entity.VisitorEvent{}
+			}
+			raw, err := json.Marshal(events)
+			if err != nil {
+				return err
+			}
+			sections["visitor_events"] = raw
+		}
+	}
+
+	keys := make([]string, 0, len(sections))
+	for key := range sections {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	archive := zip.NewWriter(w)
+	for _, key := range keys {
+		name := key + ".json"
+		if err := writeJSONFile(archive, name, sections[key]); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, name)
+	}
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := writeJSONFile(archive, "manifest.json", raw); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// Delete удаляет аккаунт: сессии отзываются, личные данные стираются,
+// строка пользователя остаётся обезличенной (на неё ссылаются курсы и комментарии)
+func (s *AccountService) Delete(ctx context.Context, userID uuid.UUID, in DeleteAccountInput) error {
+	hash, err := s.users.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if hash != "" {
+		if err := password.Verify(in.Password, hash); err != nil {
+			if errors.Is(err, password.ErrMismatch) {
+				return ErrInvalidCredentials
+			}
+			return err
+		}
+	}
+	mfaEnabled, err := s.mfa.Enabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if mfaEnabled {
+		if err := s.mfa.Verify(ctx, userID, in.Code); err != nil {
+			return err
+		}
+	}
+
+	// Отзыв до удаления строк сбрасывает кэш сессий на всех инстансах
+	if _, err := s.sessionUC.RevokeAllSessions(ctx, userID, entity.RevokeReasonAccountDelete); err != nil {
+		return err
+	}
+	visitorID, err := s.repo.Anonymize(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if visitorID != nil {
+		// Аккаунт уже удалён; события можно дочистить вручную по логу
+		if err := s.events.DeleteEventsByVisitor(ctx, visitorID.String()); err != nil {
+			log.Printf("delete visitor events of %s (visitor %s): %v", userID, visitorID, err)
+		}
+	}
+	return nil
+}
+
+func validPhotoURL(raw string) bool {
+	if len(raw) > profileMaxPhotoURL {
+		return false
+	}
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+func writeJSONFile(archive *zip.Writer, name string, raw []byte) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, raw, "", "  "); err != nil {
+		return err
+	}
+	_, err = pretty.WriteTo(f)
+	return err
+}
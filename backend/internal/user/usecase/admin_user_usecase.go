@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrInvalidRole           = errors.New("invalid role")
+	ErrAdminSelfAction       = errors.New("administrators cannot apply this action to their own account")
+	ErrBanReasonRequired     = errors.New("ban reason is required")
+	ErrBanUntilPast          = errors.New("ban end must be in the future")
+	ErrImpersonateAdmin      = errors.New("administrators cannot be impersonated")
+	ErrImpersonationReason   = errors.New("impersonation reason is required")
+	ErrBulkTooLarge          = errors.New("too many users in one bulk operation")
+	ErrBulkEmpty             = errors.New("user_ids is required")
+	ErrBulkActionUnsupported = errors.New("unsupported bulk action")
+)
+
+const bulkMaxUsers = 100
+
+// Массовые операции над пользователями
+const (
+	BulkSetRole        = "set_role"
+	BulkBan            = "ban"
+	BulkUnban          = "unban"
+	BulkRevokeSessions = "revoke_sessions"
+)
+
+// AdminActor — администратор, выполняющий действие; попадает в журнал
+type AdminActor struct {
+	ID uuid.UUID
+	IP string
+}
+
+// BanInput — причина и срок блокировки; Until nil — бессрочно
+type BanInput struct {
+	Reason string     `json:"reason" example:"spam in comments"`
+	Until  *time.Time `json:"until,omitempty" example:"2026-12-31T00:00:00Z"`
+}
+
+// BulkUserAction — одна операция над списком пользователей
+type BulkUserAction struct {
+	UserIDs []uuid.UUID `json:"user_ids"`
+	Action  string      `json:"action" example:"ban"`
+	// Для set_role
+	Role entity.Role `json:"role,omitempty" example:"teacher"`
+	// Для ban
+	Reason string     `json:"reason,omitempty"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+// BulkResult — итог операции для одного пользователя; Error пуст при успехе
+type BulkResult struct {
+	UserID uuid.UUID `json:"user_id"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// ImpersonatedRequest — запрос, выполненный администратором от имени пользователя
+type ImpersonatedRequest struct {
+	ImpersonatorID uuid.UUID
+	UserID         uuid.UUID
+	SessionID      uuid.UUID
+	Method         string
+	Path           string
+	Status         int
+	IP             string
+}
+
+// AdminUserService — управление пользователями: поиск, роли, блокировки,
+// вход от имени пользователя для поддержки. Каждое действие пишется в журнал
+// в одной транзакции с самим изменением
+type AdminUserService struct {
+	repo   repository.AdminUserRepository
+	users  CredentialsRepository
+	tokens *TokenService
+}
+
+func NewAdminUserService(
+	repo repository.AdminUserRepository,
+	users CredentialsRepository,
+	tokens *TokenService,
+) *AdminUserService {
+	return &AdminUserService{repo: repo, users: users, tokens: tokens}
+}
+
+func (s *AdminUserService) Search(ctx context.Context, f repository.UserFilter, pag pagination.Params) ([]*entity.User, int, error) {
+	return s.repo.Search(ctx, f, pag)
+}
+
+func (s *AdminUserService) SetRole(ctx context.Context, actor AdminActor, userID uuid.UUID, role entity.Role) error {
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+	if userID == actor.ID {
+		return ErrAdminSelfAction
+	}
+	_, err := s.repo.SetRole(ctx, userID, role, auditEntry(actor, entity.AuditRoleChange, userID, map[string]any{"to": role}))
+	return err
+}
+
+// Ban блокирует пользователя и завершает его сессии. Персональные токены перестают
+// работать сразу (LoadCurrentUser), новые входы отклоняет TokenService
+func (s *AdminUserService) Ban(ctx context.Context, actor AdminActor, userID uuid.UUID, in BanInput) error {
+	if userID == actor.ID {
+		return ErrAdminSelfAction
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return ErrBanReasonRequired
+	}
+	if in.Until != nil && !in.Until.After(time.Now()) {
+		return ErrBanUntilPast
+	}
+	_, err := s.repo.Ban(ctx, userID, actor.ID, reason, in.Until, auditEntry(actor, entity.AuditBan, userID, map[string]any{
+		"reason": reason,
+		"until":  in.Until,
+	}))
+	return err
+}
+
+func (s *AdminUserService) Unban(ctx context.Context, actor AdminActor, userID uuid.UUID) error {
+	return s.repo.Unban(ctx, userID, auditEntry(actor, entity.AuditUnban, userID, nil))
+}
+
+// RevokeSessions завершает все сессии пользователя и возвращает их число
+func (s *AdminUserService) RevokeSessions(ctx context.Context, actor AdminActor, userID uuid.UUID) (int, error) {
+	return s.repo.RevokeSessions(ctx, userID, entity.RevokeReasonLogoutAll,
+		auditEntry(actor, entity.AuditRevokeSessions, userID, nil))
+}
+
+// Impersonate выдаёт администратору access-токен сессии от имени пользователя.
+// Запросы в этой сессии пишутся в журнал (см. RecordImpersonatedRequest)
+func (s *AdminUserService) Impersonate(ctx context.Context, actor AdminActor, userID uuid.UUID, reason string, meta SessionMeta) (*TokenPair, error) {
+	if userID == actor.ID {
+		return nil, ErrAdminSelfAction
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrImpersonationReason
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == entity.RoleAdmin {
+		return nil, ErrImpersonateAdmin
+	}
+	if user.IsBanned(time.Now()) {
+		return nil, ErrUserBanned
+	}
+	return s.tokens.Impersonate(ctx, userID, actor.ID, meta, func(ctx context.Context, session *entity.UserSession) error {
+		return s.repo.StartImpersonation(ctx, session, auditEntry(actor, entity.AuditImpersonationStart, userID, map[string]any{
+			"reason":     reason,
+			"session_id": session.ID,
+			"expires_at": session.ExpiresAt,
+		}))
+	})
+}
+
+func (s *AdminUserService) RecordImpersonatedRequest(ctx context.Context, r ImpersonatedRequest) error {
+	return s.repo.RecordAudit(ctx, auditEntry(AdminActor{ID: r.ImpersonatorID, IP: r.IP}, entity.AuditImpersonatedRequest, r.UserID, map[string]any{
+		"session_id": r.SessionID,
+		"method":     r.Method,
+		"path":       r.Path,
+		"status":     r.Status,
+	}))
+}
+
+// Bulk применяет операцию к каждому пользователю по отдельности: ошибка по одному
+// пользователю не отменяет остальные
+func (s *AdminUserService) Bulk(ctx context.Context, actor AdminActor, in BulkUserAction) ([]BulkResult, error) {
+	if len(in.UserIDs) == 0 {
+		return nil, ErrBulkEmpty
+	}
+	if len(in.UserIDs) > bulkMaxUsers {
+		return nil, ErrBulkTooLarge
+	}
+	var apply func(uuid.UUID) error
+	switch in.Action {
+	case BulkSetRole:
+		if !in.Role.Valid() {
+			return nil, ErrInvalidRole
+		}
+		apply = func(id uuid.UUID) error { return s.SetRole(ctx, actor, id, in.Role) }
+	case BulkBan:
+		if strings.TrimSpace(in.Reason) == "" {
+			return nil, ErrBanReasonRequired
+		}
+		ban := BanInput{Reason: in.Reason, Until: in.Until}
+		apply = func(id uuid.UUID) error { return s.Ban(ctx, actor, id, ban) }
+	case BulkUnban:
+		apply = func(id uuid.UUID) error { return s.Unban(ctx, actor, id) }
+	case BulkRevokeSessions:
+		apply = func(id uuid.UUID) error {
+			_, err := s.RevokeSessions(ctx, actor, id)
+			return err
+		}
+	default:
+		return nil, ErrBulkActionUnsupported
+	}
+
+	results := make([]BulkResult, 0, len(in.UserIDs))
+	seen := make(map[uuid.UUID]bool, len(in.UserIDs))
+	for _, id := range in.UserIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result := BulkResult{UserID: id}
+		if err := apply(id); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *AdminUserService) Audit(ctx context.Context, f repository.AuditFilter, pag pagination.Params) ([]*entity.AdminAuditEntry, int, error) {
+	return s.repo.ListAudit(ctx, f, pag)
+}
+
+// auditEntry — запись журнала; репозиторий сохраняет её вместе с действием
+func auditEntry(actor AdminActor, action string, userID uuid.UUID, details map[string]any) *entity.AdminAuditEntry {
+	return &entity.AdminAuditEntry{
+		ActorID:      actor.ID,
+		Action:       action,
+		TargetUserID: &userID,
+		Details:      details,
+		IPAddress:    actor.IP,
+	}
+}
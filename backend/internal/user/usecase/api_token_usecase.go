@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrAPITokenNotFound     = repository.ErrAPITokenNotFound
+	ErrAPITokenInvalid      = errors.New("invalid or expired api token")
+	ErrAPITokenName         = errors.New("token name must be 1 to 100 characters")
+	ErrAPITokenScopeInvalid = errors.New("invalid token scope")
+	ErrAPITokenExpiry       = errors.New("token lifetime must be between 1 and 365 days")
+	ErrAPITokenLimit        = errors.New("too many active api tokens")
+)
+
+const (
+	// APITokenPrefix отличает персональные токены от JWT в заголовке Authorization
+	APITokenPrefix = "edu_pat_"
+
+	apiTokenDefaultDays = 90
+	apiTokenMaxDays     = 365
+	apiTokenMaxActive   = 50
+	apiTokenMaxName     = 100
+	// Начало токена, которое показывается в списке
+	apiTokenDisplayLen = len(APITokenPrefix) + 4
+	// last_used_at пишется в БД не чаще этого интервала
+	apiTokenTouchInterval = time.Minute
+)
+
+var scopePattern = regexp.MustCompile(`^([a-z_]+|\*):([a-z_]+|\*)$`)
+
+// CreateAPITokenInput — параметры нового токена
+type CreateAPITokenInput struct {
+	Name   string   `json:"name" example:"course import"`
+	Scopes []string `json:"scopes" example:"course:create,lesson:create"`
+	// Срок жизни в днях, 1–365; по умолчанию 90
+	ExpiresInDays int `json:"expires_in_days" example:"90"`
+}
+
+// APITokenService — персональные API-токены. Права токена — пересечение его
+// scopes (пар resource:action) с тем, что ABAC разрешает владельцу
+type APITokenService struct {
+	repo repository.APITokenRepository
+}
+
+func NewAPITokenService(repo repository.APITokenRepository) *APITokenService {
+	return &APITokenService{repo: repo}
+}
+
+// Create выпускает токен и возвращает его вместе с открытым значением,
+// которое больше нигде не сохраняется. mfa — текущая сессия прошла 2FA
+func (s *APITokenService) Create(ctx context.Context, userID uuid.UUID, mfa bool, in CreateAPITokenInput) (*entity.APIToken, string, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || utf8.RuneCountInString(name) > apiTokenMaxName {
+		return nil, "", ErrAPITokenName
+	}
+	scopes, err := normalizeScopes(in.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	days := in.ExpiresInDays
+	if days == 0 {
+		days = apiTokenDefaultDays
+	}
+	if days < 1 || days > apiTokenMaxDays {
+		return nil, "", ErrAPITokenExpiry
+	}
+	active, err := s.repo.CountActive(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if active >= apiTokenMaxActive {
+		return nil, "", ErrAPITokenLimit
+	}
+
+	secret, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	raw := APITokenPrefix + secret
+	now := time.Now()
+	token := &entity.APIToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:apiTokenDisplayLen],
+		TokenHash: hashToken(raw),
+		Scopes:    scopes,
+		MFA:       mfa,
+		ExpiresAt: now.AddDate(0, 0, days),
+		CreatedAt: now,
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+	return token, raw, nil
+}
+
+func (s *APITokenService) List(ctx context.Context, userID uuid.UUID) ([]*entity.APIToken, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+func (s *APITokenService) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.Revoke(ctx, userID, id)
+}
+
+// Authenticate проверяет токен из заголовка и отмечает его использование
+func (s *APITokenService) Authenticate(ctx context.Context, raw, ip string) (*entity.APIToken, error) {
+	if !strings.HasPrefix(raw, APITokenPrefix) {
+		return nil, ErrAPITokenInvalid
+	}
+	token, err := s.repo.FindByHash(ctx, hashToken(raw))
+	if errors.Is(err, repository.ErrAPITokenNotFound) {
+		return nil, ErrAPITokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.After(token.ExpiresAt) {
+		return nil, ErrAPITokenInvalid
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenTouchInterval || token.LastUsedIP != ip {
+		// Без отметки об использовании запрос всё равно обслуживаем
+		if err := s.repo.TouchLastUsed(ctx, token.ID, ip, now); err != nil {
+			log.Printf("touch api token %s: %v", token.ID, err)
+		}
+		token.LastUsedAt, token.LastUsedIP = &now, ip
+	}
+	return token, nil
+}
+
+// normalizeScopes проверяет формат resource:action и убирает повторы
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrAPITokenScopeInvalid)
+	}
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !scopePattern.MatchString(scope) {
+			return nil, fmt.Errorf("%w: %q", ErrAPITokenScopeInvalid, scope)
+		}
+		resource, _, _ := strings.Cut(scope, ":")
+		for _, r := range entity.APITokenSessionOnlyResources {
+			if r == resource {
+				return nil, fmt.Errorf("%w: %q is not available to api tokens", ErrAPITokenScopeInvalid, scope)
+			}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/mailer"
+	"github.com/kostinp/edu-platform-backend/internal/shared/password"
+	"github.com/kostinp/edu-platform-backend/internal/shared/ratelimit"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrUserNotFound       = repository.ErrUserNotFound
+	ErrEmailTaken         = repository.ErrEmailTaken
+	ErrTokenInvalid       = repository.ErrTokenInvalid
+	ErrInvalidEmail       = errors.New("invalid email address")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	ErrTooManyRequests    = errors.New("too many requests, try again later")
+)
+
+// Время жизни токенов из писем
+const (
+	verificationTokenTTL = 24 * time.Hour
+	resetTokenTTL        = time.Hour
+	magicLinkTTL         = 15 * time.Minute
+)
+
+// CredentialsRepository — операции с пользователями, нужные для входа по email
+type CredentialsRepository interface {
+	CreateWithPassword(ctx context.Context, user *entity.User, passwordHash string) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error)
+	SetPasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
+
+// AuthLinkBaseURL — адрес фронтенда, на который ведут ссылки из писем
+type AuthLinkBaseURL string
+
+// EmailAuthLimits — лимиты запросов ко входу по email, отдельно по IP и по адресу.
+// Login защищает дорогое хэширование пароля, Mail — почтовый ящик от засыпания письмами
+type EmailAuthLimits struct {
+	LoginByIP    ratelimit.Limiter
+	LoginByEmail ratelimit.Limiter
+	MailByIP     ratelimit.Limiter
+	MailByEmail  ratelimit.Limiter
+}
+
+// EmailAuthService — регистрация и вход по email: пароль, подтверждение адреса,
+// сброс пароля и беспарольные magic links
+type EmailAuthService struct {
+	users     CredentialsRepository
+	tokens    repository.AuthTokenRepository
+	sessionUC SessionUsecase
+	mailer    mailer.Mailer
+	baseURL   string
+	limits    *EmailAuthLimits
+}
+
+func NewEmailAuthService(
+	users CredentialsRepository,
+	tokens repository.AuthTokenRepository,
+	sessionUC SessionUsecase,
+	m mailer.Mailer,
+	baseURL AuthLinkBaseURL,
+	limits *EmailAuthLimits,
+) *EmailAuthService {
+	return &EmailAuthService{
+		users:     users,
+		tokens:    tokens,
+		sessionUC: sessionUC,
+		mailer:    m,
+		baseURL:   strings.TrimRight(string(baseURL), "/"),
+		limits:    limits,
+	}
+}
+
+// ThrottleLogin учитывает попытку входа по паролю; ErrTooManyRequests — лимит исчерпан
+func (s *EmailAuthService) ThrottleLogin(ctx context.Context, ip, email string) error {
+	if s.limits == nil {
+		return nil
+	}
+	return throttle(ctx, s.limits.LoginByIP, ip, s.limits.LoginByEmail, email)
+}
+
+// ThrottleMail учитывает запрос, после которого уходит письмо
+func (s *EmailAuthService) ThrottleMail(ctx context.Context, ip, email string) error {
+	if s.limits == nil {
+		return nil
+	}
+	return throttle(ctx, s.limits.MailByIP, ip, s.limits.MailByEmail, email)
+}
+
+// throttle проверяет оба лимита. Сбой хранилища лимитов не блокирует вход:
+// он только логируется, иначе недоступный Redis закрыл бы вход всем
+func throttle(ctx context.Context, byIP ratelimit.Limiter, ip string, byEmail ratelimit.Limiter, email string) error {
+	checks := []struct {
+		limiter ratelimit.Limiter
+		key     string
+	}{
+		{byIP, ip},
+		// Ключ — адрес в том же виде, что и в базе: регистр и пробелы не обходят лимит
+		{byEmail, strings.ToLower(strings.TrimSpace(email))},
+	}
+	for _, check := range checks {
+		if check.limiter == nil || check.key == "" {
+			continue
+		}
+		allowed, err := check.limiter.Allow(ctx, check.key)
+		if err != nil {
+			log.Printf("auth rate limiter: %v", err)
+			continue
+		}
+		if !allowed {
+			return ErrTooManyRequests
+		}
+	}
+	return nil
+}
+
+// Register создаёт пользователя с паролем и отправляет письмо для подтверждения.
+// Ошибка отправки письма не отменяет регистрацию: письмо можно запросить повторно
+func (s *EmailAuthService) Register(ctx context.Context, email, pw, fullName string) (*entity.User, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := password.Validate(pw); err != nil {
+		return nil, err
+	}
+	hash, err := password.Hash(pw)
+	if err != nil {
+		return nil, err
+	}
+	fullName = strings.TrimSpace(fullName)
+	now := time.Now()
+	user := &entity.User{
+		Email:     &email,
+		FullName:  &fullName,
+		Role:      entity.RoleStudent,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.users.CreateWithPassword(ctx, user, hash); err != nil {
+		return nil, err
+	}
+	if err := s.sendVerification(ctx, user.ID, email); err != nil {
+		log.Printf("verification email for %s: %v", user.ID, err)
+	}
+	return user, nil
+}
+
+// Login проверяет пароль. Для неизвестного email выполняется холостая проверка,
+// чтобы по времени ответа нельзя было узнать, зарегистрирован ли адрес
+func (s *EmailAuthService) Login(ctx context.Context, email, pw string) (*entity.User, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	user, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, ErrUserNotFound) {
+		password.VerifyDummy(pw)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	hash, err := s.users.GetPasswordHash(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		password.VerifyDummy(pw)
+		return nil, ErrInvalidCredentials
+	}
+	if err := password.Verify(pw, hash); err != nil {
+		if errors.Is(err, password.ErrMismatch) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
+	return user, nil
+}
+
+// VerifyEmail подтверждает адрес по токену из письма
+func (s *EmailAuthService) VerifyEmail(ctx context.Context, token string) (*entity.User, error) {
+	return s.consume(ctx, entity.TokenEmailVerification, token)
+}
+
+// ResendVerification повторно отправляет письмо. Для неизвестных и уже
+// подтверждённых адресов ничего не делает и ошибку не возвращает
+func (s *EmailAuthService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.findForMail(ctx, email)
+	if err != nil || user == nil || user.EmailVerifiedAt != nil {
+		return err
+	}
+	return s.sendVerification(ctx, user.ID, *user.Email)
+}
+
+// RequestPasswordReset отправляет ссылку для сброса пароля, если адрес известен
+func (s *EmailAuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.findForMail(ctx, email)
+	if err != nil || user == nil {
+		return err
+	}
+	token, err := s.issueToken(ctx, user.ID, entity.TokenPasswordReset, resetTokenTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      *user.Email,
+		Subject: "Сброс пароля",
+		Body: "Чтобы задать новый пароль, перейдите по ссылке:\n\n" +
+			s.link("/auth/reset-password", token) + "\n\n" +
+			"Ссылка действует 1 час. Если вы не запрашивали сброс, просто проигнорируйте письмо.",
+	})
+}
+
+// ResetPassword задаёт новый пароль по токену и завершает все сессии пользователя.
+// Переход по ссылке из письма заодно подтверждает адрес
+func (s *EmailAuthService) ResetPassword(ctx context.Context, token, newPassword string) (*entity.User, error) {
+	if err := password.Validate(newPassword); err != nil {
+		return nil, err
+	}
+	hash, err := password.Hash(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.consume(ctx, entity.TokenPasswordReset, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.SetPasswordHash(ctx, user.ID, hash); err != nil {
+		return nil, err
+	}
+	if _, err := s.sessionUC.RevokeAllSessions(ctx, user.ID, entity.RevokeReasonPasswordReset); err != nil {
+		return nil, fmt.Errorf("не удалось завершить сессии: %w", err)
+	}
+	return user, nil
+}
+
+// RequestMagicLink отправляет одноразовую ссылку для входа без пароля
+func (s *EmailAuthService) RequestMagicLink(ctx context.Context, email string) error {
+	user, err := s.findForMail(ctx, email)
+	if err != nil || user == nil {
+		return err
+	}
+	token, err := s.issueToken(ctx, user.ID, entity.TokenMagicLink, magicLinkTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      *user.Email,
+		Subject: "Вход на платформу",
+		Body: "Чтобы войти, перейдите по ссылке:\n\n" +
+			s.link("/auth/magic-link", token) + "\n\n" +
+			"Ссылка одноразовая и действует 15 минут.",
+	})
+}
+
+// ConsumeMagicLink выполняет вход по ссылке из письма
+func (s *EmailAuthService) ConsumeMagicLink(ctx context.Context, token string) (*entity.User, error) {
+	return s.consume(ctx, entity.TokenMagicLink, token)
+}
+
+// consume гасит токен и подтверждает email: владелец токена получил письмо
+func (s *EmailAuthService) consume(ctx context.Context, purpose entity.TokenPurpose, token string) (*entity.User, error) {
+	if token == "" {
+		return nil, ErrTokenInvalid
+	}
+	userID, err := s.tokens.Consume(ctx, purpose, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.MarkEmailVerified(ctx, userID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.users.GetByID(ctx, userID)
+}
+
+// findForMail возвращает nil без ошибки, если письмо отправлять некому
+func (s *EmailAuthService) findForMail(ctx context.Context, email string) (*entity.User, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *EmailAuthService) sendVerification(ctx context.Context, userID uuid.UUID, email string) error {
+	token, err := s.issueToken(ctx, userID, entity.TokenEmailVerification, verificationTokenTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Подтверждение email",
+		Body: "Подтвердите адрес, перейдя по ссылке:\n\n" +
+			s.link("/auth/verify-email", token) + "\n\n" +
+			"Ссылка действует 24 часа.",
+	})
+}
+
+func (s *EmailAuthService) issueToken(ctx context.Context, userID uuid.UUID, purpose entity.TokenPurpose, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	if err := s.tokens.Create(ctx, userID, purpose, hashToken(token), time.Now().Add(ttl)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *EmailAuthService) link(path, token string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// hashToken — в БД хранится только SHA-256 токена
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeEmail принимает только голый адрес и приводит его к нижнему регистру
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 254 {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(email), nil
+}
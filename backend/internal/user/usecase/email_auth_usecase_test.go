@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kostinp/edu-platform-backend/internal/shared/mailer"
+	"github.com/kostinp/edu-platform-backend/internal/shared/ratelimit"
+)
+
+type emailAuthFixture struct {
+	svc      *EmailAuthService
+	users    *fakeUsers
+	sessions *fakeSessions
+	mailDir  string
+}
+
+func newEmailAuthFixture(t *testing.T, limits *EmailAuthLimits) *emailAuthFixture {
+	t.Helper()
+	f := &emailAuthFixture{users: newFakeUsers(), sessions: &fakeSessions{}, mailDir: t.TempDir()}
+	f.svc = NewEmailAuthService(f.users, newFakeTokens(), f.sessions,
+		mailer.NewFileMailer(f.mailDir, "noreply@example.com"), "https://edu.example.com/", limits)
+	return f
+}
+
+var linkToken = regexp.MustCompile(`https://edu\.example\.com(/[a-z/-]+)\?token=(\S+)`)
+
+// lastLink читает последнее письмо из каталога FileMailer и достаёт из него ссылку
+func (f *emailAuthFixture) lastLink(t *testing.T, wantPath string) string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(f.mailDir, "*.eml"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no mail sent (err=%v)", err)
+	}
+	sort.Strings(files)
+	body, err := os.ReadFile(files[len(files)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := linkToken.FindStringSubmatch(string(body))
+	if m == nil {
+		t.Fatalf("no link in mail:\n%s", body)
+	}
+	if m[1] != wantPath {
+		t.Fatalf("link path = %s, want %s", m[1], wantPath)
+	}
+	token, err := url.QueryUnescape(m[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func (f *emailAuthFixture) mailCount(t *testing.T) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(f.mailDir, "*.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestEmailAuth_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailAuthFixture(t, nil)
+
+	if _, err := f.svc.Register(ctx, "Ann@Example.com", "correct horse", "Ann"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := f.svc.Login(ctx, "ann@example.com", "correct horse"); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("Login before verification: err = %v, want ErrEmailNotVerified", err)
+	}
+
+	token := f.lastLink(t, "/auth/verify-email")
+	user, err := f.svc.VerifyEmail(ctx, token)
+	if err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if user.EmailVerifiedAt == nil {
+		t.Fatal("email is not marked verified")
+	}
+	if _, err := f.svc.VerifyEmail(ctx, token); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("second VerifyEmail: err = %v, want ErrTokenInvalid", err)
+	}
+	if _, err := f.svc.Login(ctx, "ann@example.com", "correct horse"); err != nil {
+		t.Fatalf("Login after verification: %v", err)
+	}
+}
+
+func TestEmailAuth_ResendInvalidatesPreviousLink(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailAuthFixture(t, nil)
+
+	if _, err := f.svc.Register(ctx, "ann@example.com", "correct horse", "Ann"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	first := f.lastLink(t, "/auth/verify-email")
+	if err := f.svc.ResendVerification(ctx, "ann@example.com"); err != nil {
+		t.Fatalf("ResendVerification: %v", err)
+	}
+	second := f.lastLink(t, "/auth/verify-email")
+
+	if _, err := f.svc.VerifyEmail(ctx, first); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("old link: err = %v, want ErrTokenInvalid", err)
+	}
+	if _, err := f.svc.VerifyEmail(ctx, second); err != nil {
+		t.Fatalf("new link: %v", err)
+	}
+
+	// Подтверждённому адресу письмо больше не отправляется
+	before := f.mailCount(t)
+	if err := f.svc.ResendVerification(ctx, "ann@example.com"); err != nil {
+		t.Fatalf("ResendVerification: %v", err)
+	}
+	if got := f.mailCount(t); got != before {
+		t.Fatalf("mail sent to verified address: %d letters, want %d", got, before)
+	}
+}
+
+func TestEmailAuth_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailAuthFixture(t, nil)
+
+	if _, err := f.svc.Register(ctx, "ann@example.com", "old password", "Ann"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := f.svc.RequestPasswordReset(ctx, "ANN@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	token := f.lastLink(t, "/auth/reset-password")
+
+	if _, err := f.svc.ResetPassword(ctx, token, "short"); err == nil {
+		t.Fatal("ResetPassword accepted a too short password")
+	}
+	user, err := f.svc.ResetPassword(ctx, token, "new password")
+	if err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if len(f.sessions.revokedFor) != 1 || f.sessions.revokedFor[0] != user.ID {
+		t.Fatalf("sessions revoked for %v, want [%s]", f.sessions.revokedFor, user.ID)
+	}
+	if _, err := f.svc.ResetPassword(ctx, token, "another password"); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("token reuse: err = %v, want ErrTokenInvalid", err)
+	}
+
+	// Переход по ссылке из письма подтвердил адрес, поэтому вход проходит
+	if _, err := f.svc.Login(ctx, "ann@example.com", "old password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login with old password: err = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := f.svc.Login(ctx, "ann@example.com", "new password"); err != nil {
+		t.Fatalf("Login with new password: %v", err)
+	}
+}
+
+func TestEmailAuth_MailToUnknownAddress(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailAuthFixture(t, nil)
+
+	if err := f.svc.RequestPasswordReset(ctx, "nobody@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	if err := f.svc.RequestMagicLink(ctx, "nobody@example.com"); err != nil {
+		t.Fatalf("RequestMagicLink: %v", err)
+	}
+	if got := f.mailCount(t); got != 0 {
+		t.Fatalf("%d letters sent to unknown address", got)
+	}
+}
+
+func TestEmailAuth_MagicLink(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailAuthFixture(t, nil)
+
+	if _, err := f.svc.Register(ctx, "ann@example.com", "correct horse", "Ann"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := f.svc.RequestMagicLink(ctx, "ann@example.com"); err != nil {
+		t.Fatalf("RequestMagicLink: %v", err)
+	}
+	token := f.lastLink(t, "/auth/magic-link")
+
+	if _, err := f.svc.VerifyEmail(ctx, token); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("magic link used as verification token: err = %v, want ErrTokenInvalid", err)
+	}
+	user, err := f.svc.ConsumeMagicLink(ctx, token)
+	if err != nil {
+		t.Fatalf("ConsumeMagicLink: %v", err)
+	}
+	if user.EmailVerifiedAt == nil {
+		t.Fatal("magic link did not verify the address")
+	}
+	if _, err := f.svc.ConsumeMagicLink(ctx, token); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("second ConsumeMagicLink: err = %v, want ErrTokenInvalid", err)
+	}
+}
+
+func TestEmailAuth_Throttle(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailAuthFixture(t, &EmailAuthLimits{
+		LoginByIP:    ratelimit.NewMemory(3, time.Minute),
+		LoginByEmail: ratelimit.NewMemory(2, time.Minute),
+		MailByIP:     ratelimit.NewMemory(10, time.Minute),
+		MailByEmail:  ratelimit.NewMemory(1, time.Minute),
+	})
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"login 1", func() error { return f.svc.ThrottleLogin(ctx, "10.0.0.1", "ann@example.com") }, nil},
+		{"login 2, other case", func() error { return f.svc.ThrottleLogin(ctx, "10.0.0.2", " ANN@example.com") }, nil},
+		{"login 3, email limit", func() error { return f.svc.ThrottleLogin(ctx, "10.0.0.3", "ann@example.com") }, ErrTooManyRequests},
+		{"login other email", func() error { return f.svc.ThrottleLogin(ctx, "10.0.0.1", "bob@example.com") }, nil},
+		{"login third from ip", func() error { return f.svc.ThrottleLogin(ctx, "10.0.0.1", "eve@example.com") }, nil},
+		{"login ip exhausted", func() error { return f.svc.ThrottleLogin(ctx, "10.0.0.1", "kim@example.com") }, ErrTooManyRequests},
+		{"mail 1", func() error { return f.svc.ThrottleMail(ctx, "10.0.0.1", "ann@example.com") }, nil},
+		{"mail 2, email limit", func() error { return f.svc.ThrottleMail(ctx, "10.0.0.9", "Ann@Example.com") }, ErrTooManyRequests},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEmailAuth_ThrottleFailsOpen(t *testing.T) {
+	f := newEmailAuthFixture(t, &EmailAuthLimits{LoginByIP: failingLimiter{}, LoginByEmail: failingLimiter{}})
+	if err := f.svc.ThrottleLogin(context.Background(), "10.0.0.1", "ann@example.com"); err != nil {
+		t.Fatalf("limiter failure blocked login: %v", err)
+	}
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (bool, error) {
+	return false, errors.New("redis is down")
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{in: "Ann@Example.com", want: "ann@example.com"},
+		{in: "  ann@example.com ", want: "ann@example.com"},
+		{in: "Ann <ann@example.com>", wantErr: true},
+		{in: "not an email", wantErr: true},
+		{in: strings.Repeat("a", 250) + "@example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeEmail(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeEmail(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+// fakeUsers — пользователи в памяти для EmailAuthService и OIDCService
+type fakeUsers struct {
+	mu     sync.Mutex
+	users  map[uuid.UUID]*entity.User
+	hashes map[uuid.UUID]string
+}
+
+func newFakeUsers() *fakeUsers {
+	return &fakeUsers{users: map[uuid.UUID]*entity.User{}, hashes: map[uuid.UUID]string{}}
+}
+
+func (r *fakeUsers) add(u *entity.User, hash string) *entity.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	r.users[u.ID] = u
+	r.hashes[u.ID] = hash
+	return u
+}
+
+func (r *fakeUsers) CreateWithPassword(_ context.Context, user *entity.User, passwordHash string) error {
+	if user.Email != nil {
+		if _, err := r.GetByEmail(context.Background(), *user.Email); err == nil {
+			return repository.ErrEmailTaken
+		}
+	}
+	r.add(user, passwordHash)
+	return nil
+}
+
+func (r *fakeUsers) GetByID(_ context.Context, id uuid.UUID) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (r *fakeUsers) GetByEmail(_ context.Context, email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email != nil && *u.Email == email {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *fakeUsers) GetPasswordHash(_ context.Context, userID uuid.UUID) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[userID]; !ok {
+		return "", repository.ErrUserNotFound
+	}
+	return r.hashes[userID], nil
+}
+
+func (r *fakeUsers) SetPasswordHash(_ context.Context, userID uuid.UUID, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hashes[userID] = passwordHash
+	return nil
+}
+
+func (r *fakeUsers) MarkEmailVerified(_ context.Context, userID uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[userID]; ok && u.EmailVerifiedAt == nil {
+		u.EmailVerifiedAt = &at
+	}
+	return nil
+}
+
+// fakeTokens повторяет контракт AuthTokenRepository: новый токен гасит
+// прежние того же назначения, Consume срабатывает один раз
+type fakeTokens struct {
+	mu     sync.Mutex
+	tokens map[string]*fakeToken
+}
+
+type fakeToken struct {
+	userID    uuid.UUID
+	purpose   entity.TokenPurpose
+	expiresAt time.Time
+	used      bool
+}
+
+func newFakeTokens() *fakeTokens {
+	return &fakeTokens{tokens: map[string]*fakeToken{}}
+}
+
+func (r *fakeTokens) Create(_ context.Context, userID uuid.UUID, purpose entity.TokenPurpose, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.userID == userID && t.purpose == purpose {
+			t.used = true
+		}
+	}
+	r.tokens[tokenHash] = &fakeToken{userID: userID, purpose: purpose, expiresAt: expiresAt}
+	return nil
+}
+
+func (r *fakeTokens) Consume(_ context.Context, purpose entity.TokenPurpose, tokenHash string) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[tokenHash]
+	if !ok || t.used || t.purpose != purpose || time.Now().After(t.expiresAt) {
+		return uuid.Nil, repository.ErrTokenInvalid
+	}
+	t.used = true
+	return t.userID, nil
+}
+
+// fakeSessions считает вызовы RevokeAllSessions; остальные методы не нужны тестам
+type fakeSessions struct {
+	SessionUsecase
+	revokedFor []uuid.UUID
+}
+
+func (s *fakeSessions) RevokeAllSessions(_ context.Context, userID uuid.UUID, _ string) (int, error) {
+	s.revokedFor = append(s.revokedFor, userID)
+	return 1, nil
+}
+
+// fakeIdentities — привязанные внешние аккаунты в памяти
+type fakeIdentities struct {
+	mu         sync.Mutex
+	users      *fakeUsers
+	identities []*entity.Identity
+}
+
+func (r *fakeIdentities) GetByProviderSubject(_ context.Context, provider, subject string) (*entity.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, repository.ErrIdentityNotFound
+}
+
+func (r *fakeIdentities) ListByUser(_ context.Context, userID uuid.UUID) ([]*entity.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []*entity.Identity
+	for _, i := range r.identities {
+		if i.UserID == userID {
+			items = append(items, i)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakeIdentities) Create(_ context.Context, identity *entity.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.identities {
+		if (i.UserID == identity.UserID && i.Provider == identity.Provider) ||
+			(i.Provider == identity.Provider && i.Subject == identity.Subject) {
+			return repository.ErrIdentityExists
+		}
+	}
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeIdentities) CreateWithUser(ctx context.Context, user *entity.User, identity *entity.Identity) error {
+	if user.Email != nil {
+		if _, err := r.users.GetByEmail(ctx, *user.Email); err == nil {
+			return repository.ErrEmailTaken
+		}
+	}
+	r.users.add(user, "")
+	identity.UserID = user.ID
+	return r.Create(ctx, identity)
+}
+
+func (r *fakeIdentities) TouchLogin(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, i := range r.identities {
+		if i.ID == id {
+			i.LastLoginAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeIdentities) Delete(_ context.Context, userID uuid.UUID, provider string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, i := range r.identities {
+		if i.UserID == userID && i.Provider == provider {
+			r.identities = append(r.identities[:n], r.identities[n+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrIdentityNotFound
+}
+
+// fakeMFA повторяет контракт MFARepository для TOTP: попытка засчитывается
+// под мьютексом так же атомарно, как UPDATE ... WHERE в Postgres
+type fakeMFA struct {
+	repository.MFARepository
+	mu       sync.Mutex
+	totp     entity.TOTP
+	recovery map[string]bool
+}
+
+func (r *fakeMFA) GetTOTP(_ context.Context, userID uuid.UUID) (*entity.TOTP, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.totp.UserID != userID {
+		return nil, repository.ErrTOTPNotFound
+	}
+	copied := r.totp
+	return &copied, nil
+}
+
+func (r *fakeMFA) ReserveTOTPAttempt(_ context.Context, _ uuid.UUID, maxAttempts int, lockUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.totp.LockedUntil != nil {
+		if time.Now().Before(*r.totp.LockedUntil) {
+			return repository.ErrTOTPLocked
+		}
+		r.totp.FailedAttempts, r.totp.LockedUntil = 0, nil
+	}
+	r.totp.FailedAttempts++
+	if r.totp.FailedAttempts >= maxAttempts {
+		r.totp.LockedUntil = &lockUntil
+	}
+	return nil
+}
+
+func (r *fakeMFA) ResetTOTPAttempts(_ context.Context, _ uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.totp.FailedAttempts, r.totp.LockedUntil = 0, nil
+	return nil
+}
+
+func (r *fakeMFA) UseTOTPStep(_ context.Context, _ uuid.UUID, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.totp.LastUsedStep >= step {
+		return repository.ErrTOTPStepUsed
+	}
+	r.totp.LastUsedStep, r.totp.FailedAttempts, r.totp.LockedUntil = step, 0, nil
+	return nil
+}
+
+func (r *fakeMFA) UseRecoveryCode(_ context.Context, _ uuid.UUID, codeHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.recovery[codeHash] {
+		return repository.ErrRecoveryCodeInvalid
+	}
+	delete(r.recovery, codeHash)
+	return nil
+}
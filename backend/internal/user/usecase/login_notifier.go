@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/kostinp/edu-platform-backend/internal/shared/mailer"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+// Каналы доставки кода подтверждения входа
+const (
+	LoginChannelEmail    = "email"
+	LoginChannelTelegram = "telegram"
+	// Код из приложения-аутентификатора (2FA), ничего не отправляется
+	LoginChannelTOTP = "totp"
+)
+
+// TelegramSender — отправка сообщений пользователю в Telegram (HTML-разметка)
+type TelegramSender interface {
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
+
+var riskReasonTitles = map[string]string{
+	entity.RiskNewDevice:        "новое устройство",
+	entity.RiskNewCountry:       "вход из новой страны",
+	entity.RiskImpossibleTravel: "недавняя активность в другой стране",
+	entity.RiskFailedAttempts:   "неудачные попытки входа в аккаунт",
+	entity.RiskIPFailedAttempts: "много неудачных попыток входа с этого адреса",
+	entity.RiskStepUpVerified:   "вход подтверждён кодом",
+}
+
+// ChannelLoginNotifier пишет на подтверждённый email и в Telegram, если аккаунт привязан
+type ChannelLoginNotifier struct {
+	mailer   mailer.Mailer
+	telegram TelegramSender
+	baseURL  string
+}
+
+func NewChannelLoginNotifier(m mailer.Mailer, telegram TelegramSender, baseURL AuthLinkBaseURL) *ChannelLoginNotifier {
+	return &ChannelLoginNotifier{
+		mailer:   m,
+		telegram: telegram,
+		baseURL:  strings.TrimRight(string(baseURL), "/"),
+	}
+}
+
+func (n *ChannelLoginNotifier) NotifyLogin(ctx context.Context, user *entity.User, session *entity.UserSession) error {
+	subject := "Вход в аккаунт с нового устройства"
+	if session.Suspicious {
+		subject = "Подозрительный вход в аккаунт"
+	}
+	details := []string{
+		"Время: " + session.CreatedAt.Format("02.01.2006 15:04 MST"),
+		"Устройство: " + session.UserAgent,
+		"IP-адрес: " + session.IPAddress,
+		"Местоположение: " + session.Country + ", " + session.City,
+	}
+	if reasons := describeRiskReasons(session.RiskReasons); reasons != "" {
+		details = append(details, "Признаки: "+reasons)
+	}
+	footer := "Если это были не вы, завершите сессию и смените пароль: " + n.baseURL + "/me/settings"
+
+	var errs []error
+	if email := verifiedEmail(user); email != "" {
+		errs = append(errs, n.mailer.Send(ctx, mailer.Message{
+			To:      email,
+			Subject: subject,
+			Body:    "В ваш аккаунт выполнен вход.\n\n" + strings.Join(details, "\n") + "\n\n" + footer,
+		}))
+	}
+	if user.TelegramID != nil && n.telegram != nil {
+		escaped := make([]string, len(details))
+		for i, d := range details {
+			escaped[i] = html.EscapeString(d)
+		}
+		text := "<b>" + subject + "</b>\n\n" + strings.Join(escaped, "\n") + "\n\n" + html.EscapeString(footer)
+		errs = append(errs, n.telegram.SendMessage(ctx, *user.TelegramID, text))
+	}
+	return errors.Join(errs...)
+}
+
+// SendStepUpCode предпочитает email: Telegram-аккаунт могли угнать вместе с устройством
+func (n *ChannelLoginNotifier) SendStepUpCode(ctx context.Context, user *entity.User, code string, ttl time.Duration) (string, error) {
+	text := fmt.Sprintf("Код подтверждения входа: %s\n\nКод действует %d минут. Если вы не входили в аккаунт, смените пароль.",
+		code, int(ttl.Minutes()))
+	if email := verifiedEmail(user); email != "" {
+		err := n.mailer.Send(ctx, mailer.Message{
+			To:      email,
+			Subject: "Подтверждение входа",
+			Body:    text,
+		})
+		return LoginChannelEmail, err
+	}
+	if user.TelegramID != nil && n.telegram != nil {
+		return LoginChannelTelegram, n.telegram.SendMessage(ctx, *user.TelegramID, html.EscapeString(text))
+	}
+	return "", ErrStepUpUnavailable
+}
+
+func verifiedEmail(user *entity.User) string {
+	if user.Email == nil || user.EmailVerifiedAt == nil {
+		return ""
+	}
+	return *user.Email
+}
+
+func describeRiskReasons(reasons []string) string {
+	titles := make([]string, 0, len(reasons))
+	for _, r := range reasons {
+		if t, ok := riskReasonTitles[r]; ok {
+			titles = append(titles, t)
+		}
+	}
+	return strings.Join(titles, ", ")
+}
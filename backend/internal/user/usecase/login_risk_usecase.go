@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/geo"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrStepUpRequired    = errors.New("login confirmation required")
+	ErrStepUpCodeInvalid = errors.New("invalid or expired confirmation code")
+	// Подтвердить вход нечем: у пользователя нет ни подтверждённого email, ни Telegram
+	ErrStepUpUnavailable = errors.New("no channel for login confirmation")
+)
+
+// StepUpRequiredError — вход отложен до ввода кода, отправленного пользователю
+type StepUpRequiredError struct {
+	ChallengeID uuid.UUID
+	Channel     string
+	ExpiresAt   time.Time
+}
+
+func (e *StepUpRequiredError) Error() string { return ErrStepUpRequired.Error() }
+func (e *StepUpRequiredError) Unwrap() error { return ErrStepUpRequired }
+
+// Веса признаков риска
+const (
+	riskWeightNewDevice        = 30
+	riskWeightNewCountry       = 20
+	riskWeightImpossibleTravel = 40
+	riskWeightFailedAttempts   = 20
+	riskWeightManyFailures     = 40
+	riskWeightIPFailures       = 20
+
+	failedAttemptsWindow  = time.Hour
+	failedAttemptsLimit   = 3
+	manyFailedAttempts    = 10
+	ipFailedAttemptsLimit = 20
+
+	stepUpCodeTTL     = 10 * time.Minute
+	stepUpMaxAttempts = 5
+	stepUpCodeDigits  = 6
+)
+
+// LoginRiskPolicy — пороги оценки риска
+type LoginRiskPolicy struct {
+	// С этого score сессия помечается подозрительной
+	FlagScore int
+	// С этого score вход нужно подтвердить кодом; 0 — не требовать
+	StepUpScore int
+	// Вход из другой страны раньше этого срока после активности в прежней — «невозможное перемещение»
+	ImpossibleTravel time.Duration
+}
+
+// LoginRisk — результат оценки входа
+type LoginRisk struct {
+	Score      int
+	Reasons    []string
+	DeviceID   string
+	NewDevice  bool
+	Suspicious bool
+	StepUp     bool
+	// У пользователя включена 2FA: вход завершается кодом из аутентификатора
+	MFARequired bool
+	// Второй фактор пройден
+	MFA bool
+}
+
+// LoginNotifier сообщает пользователю о входах и доставляет коды подтверждения
+type LoginNotifier interface {
+	// NotifyLogin — вход с нового устройства или подозрительный вход
+	NotifyLogin(ctx context.Context, user *entity.User, session *entity.UserSession) error
+	// SendStepUpCode отправляет код подтверждения входа и возвращает канал;
+	// ErrStepUpUnavailable, если каналов нет
+	SendStepUpCode(ctx context.Context, user *entity.User, code string, ttl time.Duration) (string, error)
+}
+
+// LoginRiskService оценивает риск входа: новое устройство, новая страна,
+// невозможное перемещение между странами и неудачные попытки перед входом
+type LoginRiskService struct {
+	repo     repository.LoginRiskRepository
+	users    CredentialsRepository
+	notifier LoginNotifier
+	policy   LoginRiskPolicy
+	mfa      *MFAService
+}
+
+func NewLoginRiskService(
+	repo repository.LoginRiskRepository,
+	users CredentialsRepository,
+	notifier LoginNotifier,
+	policy LoginRiskPolicy,
+	mfa *MFAService,
+) *LoginRiskService {
+	if policy.FlagScore <= 0 {
+		policy.FlagScore = 50
+	}
+	if policy.ImpossibleTravel <= 0 {
+		policy.ImpossibleTravel = 2 * time.Hour
+	}
+	return &LoginRiskService{repo: repo, users: users, notifier: notifier, policy: policy, mfa: mfa}
+}
+
+// RecordFailure запоминает неудачную попытку входа. identifier — email, если он
+// известен: попытка учитывается и для аккаунта с этим адресом
+func (s *LoginRiskService) RecordFailure(ctx context.Context, identifier, ip, reason string) error {
+	attempt := &entity.LoginAttempt{
+		ID:         uuid.New(),
+		Identifier: identifier,
+		IPAddress:  ip,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+	if email, err := normalizeEmail(identifier); err == nil {
+		user, err := s.users.GetByEmail(ctx, email)
+		if err != nil && !errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+		if user != nil {
+			attempt.UserID = &user.ID
+		}
+	}
+	return s.repo.RecordFailure(ctx, attempt)
+}
+
+// Assess оценивает вход пользователя с параметрами meta
+func (s *LoginRiskService) Assess(ctx context.Context, userID uuid.UUID, meta SessionMeta) (*LoginRisk, error) {
+	now := time.Now()
+	risk := &LoginRisk{DeviceID: deviceFingerprint(meta.UserAgent)}
+	knownCountry := meta.Country != "" && meta.Country != geo.Unknown
+
+	history, err := s.repo.History(ctx, userID, risk.DeviceID, meta.Country)
+	if err != nil {
+		return nil, err
+	}
+	if history.HasLogins {
+		if !history.KnownDevice {
+			risk.NewDevice = true
+			risk.add(entity.RiskNewDevice, riskWeightNewDevice)
+		}
+		if knownCountry && !history.KnownCountry {
+			risk.add(entity.RiskNewCountry, riskWeightNewCountry)
+		}
+	}
+	if knownCountry && history.LastCountry != "" && history.LastCountry != meta.Country &&
+		now.Sub(history.LastActiveAt) < s.policy.ImpossibleTravel {
+		risk.add(entity.RiskImpossibleTravel, riskWeightImpossibleTravel)
+	}
+
+	byUser, byIP, err := s.repo.CountFailures(ctx, userID, meta.IP, now.Add(-failedAttemptsWindow))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case byUser >= manyFailedAttempts:
+		risk.add(entity.RiskFailedAttempts, riskWeightManyFailures)
+	case byUser >= failedAttemptsLimit:
+		risk.add(entity.RiskFailedAttempts, riskWeightFailedAttempts)
+	}
+	if byIP >= ipFailedAttemptsLimit {
+		risk.add(entity.RiskIPFailedAttempts, riskWeightIPFailures)
+	}
+
+	risk.Suspicious = risk.Score >= s.policy.FlagScore
+	risk.StepUp = s.policy.StepUpScore > 0 && risk.Score >= s.policy.StepUpScore
+	if risk.MFARequired, err = s.mfa.Enabled(ctx, userID); err != nil {
+		return nil, err
+	}
+	return risk, nil
+}
+
+// StartStepUp создаёт запрос подтверждения входа. С включённой 2FA ждём код из
+// аутентификатора (он же подтверждает рискованный вход), иначе отправляем код пользователю
+func (s *LoginRiskService) StartStepUp(ctx context.Context, userID uuid.UUID, risk *LoginRisk) (*StepUpRequiredError, error) {
+	channel, codeHash := LoginChannelTOTP, ""
+	if !risk.MFARequired {
+		user, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		code, err := newStepUpCode()
+		if err != nil {
+			return nil, err
+		}
+		if channel, err = s.notifier.SendStepUpCode(ctx, user, code, stepUpCodeTTL); err != nil {
+			return nil, err
+		}
+		codeHash = hashStepUpCode(code)
+	}
+	now := time.Now()
+	challenge := &entity.LoginChallenge{
+		ID:          uuid.New(),
+		UserID:      userID,
+		CodeHash:    codeHash,
+		Channel:     channel,
+		RiskScore:   risk.Score,
+		RiskReasons: risk.Reasons,
+		DeviceID:    risk.DeviceID,
+		ExpiresAt:   now.Add(stepUpCodeTTL),
+		CreatedAt:   now,
+	}
+	if err := s.repo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+	return &StepUpRequiredError{ChallengeID: challenge.ID, Channel: channel, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// VerifyStepUp проверяет код и возвращает подтверждённую оценку риска
+func (s *LoginRiskService) VerifyStepUp(ctx context.Context, challengeID uuid.UUID, code, ip string) (uuid.UUID, *LoginRisk, error) {
+	challenge, err := s.repo.GetChallenge(ctx, challengeID)
+	if errors.Is(err, repository.ErrChallengeNotFound) {
+		return uuid.Nil, nil, ErrStepUpCodeInvalid
+	}
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	// Попытка засчитывается до проверки кода: параллельные запросы не могут
+	// перебрать больше stepUpMaxAttempts кодов, прочитав один и тот же счётчик
+	_, err = s.repo.UseChallengeAttempt(ctx, challenge.ID, stepUpMaxAttempts)
+	if errors.Is(err, repository.ErrChallengeNotFound) {
+		return uuid.Nil, nil, ErrStepUpCodeInvalid
+	}
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	valid, err := s.checkChallengeCode(ctx, challenge, code)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if !valid {
+		_ = s.repo.RecordFailure(ctx, &entity.LoginAttempt{
+			ID:        uuid.New(),
+			UserID:    &challenge.UserID,
+			IPAddress: ip,
+			Reason:    entity.LoginFailureStepUp,
+			CreatedAt: time.Now(),
+		})
+		return uuid.Nil, nil, ErrStepUpCodeInvalid
+	}
+	if err := s.repo.ConsumeChallenge(ctx, challenge.ID); err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			return uuid.Nil, nil, ErrStepUpCodeInvalid
+		}
+		return uuid.Nil, nil, err
+	}
+	risk := &LoginRisk{
+		Score:    challenge.RiskScore,
+		Reasons:  challenge.RiskReasons,
+		DeviceID: challenge.DeviceID,
+		MFA:      challenge.Channel == LoginChannelTOTP,
+	}
+	if challenge.Channel != LoginChannelTOTP || s.policy.StepUpScore > 0 && challenge.RiskScore >= s.policy.StepUpScore {
+		risk.Reasons = append(risk.Reasons, entity.RiskStepUpVerified)
+	}
+	return challenge.UserID, risk, nil
+}
+
+// checkChallengeCode сверяет код с отправленным или, для 2FA, проверяет его в MFAService
+func (s *LoginRiskService) checkChallengeCode(ctx context.Context, challenge *entity.LoginChallenge, code string) (bool, error) {
+	if challenge.Channel != LoginChannelTOTP {
+		return subtle.ConstantTimeCompare([]byte(hashStepUpCode(strings.TrimSpace(code))), []byte(challenge.CodeHash)) == 1, nil
+	}
+	err := s.mfa.Verify(ctx, challenge.UserID, code)
+	// 2FA могли отключить, пока пользователь вводил код
+	if errors.Is(err, ErrMFACodeInvalid) || errors.Is(err, ErrMFANotEnabled) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// AfterLogin запоминает устройство и страну входа и уведомляет пользователя о
+// входе с нового устройства или подозрительном входе. Уведомление отправляется
+// в фоне и не задерживает ответ
+func (s *LoginRiskService) AfterLogin(ctx context.Context, session *entity.UserSession, risk *LoginRisk) {
+	if err := s.repo.RememberLogin(ctx, session.UserID, risk.DeviceID, session.UserAgent, session.Country, session.CreatedAt); err != nil {
+		log.Printf("remember login for %s: %v", session.UserID, err)
+	}
+	if !risk.NewDevice && !risk.Suspicious {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		user, err := s.users.GetByID(ctx, session.UserID)
+		if err != nil {
+			log.Printf("login alert for %s: %v", session.UserID, err)
+			return
+		}
+		if err := s.notifier.NotifyLogin(ctx, user, session); err != nil {
+			log.Printf("login alert for %s: %v", session.UserID, err)
+		}
+	}()
+}
+
+func (r *LoginRisk) add(reason string, weight int) {
+	r.Reasons = append(r.Reasons, reason)
+	r.Score += weight
+}
+
+// deviceFingerprint — отпечаток устройства по User-Agent
+func deviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(userAgent))))
+	return hex.EncodeToString(sum[:8])
+}
+
+func newStepUpCode() (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(stepUpCodeDigits), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", stepUpCodeDigits, n), nil
+}
+
+// hashStepUpCode — в БД хранится только SHA-256 кода
+func hashStepUpCode(code string) string {
+	return hashToken(code)
+}
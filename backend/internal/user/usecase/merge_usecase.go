@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrMergeSameUser     = errors.New("cannot merge an account into itself")
+	ErrMergeTokenInvalid = errors.New("token of the account to merge is invalid or expired")
+)
+
+// MergeService объединяет два аккаунта одного человека: данные источника
+// переносятся в целевой аккаунт, источник помечается удалённым
+type MergeService struct {
+	repo      repository.MergeRepository
+	sessionUC SessionUsecase
+}
+
+func NewMergeService(repo repository.MergeRepository, sessionUC SessionUsecase) *MergeService {
+	return &MergeService{repo: repo, sessionUC: sessionUC}
+}
+
+// Merge переносит sourceID в targetID. initiatedBy — кто запустил слияние (nil — система)
+func (s *MergeService) Merge(ctx context.Context, sourceID, targetID uuid.UUID, reason entity.MergeReason, initiatedBy *uuid.UUID) (*entity.AccountMerge, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeSameUser
+	}
+	merge := &entity.AccountMerge{
+		SourceUserID: sourceID,
+		TargetUserID: targetID,
+		InitiatedBy:  initiatedBy,
+		Reason:       reason,
+	}
+	if err := s.repo.Merge(ctx, merge); err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// MergeOwnAccount присоединяет к targetID аккаунт, во владении которым пользователь
+// подтвердил токеном его активной сессии
+func (s *MergeService) MergeOwnAccount(ctx context.Context, targetID, sourceID, sourceSessionID uuid.UUID) (*entity.AccountMerge, error) {
+	if err := s.CheckSession(ctx, sourceID, sourceSessionID); err != nil {
+		return nil, err
+	}
+	return s.Merge(ctx, sourceID, targetID, entity.MergeReasonSelf, &targetID)
+}
+
+// CheckSession проверяет, что сессия существует, не истекла и принадлежит userID
+func (s *MergeService) CheckSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionUC.GetSessionByID(ctx, sessionID)
+	if err != nil || session == nil || session.UserID != userID || time.Now().After(session.ExpiresAt) {
+		return ErrMergeTokenInvalid
+	}
+	return nil
+}
+
+func (s *MergeService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.AccountMerge, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/totp"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled     = errors.New("two-factor authentication not enabled")
+	ErrMFACodeInvalid    = errors.New("invalid two-factor code")
+	ErrMFALocked         = errors.New("too many invalid two-factor codes, try again later")
+)
+
+const (
+	recoveryCodeCount = 10
+	// Допуск на расхождение часов телефона и сервера — по интервалу в обе стороны
+	totpSkew           = 1
+	mfaMaxFailures     = 5
+	mfaLockoutDuration = 15 * time.Minute
+)
+
+// MFAIssuer — название сервиса в приложении-аутентификаторе
+type MFAIssuer string
+
+// MFAStatus — состояние двухфакторной аутентификации пользователя
+type MFAStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+	// Второй фактор пройден в текущей сессии (env.mfa)
+	SessionVerified bool `json:"session_verified"`
+}
+
+// TOTPEnrollment — данные для подключения аутентификатора; фронтенд показывает
+// provisioning_uri QR-кодом, secret — для ручного ввода
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAService — двухфакторная аутентификация: TOTP и одноразовые коды восстановления.
+// Если 2FA включена, вход завершается только после кода (см. TokenService.Issue),
+// а сессия получает отметку mfa_verified_at — атрибут env.mfa в ABAC
+type MFAService struct {
+	repo     repository.MFARepository
+	sessions repository.SessionRepository
+	users    CredentialsRepository
+	issuer   string
+}
+
+func NewMFAService(
+	repo repository.MFARepository,
+	sessions repository.SessionRepository,
+	users CredentialsRepository,
+	issuer MFAIssuer,
+) *MFAService {
+	if issuer == "" {
+		issuer = "Edu Platform"
+	}
+	return &MFAService{repo: repo, sessions: sessions, users: users, issuer: string(issuer)}
+}
+
+// Enabled — включена ли у пользователя 2FA
+func (s *MFAService) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if errors.Is(err, repository.ErrTOTPNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return t.EnabledAt != nil, nil
+}
+
+func (s *MFAService) Status(ctx context.Context, userID, sessionID uuid.UUID) (*MFAStatus, error) {
+	status := &MFAStatus{}
+	t, err := s.repo.GetTOTP(ctx, userID)
+	switch {
+	case errors.Is(err, repository.ErrTOTPNotFound):
+	case err != nil:
+		return nil, err
+	case t.EnabledAt != nil:
+		status.Enabled = true
+		status.EnabledAt = t.EnabledAt
+		if status.RecoveryCodesLeft, err = s.repo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	status.SessionVerified = session.MFAVerifiedAt != nil
+	return status, nil
+}
+
+// BeginTOTP создаёт новый секрет. 2FA включится после ConfirmTOTP
+func (s *MFAService) BeginTOTP(ctx context.Context, userID uuid.UUID) (*TOTPEnrollment, error) {
+	enabled, err := s.Enabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return &TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, accountName(user), secret),
+	}, nil
+}
+
+// ConfirmTOTP включает 2FA по первому коду из приложения и возвращает коды
+// восстановления — они показываются один раз. Текущая сессия считается прошедшей 2FA
+func (s *MFAService) ConfirmTOTP(ctx context.Context, userID, sessionID uuid.UUID, code string) ([]string, error) {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if errors.Is(err, repository.ErrTOTPNotFound) {
+		return nil, ErrMFANotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err := s.reserveAttempt(ctx, userID); err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(t.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrMFACodeInvalid
+	}
+	codes, records, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.EnableTOTP(ctx, userID, step, records); err != nil {
+		if errors.Is(err, repository.ErrTOTPNotFound) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+	if err := s.markSession(ctx, userID, sessionID); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify проверяет код из приложения или код восстановления
+func (s *MFAService) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if errors.Is(err, repository.ErrTOTPNotFound) {
+		return ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if t.EnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+	if err := s.reserveAttempt(ctx, userID); err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(t.Secret, code, time.Now(), totpSkew)
+		if !ok {
+			return ErrMFACodeInvalid
+		}
+		// Один и тот же код не принимается дважды; UseTOTPStep заодно обнуляет счётчик попыток
+		err := s.repo.UseTOTPStep(ctx, userID, step)
+		if errors.Is(err, repository.ErrTOTPStepUsed) {
+			return ErrMFACodeInvalid
+		}
+		return err
+	}
+
+	err = s.repo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	if errors.Is(err, repository.ErrRecoveryCodeInvalid) {
+		return ErrMFACodeInvalid
+	}
+	if err != nil {
+		return err
+	}
+	return s.repo.ResetTOTPAttempts(ctx, userID)
+}
+
+// VerifySession подтверждает вторым фактором уже открытую сессию — например,
+// начатую до включения 2FA, — чтобы ей стали доступны действия, требующие env.mfa
+func (s *MFAService) VerifySession(ctx context.Context, userID, sessionID uuid.UUID, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	return s.markSession(ctx, userID, sessionID)
+}
+
+// RegenerateRecoveryCodes заменяет все коды восстановления новыми
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	codes, records, err := newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable отключает 2FA; сессии теряют отметку о пройденном втором факторе
+func (s *MFAService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTOTP(ctx, userID); err != nil {
+		return err
+	}
+	return s.sessions.SetMFAVerified(ctx, userID, uuid.Nil, nil)
+}
+
+func (s *MFAService) markSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return s.sessions.SetMFAVerified(ctx, userID, session.FamilyID, &now)
+}
+
+// reserveAttempt засчитывает попытку до проверки кода: параллельные запросы не могут
+// перебрать больше mfaMaxFailures кодов подряд, прочитав одну и ту же блокировку
+func (s *MFAService) reserveAttempt(ctx context.Context, userID uuid.UUID) error {
+	err := s.repo.ReserveTOTPAttempt(ctx, userID, mfaMaxFailures, time.Now().Add(mfaLockoutDuration))
+	if errors.Is(err, repository.ErrTOTPLocked) {
+		return ErrMFALocked
+	}
+	return err
+}
+
+// accountName — подпись аккаунта в приложении-аутентификаторе
+func accountName(user *entity.User) string {
+	switch {
+	case user.Email != nil:
+		return *user.Email
+	case user.Username != nil:
+		return *user.Username
+	}
+	return user.ID.String()
+}
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes возвращает коды вида xxxxx-xxxxx и записи с их хэшами
+func newRecoveryCodes(userID uuid.UUID) ([]string, []*entity.RecoveryCode, error) {
+	now := time.Now()
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]*entity.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryEncoding.EncodeToString(raw))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		records = append(records, &entity.RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CodeHash:  hashToken(code),
+			CreatedAt: now,
+		})
+	}
+	return codes, records, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/totp"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+func newMFAFixture(t *testing.T) (*MFAService, *fakeMFA, uuid.UUID) {
+	t.Helper()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID := uuid.New()
+	enabledAt := time.Now()
+	repo := &fakeMFA{
+		totp:     entity.TOTP{UserID: userID, Secret: secret, EnabledAt: &enabledAt},
+		recovery: map[string]bool{hashToken("abcde12345"): true},
+	}
+	return NewMFAService(repo, nil, nil, ""), repo, userID
+}
+
+// wrongCode — шестизначный код, не совпадающий ни с одним интервалом в допуске
+func wrongCode(t *testing.T, secret string) string {
+	t.Helper()
+	now := totp.Step(time.Now())
+	for _, candidate := range []string{"000000", "111111", "222222"} {
+		clash := false
+		for step := now - totpSkew - 1; step <= now+totpSkew+1; step++ {
+			if c, _ := totp.Code(secret, step); c == candidate {
+				clash = true
+			}
+		}
+		if !clash {
+			return candidate
+		}
+	}
+	t.Fatal("no wrong code candidate")
+	return ""
+}
+
+func TestMFA_ConcurrentWrongCodesHitLockout(t *testing.T) {
+	svc, repo, userID := newMFAFixture(t)
+	code := wrongCode(t, repo.totp.Secret)
+
+	const requests = 50
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		invalid int
+		locked  int
+	)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := svc.Verify(context.Background(), userID, code)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, ErrMFACodeInvalid):
+				invalid++
+			case errors.Is(err, ErrMFALocked):
+				locked++
+			default:
+				t.Errorf("Verify() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if invalid != mfaMaxFailures {
+		t.Fatalf("checked codes = %d, want %d", invalid, mfaMaxFailures)
+	}
+	if locked != requests-mfaMaxFailures {
+		t.Fatalf("locked = %d, want %d", locked, requests-mfaMaxFailures)
+	}
+
+	// Верный код во время блокировки тоже отклоняется
+	valid, err := totp.Code(repo.totp.Secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Verify(context.Background(), userID, valid); !errors.Is(err, ErrMFALocked) {
+		t.Fatalf("Verify(valid) while locked = %v, want ErrMFALocked", err)
+	}
+}
+
+func TestMFA_SuccessResetsAttempts(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid func(t *testing.T, repo *fakeMFA) string
+	}{
+		{"totp", func(t *testing.T, repo *fakeMFA) string {
+			code, err := totp.Code(repo.totp.Secret, totp.Step(time.Now()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return code
+		}},
+		{"recovery code", func(*testing.T, *fakeMFA) string { return "ABCDE-12345" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, userID := newMFAFixture(t)
+			wrong := wrongCode(t, repo.totp.Secret)
+			for i := 0; i < mfaMaxFailures-1; i++ {
+				if err := svc.Verify(context.Background(), userID, wrong); !errors.Is(err, ErrMFACodeInvalid) {
+					t.Fatalf("Verify(wrong) = %v", err)
+				}
+			}
+			if err := svc.Verify(context.Background(), userID, tt.valid(t, repo)); err != nil {
+				t.Fatalf("Verify(valid) = %v", err)
+			}
+			// После верного кода серия начинается заново
+			for i := 0; i < mfaMaxFailures; i++ {
+				if err := svc.Verify(context.Background(), userID, wrong); !errors.Is(err, ErrMFACodeInvalid) {
+					t.Fatalf("attempt %d after reset = %v", i+1, err)
+				}
+			}
+			if err := svc.Verify(context.Background(), userID, wrong); !errors.Is(err, ErrMFALocked) {
+				t.Fatalf("Verify() after %d failures = %v, want ErrMFALocked", mfaMaxFailures, err)
+			}
+		})
+	}
+}
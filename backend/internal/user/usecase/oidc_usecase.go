@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/oidc"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrIdentityNotFound        = repository.ErrIdentityNotFound
+	ErrUnknownProvider         = errors.New("unknown identity provider")
+	ErrOIDCStateInvalid        = errors.New("login session expired, start again")
+	ErrIdentityLinkedElsewhere = errors.New("this external account is linked to another user")
+	ErrProviderAlreadyLinked   = errors.New("another account of this provider is already linked")
+	ErrEmailBelongsToAccount   = errors.New("an account with this email exists; sign in and link the provider in settings")
+	ErrLastLoginMethod         = errors.New("cannot unlink the only way to sign in")
+	ErrLinkUserMismatch        = errors.New("account linking must be completed by the user who started it")
+)
+
+// Сколько ждём возврата пользователя от провайдера
+const OIDCStateTTL = 10 * time.Minute
+
+// OIDCClients — настроенные провайдеры по имени
+type OIDCClients map[string]*oidc.Client
+
+// OIDCUserRepository — операции с пользователями, нужные для входа через OIDC
+type OIDCUserRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// OIDCResult — итог callback: вход (Linked=false) или привязка аккаунта
+type OIDCResult struct {
+	User     *entity.User
+	Identity *entity.Identity
+	Linked   bool
+}
+
+// OIDCService — вход через внешних провайдеров (authorization code + PKCE)
+// и управление привязанными аккаунтами
+type OIDCService struct {
+	clients    OIDCClients
+	states     oidc.StateStore
+	identities repository.IdentityRepository
+	users      OIDCUserRepository
+}
+
+func NewOIDCService(clients OIDCClients, states oidc.StateStore, identities repository.IdentityRepository, users OIDCUserRepository) *OIDCService {
+	return &OIDCService{clients: clients, states: states, identities: identities, users: users}
+}
+
+// Providers — имена доступных провайдеров
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.clients))
+	for name := range s.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Authorize готовит state, nonce и PKCE и возвращает адрес страницы входа провайдера
+// и binding — значение, которое нужно сохранить в HttpOnly-cookie браузера.
+// linkUserID задаётся, когда вошедший пользователь привязывает ещё один аккаунт
+func (s *OIDCService) Authorize(ctx context.Context, provider string, linkUserID *uuid.UUID) (authURL, binding string, err error) {
+	client, ok := s.clients[provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+	state, err := oidc.RandomString(24)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString(24)
+	if err != nil {
+		return "", "", err
+	}
+	binding, err = oidc.RandomString(24)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", "", err
+	}
+	data := &oidc.AuthState{Provider: provider, CodeVerifier: verifier, Nonce: nonce, Binding: binding}
+	if linkUserID != nil {
+		data.LinkUserID = linkUserID.String()
+	}
+	if err := s.states.Save(ctx, state, data, OIDCStateTTL); err != nil {
+		return "", "", err
+	}
+	authURL, err = client.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, binding, nil
+}
+
+// Callback завершает вход или привязку по code и state, полученным от провайдера.
+// binding — значение cookie из Authorize: state, подброшенный из чужого браузера,
+// не пройдёт. callerID — вошедший пользователь; привязка завершается только им
+// самим, вход — только без него
+func (s *OIDCService) Callback(ctx context.Context, provider, code, state, binding string, callerID *uuid.UUID) (*OIDCResult, error) {
+	client, ok := s.clients[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	data, err := s.states.Take(ctx, state)
+	if errors.Is(err, oidc.ErrStateNotFound) {
+		return nil, ErrOIDCStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data.Provider != provider || data.Binding == "" ||
+		subtle.ConstantTimeCompare([]byte(data.Binding), []byte(binding)) != 1 {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	var linkUserID uuid.UUID
+	if data.LinkUserID != "" {
+		linkUserID, err = uuid.Parse(data.LinkUserID)
+		if err != nil {
+			return nil, ErrOIDCStateInvalid
+		}
+		if callerID == nil || *callerID != linkUserID {
+			return nil, ErrLinkUserMismatch
+		}
+	} else if callerID != nil {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	claims, err := client.Exchange(ctx, code, data.CodeVerifier, data.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if data.LinkUserID != "" {
+		return s.link(ctx, linkUserID, provider, claims)
+	}
+	return s.login(ctx, provider, claims)
+}
+
+func (s *OIDCService) login(ctx context.Context, provider string, claims *oidc.Claims) (*OIDCResult, error) {
+	identity, err := s.identities.GetByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		if err := s.identities.TouchLogin(ctx, identity.ID); err != nil {
+			return nil, err
+		}
+		user, err := s.users.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return &OIDCResult{User: user, Identity: identity}, nil
+	}
+	if !errors.Is(err, ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	identity = &entity.Identity{Provider: provider, Subject: claims.Subject, LastLoginAt: &now}
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email != "" {
+		identity.Email = &email
+	}
+
+	// Существующий аккаунт с тем же email привязываем автоматически, только если
+	// адрес подтверждён с обеих сторон — иначе это путь к захвату чужого аккаунта
+	if email != "" {
+		existing, err := s.users.GetByEmail(ctx, email)
+		switch {
+		case err == nil && claims.EmailVerified && existing.EmailVerifiedAt != nil:
+			identity.UserID = existing.ID
+			if err := s.identities.Create(ctx, identity); err != nil {
+				if errors.Is(err, repository.ErrIdentityExists) {
+					return nil, ErrProviderAlreadyLinked
+				}
+				return nil, err
+			}
+			return &OIDCResult{User: existing, Identity: identity}, nil
+		case err == nil:
+			return nil, ErrEmailBelongsToAccount
+		case !errors.Is(err, ErrUserNotFound):
+			return nil, err
+		}
+	}
+
+	user := &entity.User{
+		Role:      entity.RoleStudent,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if email != "" {
+		user.Email = &email
+		if claims.EmailVerified {
+			user.EmailVerifiedAt = &now
+		}
+	}
+	if claims.Name != "" {
+		user.FullName = &claims.Name
+	}
+	if claims.Picture != "" {
+		user.PhotoURL = &claims.Picture
+	}
+	if err := s.identities.CreateWithUser(ctx, user, identity); err != nil {
+		if errors.Is(err, ErrEmailTaken) {
+			return nil, ErrEmailBelongsToAccount
+		}
+		return nil, err
+	}
+	return &OIDCResult{User: user, Identity: identity}, nil
+}
+
+func (s *OIDCService) link(ctx context.Context, userID uuid.UUID, provider string, claims *oidc.Claims) (*OIDCResult, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.identities.GetByProviderSubject(ctx, provider, claims.Subject)
+	switch {
+	case err == nil && existing.UserID == userID:
+		return &OIDCResult{User: user, Identity: existing, Linked: true}, nil
+	case err == nil:
+		return nil, ErrIdentityLinkedElsewhere
+	case !errors.Is(err, ErrIdentityNotFound):
+		return nil, err
+	}
+
+	identity := &entity.Identity{UserID: userID, Provider: provider, Subject: claims.Subject}
+	if email := strings.ToLower(strings.TrimSpace(claims.Email)); email != "" {
+		identity.Email = &email
+	}
+	if err := s.identities.Create(ctx, identity); err != nil {
+		if errors.Is(err, repository.ErrIdentityExists) {
+			return nil, ErrProviderAlreadyLinked
+		}
+		return nil, err
+	}
+	return &OIDCResult{User: user, Identity: identity, Linked: true}, nil
+}
+
+func (s *OIDCService) ListIdentities(ctx context.Context, userID uuid.UUID) ([]*entity.Identity, error) {
+	return s.identities.ListByUser(ctx, userID)
+}
+
+// Unlink отвязывает провайдера, если после этого у пользователя остаётся способ
+// войти: Telegram, пароль, подтверждённый email (magic link) или другой провайдер
+func (s *OIDCService) Unlink(ctx context.Context, userID uuid.UUID, provider string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	identities, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, i := range identities {
+		if i.Provider == provider {
+			found = true
+		}
+	}
+	if !found {
+		return ErrIdentityNotFound
+	}
+	hasOther := len(identities) > 1 || user.TelegramID != nil || user.EmailVerifiedAt != nil
+	if !hasOther {
+		hash, err := s.users.GetPasswordHash(ctx, userID)
+		if err != nil {
+			return err
+		}
+		hasOther = hash != ""
+	}
+	if !hasOther {
+		return ErrLastLoginMethod
+	}
+	return s.identities.Delete(ctx, userID, provider)
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/oidc"
+	"github.com/kostinp/edu-platform-backend/internal/shared/oidc/mock"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+const mockProviderName = "mock"
+
+type oidcFixture struct {
+	svc        *OIDCService
+	users      *fakeUsers
+	identities *fakeIdentities
+	provider   *mock.Provider
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	provider, err := mock.NewProvider("http://idp.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := newFakeUsers()
+	identities := &fakeIdentities{users: users}
+	client := oidc.NewClient(oidc.ProviderConfig{
+		Name:        mockProviderName,
+		Issuer:      provider.Issuer(),
+		ClientID:    mock.ClientID,
+		RedirectURL: "https://edu.example.com/auth/oidc/mock/callback",
+	}, provider.HTTPClient())
+	return &oidcFixture{
+		svc:        NewOIDCService(OIDCClients{mockProviderName: client}, oidc.NewMemoryStateStore(), identities, users),
+		users:      users,
+		identities: identities,
+		provider:   provider,
+	}
+}
+
+// signIn проходит страницу входа mock-провайдера под email и возвращает code и state из редиректа
+func (f *oidcFixture) signIn(t *testing.T, authURL, email string) (code, state string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("login_hint", email)
+	req := httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil)
+	rec := httptest.NewRecorder()
+	f.provider.ServeHTTP(rec, req)
+	if rec.Code != http.StatusFound {
+		t.Fatalf("authorize: status %d: %s", rec.Code, rec.Body.String())
+	}
+	target, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return target.Query().Get("code"), target.Query().Get("state")
+}
+
+// authorize начинает вход или привязку и сразу проходит страницу провайдера
+func (f *oidcFixture) authorize(t *testing.T, email string, linkUserID *uuid.UUID) (code, state, binding string) {
+	t.Helper()
+	authURL, binding, err := f.svc.Authorize(context.Background(), mockProviderName, linkUserID)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	code, state = f.signIn(t, authURL, email)
+	return code, state, binding
+}
+
+func TestOIDC_LoginCreatesAndReusesUser(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+
+	code, state, binding := f.authorize(t, "ann@example.com", nil)
+	first, err := f.svc.Callback(ctx, mockProviderName, code, state, binding, nil)
+	if err != nil {
+		t.Fatalf("first Callback: %v", err)
+	}
+	if first.Linked || first.User.Email == nil || *first.User.Email != "ann@example.com" || first.User.EmailVerifiedAt == nil {
+		t.Fatalf("unexpected first login result: %+v", first.User)
+	}
+
+	code, state, binding = f.authorize(t, "ann@example.com", nil)
+	second, err := f.svc.Callback(ctx, mockProviderName, code, state, binding, nil)
+	if err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if second.User.ID != first.User.ID {
+		t.Fatalf("second login created another user: %s != %s", second.User.ID, first.User.ID)
+	}
+	if second.Identity.LastLoginAt == nil {
+		t.Fatal("last login time is not updated")
+	}
+}
+
+func TestOIDC_LoginWithExistingEmail(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	verified, unverified := "ann@example.com", "bob@example.com"
+
+	tests := []struct {
+		name     string
+		user     *entity.User
+		wantErr  error
+		wantSame bool
+	}{
+		{"verified address is linked", &entity.User{Email: &verified, EmailVerifiedAt: &now}, nil, true},
+		{"unverified address is refused", &entity.User{Email: &unverified}, ErrEmailBelongsToAccount, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOIDCFixture(t)
+			existing := f.users.add(tt.user, "")
+
+			code, state, binding := f.authorize(t, *tt.user.Email, nil)
+			result, err := f.svc.Callback(ctx, mockProviderName, code, state, binding, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantSame && result.User.ID != existing.ID {
+				t.Fatalf("signed in as %s, want existing %s", result.User.ID, existing.ID)
+			}
+		})
+	}
+}
+
+func TestOIDC_CallbackRejectsForeignState(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+	code, state, binding := f.authorize(t, "ann@example.com", nil)
+
+	tests := []struct {
+		name     string
+		provider string
+		binding  string
+		wantErr  error
+	}{
+		{"unknown provider", "google", binding, ErrUnknownProvider},
+		{"no binding cookie", mockProviderName, "", ErrOIDCStateInvalid},
+		// state одноразовый: предыдущая попытка его уже погасила
+		{"state already used", mockProviderName, binding, ErrOIDCStateInvalid},
+	}
+	for _, tt := range tests {
+		if _, err := f.svc.Callback(ctx, tt.provider, code, state, tt.binding, nil); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	code, state, _ = f.authorize(t, "ann@example.com", nil)
+	if _, err := f.svc.Callback(ctx, mockProviderName, code, state, "attacker-binding", nil); !errors.Is(err, ErrOIDCStateInvalid) {
+		t.Fatalf("binding of another browser: err = %v, want ErrOIDCStateInvalid", err)
+	}
+}
+
+func TestOIDC_Link(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t)
+	owner := f.users.add(&entity.User{}, "hash")
+	other := f.users.add(&entity.User{}, "hash")
+
+	tests := []struct {
+		name     string
+		callerID *uuid.UUID
+		wantErr  error
+	}{
+		{"public callback", nil, ErrLinkUserMismatch},
+		{"another user", &other.ID, ErrLinkUserMismatch},
+		{"the user who started linking", &owner.ID, nil},
+	}
+	for _, tt := range tests {
+		code, state, binding := f.authorize(t, "ann@example.com", &owner.ID)
+		result, err := f.svc.Callback(ctx, mockProviderName, code, state, binding, tt.callerID)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && (!result.Linked || result.Identity.UserID != owner.ID) {
+			t.Fatalf("%s: identity linked to %s, want %s", tt.name, result.Identity.UserID, owner.ID)
+		}
+	}
+
+	items, err := f.svc.ListIdentities(ctx, owner.ID)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("ListIdentities = %d items, %v; want 1", len(items), err)
+	}
+
+	// Тот же внешний аккаунт нельзя привязать второму пользователю
+	code, state, binding := f.authorize(t, "ann@example.com", &other.ID)
+	if _, err := f.svc.Callback(ctx, mockProviderName, code, state, binding, &other.ID); !errors.Is(err, ErrIdentityLinkedElsewhere) {
+		t.Fatalf("link to another user: err = %v, want ErrIdentityLinkedElsewhere", err)
+	}
+
+	// Вход с состоянием входа не завершается на маршруте привязки
+	code, state, binding = f.authorize(t, "bob@example.com", nil)
+	if _, err := f.svc.Callback(ctx, mockProviderName, code, state, binding, &owner.ID); !errors.Is(err, ErrOIDCStateInvalid) {
+		t.Fatalf("login state on link route: err = %v, want ErrOIDCStateInvalid", err)
+	}
+}
+
+func TestOIDC_Unlink(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	telegramID := int64(42)
+	email := "ann@example.com"
+
+	tests := []struct {
+		name      string
+		user      *entity.User
+		hash      string
+		providers []string
+		unlink    string
+		wantErr   error
+	}{
+		{"only login method", &entity.User{}, "", []string{mockProviderName}, mockProviderName, ErrLastLoginMethod},
+		{"unverified email is not a login method", &entity.User{Email: &email}, "", []string{mockProviderName}, mockProviderName, ErrLastLoginMethod},
+		{"password remains", &entity.User{}, "hash", []string{mockProviderName}, mockProviderName, nil},
+		{"telegram remains", &entity.User{TelegramID: &telegramID}, "", []string{mockProviderName}, mockProviderName, nil},
+		{"magic link remains", &entity.User{Email: &email, EmailVerifiedAt: &now}, "", []string{mockProviderName}, mockProviderName, nil},
+		{"another provider remains", &entity.User{}, "", []string{mockProviderName, "google"}, mockProviderName, nil},
+		{"provider not linked", &entity.User{}, "hash", []string{mockProviderName}, "google", ErrIdentityNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOIDCFixture(t)
+			user := f.users.add(tt.user, tt.hash)
+			for _, p := range tt.providers {
+				if err := f.identities.Create(ctx, &entity.Identity{UserID: user.ID, Provider: p, Subject: p + "-sub"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := f.svc.Unlink(ctx, user.ID, tt.unlink)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			items, _ := f.identities.ListByUser(ctx, user.ID)
+			wantLeft := len(tt.providers)
+			if err == nil {
+				wantLeft--
+			}
+			if len(items) != wantLeft {
+				t.Fatalf("%d identities left, want %d", len(items), wantLeft)
+			}
+		})
+	}
+}
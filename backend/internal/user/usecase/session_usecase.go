@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/kostinp/edu-platform-backend/internal/user/repository"
@@ -15,21 +17,53 @@ type SessionUsecase interface {
 	CreateSession(ctx context.Context, userID uuid.UUID, token, userAgent, ip, country, city string, expiresIn time.Duration) (*entity.UserSession, error)
 	UpdateLastActive(ctx context.Context, sessionID uuid.UUID) error
 	ListSessions(ctx context.Context, userID uuid.UUID) ([]*entity.UserSession, error)
-	DeleteSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	// RevokeSession отзывает сессию пользователя (строка остаётся с revoked_at и причиной)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, reason string) error
+	// RevokeOtherSessions завершает все сессии пользователя, кроме текущей; возвращает их число
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int, error)
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID, reason string) (int, error)
 	SetInactivityTimeout(ctx context.Context, userID uuid.UUID, timeout time.Duration) error
 	GetInactivityTimeout(ctx context.Context, userID uuid.UUID) (time.Duration, error)
 	GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*entity.UserSession, error)
 	DeleteExpiredSessions(ctx context.Context) error
+	// CheckInactivity отзывает сессию, если она простаивала дольше таймаута
+	// неактивности пользователя, и возвращает ErrSessionInactive
+	CheckInactivity(ctx context.Context, session *entity.UserSession) error
+}
+
+var (
+	ErrSessionInactive = errors.New("session expired due to inactivity")
+	ErrTimeoutTooShort = errors.New("inactivity timeout is too short")
+	ErrSessionNotFound = repository.ErrSessionNotFound
+)
+
+// Сколько таймаут неактивности живёт в кэше. Кэш у каждого инстанса свой:
+// изменение таймаута на другом инстансе применится здесь не позже чем через это время
+const inactivityTimeoutCacheTTL = time.Minute
+
+// MinInactivityTimeout — наименьший допустимый таймаут неактивности. Он должен быть
+// заметно больше задержки, с которой last_active_at доходит до БД и кэшей других
+// инстансов (до минуты записи плюс TTL кэша сессий), иначе активная сессия
+// может быть отозвана как простаивающая
+const MinInactivityTimeout = 5 * time.Minute
+
+type cachedTimeout struct {
+	timeout   time.Duration
+	expiresAt time.Time
 }
 
 // Структура-реализация
 type SessionUsecaseImpl struct {
 	repo repository.SessionRepository
+
+	// Таймауты проверяются на каждом запросе, поэтому кэшируются
+	mu       sync.Mutex
+	timeouts map[uuid.UUID]cachedTimeout
 }
 
 // Конструктор
 func NewSessionUsecase(repo repository.SessionRepository) *SessionUsecaseImpl {
-	return &SessionUsecaseImpl{repo: repo}
+	return &SessionUsecaseImpl{repo: repo, timeouts: make(map[uuid.UUID]cachedTimeout)}
 }
 
 // Реализация методов
@@ -67,22 +101,93 @@ func (s *SessionUsecaseImpl) ListSessions(ctx context.Context, userID uuid.UUID)
 	return s.repo.FindByUserID(ctx, userID)
 }
 
-func (s *SessionUsecaseImpl) DeleteSession(ctx context.Context, userID, sessionID uuid.UUID) error {
-	return s.repo.Delete(ctx, userID, sessionID)
+func (s *SessionUsecaseImpl) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, reason string) error {
+	_, err := s.repo.Revoke(ctx, userID, sessionID, reason)
+	return err
+}
+
+func (s *SessionUsecaseImpl) RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int, error) {
+	current, err := s.repo.FindByID(ctx, currentSessionID)
+	if err != nil {
+		return 0, err
+	}
+	if current.UserID != userID {
+		return 0, ErrSessionNotFound
+	}
+	families, err := s.repo.RevokeByUserID(ctx, userID, current.FamilyID, entity.RevokeReasonLogoutOthers)
+	return len(families), err
+}
+
+func (s *SessionUsecaseImpl) RevokeAllSessions(ctx context.Context, userID uuid.UUID, reason string) (int, error) {
+	families, err := s.repo.RevokeByUserID(ctx, userID, uuid.Nil, reason)
+	return len(families), err
 }
 
+// SetInactivityTimeout сохраняет таймаут неактивности; 0 отключает его,
+// значения меньше MinInactivityTimeout — ErrTimeoutTooShort
 func (s *SessionUsecaseImpl) SetInactivityTimeout(ctx context.Context, userID uuid.UUID, timeout time.Duration) error {
-	return s.repo.SaveInactivityTimeout(ctx, userID, timeout)
+	if timeout != 0 && timeout < MinInactivityTimeout {
+		return ErrTimeoutTooShort
+	}
+	if err := s.repo.SaveInactivityTimeout(ctx, userID, timeout); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.timeouts, userID)
+	s.mu.Unlock()
+	return nil
 }
 
+// GetInactivityTimeout возвращает таймаут неактивности; 0 — таймаут отключён
 func (s *SessionUsecaseImpl) GetInactivityTimeout(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
-	return s.repo.GetInactivityTimeout(ctx, userID)
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.timeouts[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.timeout, nil
+	}
+
+	timeout, err := s.repo.GetInactivityTimeout(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	// Заодно выбрасываем устаревшие записи, чтобы кэш не рос бесконечно
+	if !ok && len(s.timeouts) >= 10000 {
+		for id, c := range s.timeouts {
+			if now.After(c.expiresAt) {
+				delete(s.timeouts, id)
+			}
+		}
+	}
+	s.timeouts[userID] = cachedTimeout{timeout: timeout, expiresAt: now.Add(inactivityTimeoutCacheTTL)}
+	s.mu.Unlock()
+	return timeout, nil
+}
+
+func (s *SessionUsecaseImpl) CheckInactivity(ctx context.Context, session *entity.UserSession) error {
+	timeout, err := s.GetInactivityTimeout(ctx, session.UserID)
+	if err != nil {
+		return err
+	}
+	if timeout <= 0 || time.Since(session.LastActiveAt) <= timeout {
+		return nil
+	}
+	if err := s.repo.RevokeFamily(ctx, session.FamilyID, entity.RevokeReasonInactivity); err != nil {
+		return err
+	}
+	return ErrSessionInactive
 }
 
 func (s *SessionUsecaseImpl) GetSessionByID(ctx context.Context, sessionID uuid.UUID) (*entity.UserSession, error) {
 	return s.repo.FindByID(ctx, sessionID)
 }
 
+// DeleteExpiredSessions удаляет истёкшие сессии и отзывает простаивающие дольше таймаута
 func (s *SessionUsecaseImpl) DeleteExpiredSessions(ctx context.Context) error {
+	if _, err := s.repo.RevokeInactiveSessions(ctx); err != nil {
+		return err
+	}
 	return s.repo.DeleteExpiredSessions(ctx)
 }
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+// fakeTimeoutRepo хранит только таймауты неактивности
+type fakeTimeoutRepo struct {
+	repository.SessionRepository
+	saved map[uuid.UUID]time.Duration
+}
+
+func (r *fakeTimeoutRepo) SaveInactivityTimeout(_ context.Context, userID uuid.UUID, timeout time.Duration) error {
+	r.saved[userID] = timeout
+	return nil
+}
+
+func TestSetInactivityTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		wantErr error
+	}{
+		{"disabled", 0, nil},
+		{"one minute", time.Minute, ErrTimeoutTooShort},
+		{"just below minimum", MinInactivityTimeout - time.Second, ErrTimeoutTooShort},
+		{"minimum", MinInactivityTimeout, nil},
+		{"one hour", time.Hour, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTimeoutRepo{saved: map[uuid.UUID]time.Duration{}}
+			userID := uuid.New()
+			err := NewSessionUsecase(repo).SetInactivityTimeout(context.Background(), userID, tt.timeout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetInactivityTimeout(%v) = %v, want %v", tt.timeout, err, tt.wantErr)
+			}
+			if _, saved := repo.saved[userID]; saved != (tt.wantErr == nil) {
+				t.Fatalf("saved = %v, want %v", saved, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"html"
+	"strings"
+
+	"github.com/kostinp/edu-platform-backend/internal/shared/mailer"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+)
+
+// TeacherApplicationNotifier сообщает заявителю о приёме заявки и о решении по ней
+type TeacherApplicationNotifier interface {
+	NotifyTeacherApplication(ctx context.Context, user *entity.User, app *entity.TeacherApplication) error
+}
+
+// ChannelApplicationNotifier пишет на подтверждённый email и в Telegram, если аккаунт привязан
+type ChannelApplicationNotifier struct {
+	mailer   mailer.Mailer
+	telegram TelegramSender
+	baseURL  string
+}
+
+func NewChannelApplicationNotifier(m mailer.Mailer, telegram TelegramSender, baseURL AuthLinkBaseURL) *ChannelApplicationNotifier {
+	return &ChannelApplicationNotifier{
+		mailer:   m,
+		telegram: telegram,
+		baseURL:  strings.TrimRight(string(baseURL), "/"),
+	}
+}
+
+func (n *ChannelApplicationNotifier) NotifyTeacherApplication(ctx context.Context, user *entity.User, app *entity.TeacherApplication) error {
+	var subject, body string
+	switch app.Status {
+	case entity.TeacherApplicationPending:
+		subject = "Заявка на роль преподавателя принята"
+		body = "Мы получили вашу заявку и сообщим о решении, когда её рассмотрят."
+	case entity.TeacherApplicationApproved:
+		subject = "Заявка на роль преподавателя одобрена"
+		body = "Теперь вы преподаватель и можете создавать курсы: " + n.baseURL + "/courses/new"
+	case entity.TeacherApplicationRejected:
+		subject = "Заявка на роль преподавателя отклонена"
+		body = "К сожалению, заявку не одобрили."
+	default:
+		return nil
+	}
+	if app.ReviewComment != nil && *app.ReviewComment != "" {
+		body += "\n\nКомментарий: " + *app.ReviewComment
+	}
+
+	var errs []error
+	if email := verifiedEmail(user); email != "" {
+		errs = append(errs, n.mailer.Send(ctx, mailer.Message{
+			To:      email,
+			Subject: subject,
+			Body:    body,
+		}))
+	}
+	if user.TelegramID != nil && n.telegram != nil {
+		text := "<b>" + subject + "</b>\n\n" + html.EscapeString(body)
+		errs = append(errs, n.telegram.SendMessage(ctx, *user.TelegramID, text))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/pagination"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrTeacherApplicationNotFound   = repository.ErrTeacherApplicationNotFound
+	ErrTeacherApplicationPending    = repository.ErrTeacherApplicationPending
+	ErrTeacherApplicationNotPending = repository.ErrTeacherApplicationNotPending
+	ErrTeacherApplicationRole       = errors.New("only students can apply to become a teacher")
+	ErrTeacherApplicationBio        = errors.New("bio must be between 50 and 5000 characters")
+	ErrTeacherApplicationLinks      = errors.New("portfolio links must be absolute http(s) urls, at most 10")
+	ErrTeacherApplicationStatus     = errors.New("invalid application status")
+	ErrReviewCommentRequired        = errors.New("comment is required when rejecting an application")
+	ErrReviewCommentTooLong         = errors.New("comment must be at most 2000 characters")
+)
+
+const (
+	teacherBioMinLen    = 50
+	teacherBioMaxLen    = 5000
+	teacherMaxLinks     = 10
+	teacherMaxLinkLen   = 2048
+	reviewCommentMaxLen = 2000
+)
+
+// ApplyTeacherInput — заявка на роль преподавателя
+type ApplyTeacherInput struct {
+	// О себе: опыт, чему хотите учить (50–5000 символов)
+	Bio string `json:"bio" example:"Backend developer, 8 years of Go, mentor at local meetups"`
+	// Ссылки на портфолио, GitHub, публикации — до 10
+	PortfolioLinks []string `json:"portfolio_links" example:"https://github.com/example"`
+}
+
+// ReviewTeacherInput — решение администратора; при отказе комментарий обязателен
+type ReviewTeacherInput struct {
+	Comment string `json:"comment,omitempty" example:"Please add links to your previous courses"`
+}
+
+// TeacherApplicationService — заявки студентов на роль преподавателя и их рассмотрение
+type TeacherApplicationService struct {
+	repo     repository.TeacherApplicationRepository
+	users    CredentialsRepository
+	notifier TeacherApplicationNotifier
+}
+
+func NewTeacherApplicationService(
+	repo repository.TeacherApplicationRepository,
+	users CredentialsRepository,
+	notifier TeacherApplicationNotifier,
+) *TeacherApplicationService {
+	return &TeacherApplicationService{repo: repo, users: users, notifier: notifier}
+}
+
+// Apply подаёт заявку от имени студента. Пока заявка на рассмотрении, новую подать нельзя
+func (s *TeacherApplicationService) Apply(ctx context.Context, userID uuid.UUID, in ApplyTeacherInput) (*entity.TeacherApplication, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != entity.RoleStudent {
+		return nil, ErrTeacherApplicationRole
+	}
+	bio := strings.TrimSpace(in.Bio)
+	if n := utf8.RuneCountInString(bio); n < teacherBioMinLen || n > teacherBioMaxLen {
+		return nil, ErrTeacherApplicationBio
+	}
+	links, err := normalizePortfolioLinks(in.PortfolioLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &entity.TeacherApplication{UserID: userID, Bio: bio, PortfolioLinks: links}
+	if err := s.repo.Create(ctx, app); err != nil {
+		return nil, err
+	}
+	s.notify(ctx, user, app)
+	return app, nil
+}
+
+// ListMine — история заявок пользователя вместе с решениями
+func (s *TeacherApplicationService) ListMine(ctx context.Context, userID uuid.UUID) ([]*entity.TeacherApplication, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *TeacherApplicationService) Withdraw(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.Withdraw(ctx, id, userID)
+}
+
+func (s *TeacherApplicationService) List(ctx context.Context, status entity.TeacherApplicationStatus, pag pagination.Params) ([]*entity.TeacherApplication, int, error) {
+	if status != "" && !status.Valid() {
+		return nil, 0, ErrTeacherApplicationStatus
+	}
+	return s.repo.List(ctx, status, pag)
+}
+
+func (s *TeacherApplicationService) Get(ctx context.Context, id uuid.UUID) (*entity.TeacherApplication, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Approve одобряет заявку и назначает пользователю роль teacher
+func (s *TeacherApplicationService) Approve(ctx context.Context, actor AdminActor, id uuid.UUID, in ReviewTeacherInput) (*entity.TeacherApplication, error) {
+	return s.review(ctx, actor, id, entity.TeacherApplicationApproved, in.Comment)
+}
+
+func (s *TeacherApplicationService) Reject(ctx context.Context, actor AdminActor, id uuid.UUID, in ReviewTeacherInput) (*entity.TeacherApplication, error) {
+	return s.review(ctx, actor, id, entity.TeacherApplicationRejected, in.Comment)
+}
+
+func (s *TeacherApplicationService) review(
+	ctx context.Context,
+	actor AdminActor,
+	id uuid.UUID,
+	status entity.TeacherApplicationStatus,
+	comment string,
+) (*entity.TeacherApplication, error) {
+	comment = strings.TrimSpace(comment)
+	if status == entity.TeacherApplicationRejected && comment == "" {
+		return nil, ErrReviewCommentRequired
+	}
+	if utf8.RuneCountInString(comment) > reviewCommentMaxLen {
+		return nil, ErrReviewCommentTooLong
+	}
+	app, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if app.UserID == actor.ID {
+		return nil, ErrAdminSelfAction
+	}
+
+	app.Status = status
+	app.ReviewedBy = &actor.ID
+	app.ReviewComment = nil
+	if comment != "" {
+		app.ReviewComment = &comment
+	}
+	action := entity.AuditTeacherRejected
+	if status == entity.TeacherApplicationApproved {
+		action = entity.AuditTeacherApproved
+	}
+	// Смену роли репозиторий допишет в details в той же транзакции
+	entry := auditEntry(actor, action, app.UserID, map[string]any{"application_id": app.ID, "comment": comment})
+	if _, err := s.repo.Review(ctx, app, entry); err != nil {
+		return nil, err
+	}
+
+	if user, err := s.users.GetByID(ctx, app.UserID); err != nil {
+		log.Printf("teacher application %s: load applicant: %v", app.ID, err)
+	} else {
+		s.notify(ctx, user, app)
+	}
+	return app, nil
+}
+
+// notify не влияет на результат: заявка уже сохранена
+func (s *TeacherApplicationService) notify(ctx context.Context, user *entity.User, app *entity.TeacherApplication) {
+	if err := s.notifier.NotifyTeacherApplication(ctx, user, app); err != nil {
+		log.Printf("teacher application %s: notify %s: %v", app.ID, user.ID, err)
+	}
+}
+
+func normalizePortfolioLinks(raw []string) ([]string, error) {
+	links := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, l := range raw {
+		l = strings.TrimSpace(l)
+		if l == "" || seen[l] {
+			continue
+		}
+		if len(l) > teacherMaxLinkLen {
+			return nil, ErrTeacherApplicationLinks
+		}
+		u, err := url.Parse(l)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, ErrTeacherApplicationLinks
+		}
+		seen[l] = true
+		links = append(links, l)
+	}
+	if len(links) > teacherMaxLinks {
+		return nil, ErrTeacherApplicationLinks
+	}
+	return links, nil
+}
@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/kostinp/edu-platform-backend/internal/shared/geo"
+	"github.com/kostinp/edu-platform-backend/internal/shared/jwtkeys"
+	"github.com/kostinp/edu-platform-backend/internal/user/entity"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+)
+
+var (
+	ErrAccessTokenInvalid  = errors.New("invalid access token")
+	ErrRefreshTokenInvalid = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token already used, session revoked")
+	ErrUserBanned          = errors.New("account is suspended")
+)
+
+// Значение claim "typ" у access-токенов; токены без него (выданные до
+// появления refresh-токенов) не принимаются
+const accessTokenType = "access"
+
+// Сессия поддержки от имени пользователя живёт без продления
+const impersonationTTL = 30 * time.Minute
+
+// Сколько после ротации старый refresh-токен ещё принимается от того же браузера.
+// Две вкладки с общим localStorage обновляют токен почти одновременно, и вторая
+// приходит с уже обменянным токеном — это не кража
+const refreshReuseGrace = 30 * time.Second
+
+// TokenTTL — сроки жизни токенов
+type TokenTTL struct {
+	Access  time.Duration
+	Refresh time.Duration
+}
+
+// SessionMeta — откуда пришёл запрос на вход или обновление токенов
+type SessionMeta struct {
+	IP        string
+	UserAgent string
+	Country   string
+	City      string
+}
+
+// TokenPair — выданные токены; RefreshToken возвращается клиенту один раз и в БД не хранится
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	Session          *entity.UserSession
+}
+
+// AccessClaims — проверенное содержимое access-токена
+type AccessClaims struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	ExpiresAt time.Time
+}
+
+// TokenService выдаёт короткоживущие access-токены (JWT) и непрозрачные
+// refresh-токены с ротацией. Каждое обновление создаёт новую сессию того же
+// семейства и отзывает предыдущую; повторное предъявление старого refresh-токена
+// позже refreshReuseGrace считается кражей и отзывает всё семейство
+type TokenService struct {
+	sessions repository.SessionRepository
+	activity SessionUsecase
+	keys     *jwtkeys.KeySet
+	ttl      TokenTTL
+	geo      *geo.Locator
+	risk     *LoginRiskService
+	users    CredentialsRepository
+}
+
+func NewTokenService(
+	sessions repository.SessionRepository,
+	activity SessionUsecase,
+	keys *jwtkeys.KeySet,
+	ttl TokenTTL,
+	locator *geo.Locator,
+	risk *LoginRiskService,
+	users CredentialsRepository,
+) *TokenService {
+	if ttl.Access <= 0 {
+		ttl.Access = 15 * time.Minute
+	}
+	if ttl.Refresh <= 0 {
+		ttl.Refresh = 30 * 24 * time.Hour
+	}
+	return &TokenService{sessions: sessions, activity: activity, keys: keys, ttl: ttl, geo: locator, risk: risk, users: users}
+}
+
+// Issue оценивает риск входа, создаёт новую сессию (новое семейство) и выдаёт
+// пару токенов. При высоком риске или включённой 2FA возвращает *StepUpRequiredError:
+// токены будут выданы после ввода кода в CompleteStepUp
+func (s *TokenService) Issue(ctx context.Context, userID uuid.UUID, meta SessionMeta) (*TokenPair, error) {
+	// До оценки риска: заблокированному пользователю код подтверждения не отправляется
+	if err := s.checkBanned(ctx, userID); err != nil {
+		return nil, err
+	}
+	meta = s.locate(meta)
+	risk, err := s.risk.Assess(ctx, userID, meta)
+	if err != nil {
+		return nil, err
+	}
+	if risk.StepUp || risk.MFARequired {
+		challenge, err := s.risk.StartStepUp(ctx, userID, risk)
+		switch {
+		case err == nil:
+			return nil, challenge
+		case errors.Is(err, ErrStepUpUnavailable):
+			// Кода не отправить — пускаем, сессия останется помеченной подозрительной
+		default:
+			return nil, err
+		}
+	}
+	return s.issue(ctx, userID, meta, risk)
+}
+
+// CompleteStepUp проверяет код подтверждения входа и выдаёт пару токенов
+func (s *TokenService) CompleteStepUp(ctx context.Context, challengeID uuid.UUID, code string, meta SessionMeta) (*TokenPair, error) {
+	meta = s.locate(meta)
+	userID, risk, err := s.risk.VerifyStepUp(ctx, challengeID, code, meta.IP)
+	if err != nil {
+		return nil, err
+	}
+	// Блокировка могла появиться, пока пользователь вводил код
+	if err := s.checkBanned(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, userID, meta, risk)
+}
+
+func (s *TokenService) issue(ctx context.Context, userID uuid.UUID, meta SessionMeta, risk *LoginRisk) (*TokenPair, error) {
+	now := time.Now()
+	session := s.newSession(userID, uuid.Nil, meta, now)
+	session.DeviceID = risk.DeviceID
+	session.RiskScore = risk.Score
+	session.RiskReasons = risk.Reasons
+	session.Suspicious = risk.Suspicious
+	if risk.MFA {
+		session.MFAVerifiedAt = &now
+	}
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	session.RefreshTokenHash = hashToken(refresh)
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return nil, err
+	}
+	s.risk.AfterLogin(ctx, session, risk)
+	return s.pair(session, refresh, now)
+}
+
+// Refresh обменивает refresh-токен на новую пару
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string, meta SessionMeta) (*TokenPair, error) {
+	return s.refresh(ctx, refreshToken, meta, false)
+}
+
+// retried — повторная попытка после гонки с параллельным обновлением того же токена
+func (s *TokenService) refresh(ctx context.Context, refreshToken string, meta SessionMeta, retried bool) (*TokenPair, error) {
+	if refreshToken == "" {
+		return nil, ErrRefreshTokenInvalid
+	}
+	current, err := s.sessions.FindByRefreshHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current.RevokedAt != nil {
+		// Сессия завершена выходом, таймаутом и т.п. — токен просто недействителен
+		if current.RevokeReason != entity.RevokeReasonRotated {
+			return nil, ErrRefreshTokenInvalid
+		}
+		// Уже обменянный токен: соседняя вкладка или кража
+		current, err = s.graceSuccessor(ctx, current, meta)
+		if err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	if now.After(current.ExpiresAt) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	// Иначе простаивающий клиент продлевал бы сессию обновлением токена
+	err = s.activity.CheckInactivity(ctx, current)
+	if errors.Is(err, ErrSessionInactive) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBanned(ctx, current.UserID); err != nil {
+		return nil, err
+	}
+
+	next := s.newSession(current.UserID, current.FamilyID, meta, now)
+	// Оценка риска относится ко входу и переходит ко всем сессиям семейства
+	next.DeviceID = current.DeviceID
+	next.RiskScore = current.RiskScore
+	next.RiskReasons = current.RiskReasons
+	next.Suspicious = current.Suspicious
+	next.MFAVerifiedAt = current.MFAVerifiedAt
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	next.RefreshTokenHash = hashToken(refresh)
+	err = s.sessions.Rotate(ctx, current.ID, next)
+	if errors.Is(err, repository.ErrSessionRotated) {
+		// Параллельный запрос только что обменял эту сессию: повторяем через
+		// graceSuccessor. Вторая подряд гонка уже подозрительна
+		if !retried {
+			return s.refresh(ctx, refreshToken, meta, true)
+		}
+		if err := s.sessions.RevokeFamily(ctx, current.FamilyID, entity.RevokeReasonRefreshReuse); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.pair(next, refresh, now)
+}
+
+// graceSuccessor решает, что делать с уже обменянным refresh-токеном. В течение
+// refreshReuseGrace тот же браузер получает ротацию действующей сессии семейства;
+// иначе повторное предъявление считается кражей и отзывает всё семейство
+func (s *TokenService) graceSuccessor(ctx context.Context, rotated *entity.UserSession, meta SessionMeta) (*entity.UserSession, error) {
+	if time.Since(*rotated.RevokedAt) <= refreshReuseGrace && rotated.UserAgent == meta.UserAgent {
+		head, err := s.sessions.FindFamilyHead(ctx, rotated.FamilyID)
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			// Семейство уже завершено
+			return nil, ErrRefreshTokenInvalid
+		}
+		return head, err
+	}
+	if err := s.sessions.RevokeFamily(ctx, rotated.FamilyID, entity.RevokeReasonRefreshReuse); err != nil {
+		return nil, err
+	}
+	return nil, ErrRefreshTokenReused
+}
+
+// Impersonate открывает администратору сессию от имени пользователя: без refresh-токена,
+// на impersonationTTL. Второй фактор пользователя в ней не считается пройденным.
+// save сохраняет сессию — вместе с записью в журнале администратора
+func (s *TokenService) Impersonate(ctx context.Context, userID, impersonatorID uuid.UUID, meta SessionMeta, save func(context.Context, *entity.UserSession) error) (*TokenPair, error) {
+	now := time.Now()
+	session := s.newSession(userID, uuid.Nil, meta, now)
+	session.ExpiresAt = now.Add(impersonationTTL)
+	session.ImpersonatorID = &impersonatorID
+	if err := save(ctx, session); err != nil {
+		return nil, err
+	}
+	return s.pair(session, "", now)
+}
+
+// ParseAccessToken проверяет подпись, срок действия и тип access-токена
+func (s *TokenService) ParseAccessToken(tokenString string) (*AccessClaims, error) {
+	token, err := jwt.Parse(tokenString, s.keys.Keyfunc,
+		jwt.WithValidMethods(s.keys.ValidMethods()), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrAccessTokenInvalid
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != accessTokenType {
+		return nil, ErrAccessTokenInvalid
+	}
+	userIDStr, _ := claims["user_id"].(string)
+	sessionIDStr, _ := claims["session_id"].(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, ErrAccessTokenInvalid
+	}
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return nil, ErrAccessTokenInvalid
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return nil, ErrAccessTokenInvalid
+	}
+	return &AccessClaims{UserID: userID, SessionID: sessionID, ExpiresAt: exp.Time}, nil
+}
+
+func (s *TokenService) newSession(userID, familyID uuid.UUID, meta SessionMeta, now time.Time) *entity.UserSession {
+	id := uuid.New()
+	if familyID == uuid.Nil {
+		familyID = id
+	}
+	meta = s.locate(meta)
+	return &entity.UserSession{
+		ID:           id,
+		UserID:       userID,
+		Token:        id.String(),
+		UserAgent:    meta.UserAgent,
+		IPAddress:    meta.IP,
+		Country:      meta.Country,
+		City:         meta.City,
+		CreatedAt:    now,
+		LastActiveAt: now,
+		ExpiresAt:    now.Add(s.ttl.Refresh),
+		FamilyID:     familyID,
+	}
+}
+
+// locate дополняет meta страной и городом по IP
+func (s *TokenService) locate(meta SessionMeta) SessionMeta {
+	if meta.Country == "" && meta.City == "" {
+		meta.Country, meta.City = s.geo.Lookup(meta.IP)
+	}
+	return meta
+}
+
+// checkBanned не даёт войти или продлить сессию заблокированному пользователю
+func (s *TokenService) checkBanned(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsBanned(time.Now()) {
+		return ErrUserBanned
+	}
+	return nil
+}
+
+func (s *TokenService) pair(session *entity.UserSession, refresh string, now time.Time) (*TokenPair, error) {
+	accessExpiresAt := now.Add(s.ttl.Access)
+	// Сессию поддержки нельзя продлить, поэтому access-токен живёт столько же, сколько она
+	if session.ImpersonatorID != nil {
+		accessExpiresAt = session.ExpiresAt
+	}
+	accessToken, err := s.keys.Sign(jwt.MapClaims{
+		"typ":        accessTokenType,
+		"user_id":    session.UserID.String(),
+		"session_id": session.ID.String(),
+		"exp":        accessExpiresAt.Unix(),
+		"iat":        now.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: session.ExpiresAt,
+		Session:          session,
+	}, nil
+}
+
+func newRefreshToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -89,17 +89,18 @@ func (s *UserService) LinkVisitorToUser(ctx context.Context, userID, visitorID u
 	return nil
 }
 
-// GetByTelegramID возвращает пользователя по Telegram ID
+// GetByTelegramID возвращает пользователя по Telegram ID; ErrUserNotFound, если такого нет
 func (s *UserService) GetByTelegramID(ctx context.Context, telegramID int64) (*entity.User, error) {
 	return s.repo.GetByTelegramID(ctx, telegramID)
 }
 
 // CreateFromTelegramAuth создает нового пользователя из Telegram-авторизации
 func (s *UserService) CreateFromTelegramAuth(ctx context.Context, data telegram.AuthData) (*entity.User, error) {
+	fullName := data.FullName()
 	user := &entity.User{
 		TelegramID: &data.ID,
 		Username:   &data.Username,
-		FullName:   &data.FirstName,
+		FullName:   &fullName,
 		Role:       entity.RoleStudent,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
@@ -108,16 +109,32 @@ func (s *UserService) CreateFromTelegramAuth(ctx context.Context, data telegram.
 	return user, err
 }
 
-func (s *UserService) CreateUserSession(
-	ctx context.Context,
-	userID uuid.UUID,
-	ip, userAgent, country, city string,
-) (*entity.UserSession, error) {
-	// Срок жизни — 6 месяцев
-	expiresIn := time.Hour * 24 * 30 * 6
-
-	token := uuid.New().String() // можно использовать как внутренний ID
-	return s.sessionUC.CreateSession(ctx, userID, token, userAgent, ip, country, city, expiresIn)
+// UpsertFromTelegramAuth находит пользователя по Telegram ID и обновляет профиль
+// из Telegram либо создаёт нового. Создаёт только при ErrUserNotFound: сбой базы
+// не должен плодить дубликаты аккаунтов
+func (s *UserService) UpsertFromTelegramAuth(ctx context.Context, data telegram.AuthData) (*entity.User, error) {
+	user, err := s.repo.GetByTelegramID(ctx, data.ID)
+	if errors.Is(err, ErrUserNotFound) {
+		return s.CreateFromTelegramAuth(ctx, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fullName := data.FullName()
+	if data.Username != "" {
+		user.Username = &data.Username
+	}
+	if fullName != "" {
+		user.FullName = &fullName
+	}
+	if data.PhotoURL != "" {
+		user.PhotoURL = &data.PhotoURL
+	}
+	user.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("не удалось обновить пользователя: %w", err)
+	}
+	return user, nil
 }
 
 func (s *UserService) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
@@ -31,10 +31,68 @@ var UserRepoSet = wire.NewSet(
 	wire.Bind(new(usecase.UserRepository), new(*repository.PostgresUserRepository)),
 )
 
+// EmailAuthRepoSet - набор для входа по email
+var EmailAuthRepoSet = wire.NewSet(
+	repository.NewPostgresAuthTokenRepository,
+	wire.Bind(new(repository.AuthTokenRepository), new(*repository.PostgresAuthTokenRepository)),
+	wire.Bind(new(usecase.CredentialsRepository), new(*repository.PostgresUserRepository)),
+)
+
+// OIDCRepoSet - набор для входа через внешних провайдеров
+var OIDCRepoSet = wire.NewSet(
+	repository.NewPostgresIdentityRepository,
+	wire.Bind(new(repository.IdentityRepository), new(*repository.PostgresIdentityRepository)),
+	wire.Bind(new(usecase.OIDCUserRepository), new(*repository.PostgresUserRepository)),
+)
+
+// MergeRepoSet - набор для слияния аккаунтов
+var MergeRepoSet = wire.NewSet(
+	repository.NewPostgresMergeRepository,
+	wire.Bind(new(repository.MergeRepository), new(*repository.PostgresMergeRepository)),
+)
+
+// LoginRiskRepoSet - набор для оценки риска входа
+var LoginRiskRepoSet = wire.NewSet(
+	repository.NewPostgresLoginRiskRepository,
+	wire.Bind(new(repository.LoginRiskRepository), new(*repository.PostgresLoginRiskRepository)),
+)
+
+// MFARepoSet - набор для двухфакторной аутентификации
+var MFARepoSet = wire.NewSet(
+	repository.NewPostgresMFARepository,
+	wire.Bind(new(repository.MFARepository), new(*repository.PostgresMFARepository)),
+)
+
+// APITokenRepoSet - набор для персональных API-токенов
+var APITokenRepoSet = wire.NewSet(
+	repository.NewPostgresAPITokenRepository,
+	wire.Bind(new(repository.APITokenRepository), new(*repository.PostgresAPITokenRepository)),
+)
+
+// AccountRepoSet - набор для профиля, выгрузки и удаления аккаунта
+var AccountRepoSet = wire.NewSet(
+	repository.NewPostgresAccountRepository,
+	wire.Bind(new(repository.AccountRepository), new(*repository.PostgresAccountRepository)),
+)
+
+// AdminUserRepoSet - набор для управления пользователями в админке
+var AdminUserRepoSet = wire.NewSet(
+	repository.NewPostgresAdminUserRepository,
+	wire.Bind(new(repository.AdminUserRepository), new(*repository.PostgresAdminUserRepository)),
+)
+
+// TeacherApplicationRepoSet - набор для заявок на роль преподавателя
+var TeacherApplicationRepoSet = wire.NewSet(
+	repository.NewPostgresTeacherApplicationRepository,
+	wire.Bind(new(repository.TeacherApplicationRepository), new(*repository.PostgresTeacherApplicationRepository)),
+)
+
 // SessionRepoSet - набор для сессий
 var SessionRepoSet = wire.NewSet(
 	repository.NewPostgresSessionRepository,
-	wire.Bind(new(repository.SessionRepository), new(*repository.PostgresSessionRepository)),
+	ProvideSessionRevocationCache,
+	repository.NewCachedSessionRepository,
+	wire.Bind(new(repository.SessionRepository), new(*repository.CachedSessionRepository)),
 )
 
 var UserSet = wire.NewSet(
@@ -55,10 +113,63 @@ var UserSet = wire.NewSet(
 	http.NewVisitorEventHandler,
 	http.NewSessionHandler,
 	http.NewAnalyticsHandler,
+	// --- Tokens ---
+	ProvideJWTKeySet,
+	ProvideTokenTTL,
+	ProvideGeoLocator,
+	// --- MFA ---
+	MFARepoSet,
+	ProvideMFAIssuer,
+	ProvideEmailAuthLimits,
+	usecase.NewMFAService,
+	http.NewMFAHandler,
+	// --- API Tokens ---
+	APITokenRepoSet,
+	usecase.NewAPITokenService,
+	http.NewAPITokenHandler,
+	// --- Login Risk ---
+	LoginRiskRepoSet,
+	ProvideLoginRiskPolicy,
+	ProvideLoginNotifier,
+	usecase.NewLoginRiskService,
+	usecase.NewTokenService,
+	http.NewTokenHandler,
+	http.NewJWKSHandler,
+	// --- Account Merge ---
+	MergeRepoSet,
+	usecase.NewMergeService,
+	http.NewMergeHandler,
 	// --- Telegram Auth ---
 	ProvideBotToken,
-	ProvideJwtSecret,
+	ProvideTelegramReplayCache,
+	ProvideTelegramLoginVerifier,
 	http.NewTelegramAuthHandler,
+	// --- Email Auth ---
+	EmailAuthRepoSet,
+	ProvideMailer,
+	ProvideAuthLinkBaseURL,
+	usecase.NewEmailAuthService,
+	http.NewEmailAuthHandler,
+	// --- Account ---
+	AccountRepoSet,
+	usecase.NewAccountService,
+	http.NewAccountHandler,
+	// --- Admin Users ---
+	AdminUserRepoSet,
+	usecase.NewAdminUserService,
+	http.NewAdminUserHandler,
+	// --- Teacher Applications ---
+	TeacherApplicationRepoSet,
+	ProvideTeacherApplicationNotifier,
+	usecase.NewTeacherApplicationService,
+	http.NewTeacherApplicationHandler,
+	// --- OIDC ---
+	OIDCRepoSet,
+	ProvideOIDCMockProvider,
+	ProvideOIDCClients,
+	ProvideOIDCStateStore,
+	usecase.NewOIDCService,
+	http.NewOIDCHandler,
 )
 
 // SessionUsecaseSet - отдельный набор для middleware и фоновых задач
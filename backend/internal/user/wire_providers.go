@@ -1,20 +1,224 @@
 package user
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ClickHouse/clickhouse-go/v2"
+	bot_client "github.com/kostinp/edu-platform-backend/internal/bot/client"
 	"github.com/kostinp/edu-platform-backend/internal/shared/config"
 	"github.com/kostinp/edu-platform-backend/internal/shared/db"
+	"github.com/kostinp/edu-platform-backend/internal/shared/geo"
+	"github.com/kostinp/edu-platform-backend/internal/shared/jwtkeys"
+	"github.com/kostinp/edu-platform-backend/internal/shared/logger"
+	"github.com/kostinp/edu-platform-backend/internal/shared/mailer"
+	"github.com/kostinp/edu-platform-backend/internal/shared/oidc"
+	"github.com/kostinp/edu-platform-backend/internal/shared/oidc/mock"
+	"github.com/kostinp/edu-platform-backend/internal/shared/ratelimit"
+	"github.com/kostinp/edu-platform-backend/internal/shared/telegram"
+	"github.com/kostinp/edu-platform-backend/internal/user/repository"
+	"github.com/kostinp/edu-platform-backend/internal/user/usecase"
 )
 
 func ProvideBotToken(cfg *config.Config) config.BotToken {
 	return config.BotToken(cfg.Telegram.Token)
 }
 
-func ProvideJwtSecret(cfg *config.Config) config.JwtSecret {
-	return config.JwtSecret(cfg.JWT.Secret)
+// ProvideJWTKeySet — асимметричные ключи из jwt.keys_dir или HS256 с общим секретом
+func ProvideJWTKeySet(cfg *config.Config) (*jwtkeys.KeySet, error) {
+	if cfg.JWT.KeysDir != "" {
+		return jwtkeys.Load(cfg.JWT.KeysDir)
+	}
+	return jwtkeys.NewHMAC([]byte(cfg.JWT.Secret)), nil
+}
+
+func ProvideTokenTTL(cfg *config.Config) usecase.TokenTTL {
+	return usecase.TokenTTL{
+		Access:  time.Duration(cfg.JWT.AccessTTLMinutes) * time.Minute,
+		Refresh: time.Duration(cfg.JWT.RefreshTTLDays) * 24 * time.Hour,
+	}
+}
+
+// ProvideGeoLocator открывает базу GeoIP; без неё страна и город сессий — Unknown
+func ProvideGeoLocator(cfg *config.Config) (*geo.Locator, error) {
+	return geo.NewLocator(cfg.GeoIP.DatabasePath, cfg.GeoIP.Language)
+}
+
+// ProvideLoginRiskPolicy — пороги оценки риска входа
+func ProvideLoginRiskPolicy(cfg *config.Config) usecase.LoginRiskPolicy {
+	return usecase.LoginRiskPolicy{
+		FlagScore:        cfg.LoginRisk.FlagScore,
+		StepUpScore:      cfg.LoginRisk.StepUpScore,
+		ImpossibleTravel: time.Duration(cfg.LoginRisk.ImpossibleTravelMinutes) * time.Minute,
+	}
+}
+
+// ProvideEmailAuthLimits — лимиты входа по email в Redis (общие для инстансов) или в памяти процесса
+func ProvideEmailAuthLimits(cfg *config.Config) *usecase.EmailAuthLimits {
+	c := cfg.AuthRateLimit
+	loginWindow := minutesOr(c.LoginWindowMinutes, 15)
+	mailWindow := minutesOr(c.MailWindowMinutes, 60)
+	var redis *db.Redis
+	if cfg.Redis.URL != "" {
+		r, err := db.NewRedis(cfg.Redis.URL)
+		if err != nil {
+			logger.Error("Некорректный REDIS_URL, лимиты входа будут в памяти", err)
+		} else {
+			redis = r
+		}
+	}
+	newLimiter := func(prefix string, limit, def int, window time.Duration) ratelimit.Limiter {
+		if limit <= 0 {
+			limit = def
+		}
+		if redis != nil {
+			return ratelimit.NewRedis(redis.Client, prefix, limit, window)
+		}
+		return ratelimit.NewMemory(limit, window)
+	}
+	return &usecase.EmailAuthLimits{
+		LoginByIP:    newLimiter("auth_rl:login:ip:", c.LoginPerIP, 100, loginWindow),
+		LoginByEmail: newLimiter("auth_rl:login:email:", c.LoginPerEmail, 10, loginWindow),
+		MailByIP:     newLimiter("auth_rl:mail:ip:", c.MailPerIP, 60, mailWindow),
+		MailByEmail:  newLimiter("auth_rl:mail:email:", c.MailPerEmail, 5, mailWindow),
+	}
+}
+
+func minutesOr(minutes, def int) time.Duration {
+	if minutes <= 0 {
+		minutes = def
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ProvideMFAIssuer — название сервиса в приложении-аутентификаторе
+func ProvideMFAIssuer(cfg *config.Config) usecase.MFAIssuer {
+	return usecase.MFAIssuer(cfg.MFA.Issuer)
+}
+
+// ProvideLoginNotifier — уведомления о входах на email и через бота в Telegram
+func ProvideLoginNotifier(m mailer.Mailer, botToken config.BotToken, baseURL usecase.AuthLinkBaseURL) usecase.LoginNotifier {
+	var telegram usecase.TelegramSender
+	if botToken != "" {
+		telegram = bot_client.NewTelegramAPI(botToken)
+	}
+	return usecase.NewChannelLoginNotifier(m, telegram, baseURL)
+}
+
+// ProvideTeacherApplicationNotifier — уведомления о заявках на роль преподавателя
+func ProvideTeacherApplicationNotifier(m mailer.Mailer, botToken config.BotToken, baseURL usecase.AuthLinkBaseURL) usecase.TeacherApplicationNotifier {
+	var telegram usecase.TelegramSender
+	if botToken != "" {
+		telegram = bot_client.NewTelegramAPI(botToken)
+	}
+	return usecase.NewChannelApplicationNotifier(m, telegram, baseURL)
 }
 
 // ProvideClickHouseConn предоставляет подключение к ClickHouse
 func ProvideClickHouseConn(cfg *config.Config) clickhouse.Conn {
 	return db.ConnectClickhouse(cfg)
 }
+
+// ProvideMailer выбирает SMTP или запись писем в файлы (по умолчанию)
+func ProvideMailer(cfg *config.Config) mailer.Mailer {
+	if cfg.Mail.Driver == "smtp" {
+		return mailer.NewSMTPMailer(cfg.Mail.SMTP.Host, cfg.Mail.SMTP.Port, cfg.Mail.SMTP.Username, cfg.Mail.SMTP.Password, cfg.Mail.From)
+	}
+	dir := cfg.Mail.FileDir
+	if dir == "" {
+		dir = "./tmp/mail"
+	}
+	return mailer.NewFileMailer(dir, cfg.Mail.From)
+}
+
+func ProvideAuthLinkBaseURL(cfg *config.Config) usecase.AuthLinkBaseURL {
+	return usecase.AuthLinkBaseURL(cfg.Mail.LinkBaseURL)
+}
+
+// ProvideTelegramReplayCache выбирает Redis, если он настроен, иначе кэш в памяти
+func ProvideTelegramReplayCache(cfg *config.Config) telegram.ReplayCache {
+	if cfg.Redis.URL == "" {
+		return telegram.NewMemoryReplayCache()
+	}
+	r, err := db.NewRedis(cfg.Redis.URL)
+	if err != nil {
+		logger.Error("Некорректный REDIS_URL, replay-кэш Telegram будет в памяти", err)
+		return telegram.NewMemoryReplayCache()
+	}
+	return telegram.NewRedisReplayCache(r.Client, "tg_auth:")
+}
+
+// ProvideSessionRevocationCache выбирает Redis, если он настроен, иначе кэш в памяти
+func ProvideSessionRevocationCache(cfg *config.Config) repository.RevocationCache {
+	if cfg.Redis.URL == "" {
+		return repository.NewMemoryRevocationCache()
+	}
+	r, err := db.NewRedis(cfg.Redis.URL)
+	if err != nil {
+		logger.Error("Некорректный REDIS_URL, кэш отзыва сессий будет в памяти", err)
+		return repository.NewMemoryRevocationCache()
+	}
+	return repository.NewRedisRevocationCache(r.Client, "session_revoked:")
+}
+
+func ProvideTelegramLoginVerifier(cfg *config.Config, botToken config.BotToken, cache telegram.ReplayCache) *telegram.LoginVerifier {
+	maxAge := time.Duration(cfg.Telegram.AuthMaxAgeSeconds) * time.Second
+	return telegram.NewLoginVerifier(string(botToken), maxAge, cache)
+}
+
+// ProvideOIDCMockProvider создаёт встроенный тестовый провайдер; nil, если он выключен
+func ProvideOIDCMockProvider(cfg *config.Config) *mock.Provider {
+	if !cfg.OIDC.Mock.Enabled {
+		return nil
+	}
+	issuer := cfg.OIDC.Mock.Issuer
+	if issuer == "" {
+		issuer = fmt.Sprintf("http://localhost:%d/api/dev/oidc", cfg.App.Port)
+	}
+	provider, err := mock.NewProvider(issuer)
+	if err != nil {
+		logger.Error("Не удалось запустить тестовый OIDC-провайдер", err)
+		return nil
+	}
+	return provider
+}
+
+// ProvideOIDCClients создаёт клиентов для провайдеров с заданным client_id
+func ProvideOIDCClients(cfg *config.Config, mockProvider *mock.Provider) usecase.OIDCClients {
+	clients := usecase.OIDCClients{}
+	for _, p := range cfg.OIDC.Providers {
+		if p.Name == "" || p.ClientID == "" {
+			continue
+		}
+		clients[p.Name] = oidc.NewClient(oidc.ProviderConfig{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, nil)
+	}
+	if mockProvider != nil {
+		clients["mock"] = oidc.NewClient(oidc.ProviderConfig{
+			Name:        "mock",
+			Issuer:      mockProvider.Issuer(),
+			ClientID:    mock.ClientID,
+			RedirectURL: cfg.OIDC.Mock.RedirectURL,
+		}, mockProvider.HTTPClient())
+	}
+	return clients
+}
+
+// ProvideOIDCStateStore выбирает Redis, если он настроен, иначе хранилище в памяти
+func ProvideOIDCStateStore(cfg *config.Config) oidc.StateStore {
+	if cfg.Redis.URL == "" {
+		return oidc.NewMemoryStateStore()
+	}
+	r, err := db.NewRedis(cfg.Redis.URL)
+	if err != nil {
+		logger.Error("Некорректный REDIS_URL, state OIDC будет в памяти", err)
+		return oidc.NewMemoryStateStore()
+	}
+	return oidc.NewRedisStateStore(r.Client, "oidc_state:")
+}
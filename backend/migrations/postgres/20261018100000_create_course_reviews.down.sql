@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_courses_rating;
+DROP INDEX IF EXISTS idx_course_reviews_status;
+DROP INDEX IF EXISTS idx_course_reviews_course_status;
+DROP INDEX IF EXISTS idx_course_enrollments_user;
+
+ALTER TABLE courses DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE courses DROP COLUMN IF EXISTS rating_avg;
+
+DROP TABLE IF EXISTS course_reviews;
+DROP TABLE IF EXISTS course_enrollments;
@@ -0,0 +1,35 @@
+-- Записи на курсы
+CREATE TABLE course_enrollments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+
+    UNIQUE(course_id, user_id)
+);
+
+-- Отзывы о курсах (один отзыв на пользователя и курс)
+CREATE TABLE course_reviews (
+    id UUID PRIMARY KEY,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text TEXT,
+    status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'approved', 'rejected'
+    rejection_reason TEXT,
+    moderated_by UUID REFERENCES users(id),
+    moderated_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+
+    UNIQUE(course_id, author_id)
+);
+
+-- Агрегаты рейтинга считаются только по одобренным отзывам
+ALTER TABLE courses ADD COLUMN rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0;
+ALTER TABLE courses ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_course_enrollments_user ON course_enrollments(user_id);
+CREATE INDEX idx_course_reviews_course_status ON course_reviews(course_id, status);
+CREATE INDEX idx_course_reviews_status ON course_reviews(status, created_at);
+CREATE INDEX idx_courses_rating ON courses(rating_avg DESC, rating_count DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_lesson_comment_revisions_comment;
+DROP INDEX IF EXISTS idx_lesson_comments_root;
+DROP INDEX IF EXISTS idx_lesson_comments_lesson_root;
+
+DROP TABLE IF EXISTS lesson_comment_revisions;
+DROP TABLE IF EXISTS lesson_comments;
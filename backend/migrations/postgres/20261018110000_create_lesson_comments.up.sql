@@ -0,0 +1,29 @@
+-- Обсуждения уроков (древовидные комментарии)
+CREATE TABLE lesson_comments (
+    id UUID PRIMARY KEY,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES lesson_comments(id) ON DELETE CASCADE,
+    root_id UUID REFERENCES lesson_comments(id) ON DELETE CASCADE, -- корень ветки, NULL для корневых комментариев
+    author_id UUID NOT NULL REFERENCES users(id),
+    body TEXT NOT NULL,
+    is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    is_answer BOOLEAN NOT NULL DEFAULT FALSE,
+    edited_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    deleted_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- История правок: предыдущие версии текста
+CREATE TABLE lesson_comment_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID NOT NULL REFERENCES lesson_comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_lesson_comments_lesson_root ON lesson_comments(lesson_id, created_at) WHERE parent_id IS NULL;
+CREATE INDEX idx_lesson_comments_root ON lesson_comments(root_id);
+CREATE INDEX idx_lesson_comment_revisions_comment ON lesson_comment_revisions(comment_id, created_at);
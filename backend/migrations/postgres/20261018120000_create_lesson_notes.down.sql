@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_lesson_notes_search;
+DROP INDEX IF EXISTS idx_lesson_notes_user_lesson;
+
+DROP TABLE IF EXISTS lesson_notes;
@@ -0,0 +1,22 @@
+-- Личные заметки и выделения в уроках (видны только владельцу)
+CREATE TABLE lesson_notes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL DEFAULT 'note', -- 'note' или 'highlight'
+    body TEXT NOT NULL DEFAULT '',
+    quote TEXT NOT NULL DEFAULT '', -- выделенный фрагмент текста урока
+    anchor_start INTEGER, -- смещения в символах внутри lessons.content
+    anchor_end INTEGER,
+    color TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('russian', body || ' ' || quote)
+    ) STORED,
+
+    CHECK (anchor_start IS NULL OR (anchor_start >= 0 AND anchor_end > anchor_start))
+);
+
+CREATE INDEX idx_lesson_notes_user_lesson ON lesson_notes(user_id, lesson_id);
+CREATE INDEX idx_lesson_notes_search ON lesson_notes USING GIN(search_vector);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_bookmarks_target;
+DROP INDEX IF EXISTS idx_bookmarks_user_folder;
+DROP INDEX IF EXISTS idx_bookmark_folders_user;
+
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS bookmark_folders;
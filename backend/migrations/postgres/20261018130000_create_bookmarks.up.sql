@@ -0,0 +1,31 @@
+-- Папки закладок пользователя
+CREATE TABLE bookmark_folders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    ordinal INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+
+    UNIQUE(user_id, name)
+);
+
+-- Закладки (избранное): полиморфная ссылка по аналогии с category_assignments
+CREATE TABLE bookmarks (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    folder_id UUID REFERENCES bookmark_folders(id) ON DELETE SET NULL, -- NULL — без папки
+    target_type TEXT NOT NULL, -- 'course', 'module', 'lesson'
+    target_id UUID NOT NULL,
+    ordinal INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+
+    -- Один ресурс можно добавить в закладки только один раз
+    UNIQUE(user_id, target_type, target_id),
+    CHECK (target_type IN ('course', 'module', 'lesson'))
+);
+
+CREATE INDEX idx_bookmark_folders_user ON bookmark_folders(user_id, ordinal);
+CREATE INDEX idx_bookmarks_user_folder ON bookmarks(user_id, folder_id, ordinal);
+CREATE INDEX idx_bookmarks_target ON bookmarks(target_type, target_id);
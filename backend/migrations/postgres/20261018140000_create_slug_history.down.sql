@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_slug_history_entity;
+
+DROP TABLE IF EXISTS slug_history;
@@ -0,0 +1,13 @@
+-- Прежние slug курсов и категорий для редиректа со старых URL после переименования
+CREATE TABLE slug_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type TEXT NOT NULL, -- 'course', 'category'
+    entity_id UUID NOT NULL,
+    slug TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+
+    -- Старый slug ведёт ровно на одну сущность
+    UNIQUE(entity_type, slug)
+);
+
+CREATE INDEX idx_slug_history_entity ON slug_history(entity_type, entity_id);
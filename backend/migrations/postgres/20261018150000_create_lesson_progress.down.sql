@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_lesson_progress_user_course;
+DROP INDEX IF EXISTS idx_lesson_progress_user_viewed;
+
+DROP TABLE IF EXISTS learning_activity_days;
+DROP TABLE IF EXISTS lesson_progress;
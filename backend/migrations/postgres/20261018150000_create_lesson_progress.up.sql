@@ -0,0 +1,22 @@
+-- Прогресс пользователя по урокам
+CREATE TABLE lesson_progress (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE, -- денормализовано для выборок по курсу
+    last_viewed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, lesson_id)
+);
+
+-- Дни, в которые пользователь занимался (для серии занятий)
+CREATE TABLE learning_activity_days (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+
+    PRIMARY KEY (user_id, day)
+);
+
+CREATE INDEX idx_lesson_progress_user_viewed ON lesson_progress(user_id, last_viewed_at DESC);
+CREATE INDEX idx_lesson_progress_user_course ON lesson_progress(user_id, course_id);
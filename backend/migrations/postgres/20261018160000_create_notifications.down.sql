@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_deliveries_user;
+DROP INDEX IF EXISTS idx_notification_deliveries_dedup;
+
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS notification_preferences;
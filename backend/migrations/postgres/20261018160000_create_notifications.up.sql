@@ -0,0 +1,30 @@
+-- Настройки уведомлений (нет строки — действуют значения по умолчанию)
+CREATE TABLE notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    language TEXT NOT NULL DEFAULT 'ru' CHECK (language IN ('ru', 'en')),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    inactivity_reminder BOOLEAN NOT NULL DEFAULT TRUE,
+    inactivity_days INT NOT NULL DEFAULT 3 CHECK (inactivity_days BETWEEN 1 AND 30),
+    new_module BOOLEAN NOT NULL DEFAULT TRUE,
+    assignment_due BOOLEAN NOT NULL DEFAULT TRUE,
+    certificate_issued BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Журнал доставки уведомлений
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    dedup_key TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL, -- 'sent', 'failed', 'rate_limited'
+    error TEXT,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Одно успешно доставленное уведомление на событие
+CREATE UNIQUE INDEX idx_notification_deliveries_dedup ON notification_deliveries(user_id, kind, dedup_key)
+    WHERE status = 'sent' AND dedup_key <> '';
+CREATE INDEX idx_notification_deliveries_user ON notification_deliveries(user_id, created_at DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_auth_tokens_user_purpose;
+DROP TABLE IF EXISTS auth_tokens;
+
+DROP INDEX IF EXISTS idx_users_email_lower;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS password_hash;
@@ -0,0 +1,20 @@
+-- Вход по email и паролю
+ALTER TABLE users ADD COLUMN password_hash TEXT;
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- Один активный аккаунт на email без учёта регистра
+CREATE UNIQUE INDEX idx_users_email_lower ON users (lower(email))
+    WHERE email IS NOT NULL AND deleted_at IS NULL;
+
+-- Одноразовые токены из писем; хранится только SHA-256 от токена
+CREATE TABLE auth_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose TEXT NOT NULL CHECK (purpose IN ('email_verification', 'password_reset', 'magic_link')),
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_tokens_user_purpose ON auth_tokens(user_id, purpose);
@@ -0,0 +1,14 @@
+-- Внешние аккаунты (OIDC), привязанные к пользователю. У пользователя может быть
+-- несколько провайдеров, но не больше одного аккаунта на провайдера
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL, -- claim sub из ID token
+    email TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    last_login_at TIMESTAMP,
+
+    UNIQUE(provider, subject),
+    UNIQUE(user_id, provider)
+);
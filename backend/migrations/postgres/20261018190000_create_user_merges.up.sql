@@ -0,0 +1,14 @@
+-- Журнал слияний аккаунтов: источник после слияния помечается удалённым
+CREATE TABLE user_merges (
+    id UUID PRIMARY KEY,
+    source_user_id UUID NOT NULL REFERENCES users(id),
+    target_user_id UUID NOT NULL REFERENCES users(id),
+    initiated_by UUID REFERENCES users(id), -- NULL — слияние при входе через Telegram
+    reason TEXT NOT NULL, -- 'telegram_login', 'self', 'admin'
+    source_snapshot JSONB NOT NULL DEFAULT '{}'::jsonb, -- telegram_id, email, visitor_id источника до слияния
+    moved JSONB NOT NULL DEFAULT '{}'::jsonb, -- сколько записей перенесено по каждому виду данных
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_merges_source ON user_merges(source_user_id);
+CREATE INDEX idx_user_merges_target ON user_merges(target_user_id, created_at DESC);
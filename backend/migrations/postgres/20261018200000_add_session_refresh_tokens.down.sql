@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_sessions_family;
+DROP INDEX IF EXISTS idx_user_sessions_refresh_token_hash;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS family_id;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS refresh_token_hash;
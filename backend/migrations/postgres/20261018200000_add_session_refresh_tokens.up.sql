@@ -0,0 +1,12 @@
+-- Refresh-токены: в сессии хранится только SHA-256 от токена.
+-- При ротации старая строка помечается revoked_at, новая получает тот же family_id;
+-- предъявление уже использованного токена отзывает всё семейство
+ALTER TABLE user_sessions ADD COLUMN refresh_token_hash TEXT;
+ALTER TABLE user_sessions ADD COLUMN family_id UUID;
+
+UPDATE user_sessions SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE user_sessions ALTER COLUMN family_id SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_user_sessions_refresh_token_hash ON user_sessions(refresh_token_hash)
+    WHERE refresh_token_hash IS NOT NULL;
+CREATE INDEX idx_user_sessions_family ON user_sessions(family_id);
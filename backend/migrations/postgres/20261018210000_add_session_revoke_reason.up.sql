@@ -0,0 +1,8 @@
+-- Сессии больше не удаляются при выходе: строка остаётся с revoked_at и причиной
+-- до истечения expires_at (история входов), затем её удаляет задача очистки
+ALTER TABLE user_sessions ADD COLUMN revoke_reason TEXT;
+
+UPDATE user_sessions SET revoke_reason = 'rotated' WHERE revoked_at IS NOT NULL AND refresh_token_hash IS NOT NULL;
+UPDATE user_sessions SET is_current = (revoked_at IS NULL);
+
+CREATE INDEX idx_user_sessions_user_active ON user_sessions(user_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS user_login_countries;
+DROP TABLE IF EXISTS user_devices;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS suspicious;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS risk_reasons;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS risk_score;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS device_id;
@@ -0,0 +1,52 @@
+-- Оценка риска входа: отпечаток устройства и результат проверки хранятся в сессии
+ALTER TABLE user_sessions ADD COLUMN device_id TEXT;
+ALTER TABLE user_sessions ADD COLUMN risk_score SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE user_sessions ADD COLUMN risk_reasons TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE user_sessions ADD COLUMN suspicious BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Устройства и страны, из которых пользователь уже входил. Отдельно от сессий,
+-- потому что сессии удаляются после истечения
+CREATE TABLE user_devices (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id TEXT NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, device_id)
+);
+
+CREATE TABLE user_login_countries (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    country TEXT NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, country)
+);
+
+-- Неудачные попытки входа; хранятся сутки
+CREATE TABLE login_attempts (
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    identifier TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_login_attempts_user ON login_attempts(user_id, created_at) WHERE user_id IS NOT NULL;
+CREATE INDEX idx_login_attempts_ip ON login_attempts(ip_address, created_at);
+
+-- Подтверждение рискованного входа одноразовым кодом (step-up)
+CREATE TABLE login_challenges (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    risk_score SMALLINT NOT NULL,
+    risk_reasons TEXT[] NOT NULL DEFAULT '{}',
+    device_id TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_login_challenges_user ON login_challenges(user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS mfa_verified_at;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
@@ -0,0 +1,25 @@
+-- Двухфакторная аутентификация: TOTP и одноразовые коды восстановления
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    -- NULL — подключение начато, но не подтверждено кодом
+    enabled_at TIMESTAMP,
+    -- Последний принятый интервал: код нельзя использовать повторно
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE user_recovery_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_user_recovery_codes_user ON user_recovery_codes(user_id);
+
+-- Когда в сессии пройден второй фактор (env.mfa в ABAC)
+ALTER TABLE user_sessions ADD COLUMN mfa_verified_at TIMESTAMP;
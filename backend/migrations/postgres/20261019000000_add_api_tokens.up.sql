@@ -0,0 +1,19 @@
+-- Персональные API-токены для скриптов и интеграций
+CREATE TABLE api_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    -- Начало токена — чтобы пользователь узнал его в списке
+    prefix TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    -- Пары resource:action из ABAC, допускается *
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    -- Выпущен из сессии, прошедшей 2FA (env.mfa)
+    mfa BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    last_used_ip TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+CREATE INDEX idx_api_tokens_user ON api_tokens(user_id);
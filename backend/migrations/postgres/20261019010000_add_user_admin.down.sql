@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS admin_audit_log;
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS impersonator_id;
+ALTER TABLE users DROP COLUMN IF EXISTS banned_by;
+ALTER TABLE users DROP COLUMN IF EXISTS ban_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS banned_until;
+ALTER TABLE users DROP COLUMN IF EXISTS banned_at;
@@ -0,0 +1,21 @@
+-- Блокировка пользователей администратором; banned_until NULL — бессрочно
+ALTER TABLE users ADD COLUMN banned_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN banned_until TIMESTAMP;
+ALTER TABLE users ADD COLUMN ban_reason TEXT;
+ALTER TABLE users ADD COLUMN banned_by UUID REFERENCES users(id);
+
+-- Сессия, открытая администратором от имени пользователя (поддержка)
+ALTER TABLE user_sessions ADD COLUMN impersonator_id UUID REFERENCES users(id);
+
+-- Журнал действий администраторов над пользователями
+CREATE TABLE admin_audit_log (
+    id UUID PRIMARY KEY,
+    actor_id UUID NOT NULL REFERENCES users(id),
+    action TEXT NOT NULL,
+    target_user_id UUID REFERENCES users(id),
+    details JSONB NOT NULL DEFAULT '{}',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_admin_audit_log_target ON admin_audit_log(target_user_id, created_at DESC);
+CREATE INDEX idx_admin_audit_log_actor ON admin_audit_log(actor_id, created_at DESC);
@@ -0,0 +1,17 @@
+-- Заявки студентов на роль преподавателя
+CREATE TABLE teacher_applications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    bio TEXT NOT NULL,
+    portfolio_links TEXT[] NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending',
+    reviewed_by UUID REFERENCES users(id),
+    review_comment TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_teacher_applications_user ON teacher_applications(user_id, created_at DESC);
+CREATE INDEX idx_teacher_applications_status ON teacher_applications(status, created_at);
+-- У пользователя не больше одной заявки на рассмотрении
+CREATE UNIQUE INDEX uniq_teacher_applications_pending ON teacher_applications(user_id) WHERE status = 'pending';
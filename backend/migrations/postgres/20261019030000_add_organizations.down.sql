@@ -0,0 +1,7 @@
+ALTER TABLE tags DROP COLUMN IF EXISTS org_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS org_id;
+ALTER TABLE courses DROP COLUMN IF EXISTS org_id;
+ALTER TABLE users DROP COLUMN IF EXISTS org_joined_at;
+ALTER TABLE users DROP COLUMN IF EXISTS org_role;
+ALTER TABLE users DROP COLUMN IF EXISTS org_id;
+DROP TABLE IF EXISTS organizations;
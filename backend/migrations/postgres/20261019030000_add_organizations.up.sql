@@ -0,0 +1,23 @@
+-- Организации (школы) со своим закрытым пространством
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Пользователь состоит не больше чем в одной организации; org_role: owner, admin, member
+ALTER TABLE users ADD COLUMN org_id UUID REFERENCES organizations(id);
+ALTER TABLE users ADD COLUMN org_role TEXT;
+ALTER TABLE users ADD COLUMN org_joined_at TIMESTAMP;
+CREATE INDEX idx_users_org ON users(org_id) WHERE org_id IS NOT NULL;
+
+-- Контент организации виден только её участникам; NULL — общий каталог
+ALTER TABLE courses ADD COLUMN org_id UUID REFERENCES organizations(id);
+ALTER TABLE categories ADD COLUMN org_id UUID REFERENCES organizations(id);
+ALTER TABLE tags ADD COLUMN org_id UUID REFERENCES organizations(id);
+CREATE INDEX idx_courses_org ON courses(org_id) WHERE org_id IS NOT NULL;
+CREATE INDEX idx_categories_org ON categories(org_id) WHERE org_id IS NOT NULL;
+CREATE INDEX idx_tags_org ON tags(org_id) WHERE org_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS study_group_courses;
+DROP TABLE IF EXISTS study_group_members;
+DROP TABLE IF EXISTS study_groups;
@@ -0,0 +1,31 @@
+-- Учебные группы преподавателей: состав, код приглашения, назначенные курсы
+CREATE TABLE study_groups (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    author_id UUID NOT NULL REFERENCES users(id),
+    -- Группа организации принимает только её участников
+    org_id UUID REFERENCES organizations(id),
+    join_code TEXT NOT NULL UNIQUE,
+    join_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_study_groups_author ON study_groups(author_id);
+
+CREATE TABLE study_group_members (
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX idx_study_group_members_user ON study_group_members(user_id);
+
+-- Курсы группы: участники записываются на них при назначении и при вступлении
+CREATE TABLE study_group_courses (
+    group_id UUID NOT NULL REFERENCES study_groups(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    assigned_by UUID REFERENCES users(id),
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, course_id)
+);
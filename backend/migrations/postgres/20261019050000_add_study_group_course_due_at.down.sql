@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_study_group_courses_due;
+ALTER TABLE study_group_courses DROP COLUMN due_at;
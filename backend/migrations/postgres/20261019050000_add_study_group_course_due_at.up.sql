@@ -0,0 +1,3 @@
+-- Срок прохождения курса группой: за сутки участникам уходит напоминание (assignment_due)
+ALTER TABLE study_group_courses ADD COLUMN due_at TIMESTAMP;
+CREATE INDEX idx_study_group_courses_due ON study_group_courses(due_at) WHERE due_at IS NOT NULL;
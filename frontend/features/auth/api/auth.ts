@@ -12,6 +12,7 @@ export interface TelegramUser {
 
 export interface TelegramAuthResponse {
   token: string
+  refresh_token: string
   user_id: string
   session_id: string
   username: string
@@ -30,6 +31,7 @@ export const telegramAuth = async (telegramData: TelegramUser): Promise<Telegram
   const { data } = await axios.post('/api/telegram/auth', telegramData)
 
   localStorage.setItem('token', data.token)
+  localStorage.setItem('refresh_token', data.refresh_token)
   localStorage.setItem('user', JSON.stringify({
     id: data.user_id,
     username: data.username,
@@ -42,8 +44,34 @@ export const telegramAuth = async (telegramData: TelegramUser): Promise<Telegram
   return data
 }
 
+// Ответ 401 на вход с высоким риском: код отправлен на email или в Telegram
+export interface StepUpRequired {
+  step_up_required: true
+  challenge_id: string
+  channel: 'email' | 'telegram' | 'totp'
+  expires_at: string
+}
+
+export const completeStepUp = async (challengeId: string, code: string): Promise<TelegramAuthResponse> => {
+  const { data } = await axios.post('/api/auth/step-up', { challenge_id: challengeId, code })
+
+  localStorage.setItem('token', data.token)
+  localStorage.setItem('refresh_token', data.refresh_token)
+  localStorage.setItem('user', JSON.stringify({
+    id: data.user_id,
+    username: data.username,
+    full_name: data.full_name,
+    role: data.role
+  }))
+
+  axios.defaults.headers.common['Authorization'] = `Bearer ${data.token}`
+
+  return data
+}
+
 export const logout = (): void => {
   localStorage.removeItem('token')
+  localStorage.removeItem('refresh_token')
   localStorage.removeItem('user')
   delete axios.defaults.headers.common['Authorization']
 }
\ No newline at end of file
@@ -41,6 +41,7 @@ export const useSessionStore = create<SessionState>((set) => ({
   reset: () => {
     if (typeof window !== 'undefined') {
       localStorage.removeItem('token')
+      localStorage.removeItem('refresh_token')
     }
     set({ token: null, user: null })
   },
@@ -1,7 +1,7 @@
 'use client'
 
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
-import { getUserSessions, deleteUserSession, UserSession } from '@/features/user/api/user'
+import { getUserSessions, deleteUserSession, logoutOtherSessions, UserSession } from '@/features/user/api/user'
 import { Button } from '@/components/ui/button'
 import { formatDistanceToNow } from 'date-fns'
 import { ru } from 'date-fns/locale'
@@ -21,12 +21,26 @@ export const UserSessions = () => {
     },
   })
 
+  const { mutate: logoutOthers, isPending: isLoggingOut } = useMutation({
+    mutationFn: logoutOtherSessions,
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['sessions'] })
+    },
+  })
+
   if (isLoading) return <p className="text-muted-foreground">Загрузка сессий...</p>
   if (isError) return <p className="text-destructive">Ошибка загрузки сессий</p>
 
   return (
     <div className="space-y-4">
-      <h2 className="text-xl font-semibold">Активные сессии</h2>
+      <div className="flex justify-between items-center">
+        <h2 className="text-xl font-semibold">Активные сессии</h2>
+        {(sessions?.length ?? 0) > 1 && (
+          <Button variant="outline" size="sm" disabled={isLoggingOut} onClick={() => logoutOthers()}>
+            Выйти на других устройствах
+          </Button>
+        )}
+      </div>
       {sessions?.length === 0 ? (
         <p className="text-muted-foreground">Нет активных сессий</p>
       ) : (
@@ -35,6 +49,8 @@ export const UserSessions = () => {
             <div className="flex-1">
               <p className="font-medium">
                 {session.city || 'Неизвестно'}, {session.country || 'Неизвестно'} — {session.ip_address}
+                {session.is_current && <span className="ml-2 text-sm text-primary">(текущая)</span>}
+                {session.suspicious && <span className="ml-2 text-sm text-destructive">(подозрительный вход)</span>}
               </p>
               <p className="text-sm text-muted-foreground mt-1">
                 Устройство: {session.user_agent?.substring(0, 50)}...
@@ -29,6 +29,7 @@ export const useSessionStore = create<SessionState>((set) => ({
   setInactivityTimeout: (seconds) => set({ inactivityTimeout: seconds }),
   reset: () => {
     localStorage.removeItem('token')
+    localStorage.removeItem('refresh_token')
     set({ token: null, user: null })
   },
 }))
@@ -11,6 +11,11 @@ export interface UserSession {
   created_at: string
   expires_at: string
   last_active_at: string
+  is_current: boolean
+  suspicious: boolean
+  risk_reasons?: string[]
+  // Сессия открыта администратором от имени пользователя
+  impersonator_id?: string
 }
 
 export interface InactivityTimeout {
@@ -34,6 +39,11 @@ export const deleteUserSession = async (sessionId: string) => {
   await axios.delete(`/api/me/sessions/${sessionId}`)
 }
 
+export const logoutOtherSessions = async (): Promise<{ revoked: number }> => {
+  const { data } = await axios.post('/api/me/sessions/logout-others')
+  return data
+}
+
 export const getInactivityTimeout = async (): Promise<InactivityTimeout> => {
   const { data } = await axios.get('/api/me/inactivity-timeout')
   return data
@@ -43,4 +53,515 @@ export const setInactivityTimeout = async (timeoutSeconds: number) => {
   await axios.post('/api/me/inactivity-timeout', {
     timeout_seconds: timeoutSeconds,
   })
-}
\ No newline at end of file
+}
+export interface MFAStatus {
+  enabled: boolean
+  enabled_at?: string
+  recovery_codes_left: number
+  session_verified: boolean
+}
+
+export interface TOTPEnrollment {
+  secret: string
+  // otpauth:// — показывается QR-кодом
+  provisioning_uri: string
+}
+
+export const getMFAStatus = async (): Promise<MFAStatus> => {
+  const { data } = await axios.get('/api/me/mfa')
+  return data
+}
+
+export const beginTOTP = async (): Promise<TOTPEnrollment> => {
+  const { data } = await axios.post('/api/me/mfa/totp')
+  return data
+}
+
+export const confirmTOTP = async (code: string): Promise<string[]> => {
+  const { data } = await axios.post('/api/me/mfa/totp/confirm', { code })
+  return data.recovery_codes
+}
+
+export const verifyMFASession = async (code: string) => {
+  await axios.post('/api/me/mfa/verify', { code })
+}
+
+export const regenerateRecoveryCodes = async (code: string): Promise<string[]> => {
+  const { data } = await axios.post('/api/me/mfa/recovery-codes', { code })
+  return data.recovery_codes
+}
+
+export const disableMFA = async (code: string) => {
+  await axios.delete('/api/me/mfa', { data: { code } })
+}
+
+export interface APIToken {
+  id: string
+  name: string
+  prefix: string
+  scopes: string[]
+  mfa: boolean
+  expires_at: string
+  last_used_at?: string
+  last_used_ip?: string
+  created_at: string
+  revoked_at?: string
+}
+
+export interface CreateAPITokenInput {
+  name: string
+  scopes: string[]
+  expires_in_days?: number
+}
+
+export const listAPITokens = async (): Promise<APIToken[]> => {
+  const { data } = await axios.get('/api/me/tokens')
+  return data
+}
+
+// Значение token возвращается только при создании
+export const createAPIToken = async (input: CreateAPITokenInput): Promise<APIToken & { token: string }> => {
+  const { data } = await axios.post('/api/me/tokens', input)
+  return data
+}
+
+export const revokeAPIToken = async (id: string) => {
+  await axios.delete(`/api/me/tokens/${id}`)
+}
+
+export interface Profile {
+  id: string
+  username?: string
+  full_name?: string
+  photo_url?: string
+  email?: string
+  email_verified_at?: string
+  subscribe_to_newsletter: boolean
+  role: string
+  // Организация пользователя и его роль в ней
+  org_id?: string
+  org_role?: 'owner' | 'admin' | 'member'
+  created_at: string
+  updated_at: string
+}
+
+export interface UpdateProfileInput {
+  full_name?: string
+  photo_url?: string
+  email?: string
+  subscribe_to_newsletter?: boolean
+}
+
+export const getProfile = async (): Promise<Profile> => {
+  const { data } = await axios.get('/api/me')
+  return data
+}
+
+export const updateProfile = async (input: UpdateProfileInput): Promise<Profile> => {
+  const { data } = await axios.patch('/api/me', input)
+  return data
+}
+
+// Zip-архив со всеми данными пользователя
+export const exportMyData = async (): Promise<Blob> => {
+  const { data } = await axios.get('/api/me/export', { responseType: 'blob' })
+  return data
+}
+
+export const deleteAccount = async (input: { password?: string; code?: string }) => {
+  await axios.delete('/api/me', { data: input })
+}
+
+// Админка: управление пользователями
+
+export interface AdminUser extends Profile {
+  telegram_id?: number
+  deleted_at?: string
+  banned_at?: string
+  banned_until?: string
+  ban_reason?: string
+}
+
+export interface Paginated<T> {
+  items: T[]
+  total: number
+  limit: number
+  offset: number
+}
+
+export interface AdminUserQuery {
+  q?: string
+  role?: string
+  status?: 'active' | 'banned' | 'deleted' | 'all'
+  limit?: number
+  offset?: number
+}
+
+export interface AdminAuditEntry {
+  id: string
+  actor_id: string
+  action: string
+  target_user_id?: string
+  details: Record<string, unknown>
+  ip_address: string
+  created_at: string
+}
+
+export type BulkUserAction =
+  | { action: 'set_role'; user_ids: string[]; role: string }
+  | { action: 'ban'; user_ids: string[]; reason: string; until?: string }
+  | { action: 'unban' | 'revoke_sessions'; user_ids: string[] }
+
+export const searchUsers = async (params: AdminUserQuery): Promise<Paginated<AdminUser>> => {
+  const { data } = await axios.get('/api/admin/users', { params })
+  return data
+}
+
+export const setUserRole = async (userId: string, role: string) => {
+  await axios.put(`/api/admin/users/${userId}/role`, { role })
+}
+
+export const banUser = async (userId: string, input: { reason: string; until?: string }) => {
+  await axios.post(`/api/admin/users/${userId}/ban`, input)
+}
+
+export const unbanUser = async (userId: string) => {
+  await axios.delete(`/api/admin/users/${userId}/ban`)
+}
+
+export const revokeUserSessions = async (userId: string): Promise<{ revoked: number }> => {
+  const { data } = await axios.delete(`/api/admin/users/${userId}/sessions`)
+  return data
+}
+
+// Access-токен на 30 минут без refresh-токена; все запросы с ним попадают в журнал
+export const impersonateUser = async (
+  userId: string,
+  reason: string,
+): Promise<{ token: string; expires_at: string; user_id: string; session_id: string }> => {
+  const { data } = await axios.post(`/api/admin/users/${userId}/impersonate`, { reason })
+  return data
+}
+
+export const bulkUserAction = async (input: BulkUserAction): Promise<{ user_id: string; error?: string }[]> => {
+  const { data } = await axios.post('/api/admin/users/bulk', input)
+  return data
+}
+
+export const getAdminAudit = async (params: {
+  target_user_id?: string
+  actor_id?: string
+  limit?: number
+  offset?: number
+}): Promise<Paginated<AdminAuditEntry>> => {
+  const { data } = await axios.get('/api/admin/audit', { params })
+  return data
+}
+
+// Заявки на роль преподавателя
+
+export type TeacherApplicationStatus = 'pending' | 'approved' | 'rejected' | 'withdrawn'
+
+export interface TeacherApplication {
+  id: string
+  user_id: string
+  bio: string
+  portfolio_links: string[]
+  status: TeacherApplicationStatus
+  reviewed_by?: string
+  review_comment?: string
+  reviewed_at?: string
+  created_at: string
+  updated_at: string
+}
+
+export const listMyTeacherApplications = async (): Promise<TeacherApplication[]> => {
+  const { data } = await axios.get('/api/me/teacher-applications')
+  return data
+}
+
+export const applyForTeacher = async (input: { bio: string; portfolio_links: string[] }): Promise<TeacherApplication> => {
+  const { data } = await axios.post('/api/me/teacher-applications', input)
+  return data
+}
+
+export const withdrawTeacherApplication = async (id: string) => {
+  await axios.delete(`/api/me/teacher-applications/${id}`)
+}
+
+export const listTeacherApplications = async (params: {
+  status?: TeacherApplicationStatus
+  limit?: number
+  offset?: number
+}): Promise<Paginated<TeacherApplication>> => {
+  const { data } = await axios.get('/api/admin/teacher-applications', { params })
+  return data
+}
+
+export const approveTeacherApplication = async (id: string, comment?: string): Promise<TeacherApplication> => {
+  const { data } = await axios.post(`/api/admin/teacher-applications/${id}/approve`, { comment })
+  return data
+}
+
+// Комментарий обязателен — его увидит заявитель
+export const rejectTeacherApplication = async (id: string, comment: string): Promise<TeacherApplication> => {
+  const { data } = await axios.post(`/api/admin/teacher-applications/${id}/reject`, { comment })
+  return data
+}
+
+// Организации (закрытые пространства школ)
+
+export type OrgRole = 'owner' | 'admin' | 'member'
+
+export interface Organization {
+  id: string
+  slug: string
+  name: string
+  created_by?: string
+  created_at: string
+  updated_at: string
+  members_count: number
+}
+
+export interface OrganizationMember {
+  user_id: string
+  username?: string
+  full_name?: string
+  email?: string
+  role: string
+  org_role: OrgRole
+  joined_at?: string
+}
+
+export const getMyOrganization = async (): Promise<Organization> => {
+  const { data } = await axios.get('/api/me/organization')
+  return data
+}
+
+// Последний владелец выйти не может
+export const leaveOrganization = async () => {
+  await axios.delete('/api/me/organization')
+}
+
+export const getOrganization = async (id: string): Promise<Organization> => {
+  const { data } = await axios.get(`/api/organizations/${id}`)
+  return data
+}
+
+export const updateOrganization = async (id: string, input: { name: string; slug?: string }): Promise<Organization> => {
+  const { data } = await axios.put(`/api/organizations/${id}`, input)
+  return data
+}
+
+export const listOrganizationMembers = async (id: string, params: {
+  limit?: number
+  offset?: number
+}): Promise<Paginated<OrganizationMember>> => {
+  const { data } = await axios.get(`/api/organizations/${id}/members`, { params })
+  return data
+}
+
+export const addOrganizationMember = async (id: string, input: {
+  user_id?: string
+  email?: string
+  org_role?: OrgRole
+}): Promise<OrganizationMember> => {
+  const { data } = await axios.post(`/api/organizations/${id}/members`, input)
+  return data
+}
+
+export const setOrganizationMemberRole = async (id: string, userId: string, orgRole: OrgRole): Promise<OrganizationMember> => {
+  const { data } = await axios.put(`/api/organizations/${id}/members/${userId}`, { org_role: orgRole })
+  return data
+}
+
+export const removeOrganizationMember = async (id: string, userId: string) => {
+  await axios.delete(`/api/organizations/${id}/members/${userId}`)
+}
+
+// Только администраторы платформы
+export const createOrganization = async (input: {
+  name: string
+  slug?: string
+  owner_id?: string
+  owner_email?: string
+}): Promise<Organization> => {
+  const { data } = await axios.post('/api/organizations', input)
+  return data
+}
+
+export const listOrganizations = async (params: {
+  limit?: number
+  offset?: number
+}): Promise<Paginated<Organization>> => {
+  const { data } = await axios.get('/api/admin/organizations', { params })
+  return data
+}
+
+export const deleteOrganization = async (id: string) => {
+  await axios.delete(`/api/organizations/${id}`)
+}
+
+// Учебные группы
+
+export interface StudyGroup {
+  id: string
+  author_id: string
+  name: string
+  description: string
+  org_id?: string
+  // Код и ссылка приглашения видны только преподавателю группы
+  join_code?: string
+  join_url?: string
+  join_enabled: boolean
+  members_count: number
+  courses_count: number
+  created_at: string
+  updated_at: string
+}
+
+export interface StudyGroupMember {
+  user_id: string
+  username?: string
+  full_name?: string
+  email?: string
+  joined_at: string
+}
+
+export interface StudyGroupCourse {
+  course_id: string
+  title: string
+  slug: string
+  assigned_by?: string
+  assigned_at: string
+  // Срок прохождения: за сутки участникам приходит напоминание в Telegram
+  due_at?: string
+}
+
+export interface StudyGroupJoinResult {
+  group_id: string
+  user_id: string
+  enrolled: number
+}
+
+export interface StudyGroupProgress {
+  group_id: string
+  courses: (StudyGroupCourse & {
+    total_lessons: number
+    completed_members: number
+    not_started_members: number
+    average_percent: number
+  })[]
+  members: (StudyGroupMember & {
+    courses: {
+      course_id: string
+      total_lessons: number
+      completed_lessons: number
+      percent: number
+      last_activity_at?: string
+    }[]
+    average_percent: number
+    last_activity_at?: string
+  })[]
+  total: number
+  limit: number
+  offset: number
+}
+
+export const joinStudyGroup = async (code: string): Promise<StudyGroupJoinResult> => {
+  const { data } = await axios.post('/api/groups/join', { code })
+  return data
+}
+
+export const getMyStudyGroups = async (): Promise<StudyGroup[]> => {
+  const { data } = await axios.get('/api/me/groups')
+  return data
+}
+
+export const leaveStudyGroup = async (id: string) => {
+  await axios.delete(`/api/me/groups/${id}`)
+}
+
+export const getStudyGroup = async (id: string): Promise<StudyGroup> => {
+  const { data } = await axios.get(`/api/groups/${id}`)
+  return data
+}
+
+export const listStudyGroupCourses = async (id: string): Promise<StudyGroupCourse[]> => {
+  const { data } = await axios.get(`/api/groups/${id}/courses`)
+  return data
+}
+
+// Преподавателям
+export const createStudyGroup = async (input: {
+  name: string
+  description?: string
+  join_enabled?: boolean
+}): Promise<StudyGroup> => {
+  const { data } = await axios.post('/api/groups', input)
+  return data
+}
+
+export const listOwnedStudyGroups = async (params: {
+  limit?: number
+  offset?: number
+}): Promise<Paginated<StudyGroup>> => {
+  const { data } = await axios.get('/api/groups', { params })
+  return data
+}
+
+export const updateStudyGroup = async (id: string, input: {
+  name: string
+  description?: string
+  join_enabled?: boolean
+}): Promise<StudyGroup> => {
+  const { data } = await axios.put(`/api/groups/${id}`, input)
+  return data
+}
+
+export const deleteStudyGroup = async (id: string) => {
+  await axios.delete(`/api/groups/${id}`)
+}
+
+export const regenerateStudyGroupJoinCode = async (id: string): Promise<StudyGroup> => {
+  const { data } = await axios.post(`/api/groups/${id}/join-code`)
+  return data
+}
+
+export const listStudyGroupMembers = async (id: string, params: {
+  limit?: number
+  offset?: number
+}): Promise<Paginated<StudyGroupMember>> => {
+  const { data } = await axios.get(`/api/groups/${id}/members`, { params })
+  return data
+}
+
+export const addStudyGroupMember = async (id: string, input: {
+  user_id?: string
+  email?: string
+}): Promise<StudyGroupJoinResult> => {
+  const { data } = await axios.post(`/api/groups/${id}/members`, input)
+  return data
+}
+
+export const removeStudyGroupMember = async (id: string, userId: string) => {
+  await axios.delete(`/api/groups/${id}/members/${userId}`)
+}
+
+// Повторное назначение того же курса меняет срок (без dueAt — снимает его)
+export const assignStudyGroupCourse = async (id: string, courseId: string, dueAt?: string): Promise<StudyGroupCourse & { enrolled: number }> => {
+  const { data } = await axios.post(`/api/groups/${id}/courses`, { course_id: courseId, due_at: dueAt })
+  return data
+}
+
+export const unassignStudyGroupCourse = async (id: string, courseId: string) => {
+  await axios.delete(`/api/groups/${id}/courses/${courseId}`)
+}
+
+export const getStudyGroupProgress = async (id: string, params: {
+  limit?: number
+  offset?: number
+}): Promise<StudyGroupProgress> => {
+  const { data } = await axios.get(`/api/groups/${id}/progress`, { params })
+  return data
+}
@@ -22,24 +22,67 @@ axiosInstance.interceptors.request.use((config) => {
   return config
 })
 
-// Добавляем интерсептор для автоматического обновления токена
-axiosInstance.interceptors.response.use(
-  (response) => {
-    const newToken = response.headers['x-refresh-token']
-    if (newToken && typeof window !== 'undefined') {
-      localStorage.setItem('token', newToken)
-      axiosInstance.defaults.headers.common['Authorization'] = `Bearer ${newToken}`
+// Access-токен живёт несколько минут: при 401 один раз обмениваем refresh-токен
+// на новую пару и повторяем запрос. Параллельные запросы ждут одного обновления
+let refreshPromise: Promise<string | null> | null = null
+
+const requestNewPair = async (refreshToken: string): Promise<string | null> => {
+  try {
+    const { data } = await axios.post(`${API_BASE_URL}/api/auth/refresh`, { refresh_token: refreshToken })
+    localStorage.setItem('token', data.token)
+    localStorage.setItem('refresh_token', data.refresh_token)
+    axiosInstance.defaults.headers.common['Authorization'] = `Bearer ${data.token}`
+    return data.token
+  } catch {
+    return null
+  }
+}
+
+// Вкладки делят localStorage: обновление идёт под общей блокировкой, и вкладка,
+// дождавшаяся её после соседки, берёт уже выданную пару вместо повторного обмена
+// старого токена (сервер счёл бы это кражей и завершил сессию)
+const refreshAccessToken = async (): Promise<string | null> => {
+  const staleToken = localStorage.getItem('refresh_token')
+  if (!staleToken) return null
+  const run = async (): Promise<string | null> => {
+    const refreshToken = localStorage.getItem('refresh_token')
+    if (!refreshToken) return null
+    if (refreshToken !== staleToken) {
+      const token = localStorage.getItem('token')
+      if (token) axiosInstance.defaults.headers.common['Authorization'] = `Bearer ${token}`
+      return token
     }
-    return response
-  },
-  (error) => {
-    if (error.response?.status === 401) {
-      // Токен истек или невалиден
-      if (typeof window !== 'undefined') {
-        localStorage.removeItem('token')
-        localStorage.removeItem('user')
-        window.location.href = '/login'
+    return requestNewPair(refreshToken)
+  }
+  if (typeof navigator !== 'undefined' && navigator.locks) {
+    return navigator.locks.request('auth-refresh', run)
+  }
+  return run()
+}
+
+const clearAuth = () => {
+  localStorage.removeItem('token')
+  localStorage.removeItem('refresh_token')
+  localStorage.removeItem('user')
+  window.location.href = '/login'
+}
+
+axiosInstance.interceptors.response.use(
+  (response) => response,
+  async (error) => {
+    const original = error.config
+    if (error.response?.status === 401 && typeof window !== 'undefined') {
+      if (original && !original._retried) {
+        original._retried = true
+        refreshPromise = refreshPromise ?? refreshAccessToken().finally(() => { refreshPromise = null })
+        const token = await refreshPromise
+        if (token) {
+          original.headers.Authorization = `Bearer ${token}`
+          return axiosInstance(original)
+        }
       }
+      // Токен истек или невалиден
+      clearAuth()
     }
     return Promise.reject(error)
   }